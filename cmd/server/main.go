@@ -3,15 +3,21 @@ package main
 import (
 	"fmt"
 	"net/http"
+	"time"
 
 	"github.com/safepass/server/internal/api/handlers"
 	"github.com/safepass/server/internal/api/middlewares"
 	"github.com/safepass/server/internal/api/routes"
 	"github.com/safepass/server/internal/config"
 	"github.com/safepass/server/internal/database"
+	"github.com/safepass/server/internal/jobs"
 	"github.com/safepass/server/internal/logging"
+	"github.com/safepass/server/internal/mail"
+	"github.com/safepass/server/internal/notifications"
+	"github.com/safepass/server/internal/ratelimit"
 	"github.com/safepass/server/internal/repositories"
 	"github.com/safepass/server/internal/services"
+	"github.com/safepass/server/internal/storage"
 	"github.com/safepass/server/pkg/dotenv"
 )
 
@@ -21,6 +27,11 @@ func main() {
 	var appConfig config.Config
 	config.LoadConfig(&appConfig)
 
+	_, err := appConfig.GetExportMacKey()
+	if err != nil {
+		panic(err)
+	}
+
 	context, err := database.NewAppContextDB()
 	if err != nil {
 		fmt.Println(err)
@@ -31,24 +42,86 @@ func main() {
 	logger, err := logging.NewLogger(logging.INFO, "log.txt")
 
 	userRepository := repositories.NewUserRepository(client)
+	userKeyRepository := repositories.NewUserKeyRepository(client, logger)
+	auditRepository := repositories.NewAuditRepository(client, logger)
 	vaultRepository := repositories.NewVaultRepository(client, logger)
 	passwordRepository := repositories.NewPasswordRepository(client, logger)
+	passwordShareRepository := repositories.NewPasswordShareRepository(client, logger)
+	emergencyAccessRepository := repositories.NewEmergencyAccessRepository(client, logger)
+	organizationRepository := repositories.NewOrganizationRepository(client, logger)
+	organizationMemberRepository := repositories.NewOrganizationMemberRepository(client, logger)
+	collectionRepository := repositories.NewCollectionRepository(client, logger)
+	organizationPolicyRepository := repositories.NewOrganizationPolicyRepository(client, logger)
+	folderRepository := repositories.NewFolderRepository(client, logger)
+	tagRepository := repositories.NewTagRepository(client, logger)
+	attachmentRepository := repositories.NewAttachmentRepository(client, logger)
+	passwordHistoryRepository := repositories.NewPasswordHistoryRepository(client, logger)
+	passwordTombstoneRepository := repositories.NewPasswordTombstoneRepository(client, logger)
+	sendRepository := repositories.NewSendRepository(client, logger)
 
-	userServices := services.NewUserServices(userRepository)
-	vaultServices := services.NewVaultServices(vaultRepository, passwordRepository, &appConfig)
-	authServices := services.NewAuthServices(userServices, vaultServices, &appConfig)
+	mailer, err := mail.NewMailer(appConfig.Mail)
+	if err != nil {
+		panic(err)
+	}
+
+	blobStore, err := storage.NewBlobStore(appConfig.Attachments)
+	if err != nil {
+		panic(err)
+	}
+
+	notificationHub := notifications.NewHub(appConfig.Notifications.HistorySize)
+
+	passwordHintLimiter := ratelimit.NewLimiter(appConfig.Account.PasswordHintRateLimit, time.Second*time.Duration(appConfig.Account.PasswordHintRateWindow))
+	sendAccessLimiter := ratelimit.NewLimiter(appConfig.Sends.AccessRateLimit, time.Second*time.Duration(appConfig.Sends.AccessRateWindow))
+
+	emailVerificationServices := services.NewEmailVerificationServices(userRepository, mailer, logger, &appConfig)
+	passwordHintServices := services.NewPasswordHintServices(userRepository, mailer, passwordHintLimiter, logger, &appConfig)
+	userServices := services.NewUserServices(userRepository, userKeyRepository, auditRepository, emailVerificationServices, logger, &appConfig)
+	policyServices := services.NewPolicyServices(organizationMemberRepository, organizationPolicyRepository)
+	notificationServices := services.NewNotificationServices(notificationHub, vaultRepository, passwordShareRepository, &appConfig)
+	vaultServices := services.NewVaultServices(vaultRepository, passwordRepository, passwordShareRepository, folderRepository, tagRepository, passwordHistoryRepository, userServices, policyServices, notificationServices, &appConfig)
+	authServices := services.NewAuthServices(userServices, vaultServices, emailVerificationServices, passwordHintServices, policyServices, &appConfig)
+	emergencyAccessServices := services.NewEmergencyAccessServices(emergencyAccessRepository, auditRepository, userServices, vaultServices, mailer, logger, &appConfig)
+	folderServices := services.NewFolderServices(folderRepository, vaultServices)
+	tagServices := services.NewTagServices(tagRepository, vaultServices)
+	syncServices := services.NewSyncServices(passwordRepository, passwordTombstoneRepository, passwordShareRepository, vaultServices, userServices, &appConfig)
+	exportServices := services.NewExportServices(vaultRepository, passwordRepository, folderRepository, tagRepository, vaultServices, policyServices, &appConfig)
+	attachmentServices := services.NewAttachmentServices(attachmentRepository, passwordRepository, vaultServices, blobStore, logger, &appConfig)
+	sendServices := services.NewSendServices(sendRepository, attachmentRepository, vaultServices, blobStore, sendAccessLimiter, logger, &appConfig)
+	organizationServices := services.NewOrganizationServices(organizationRepository, organizationMemberRepository, collectionRepository, organizationPolicyRepository, passwordRepository, userServices, mailer, logger, &appConfig)
 
 	authHandlers := handlers.NewAuthHandlers(*authServices)
+	userHandlers := handlers.NewUserHandlers(*userServices)
 	vaultHandlers := handlers.NewVaultHandlers(*vaultServices)
+	emergencyAccessHandlers := handlers.NewEmergencyAccessHandlers(*emergencyAccessServices)
+	organizationHandlers := handlers.NewOrganizationHandlers(*organizationServices)
+	folderHandlers := handlers.NewFolderHandlers(*folderServices)
+	tagHandlers := handlers.NewTagHandlers(*tagServices)
+	attachmentHandlers := handlers.NewAttachmentHandlers(*attachmentServices)
+	syncHandlers := handlers.NewSyncHandlers(*syncServices)
+	notificationHandlers := handlers.NewNotificationHandlers(*notificationServices)
+	exportHandlers := handlers.NewExportHandlers(*exportServices)
+	sendHandlers := handlers.NewSendHandlers(*sendServices)
 
 	if err != nil {
 		panic(err)
 	}
 
+	scheduler := jobs.NewScheduler(logger)
+	scheduler.Register("purge_scheduled_deletions", time.Hour, userServices.PurgeScheduledDeletions)
+	scheduler.Register("approve_emergency_access", time.Hour, emergencyAccessServices.ApproveDueRecoveries)
+	scheduler.Register("purge_trash", time.Hour, vaultServices.PurgeTrash)
+	scheduler.Register("purge_tombstones", time.Hour, syncServices.PurgeTombstones)
+	scheduler.Register("purge_deleted_blobs", time.Minute*15, attachmentServices.PurgeDeletedBlobs)
+	scheduler.Register("evict_notification_histories", time.Minute*5, notificationServices.EvictHistories)
+	scheduler.Register("purge_sends", time.Hour, sendServices.PurgeSends)
+	scheduler.Start()
+	defer scheduler.Stop()
+
 	logMiddleware := middlewares.NewLogMiddleware(logger)
-	authMiddleware := middlewares.NewAuthMiddleware(logger, appConfig)
+	authMiddleware := middlewares.NewAuthMiddleware(logger, userRepository, appConfig)
 
-	router := routes.NewRouter(authMiddleware, authHandlers, vaultHandlers)
+	router := routes.NewRouter(authMiddleware, authHandlers, userHandlers, vaultHandlers, emergencyAccessHandlers, organizationHandlers, folderHandlers, tagHandlers, attachmentHandlers, syncHandlers, notificationHandlers, exportHandlers, sendHandlers)
 	mux := router.NewServer()
 
 	loggedMux := logMiddleware.LogMiddlewareFunc(mux)
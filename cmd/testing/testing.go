@@ -44,8 +44,9 @@ func generatePrivateKey() {
 func vaults(client *supabase.Client, appConfig config.Config, logger *logging.Logger) {
 	vaultRepository := repositories.NewVaultRepository(client, logger)
 	passwordRepository := repositories.NewPasswordRepository(client, logger)
+	passwordShareRepository := repositories.NewPasswordShareRepository(client, logger)
 
-	vaultServices := services.NewVaultServices(vaultRepository, passwordRepository, &appConfig)
+	vaultServices := services.NewVaultServices(vaultRepository, passwordRepository, passwordShareRepository, nil, nil, nil, nil, nil, nil, &appConfig)
 
 	vault, err := vaultServices.GetVaultByUserID("10")
 	if err != nil {
@@ -0,0 +1,306 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"strconv"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/safepass/server/internal/services"
+	"github.com/safepass/server/pkg/dtos/attachment"
+	"github.com/safepass/server/pkg/models"
+)
+
+type AttachmentHandlersFuncs interface {
+	Attachments(w http.ResponseWriter, r *http.Request)
+	Attachment(w http.ResponseWriter, r *http.Request)
+	GetAttachments(w http.ResponseWriter, r *http.Request)
+	UploadAttachment(w http.ResponseWriter, r *http.Request)
+	DownloadAttachment(w http.ResponseWriter, r *http.Request)
+	DeleteAttachment(w http.ResponseWriter, r *http.Request)
+}
+
+type AttachmentHandlers struct {
+	attachmentServices services.AttachmentServices
+
+	AttachmentHandlersFuncs
+}
+
+func NewAttachmentHandlers(attachmentServices services.AttachmentServices) *AttachmentHandlers {
+	return &AttachmentHandlers{
+		attachmentServices: attachmentServices,
+	}
+}
+
+// Attachments dispatches /api/v1/vaults/{vaultId}/items/{itemId}/attachments to the handler for the request
+// method.
+func (a *AttachmentHandlers) Attachments(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+		a.GetAttachments(w, r)
+	case http.MethodPost:
+		a.UploadAttachment(w, r)
+	default:
+		httpError(w, http.StatusMethodNotAllowed, nil)
+	}
+}
+
+// Attachment dispatches /api/v1/vaults/{vaultId}/items/{itemId}/attachments/{attachmentId} to the handler for the request
+// method.
+func (a *AttachmentHandlers) Attachment(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+		a.DownloadAttachment(w, r)
+	case http.MethodDelete:
+		a.DeleteAttachment(w, r)
+	default:
+		httpError(w, http.StatusMethodNotAllowed, nil)
+	}
+}
+
+func (a *AttachmentHandlers) GetAttachments(w http.ResponseWriter, r *http.Request) {
+	if r.Method != "GET" {
+		httpError(w, http.StatusMethodNotAllowed, nil)
+		return
+	}
+
+	claims, ok := r.Context().Value("claims").(jwt.MapClaims)
+	if !ok {
+		data := map[string]string{"message": "An error occurred during token decryption."}
+		httpError(w, http.StatusInternalServerError, data)
+		return
+	}
+
+	userID, ok := claims["sub"].(float64)
+	if !ok {
+		data := map[string]string{"message": "Token does not contain a userID."}
+		httpError(w, http.StatusBadRequest, data)
+		return
+	}
+
+	passwordID, err := strconv.Atoi(r.PathValue("itemId"))
+	if err != nil {
+		httpError(w, http.StatusBadRequest, nil)
+		return
+	}
+
+	attachments, merr := a.attachmentServices.GetAttachments(r.PathValue("vaultId"), int(userID), passwordID)
+	if merr != nil {
+		data := map[string]string{"message": merr.Description}
+		httpError(w, merr.Code, data)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+
+	response := models.Response{
+		Status:     http.StatusOK,
+		StatusText: http.StatusText(http.StatusOK),
+		Data:       attachments,
+	}
+
+	json.NewEncoder(w).Encode(response)
+}
+
+// UploadAttachment takes a multipart form with encrypted_file_name and
+// encrypted_key followed by the encrypted file in data.
+func (a *AttachmentHandlers) UploadAttachment(w http.ResponseWriter, r *http.Request) {
+	if r.Method != "POST" {
+		httpError(w, http.StatusMethodNotAllowed, nil)
+		return
+	}
+
+	claims, ok := r.Context().Value("claims").(jwt.MapClaims)
+	if !ok {
+		data := map[string]string{"message": "An error occurred during token decryption."}
+		httpError(w, http.StatusInternalServerError, data)
+		return
+	}
+
+	userID, ok := claims["sub"].(float64)
+	if !ok {
+		data := map[string]string{"message": "Token does not contain a userID."}
+		httpError(w, http.StatusBadRequest, data)
+		return
+	}
+
+	passwordID, err := strconv.Atoi(r.PathValue("itemId"))
+	if err != nil {
+		httpError(w, http.StatusBadRequest, nil)
+		return
+	}
+
+	uploadRequest, err := readUploadRequest(r)
+	if err != nil {
+		data := map[string]string{"message": err.Error()}
+		httpError(w, http.StatusBadRequest, data)
+		return
+	}
+
+	created, merr := a.attachmentServices.UploadAttachment(r.PathValue("vaultId"), int(userID), passwordID, uploadRequest)
+	if merr != nil {
+		data := map[string]string{"message": merr.Description}
+		httpError(w, merr.Code, data)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+
+	response := models.Response{
+		Status:     http.StatusCreated,
+		StatusText: http.StatusText(http.StatusCreated),
+		Data:       created,
+	}
+
+	json.NewEncoder(w).Encode(response)
+}
+
+// DownloadAttachment streams the encrypted content of an attachment.
+func (a *AttachmentHandlers) DownloadAttachment(w http.ResponseWriter, r *http.Request) {
+	if r.Method != "GET" {
+		httpError(w, http.StatusMethodNotAllowed, nil)
+		return
+	}
+
+	claims, ok := r.Context().Value("claims").(jwt.MapClaims)
+	if !ok {
+		data := map[string]string{"message": "An error occurred during token decryption."}
+		httpError(w, http.StatusInternalServerError, data)
+		return
+	}
+
+	userID, ok := claims["sub"].(float64)
+	if !ok {
+		data := map[string]string{"message": "Token does not contain a userID."}
+		httpError(w, http.StatusBadRequest, data)
+		return
+	}
+
+	passwordID, err := strconv.Atoi(r.PathValue("itemId"))
+	if err != nil {
+		httpError(w, http.StatusBadRequest, nil)
+		return
+	}
+
+	attachmentID, err := strconv.Atoi(r.PathValue("attachmentId"))
+	if err != nil {
+		httpError(w, http.StatusBadRequest, nil)
+		return
+	}
+
+	found, content, merr := a.attachmentServices.OpenAttachment(r.PathValue("vaultId"), int(userID), passwordID, attachmentID)
+	if merr != nil {
+		data := map[string]string{"message": merr.Description}
+		httpError(w, merr.Code, data)
+		return
+	}
+
+	defer content.Close()
+
+	w.Header().Set("Content-Type", "application/octet-stream")
+	w.Header().Set("Content-Length", strconv.FormatInt(found.Size, 10))
+	w.WriteHeader(http.StatusOK)
+
+	io.Copy(w, content)
+}
+
+func (a *AttachmentHandlers) DeleteAttachment(w http.ResponseWriter, r *http.Request) {
+	if r.Method != "DELETE" {
+		httpError(w, http.StatusMethodNotAllowed, nil)
+		return
+	}
+
+	claims, ok := r.Context().Value("claims").(jwt.MapClaims)
+	if !ok {
+		data := map[string]string{"message": "An error occurred during token decryption."}
+		httpError(w, http.StatusInternalServerError, data)
+		return
+	}
+
+	userID, ok := claims["sub"].(float64)
+	if !ok {
+		data := map[string]string{"message": "Token does not contain a userID."}
+		httpError(w, http.StatusBadRequest, data)
+		return
+	}
+
+	passwordID, err := strconv.Atoi(r.PathValue("itemId"))
+	if err != nil {
+		httpError(w, http.StatusBadRequest, nil)
+		return
+	}
+
+	attachmentID, err := strconv.Atoi(r.PathValue("attachmentId"))
+	if err != nil {
+		httpError(w, http.StatusBadRequest, nil)
+		return
+	}
+
+	merr := a.attachmentServices.DeleteAttachment(r.PathValue("vaultId"), int(userID), passwordID, attachmentID)
+	if merr != nil {
+		data := map[string]string{"message": merr.Description}
+		httpError(w, merr.Code, data)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+
+	response := models.Response{
+		Status:     http.StatusOK,
+		StatusText: http.StatusText(http.StatusOK),
+		Data:       map[string]any{"id": attachmentID, "succeeded": true, "operation": "delete"},
+	}
+
+	json.NewEncoder(w).Encode(response)
+}
+
+// readUploadRequest reads the form fields of a multipart attachment upload up
+// to the "data" part holding the encrypted file, which the returned request
+// streams. The fields have to be sent before the file.
+func readUploadRequest(r *http.Request) (*attachment.UploadRequest, error) {
+	reader, err := r.MultipartReader()
+	if err != nil {
+		return nil, err
+	}
+
+	uploadRequest := &attachment.UploadRequest{}
+	for {
+		part, err := reader.NextPart()
+		if err == io.EOF {
+			return nil, errors.New("The upload has no data part.")
+		}
+
+		if err != nil {
+			return nil, err
+		}
+
+		switch part.FormName() {
+		case "encrypted_file_name", "encrypted_key":
+			value, err := io.ReadAll(io.LimitReader(part, 4096))
+			if err != nil {
+				return nil, err
+			}
+
+			if part.FormName() == "encrypted_file_name" {
+				uploadRequest.EncryptedFileName = string(value)
+			} else {
+				uploadRequest.EncryptedKey = string(value)
+			}
+		case "data":
+			validate := validator.New()
+			err = validate.Struct(uploadRequest)
+			if err != nil {
+				return nil, errors.New("The upload needs encrypted_file_name and encrypted_key before data.")
+			}
+
+			uploadRequest.Data = part
+			return uploadRequest, nil
+		}
+	}
+}
@@ -3,8 +3,10 @@ package handlers
 import (
 	"encoding/json"
 	"net/http"
+	"strconv"
 
 	"github.com/go-playground/validator/v10"
+	"github.com/golang-jwt/jwt/v5"
 	"github.com/safepass/server/internal/services"
 	"github.com/safepass/server/pkg/dtos/user"
 	"github.com/safepass/server/pkg/models"
@@ -13,6 +15,9 @@ import (
 type AuthHandlersFuncs interface {
 	Login(w http.ResponseWriter, r *http.Request)
 	Register(w http.ResponseWriter, r *http.Request)
+	VerifyEmail(w http.ResponseWriter, r *http.Request)
+	ResendVerificationEmail(w http.ResponseWriter, r *http.Request)
+	PasswordHint(w http.ResponseWriter, r *http.Request)
 }
 
 type AuthHandlers struct {
@@ -82,7 +87,7 @@ func (a *AuthHandlers) Login(w http.ResponseWriter, r *http.Request) {
 		response := models.Response{
 			Status:     merr.Code,
 			StatusText: http.StatusText(merr.Code),
-			Data:       map[string]string{"message": merr.Description},
+			Data:       errorData(merr),
 		}
 
 		json.NewEncoder(w).Encode(response)
@@ -178,3 +183,127 @@ func (a *AuthHandlers) Register(w http.ResponseWriter, r *http.Request) {
 
 	json.NewEncoder(w).Encode(response)
 }
+
+// VerifyEmail accepts the token either as the "token" query parameter, which
+// is what the emailed link uses, or as a JSON body from the apps.
+func (a *AuthHandlers) VerifyEmail(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet && r.Method != http.MethodPost {
+		httpError(w, http.StatusMethodNotAllowed, nil)
+		return
+	}
+
+	verifyRequest := &user.VerifyEmailRequest{
+		Token: r.URL.Query().Get("token"),
+	}
+
+	if r.Method == http.MethodPost {
+		err := json.NewDecoder(r.Body).Decode(&verifyRequest)
+		if err != nil {
+			httpError(w, http.StatusBadRequest, nil)
+			return
+		}
+	}
+
+	validate := validator.New()
+	err := validate.Struct(verifyRequest)
+	if err != nil {
+		httpError(w, http.StatusBadRequest, nil)
+		return
+	}
+
+	merr := a.authServices.VerifyEmail(verifyRequest.Token)
+	if merr != nil {
+		data := map[string]string{"message": merr.Description}
+		httpError(w, merr.Code, data)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+
+	response := models.Response{
+		Status:     http.StatusOK,
+		StatusText: http.StatusText(http.StatusOK),
+		Data:       map[string]string{"message": "Email verified"},
+	}
+
+	json.NewEncoder(w).Encode(response)
+}
+
+func (a *AuthHandlers) ResendVerificationEmail(w http.ResponseWriter, r *http.Request) {
+	if r.Method != "POST" {
+		httpError(w, http.StatusMethodNotAllowed, nil)
+		return
+	}
+
+	claims, ok := r.Context().Value("claims").(jwt.MapClaims)
+	if !ok {
+		data := map[string]string{"message": "An error occurred during token decryption."}
+		httpError(w, http.StatusInternalServerError, data)
+		return
+	}
+
+	userID, ok := claims["sub"].(float64)
+	if !ok {
+		data := map[string]string{"message": "Token does not contain a userID."}
+		httpError(w, http.StatusBadRequest, data)
+		return
+	}
+
+	merr := a.authServices.ResendVerificationEmail(strconv.Itoa(int(userID)))
+	if merr != nil {
+		data := map[string]string{"message": merr.Description}
+		httpError(w, merr.Code, data)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+
+	response := models.Response{
+		Status:     http.StatusOK,
+		StatusText: http.StatusText(http.StatusOK),
+		Data:       map[string]string{"message": "Verification email sent"},
+	}
+
+	json.NewEncoder(w).Encode(response)
+}
+
+func (a *AuthHandlers) PasswordHint(w http.ResponseWriter, r *http.Request) {
+	if r.Method != "POST" {
+		httpError(w, http.StatusMethodNotAllowed, nil)
+		return
+	}
+
+	var hintRequest *user.PasswordHintRequest
+	err := json.NewDecoder(r.Body).Decode(&hintRequest)
+	if err != nil {
+		httpError(w, http.StatusBadRequest, nil)
+		return
+	}
+
+	validate := validator.New()
+	err = validate.Struct(hintRequest)
+	if err != nil {
+		httpError(w, http.StatusBadRequest, nil)
+		return
+	}
+
+	merr := a.authServices.SendPasswordHint(hintRequest.Email)
+	if merr != nil {
+		data := map[string]string{"message": merr.Description}
+		httpError(w, merr.Code, data)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+
+	response := models.Response{
+		Status:     http.StatusOK,
+		StatusText: http.StatusText(http.StatusOK),
+		Data:       map[string]string{"message": "If an account exists for this email, the master password hint has been sent."},
+	}
+
+	json.NewEncoder(w).Encode(response)
+}
@@ -0,0 +1,595 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/safepass/server/internal/services"
+	"github.com/safepass/server/pkg/dtos/emergency"
+	"github.com/safepass/server/pkg/models"
+)
+
+type EmergencyAccessHandlersFuncs interface {
+	GetTrustedContacts(w http.ResponseWriter, r *http.Request)
+	GetGrantedAccesses(w http.ResponseWriter, r *http.Request)
+	Invite(w http.ResponseWriter, r *http.Request)
+	Accept(w http.ResponseWriter, r *http.Request)
+	Confirm(w http.ResponseWriter, r *http.Request)
+	InitiateRecovery(w http.ResponseWriter, r *http.Request)
+	ApproveRecovery(w http.ResponseWriter, r *http.Request)
+	RejectRecovery(w http.ResponseWriter, r *http.Request)
+	Delete(w http.ResponseWriter, r *http.Request)
+	View(w http.ResponseWriter, r *http.Request)
+	Takeover(w http.ResponseWriter, r *http.Request)
+}
+
+type EmergencyAccessHandlers struct {
+	emergencyAccessServices services.EmergencyAccessServices
+
+	EmergencyAccessHandlersFuncs
+}
+
+func NewEmergencyAccessHandlers(emergencyAccessServices services.EmergencyAccessServices) *EmergencyAccessHandlers {
+	return &EmergencyAccessHandlers{
+		emergencyAccessServices: emergencyAccessServices,
+	}
+}
+
+func (e *EmergencyAccessHandlers) GetTrustedContacts(w http.ResponseWriter, r *http.Request) {
+	if r.Method != "GET" {
+		httpError(w, http.StatusMethodNotAllowed, nil)
+		return
+	}
+
+	claims, ok := r.Context().Value("claims").(jwt.MapClaims)
+	if !ok {
+		data := map[string]string{"message": "An error occurred during token decryption."}
+		httpError(w, http.StatusInternalServerError, data)
+		return
+	}
+
+	userID, ok := claims["sub"].(float64)
+	if !ok {
+		data := map[string]string{"message": "Token does not contain a userID."}
+		httpError(w, http.StatusBadRequest, data)
+		return
+	}
+
+	accesses, merr := e.emergencyAccessServices.GetTrustedContacts(int(userID))
+	if merr != nil {
+		data := map[string]string{"message": merr.Description}
+		httpError(w, merr.Code, data)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+
+	response := models.Response{
+		Status:     http.StatusOK,
+		StatusText: http.StatusText(http.StatusOK),
+		Data:       accesses,
+	}
+
+	json.NewEncoder(w).Encode(response)
+}
+
+func (e *EmergencyAccessHandlers) GetGrantedAccesses(w http.ResponseWriter, r *http.Request) {
+	if r.Method != "GET" {
+		httpError(w, http.StatusMethodNotAllowed, nil)
+		return
+	}
+
+	claims, ok := r.Context().Value("claims").(jwt.MapClaims)
+	if !ok {
+		data := map[string]string{"message": "An error occurred during token decryption."}
+		httpError(w, http.StatusInternalServerError, data)
+		return
+	}
+
+	userID, ok := claims["sub"].(float64)
+	if !ok {
+		data := map[string]string{"message": "Token does not contain a userID."}
+		httpError(w, http.StatusBadRequest, data)
+		return
+	}
+
+	accesses, merr := e.emergencyAccessServices.GetGrantedAccesses(int(userID))
+	if merr != nil {
+		data := map[string]string{"message": merr.Description}
+		httpError(w, merr.Code, data)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+
+	response := models.Response{
+		Status:     http.StatusOK,
+		StatusText: http.StatusText(http.StatusOK),
+		Data:       accesses,
+	}
+
+	json.NewEncoder(w).Encode(response)
+}
+
+func (e *EmergencyAccessHandlers) Invite(w http.ResponseWriter, r *http.Request) {
+	if r.Method != "POST" {
+		httpError(w, http.StatusMethodNotAllowed, nil)
+		return
+	}
+
+	claims, ok := r.Context().Value("claims").(jwt.MapClaims)
+	if !ok {
+		data := map[string]string{"message": "An error occurred during token decryption."}
+		httpError(w, http.StatusInternalServerError, data)
+		return
+	}
+
+	userID, ok := claims["sub"].(float64)
+	if !ok {
+		data := map[string]string{"message": "Token does not contain a userID."}
+		httpError(w, http.StatusBadRequest, data)
+		return
+	}
+
+	var inviteRequest *emergency.InviteRequest
+	err := json.NewDecoder(r.Body).Decode(&inviteRequest)
+	if err != nil {
+		httpError(w, http.StatusBadRequest, nil)
+		return
+	}
+
+	validate := validator.New()
+	err = validate.Struct(inviteRequest)
+	if err != nil {
+		httpError(w, http.StatusBadRequest, nil)
+		return
+	}
+
+	access, merr := e.emergencyAccessServices.Invite(int(userID), inviteRequest)
+	if merr != nil {
+		data := map[string]string{"message": merr.Description}
+		httpError(w, merr.Code, data)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+
+	response := models.Response{
+		Status:     http.StatusCreated,
+		StatusText: http.StatusText(http.StatusCreated),
+		Data:       access,
+	}
+
+	json.NewEncoder(w).Encode(response)
+}
+
+func (e *EmergencyAccessHandlers) Accept(w http.ResponseWriter, r *http.Request) {
+	if r.Method != "POST" {
+		httpError(w, http.StatusMethodNotAllowed, nil)
+		return
+	}
+
+	claims, ok := r.Context().Value("claims").(jwt.MapClaims)
+	if !ok {
+		data := map[string]string{"message": "An error occurred during token decryption."}
+		httpError(w, http.StatusInternalServerError, data)
+		return
+	}
+
+	userID, ok := claims["sub"].(float64)
+	if !ok {
+		data := map[string]string{"message": "Token does not contain a userID."}
+		httpError(w, http.StatusBadRequest, data)
+		return
+	}
+
+	id, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil {
+		httpError(w, http.StatusBadRequest, nil)
+		return
+	}
+
+	var acceptRequest *emergency.AcceptRequest
+	err = json.NewDecoder(r.Body).Decode(&acceptRequest)
+	if err != nil {
+		httpError(w, http.StatusBadRequest, nil)
+		return
+	}
+
+	validate := validator.New()
+	err = validate.Struct(acceptRequest)
+	if err != nil {
+		httpError(w, http.StatusBadRequest, nil)
+		return
+	}
+
+	access, merr := e.emergencyAccessServices.Accept(id, int(userID), acceptRequest)
+	if merr != nil {
+		data := map[string]string{"message": merr.Description}
+		httpError(w, merr.Code, data)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+
+	response := models.Response{
+		Status:     http.StatusOK,
+		StatusText: http.StatusText(http.StatusOK),
+		Data:       access,
+	}
+
+	json.NewEncoder(w).Encode(response)
+}
+
+func (e *EmergencyAccessHandlers) Confirm(w http.ResponseWriter, r *http.Request) {
+	if r.Method != "POST" {
+		httpError(w, http.StatusMethodNotAllowed, nil)
+		return
+	}
+
+	claims, ok := r.Context().Value("claims").(jwt.MapClaims)
+	if !ok {
+		data := map[string]string{"message": "An error occurred during token decryption."}
+		httpError(w, http.StatusInternalServerError, data)
+		return
+	}
+
+	userID, ok := claims["sub"].(float64)
+	if !ok {
+		data := map[string]string{"message": "Token does not contain a userID."}
+		httpError(w, http.StatusBadRequest, data)
+		return
+	}
+
+	id, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil {
+		httpError(w, http.StatusBadRequest, nil)
+		return
+	}
+
+	var confirmRequest *emergency.ConfirmRequest
+	err = json.NewDecoder(r.Body).Decode(&confirmRequest)
+	if err != nil {
+		httpError(w, http.StatusBadRequest, nil)
+		return
+	}
+
+	validate := validator.New()
+	err = validate.Struct(confirmRequest)
+	if err != nil {
+		httpError(w, http.StatusBadRequest, nil)
+		return
+	}
+
+	access, merr := e.emergencyAccessServices.Confirm(id, int(userID), confirmRequest)
+	if merr != nil {
+		data := map[string]string{"message": merr.Description}
+		httpError(w, merr.Code, data)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+
+	response := models.Response{
+		Status:     http.StatusOK,
+		StatusText: http.StatusText(http.StatusOK),
+		Data:       access,
+	}
+
+	json.NewEncoder(w).Encode(response)
+}
+
+func (e *EmergencyAccessHandlers) InitiateRecovery(w http.ResponseWriter, r *http.Request) {
+	if r.Method != "POST" {
+		httpError(w, http.StatusMethodNotAllowed, nil)
+		return
+	}
+
+	claims, ok := r.Context().Value("claims").(jwt.MapClaims)
+	if !ok {
+		data := map[string]string{"message": "An error occurred during token decryption."}
+		httpError(w, http.StatusInternalServerError, data)
+		return
+	}
+
+	userID, ok := claims["sub"].(float64)
+	if !ok {
+		data := map[string]string{"message": "Token does not contain a userID."}
+		httpError(w, http.StatusBadRequest, data)
+		return
+	}
+
+	id, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil {
+		httpError(w, http.StatusBadRequest, nil)
+		return
+	}
+
+	access, merr := e.emergencyAccessServices.InitiateRecovery(id, int(userID))
+	if merr != nil {
+		data := map[string]string{"message": merr.Description}
+		httpError(w, merr.Code, data)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+
+	response := models.Response{
+		Status:     http.StatusOK,
+		StatusText: http.StatusText(http.StatusOK),
+		Data:       access,
+	}
+
+	json.NewEncoder(w).Encode(response)
+}
+
+func (e *EmergencyAccessHandlers) ApproveRecovery(w http.ResponseWriter, r *http.Request) {
+	if r.Method != "POST" {
+		httpError(w, http.StatusMethodNotAllowed, nil)
+		return
+	}
+
+	claims, ok := r.Context().Value("claims").(jwt.MapClaims)
+	if !ok {
+		data := map[string]string{"message": "An error occurred during token decryption."}
+		httpError(w, http.StatusInternalServerError, data)
+		return
+	}
+
+	userID, ok := claims["sub"].(float64)
+	if !ok {
+		data := map[string]string{"message": "Token does not contain a userID."}
+		httpError(w, http.StatusBadRequest, data)
+		return
+	}
+
+	id, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil {
+		httpError(w, http.StatusBadRequest, nil)
+		return
+	}
+
+	access, merr := e.emergencyAccessServices.ApproveRecovery(id, int(userID))
+	if merr != nil {
+		data := map[string]string{"message": merr.Description}
+		httpError(w, merr.Code, data)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+
+	response := models.Response{
+		Status:     http.StatusOK,
+		StatusText: http.StatusText(http.StatusOK),
+		Data:       access,
+	}
+
+	json.NewEncoder(w).Encode(response)
+}
+
+func (e *EmergencyAccessHandlers) RejectRecovery(w http.ResponseWriter, r *http.Request) {
+	if r.Method != "POST" {
+		httpError(w, http.StatusMethodNotAllowed, nil)
+		return
+	}
+
+	claims, ok := r.Context().Value("claims").(jwt.MapClaims)
+	if !ok {
+		data := map[string]string{"message": "An error occurred during token decryption."}
+		httpError(w, http.StatusInternalServerError, data)
+		return
+	}
+
+	userID, ok := claims["sub"].(float64)
+	if !ok {
+		data := map[string]string{"message": "Token does not contain a userID."}
+		httpError(w, http.StatusBadRequest, data)
+		return
+	}
+
+	id, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil {
+		httpError(w, http.StatusBadRequest, nil)
+		return
+	}
+
+	access, merr := e.emergencyAccessServices.RejectRecovery(id, int(userID))
+	if merr != nil {
+		data := map[string]string{"message": merr.Description}
+		httpError(w, merr.Code, data)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+
+	response := models.Response{
+		Status:     http.StatusOK,
+		StatusText: http.StatusText(http.StatusOK),
+		Data:       access,
+	}
+
+	json.NewEncoder(w).Encode(response)
+}
+
+func (e *EmergencyAccessHandlers) Delete(w http.ResponseWriter, r *http.Request) {
+	if r.Method != "DELETE" {
+		httpError(w, http.StatusMethodNotAllowed, nil)
+		return
+	}
+
+	claims, ok := r.Context().Value("claims").(jwt.MapClaims)
+	if !ok {
+		data := map[string]string{"message": "An error occurred during token decryption."}
+		httpError(w, http.StatusInternalServerError, data)
+		return
+	}
+
+	userID, ok := claims["sub"].(float64)
+	if !ok {
+		data := map[string]string{"message": "Token does not contain a userID."}
+		httpError(w, http.StatusBadRequest, data)
+		return
+	}
+
+	id, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil {
+		httpError(w, http.StatusBadRequest, nil)
+		return
+	}
+
+	merr := e.emergencyAccessServices.Delete(id, int(userID))
+	if merr != nil {
+		data := map[string]string{"message": merr.Description}
+		httpError(w, merr.Code, data)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+
+	response := models.Response{
+		Status:     http.StatusOK,
+		StatusText: http.StatusText(http.StatusOK),
+		Data:       map[string]any{"id": id, "succeeded": true, "operation": "delete"},
+	}
+
+	json.NewEncoder(w).Encode(response)
+}
+
+func (e *EmergencyAccessHandlers) View(w http.ResponseWriter, r *http.Request) {
+	if r.Method != "GET" {
+		httpError(w, http.StatusMethodNotAllowed, nil)
+		return
+	}
+
+	claims, ok := r.Context().Value("claims").(jwt.MapClaims)
+	if !ok {
+		data := map[string]string{"message": "An error occurred during token decryption."}
+		httpError(w, http.StatusInternalServerError, data)
+		return
+	}
+
+	userID, ok := claims["sub"].(float64)
+	if !ok {
+		data := map[string]string{"message": "Token does not contain a userID."}
+		httpError(w, http.StatusBadRequest, data)
+		return
+	}
+
+	id, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil {
+		httpError(w, http.StatusBadRequest, nil)
+		return
+	}
+
+	view, merr := e.emergencyAccessServices.View(id, int(userID))
+	if merr != nil {
+		data := map[string]string{"message": merr.Description}
+		httpError(w, merr.Code, data)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+
+	response := models.Response{
+		Status:     http.StatusOK,
+		StatusText: http.StatusText(http.StatusOK),
+		Data:       view,
+	}
+
+	json.NewEncoder(w).Encode(response)
+}
+
+// Takeover returns the wrapped vault key on GET and sets the grantor's new
+// master password on POST.
+func (e *EmergencyAccessHandlers) Takeover(w http.ResponseWriter, r *http.Request) {
+	if r.Method != "GET" && r.Method != "POST" {
+		httpError(w, http.StatusMethodNotAllowed, nil)
+		return
+	}
+
+	claims, ok := r.Context().Value("claims").(jwt.MapClaims)
+	if !ok {
+		data := map[string]string{"message": "An error occurred during token decryption."}
+		httpError(w, http.StatusInternalServerError, data)
+		return
+	}
+
+	userID, ok := claims["sub"].(float64)
+	if !ok {
+		data := map[string]string{"message": "Token does not contain a userID."}
+		httpError(w, http.StatusBadRequest, data)
+		return
+	}
+
+	id, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil {
+		httpError(w, http.StatusBadRequest, nil)
+		return
+	}
+
+	if r.Method == "GET" {
+		view, merr := e.emergencyAccessServices.GetTakeoverKey(id, int(userID))
+		if merr != nil {
+			data := map[string]string{"message": merr.Description}
+			httpError(w, merr.Code, data)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+
+		response := models.Response{
+			Status:     http.StatusOK,
+			StatusText: http.StatusText(http.StatusOK),
+			Data:       view,
+		}
+
+		json.NewEncoder(w).Encode(response)
+		return
+	}
+
+	var takeoverRequest *emergency.TakeoverRequest
+	err = json.NewDecoder(r.Body).Decode(&takeoverRequest)
+	if err != nil {
+		httpError(w, http.StatusBadRequest, nil)
+		return
+	}
+
+	validate := validator.New()
+	err = validate.Struct(takeoverRequest)
+	if err != nil {
+		httpError(w, http.StatusBadRequest, nil)
+		return
+	}
+
+	merr := e.emergencyAccessServices.Takeover(id, int(userID), takeoverRequest)
+	if merr != nil {
+		data := map[string]string{"message": merr.Description}
+		httpError(w, merr.Code, data)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+
+	response := models.Response{
+		Status:     http.StatusOK,
+		StatusText: http.StatusText(http.StatusOK),
+		Data:       map[string]any{"id": id, "succeeded": true, "operation": "takeover"},
+	}
+
+	json.NewEncoder(w).Encode(response)
+}
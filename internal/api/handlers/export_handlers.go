@@ -0,0 +1,133 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/safepass/server/internal/services"
+	"github.com/safepass/server/pkg/dtos/vault"
+	"github.com/safepass/server/pkg/models"
+)
+
+type ExportHandlersFuncs interface {
+	Export(w http.ResponseWriter, r *http.Request)
+	Import(w http.ResponseWriter, r *http.Request)
+}
+
+type ExportHandlers struct {
+	exportServices services.ExportServices
+
+	ExportHandlersFuncs
+}
+
+func NewExportHandlers(exportServices services.ExportServices) *ExportHandlers {
+	return &ExportHandlers{
+		exportServices: exportServices,
+	}
+}
+
+// Export returns a SafePass export of the vault given by ?vault=, the default
+// vault when it is left out.
+func (e *ExportHandlers) Export(w http.ResponseWriter, r *http.Request) {
+	if r.Method != "GET" {
+		httpError(w, http.StatusMethodNotAllowed, nil)
+		return
+	}
+
+	claims, ok := r.Context().Value("claims").(jwt.MapClaims)
+	if !ok {
+		data := map[string]string{"message": "An error occurred during token decryption."}
+		httpError(w, http.StatusInternalServerError, data)
+		return
+	}
+
+	userID, ok := claims["sub"].(float64)
+	if !ok {
+		data := map[string]string{"message": "Token does not contain a userID."}
+		httpError(w, http.StatusBadRequest, data)
+		return
+	}
+
+	vaultID := r.URL.Query().Get("vault")
+	if vaultID == "" {
+		vaultID = "@me"
+	}
+
+	export, merr := e.exportServices.ExportVault(vaultID, int(userID))
+	if merr != nil {
+		httpError(w, merr.Code, errorData(merr))
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+
+	response := models.Response{
+		Status:     http.StatusOK,
+		StatusText: http.StatusText(http.StatusOK),
+		Data:       export,
+	}
+
+	json.NewEncoder(w).Encode(response)
+}
+
+// Import restores a SafePass export into the vault given by ?vault=, the
+// default vault when it is left out.
+func (e *ExportHandlers) Import(w http.ResponseWriter, r *http.Request) {
+	if r.Method != "POST" {
+		httpError(w, http.StatusMethodNotAllowed, nil)
+		return
+	}
+
+	claims, ok := r.Context().Value("claims").(jwt.MapClaims)
+	if !ok {
+		data := map[string]string{"message": "An error occurred during token decryption."}
+		httpError(w, http.StatusInternalServerError, data)
+		return
+	}
+
+	userID, ok := claims["sub"].(float64)
+	if !ok {
+		data := map[string]string{"message": "Token does not contain a userID."}
+		httpError(w, http.StatusBadRequest, data)
+		return
+	}
+
+	vaultID := r.URL.Query().Get("vault")
+	if vaultID == "" {
+		vaultID = "@me"
+	}
+
+	var importRequest *vault.VaultImportRequest
+	err := json.NewDecoder(r.Body).Decode(&importRequest)
+	if err != nil {
+		httpError(w, http.StatusBadRequest, nil)
+		return
+	}
+
+	validate := validator.New()
+	err = validate.Struct(importRequest)
+	if err != nil {
+		httpError(w, http.StatusBadRequest, nil)
+		return
+	}
+
+	imported, merr := e.exportServices.ImportVault(vaultID, int(userID), importRequest)
+	if merr != nil {
+		httpError(w, merr.Code, errorData(merr))
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+
+	response := models.Response{
+		Status:     http.StatusOK,
+		StatusText: http.StatusText(http.StatusOK),
+		Data:       imported,
+	}
+
+	json.NewEncoder(w).Encode(response)
+}
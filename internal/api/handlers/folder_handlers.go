@@ -0,0 +1,256 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/safepass/server/internal/services"
+	"github.com/safepass/server/pkg/dtos/folder"
+	"github.com/safepass/server/pkg/models"
+)
+
+type FolderHandlersFuncs interface {
+	Folders(w http.ResponseWriter, r *http.Request)
+	Folder(w http.ResponseWriter, r *http.Request)
+	GetFolders(w http.ResponseWriter, r *http.Request)
+	CreateFolder(w http.ResponseWriter, r *http.Request)
+	UpdateFolder(w http.ResponseWriter, r *http.Request)
+	DeleteFolder(w http.ResponseWriter, r *http.Request)
+}
+
+type FolderHandlers struct {
+	folderServices services.FolderServices
+
+	FolderHandlersFuncs
+}
+
+func NewFolderHandlers(folderServices services.FolderServices) *FolderHandlers {
+	return &FolderHandlers{
+		folderServices: folderServices,
+	}
+}
+
+// Folders dispatches /api/v1/folders to the handler for the request
+// method.
+func (f *FolderHandlers) Folders(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+		f.GetFolders(w, r)
+	case http.MethodPost:
+		f.CreateFolder(w, r)
+	default:
+		httpError(w, http.StatusMethodNotAllowed, nil)
+	}
+}
+
+// Folder dispatches /api/v1/folders/{folderId} to the handler for the request
+// method.
+func (f *FolderHandlers) Folder(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodPut:
+		f.UpdateFolder(w, r)
+	case http.MethodDelete:
+		f.DeleteFolder(w, r)
+	default:
+		httpError(w, http.StatusMethodNotAllowed, nil)
+	}
+}
+
+func (f *FolderHandlers) GetFolders(w http.ResponseWriter, r *http.Request) {
+	if r.Method != "GET" {
+		httpError(w, http.StatusMethodNotAllowed, nil)
+		return
+	}
+
+	claims, ok := r.Context().Value("claims").(jwt.MapClaims)
+	if !ok {
+		data := map[string]string{"message": "An error occurred during token decryption."}
+		httpError(w, http.StatusInternalServerError, data)
+		return
+	}
+
+	userID, ok := claims["sub"].(float64)
+	if !ok {
+		data := map[string]string{"message": "Token does not contain a userID."}
+		httpError(w, http.StatusBadRequest, data)
+		return
+	}
+
+	folders, merr := f.folderServices.GetFolders(int(userID))
+	if merr != nil {
+		data := map[string]string{"message": merr.Description}
+		httpError(w, merr.Code, data)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+
+	response := models.Response{
+		Status:     http.StatusOK,
+		StatusText: http.StatusText(http.StatusOK),
+		Data:       folders,
+	}
+
+	json.NewEncoder(w).Encode(response)
+}
+
+func (f *FolderHandlers) CreateFolder(w http.ResponseWriter, r *http.Request) {
+	if r.Method != "POST" {
+		httpError(w, http.StatusMethodNotAllowed, nil)
+		return
+	}
+
+	claims, ok := r.Context().Value("claims").(jwt.MapClaims)
+	if !ok {
+		data := map[string]string{"message": "An error occurred during token decryption."}
+		httpError(w, http.StatusInternalServerError, data)
+		return
+	}
+
+	userID, ok := claims["sub"].(float64)
+	if !ok {
+		data := map[string]string{"message": "Token does not contain a userID."}
+		httpError(w, http.StatusBadRequest, data)
+		return
+	}
+
+	var folderRequest *folder.FolderRequest
+	err := json.NewDecoder(r.Body).Decode(&folderRequest)
+	if err != nil {
+		httpError(w, http.StatusBadRequest, nil)
+		return
+	}
+
+	validate := validator.New()
+	err = validate.Struct(folderRequest)
+	if err != nil {
+		httpError(w, http.StatusBadRequest, nil)
+		return
+	}
+
+	folder, merr := f.folderServices.CreateFolder(int(userID), folderRequest)
+	if merr != nil {
+		data := map[string]string{"message": merr.Description}
+		httpError(w, merr.Code, data)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+
+	response := models.Response{
+		Status:     http.StatusCreated,
+		StatusText: http.StatusText(http.StatusCreated),
+		Data:       folder,
+	}
+
+	json.NewEncoder(w).Encode(response)
+}
+
+func (f *FolderHandlers) UpdateFolder(w http.ResponseWriter, r *http.Request) {
+	if r.Method != "PUT" {
+		httpError(w, http.StatusMethodNotAllowed, nil)
+		return
+	}
+
+	claims, ok := r.Context().Value("claims").(jwt.MapClaims)
+	if !ok {
+		data := map[string]string{"message": "An error occurred during token decryption."}
+		httpError(w, http.StatusInternalServerError, data)
+		return
+	}
+
+	userID, ok := claims["sub"].(float64)
+	if !ok {
+		data := map[string]string{"message": "Token does not contain a userID."}
+		httpError(w, http.StatusBadRequest, data)
+		return
+	}
+
+	id, err := strconv.Atoi(r.PathValue("folderId"))
+	if err != nil {
+		httpError(w, http.StatusBadRequest, nil)
+		return
+	}
+
+	var folderRequest *folder.FolderRequest
+	err = json.NewDecoder(r.Body).Decode(&folderRequest)
+	if err != nil {
+		httpError(w, http.StatusBadRequest, nil)
+		return
+	}
+
+	validate := validator.New()
+	err = validate.Struct(folderRequest)
+	if err != nil {
+		httpError(w, http.StatusBadRequest, nil)
+		return
+	}
+
+	folder, merr := f.folderServices.UpdateFolder(id, int(userID), folderRequest)
+	if merr != nil {
+		data := map[string]string{"message": merr.Description}
+		httpError(w, merr.Code, data)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+
+	response := models.Response{
+		Status:     http.StatusOK,
+		StatusText: http.StatusText(http.StatusOK),
+		Data:       folder,
+	}
+
+	json.NewEncoder(w).Encode(response)
+}
+
+func (f *FolderHandlers) DeleteFolder(w http.ResponseWriter, r *http.Request) {
+	if r.Method != "DELETE" {
+		httpError(w, http.StatusMethodNotAllowed, nil)
+		return
+	}
+
+	claims, ok := r.Context().Value("claims").(jwt.MapClaims)
+	if !ok {
+		data := map[string]string{"message": "An error occurred during token decryption."}
+		httpError(w, http.StatusInternalServerError, data)
+		return
+	}
+
+	userID, ok := claims["sub"].(float64)
+	if !ok {
+		data := map[string]string{"message": "Token does not contain a userID."}
+		httpError(w, http.StatusBadRequest, data)
+		return
+	}
+
+	id, err := strconv.Atoi(r.PathValue("folderId"))
+	if err != nil {
+		httpError(w, http.StatusBadRequest, nil)
+		return
+	}
+
+	merr := f.folderServices.DeleteFolder(id, int(userID))
+	if merr != nil {
+		data := map[string]string{"message": merr.Description}
+		httpError(w, merr.Code, data)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+
+	response := models.Response{
+		Status:     http.StatusOK,
+		StatusText: http.StatusText(http.StatusOK),
+		Data:       map[string]any{"id": id, "succeeded": true, "operation": "delete"},
+	}
+
+	json.NewEncoder(w).Encode(response)
+}
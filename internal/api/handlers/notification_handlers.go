@@ -0,0 +1,252 @@
+package handlers
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/safepass/server/internal/consts"
+	"github.com/safepass/server/internal/services"
+	"github.com/safepass/server/pkg/models"
+	"golang.org/x/net/websocket"
+)
+
+// notificationWriteTimeout bounds how long a write to a notification stream
+// may block before the connection is given up.
+const notificationWriteTimeout = 10 * time.Second
+
+type NotificationHandlersFuncs interface {
+	Stream(w http.ResponseWriter, r *http.Request)
+	WebSocket(w http.ResponseWriter, r *http.Request)
+}
+
+type NotificationHandlers struct {
+	notificationServices services.NotificationServices
+
+	NotificationHandlersFuncs
+}
+
+func NewNotificationHandlers(notificationServices services.NotificationServices) *NotificationHandlers {
+	return &NotificationHandlers{
+		notificationServices: notificationServices,
+	}
+}
+
+// Stream pushes the user's vault events as Server-Sent Events. A reconnecting
+// client sends the id of the last event it saw in the Last-Event-ID header, or
+// in ?last_event_id=, and first gets the events it missed.
+func (n *NotificationHandlers) Stream(w http.ResponseWriter, r *http.Request) {
+	if r.Method != "GET" {
+		httpError(w, http.StatusMethodNotAllowed, nil)
+		return
+	}
+
+	claims, ok := r.Context().Value("claims").(jwt.MapClaims)
+	if !ok {
+		data := map[string]string{"message": "An error occurred during token decryption."}
+		httpError(w, http.StatusInternalServerError, data)
+		return
+	}
+
+	userID, ok := claims["sub"].(float64)
+	if !ok {
+		data := map[string]string{"message": "Token does not contain a userID."}
+		httpError(w, http.StatusBadRequest, data)
+		return
+	}
+
+	lastEventID, err := readLastEventID(r)
+	if err != nil {
+		data := map[string]string{"message": "Last event id must be an event id."}
+		httpError(w, http.StatusBadRequest, data)
+		return
+	}
+
+	controller := http.NewResponseController(w)
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+	w.Header().Set("X-Accel-Buffering", "no")
+	w.WriteHeader(http.StatusOK)
+
+	sub, missed := n.notificationServices.Subscribe(int(userID), lastEventID)
+	defer sub.Close()
+
+	send := func(write func() error) bool {
+		controller.SetWriteDeadline(time.Now().Add(notificationWriteTimeout))
+		if write() != nil {
+			return false
+		}
+
+		return controller.Flush() == nil
+	}
+
+	if !send(func() error {
+		_, err := fmt.Fprint(w, "retry: 5000\n\n")
+		return err
+	}) {
+		return
+	}
+
+	for _, event := range missed {
+		if !send(func() error { return writeServerSentEvent(w, event) }) {
+			return
+		}
+	}
+
+	heartbeat := time.NewTicker(n.notificationServices.HeartbeatInterval())
+	defer heartbeat.Stop()
+
+	for {
+		select {
+		case <-r.Context().Done():
+			return
+		case <-heartbeat.C:
+			if !send(func() error {
+				_, err := fmt.Fprint(w, ": heartbeat\n\n")
+				return err
+			}) {
+				return
+			}
+		case event, ok := <-sub.Events:
+			// A closed channel means the client fell behind. It reconnects
+			// with the last id it got and replays from there.
+			if !ok {
+				return
+			}
+
+			if !send(func() error { return writeServerSentEvent(w, event) }) {
+				return
+			}
+		}
+	}
+}
+
+// WebSocket pushes the user's vault events as JSON messages over a WebSocket.
+// Heartbeats are messages of type heartbeat without an id. A reconnecting
+// client passes the id of the last event it saw in ?last_event_id=.
+func (n *NotificationHandlers) WebSocket(w http.ResponseWriter, r *http.Request) {
+	if r.Method != "GET" {
+		httpError(w, http.StatusMethodNotAllowed, nil)
+		return
+	}
+
+	claims, ok := r.Context().Value("claims").(jwt.MapClaims)
+	if !ok {
+		data := map[string]string{"message": "An error occurred during token decryption."}
+		httpError(w, http.StatusInternalServerError, data)
+		return
+	}
+
+	userID, ok := claims["sub"].(float64)
+	if !ok {
+		data := map[string]string{"message": "Token does not contain a userID."}
+		httpError(w, http.StatusBadRequest, data)
+		return
+	}
+
+	lastEventID, err := readLastEventID(r)
+	if err != nil {
+		data := map[string]string{"message": "Last event id must be an event id."}
+		httpError(w, http.StatusBadRequest, data)
+		return
+	}
+
+	server := websocket.Server{
+		// Clients authenticate with a bearer token rather than cookies, so
+		// the origin of the connection does not matter.
+		Handshake: func(*websocket.Config, *http.Request) error { return nil },
+		Handler: func(ws *websocket.Conn) {
+			n.serveWebSocket(ws, int(userID), lastEventID)
+		},
+	}
+
+	server.ServeHTTP(w, r)
+}
+
+func (n *NotificationHandlers) serveWebSocket(ws *websocket.Conn, userID int, lastEventID int64) {
+	defer ws.Close()
+
+	sub, missed := n.notificationServices.Subscribe(userID, lastEventID)
+	defer sub.Close()
+
+	// Clients do not send anything; reading only notices when they go away.
+	closed := make(chan struct{})
+	go func() {
+		defer close(closed)
+
+		var message string
+		for websocket.Message.Receive(ws, &message) == nil {
+		}
+	}()
+
+	send := func(event *models.VaultEvent) bool {
+		ws.SetWriteDeadline(time.Now().Add(notificationWriteTimeout))
+		return websocket.JSON.Send(ws, event) == nil
+	}
+
+	for _, event := range missed {
+		if !send(event) {
+			return
+		}
+	}
+
+	heartbeat := time.NewTicker(n.notificationServices.HeartbeatInterval())
+	defer heartbeat.Stop()
+
+	for {
+		select {
+		case <-closed:
+			return
+		case <-heartbeat.C:
+			if !send(&models.VaultEvent{
+				Type:      consts.VaultEventTypes.HEARTBEAT,
+				CreatedAt: time.Now().UTC().Format(time.RFC3339),
+			}) {
+				return
+			}
+		case event, ok := <-sub.Events:
+			if !ok {
+				return
+			}
+
+			if !send(event) {
+				return
+			}
+		}
+	}
+}
+
+// readLastEventID reads the id of the last event a reconnecting client saw,
+// zero for a new client.
+func readLastEventID(r *http.Request) (int64, error) {
+	value := r.Header.Get("Last-Event-ID")
+	if value == "" {
+		value = r.URL.Query().Get("last_event_id")
+	}
+
+	if value == "" {
+		return 0, nil
+	}
+
+	id, err := strconv.ParseInt(value, 10, 64)
+	if err != nil || id < 0 {
+		return 0, fmt.Errorf("invalid last event id %q", value)
+	}
+
+	return id, nil
+}
+
+func writeServerSentEvent(w http.ResponseWriter, event *models.VaultEvent) error {
+	data, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+
+	_, err = fmt.Fprintf(w, "id: %d\nevent: %s\ndata: %s\n\n", event.ID, event.Type, data)
+	return err
+}
@@ -0,0 +1,1273 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/safepass/server/internal/services"
+	"github.com/safepass/server/pkg/dtos/organization"
+	"github.com/safepass/server/pkg/dtos/password"
+	"github.com/safepass/server/pkg/models"
+)
+
+type OrganizationHandlersFuncs interface {
+	Organizations(w http.ResponseWriter, r *http.Request)
+	Organization(w http.ResponseWriter, r *http.Request)
+	Member(w http.ResponseWriter, r *http.Request)
+	Collections(w http.ResponseWriter, r *http.Request)
+	Collection(w http.ResponseWriter, r *http.Request)
+	CollectionItems(w http.ResponseWriter, r *http.Request)
+	CollectionItem(w http.ResponseWriter, r *http.Request)
+	GetOrganizations(w http.ResponseWriter, r *http.Request)
+	CreateOrganization(w http.ResponseWriter, r *http.Request)
+	UpdateOrganization(w http.ResponseWriter, r *http.Request)
+	DeleteOrganization(w http.ResponseWriter, r *http.Request)
+	GetMembers(w http.ResponseWriter, r *http.Request)
+	InviteMember(w http.ResponseWriter, r *http.Request)
+	AcceptInvite(w http.ResponseWriter, r *http.Request)
+	ConfirmMember(w http.ResponseWriter, r *http.Request)
+	UpdateMember(w http.ResponseWriter, r *http.Request)
+	RemoveMember(w http.ResponseWriter, r *http.Request)
+	GetCollections(w http.ResponseWriter, r *http.Request)
+	CreateCollection(w http.ResponseWriter, r *http.Request)
+	UpdateCollection(w http.ResponseWriter, r *http.Request)
+	DeleteCollection(w http.ResponseWriter, r *http.Request)
+	GetItems(w http.ResponseWriter, r *http.Request)
+	GetCollectionItems(w http.ResponseWriter, r *http.Request)
+	CreateItem(w http.ResponseWriter, r *http.Request)
+	UpdateItem(w http.ResponseWriter, r *http.Request)
+	DeleteItem(w http.ResponseWriter, r *http.Request)
+	GetPolicies(w http.ResponseWriter, r *http.Request)
+	SetPolicy(w http.ResponseWriter, r *http.Request)
+}
+
+type OrganizationHandlers struct {
+	organizationServices services.OrganizationServices
+
+	OrganizationHandlersFuncs
+}
+
+func NewOrganizationHandlers(organizationServices services.OrganizationServices) *OrganizationHandlers {
+	return &OrganizationHandlers{
+		organizationServices: organizationServices,
+	}
+}
+
+func (o *OrganizationHandlers) Organizations(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+		o.GetOrganizations(w, r)
+	case http.MethodPost:
+		o.CreateOrganization(w, r)
+	default:
+		httpError(w, http.StatusMethodNotAllowed, nil)
+	}
+}
+
+func (o *OrganizationHandlers) Organization(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodPatch:
+		o.UpdateOrganization(w, r)
+	case http.MethodDelete:
+		o.DeleteOrganization(w, r)
+	default:
+		httpError(w, http.StatusMethodNotAllowed, nil)
+	}
+}
+
+func (o *OrganizationHandlers) Member(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodPatch:
+		o.UpdateMember(w, r)
+	case http.MethodDelete:
+		o.RemoveMember(w, r)
+	default:
+		httpError(w, http.StatusMethodNotAllowed, nil)
+	}
+}
+
+func (o *OrganizationHandlers) Collections(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+		o.GetCollections(w, r)
+	case http.MethodPost:
+		o.CreateCollection(w, r)
+	default:
+		httpError(w, http.StatusMethodNotAllowed, nil)
+	}
+}
+
+func (o *OrganizationHandlers) Collection(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodPut:
+		o.UpdateCollection(w, r)
+	case http.MethodDelete:
+		o.DeleteCollection(w, r)
+	default:
+		httpError(w, http.StatusMethodNotAllowed, nil)
+	}
+}
+
+func (o *OrganizationHandlers) CollectionItems(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+		o.GetCollectionItems(w, r)
+	case http.MethodPost:
+		o.CreateItem(w, r)
+	default:
+		httpError(w, http.StatusMethodNotAllowed, nil)
+	}
+}
+
+func (o *OrganizationHandlers) CollectionItem(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodPut:
+		o.UpdateItem(w, r)
+	case http.MethodDelete:
+		o.DeleteItem(w, r)
+	default:
+		httpError(w, http.StatusMethodNotAllowed, nil)
+	}
+}
+
+func (o *OrganizationHandlers) GetOrganizations(w http.ResponseWriter, r *http.Request) {
+	if r.Method != "GET" {
+		httpError(w, http.StatusMethodNotAllowed, nil)
+		return
+	}
+
+	claims, ok := r.Context().Value("claims").(jwt.MapClaims)
+	if !ok {
+		data := map[string]string{"message": "An error occurred during token decryption."}
+		httpError(w, http.StatusInternalServerError, data)
+		return
+	}
+
+	userID, ok := claims["sub"].(float64)
+	if !ok {
+		data := map[string]string{"message": "Token does not contain a userID."}
+		httpError(w, http.StatusBadRequest, data)
+		return
+	}
+
+	organizations, merr := o.organizationServices.GetOrganizations(int(userID))
+	if merr != nil {
+		data := map[string]string{"message": merr.Description}
+		httpError(w, merr.Code, data)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+
+	response := models.Response{
+		Status:     http.StatusOK,
+		StatusText: http.StatusText(http.StatusOK),
+		Data:       organizations,
+	}
+
+	json.NewEncoder(w).Encode(response)
+}
+
+func (o *OrganizationHandlers) CreateOrganization(w http.ResponseWriter, r *http.Request) {
+	if r.Method != "POST" {
+		httpError(w, http.StatusMethodNotAllowed, nil)
+		return
+	}
+
+	claims, ok := r.Context().Value("claims").(jwt.MapClaims)
+	if !ok {
+		data := map[string]string{"message": "An error occurred during token decryption."}
+		httpError(w, http.StatusInternalServerError, data)
+		return
+	}
+
+	userID, ok := claims["sub"].(float64)
+	if !ok {
+		data := map[string]string{"message": "Token does not contain a userID."}
+		httpError(w, http.StatusBadRequest, data)
+		return
+	}
+
+	var createOrganizationRequest *organization.CreateOrganizationRequest
+	err := json.NewDecoder(r.Body).Decode(&createOrganizationRequest)
+	if err != nil {
+		httpError(w, http.StatusBadRequest, nil)
+		return
+	}
+
+	validate := validator.New()
+	err = validate.Struct(createOrganizationRequest)
+	if err != nil {
+		httpError(w, http.StatusBadRequest, nil)
+		return
+	}
+
+	member, merr := o.organizationServices.CreateOrganization(int(userID), createOrganizationRequest)
+	if merr != nil {
+		data := map[string]string{"message": merr.Description}
+		httpError(w, merr.Code, data)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+
+	response := models.Response{
+		Status:     http.StatusCreated,
+		StatusText: http.StatusText(http.StatusCreated),
+		Data:       member,
+	}
+
+	json.NewEncoder(w).Encode(response)
+}
+
+func (o *OrganizationHandlers) UpdateOrganization(w http.ResponseWriter, r *http.Request) {
+	if r.Method != "PATCH" {
+		httpError(w, http.StatusMethodNotAllowed, nil)
+		return
+	}
+
+	claims, ok := r.Context().Value("claims").(jwt.MapClaims)
+	if !ok {
+		data := map[string]string{"message": "An error occurred during token decryption."}
+		httpError(w, http.StatusInternalServerError, data)
+		return
+	}
+
+	userID, ok := claims["sub"].(float64)
+	if !ok {
+		data := map[string]string{"message": "Token does not contain a userID."}
+		httpError(w, http.StatusBadRequest, data)
+		return
+	}
+
+	organizationID, err := strconv.Atoi(r.PathValue("orgId"))
+	if err != nil {
+		httpError(w, http.StatusBadRequest, nil)
+		return
+	}
+
+	var updateOrganizationRequest *organization.UpdateOrganizationRequest
+	err = json.NewDecoder(r.Body).Decode(&updateOrganizationRequest)
+	if err != nil {
+		httpError(w, http.StatusBadRequest, nil)
+		return
+	}
+
+	validate := validator.New()
+	err = validate.Struct(updateOrganizationRequest)
+	if err != nil {
+		httpError(w, http.StatusBadRequest, nil)
+		return
+	}
+
+	org, merr := o.organizationServices.UpdateOrganization(organizationID, int(userID), updateOrganizationRequest)
+	if merr != nil {
+		data := map[string]string{"message": merr.Description}
+		httpError(w, merr.Code, data)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+
+	response := models.Response{
+		Status:     http.StatusOK,
+		StatusText: http.StatusText(http.StatusOK),
+		Data:       org,
+	}
+
+	json.NewEncoder(w).Encode(response)
+}
+
+func (o *OrganizationHandlers) DeleteOrganization(w http.ResponseWriter, r *http.Request) {
+	if r.Method != "DELETE" {
+		httpError(w, http.StatusMethodNotAllowed, nil)
+		return
+	}
+
+	claims, ok := r.Context().Value("claims").(jwt.MapClaims)
+	if !ok {
+		data := map[string]string{"message": "An error occurred during token decryption."}
+		httpError(w, http.StatusInternalServerError, data)
+		return
+	}
+
+	userID, ok := claims["sub"].(float64)
+	if !ok {
+		data := map[string]string{"message": "Token does not contain a userID."}
+		httpError(w, http.StatusBadRequest, data)
+		return
+	}
+
+	organizationID, err := strconv.Atoi(r.PathValue("orgId"))
+	if err != nil {
+		httpError(w, http.StatusBadRequest, nil)
+		return
+	}
+
+	merr := o.organizationServices.DeleteOrganization(organizationID, int(userID))
+	if merr != nil {
+		data := map[string]string{"message": merr.Description}
+		httpError(w, merr.Code, data)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+
+	response := models.Response{
+		Status:     http.StatusOK,
+		StatusText: http.StatusText(http.StatusOK),
+		Data:       map[string]any{"id": organizationID, "succeeded": true, "operation": "delete"},
+	}
+
+	json.NewEncoder(w).Encode(response)
+}
+
+func (o *OrganizationHandlers) GetMembers(w http.ResponseWriter, r *http.Request) {
+	if r.Method != "GET" {
+		httpError(w, http.StatusMethodNotAllowed, nil)
+		return
+	}
+
+	claims, ok := r.Context().Value("claims").(jwt.MapClaims)
+	if !ok {
+		data := map[string]string{"message": "An error occurred during token decryption."}
+		httpError(w, http.StatusInternalServerError, data)
+		return
+	}
+
+	userID, ok := claims["sub"].(float64)
+	if !ok {
+		data := map[string]string{"message": "Token does not contain a userID."}
+		httpError(w, http.StatusBadRequest, data)
+		return
+	}
+
+	organizationID, err := strconv.Atoi(r.PathValue("orgId"))
+	if err != nil {
+		httpError(w, http.StatusBadRequest, nil)
+		return
+	}
+
+	members, merr := o.organizationServices.GetMembers(organizationID, int(userID))
+	if merr != nil {
+		data := map[string]string{"message": merr.Description}
+		httpError(w, merr.Code, data)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+
+	response := models.Response{
+		Status:     http.StatusOK,
+		StatusText: http.StatusText(http.StatusOK),
+		Data:       members,
+	}
+
+	json.NewEncoder(w).Encode(response)
+}
+
+func (o *OrganizationHandlers) InviteMember(w http.ResponseWriter, r *http.Request) {
+	if r.Method != "POST" {
+		httpError(w, http.StatusMethodNotAllowed, nil)
+		return
+	}
+
+	claims, ok := r.Context().Value("claims").(jwt.MapClaims)
+	if !ok {
+		data := map[string]string{"message": "An error occurred during token decryption."}
+		httpError(w, http.StatusInternalServerError, data)
+		return
+	}
+
+	userID, ok := claims["sub"].(float64)
+	if !ok {
+		data := map[string]string{"message": "Token does not contain a userID."}
+		httpError(w, http.StatusBadRequest, data)
+		return
+	}
+
+	organizationID, err := strconv.Atoi(r.PathValue("orgId"))
+	if err != nil {
+		httpError(w, http.StatusBadRequest, nil)
+		return
+	}
+
+	var inviteMemberRequest *organization.InviteMemberRequest
+	err = json.NewDecoder(r.Body).Decode(&inviteMemberRequest)
+	if err != nil {
+		httpError(w, http.StatusBadRequest, nil)
+		return
+	}
+
+	validate := validator.New()
+	err = validate.Struct(inviteMemberRequest)
+	if err != nil {
+		httpError(w, http.StatusBadRequest, nil)
+		return
+	}
+
+	member, merr := o.organizationServices.InviteMember(organizationID, int(userID), inviteMemberRequest)
+	if merr != nil {
+		data := map[string]string{"message": merr.Description}
+		httpError(w, merr.Code, data)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+
+	response := models.Response{
+		Status:     http.StatusCreated,
+		StatusText: http.StatusText(http.StatusCreated),
+		Data:       member,
+	}
+
+	json.NewEncoder(w).Encode(response)
+}
+
+func (o *OrganizationHandlers) AcceptInvite(w http.ResponseWriter, r *http.Request) {
+	if r.Method != "POST" {
+		httpError(w, http.StatusMethodNotAllowed, nil)
+		return
+	}
+
+	claims, ok := r.Context().Value("claims").(jwt.MapClaims)
+	if !ok {
+		data := map[string]string{"message": "An error occurred during token decryption."}
+		httpError(w, http.StatusInternalServerError, data)
+		return
+	}
+
+	userID, ok := claims["sub"].(float64)
+	if !ok {
+		data := map[string]string{"message": "Token does not contain a userID."}
+		httpError(w, http.StatusBadRequest, data)
+		return
+	}
+
+	organizationID, err := strconv.Atoi(r.PathValue("orgId"))
+	if err != nil {
+		httpError(w, http.StatusBadRequest, nil)
+		return
+	}
+
+	member, merr := o.organizationServices.AcceptInvite(organizationID, int(userID))
+	if merr != nil {
+		data := map[string]string{"message": merr.Description}
+		httpError(w, merr.Code, data)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+
+	response := models.Response{
+		Status:     http.StatusOK,
+		StatusText: http.StatusText(http.StatusOK),
+		Data:       member,
+	}
+
+	json.NewEncoder(w).Encode(response)
+}
+
+func (o *OrganizationHandlers) ConfirmMember(w http.ResponseWriter, r *http.Request) {
+	if r.Method != "POST" {
+		httpError(w, http.StatusMethodNotAllowed, nil)
+		return
+	}
+
+	claims, ok := r.Context().Value("claims").(jwt.MapClaims)
+	if !ok {
+		data := map[string]string{"message": "An error occurred during token decryption."}
+		httpError(w, http.StatusInternalServerError, data)
+		return
+	}
+
+	userID, ok := claims["sub"].(float64)
+	if !ok {
+		data := map[string]string{"message": "Token does not contain a userID."}
+		httpError(w, http.StatusBadRequest, data)
+		return
+	}
+
+	organizationID, err := strconv.Atoi(r.PathValue("orgId"))
+	if err != nil {
+		httpError(w, http.StatusBadRequest, nil)
+		return
+	}
+
+	memberID, err := strconv.Atoi(r.PathValue("memberId"))
+	if err != nil {
+		httpError(w, http.StatusBadRequest, nil)
+		return
+	}
+
+	var confirmMemberRequest *organization.ConfirmMemberRequest
+	err = json.NewDecoder(r.Body).Decode(&confirmMemberRequest)
+	if err != nil {
+		httpError(w, http.StatusBadRequest, nil)
+		return
+	}
+
+	validate := validator.New()
+	err = validate.Struct(confirmMemberRequest)
+	if err != nil {
+		httpError(w, http.StatusBadRequest, nil)
+		return
+	}
+
+	member, merr := o.organizationServices.ConfirmMember(organizationID, memberID, int(userID), confirmMemberRequest)
+	if merr != nil {
+		data := map[string]string{"message": merr.Description}
+		httpError(w, merr.Code, data)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+
+	response := models.Response{
+		Status:     http.StatusOK,
+		StatusText: http.StatusText(http.StatusOK),
+		Data:       member,
+	}
+
+	json.NewEncoder(w).Encode(response)
+}
+
+func (o *OrganizationHandlers) UpdateMember(w http.ResponseWriter, r *http.Request) {
+	if r.Method != "PATCH" {
+		httpError(w, http.StatusMethodNotAllowed, nil)
+		return
+	}
+
+	claims, ok := r.Context().Value("claims").(jwt.MapClaims)
+	if !ok {
+		data := map[string]string{"message": "An error occurred during token decryption."}
+		httpError(w, http.StatusInternalServerError, data)
+		return
+	}
+
+	userID, ok := claims["sub"].(float64)
+	if !ok {
+		data := map[string]string{"message": "Token does not contain a userID."}
+		httpError(w, http.StatusBadRequest, data)
+		return
+	}
+
+	organizationID, err := strconv.Atoi(r.PathValue("orgId"))
+	if err != nil {
+		httpError(w, http.StatusBadRequest, nil)
+		return
+	}
+
+	memberID, err := strconv.Atoi(r.PathValue("memberId"))
+	if err != nil {
+		httpError(w, http.StatusBadRequest, nil)
+		return
+	}
+
+	var updateMemberRequest *organization.UpdateMemberRequest
+	err = json.NewDecoder(r.Body).Decode(&updateMemberRequest)
+	if err != nil {
+		httpError(w, http.StatusBadRequest, nil)
+		return
+	}
+
+	validate := validator.New()
+	err = validate.Struct(updateMemberRequest)
+	if err != nil {
+		httpError(w, http.StatusBadRequest, nil)
+		return
+	}
+
+	member, merr := o.organizationServices.UpdateMember(organizationID, memberID, int(userID), updateMemberRequest)
+	if merr != nil {
+		data := map[string]string{"message": merr.Description}
+		httpError(w, merr.Code, data)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+
+	response := models.Response{
+		Status:     http.StatusOK,
+		StatusText: http.StatusText(http.StatusOK),
+		Data:       member,
+	}
+
+	json.NewEncoder(w).Encode(response)
+}
+
+func (o *OrganizationHandlers) RemoveMember(w http.ResponseWriter, r *http.Request) {
+	if r.Method != "DELETE" {
+		httpError(w, http.StatusMethodNotAllowed, nil)
+		return
+	}
+
+	claims, ok := r.Context().Value("claims").(jwt.MapClaims)
+	if !ok {
+		data := map[string]string{"message": "An error occurred during token decryption."}
+		httpError(w, http.StatusInternalServerError, data)
+		return
+	}
+
+	userID, ok := claims["sub"].(float64)
+	if !ok {
+		data := map[string]string{"message": "Token does not contain a userID."}
+		httpError(w, http.StatusBadRequest, data)
+		return
+	}
+
+	organizationID, err := strconv.Atoi(r.PathValue("orgId"))
+	if err != nil {
+		httpError(w, http.StatusBadRequest, nil)
+		return
+	}
+
+	memberID, err := strconv.Atoi(r.PathValue("memberId"))
+	if err != nil {
+		httpError(w, http.StatusBadRequest, nil)
+		return
+	}
+
+	merr := o.organizationServices.RemoveMember(organizationID, memberID, int(userID))
+	if merr != nil {
+		data := map[string]string{"message": merr.Description}
+		httpError(w, merr.Code, data)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+
+	response := models.Response{
+		Status:     http.StatusOK,
+		StatusText: http.StatusText(http.StatusOK),
+		Data:       map[string]any{"id": memberID, "succeeded": true, "operation": "delete"},
+	}
+
+	json.NewEncoder(w).Encode(response)
+}
+
+func (o *OrganizationHandlers) GetCollections(w http.ResponseWriter, r *http.Request) {
+	if r.Method != "GET" {
+		httpError(w, http.StatusMethodNotAllowed, nil)
+		return
+	}
+
+	claims, ok := r.Context().Value("claims").(jwt.MapClaims)
+	if !ok {
+		data := map[string]string{"message": "An error occurred during token decryption."}
+		httpError(w, http.StatusInternalServerError, data)
+		return
+	}
+
+	userID, ok := claims["sub"].(float64)
+	if !ok {
+		data := map[string]string{"message": "Token does not contain a userID."}
+		httpError(w, http.StatusBadRequest, data)
+		return
+	}
+
+	organizationID, err := strconv.Atoi(r.PathValue("orgId"))
+	if err != nil {
+		httpError(w, http.StatusBadRequest, nil)
+		return
+	}
+
+	collections, merr := o.organizationServices.GetCollections(organizationID, int(userID))
+	if merr != nil {
+		data := map[string]string{"message": merr.Description}
+		httpError(w, merr.Code, data)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+
+	response := models.Response{
+		Status:     http.StatusOK,
+		StatusText: http.StatusText(http.StatusOK),
+		Data:       collections,
+	}
+
+	json.NewEncoder(w).Encode(response)
+}
+
+func (o *OrganizationHandlers) CreateCollection(w http.ResponseWriter, r *http.Request) {
+	if r.Method != "POST" {
+		httpError(w, http.StatusMethodNotAllowed, nil)
+		return
+	}
+
+	claims, ok := r.Context().Value("claims").(jwt.MapClaims)
+	if !ok {
+		data := map[string]string{"message": "An error occurred during token decryption."}
+		httpError(w, http.StatusInternalServerError, data)
+		return
+	}
+
+	userID, ok := claims["sub"].(float64)
+	if !ok {
+		data := map[string]string{"message": "Token does not contain a userID."}
+		httpError(w, http.StatusBadRequest, data)
+		return
+	}
+
+	organizationID, err := strconv.Atoi(r.PathValue("orgId"))
+	if err != nil {
+		httpError(w, http.StatusBadRequest, nil)
+		return
+	}
+
+	var collectionRequest *organization.CollectionRequest
+	err = json.NewDecoder(r.Body).Decode(&collectionRequest)
+	if err != nil {
+		httpError(w, http.StatusBadRequest, nil)
+		return
+	}
+
+	validate := validator.New()
+	err = validate.Struct(collectionRequest)
+	if err != nil {
+		httpError(w, http.StatusBadRequest, nil)
+		return
+	}
+
+	collection, merr := o.organizationServices.CreateCollection(organizationID, int(userID), collectionRequest)
+	if merr != nil {
+		data := map[string]string{"message": merr.Description}
+		httpError(w, merr.Code, data)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+
+	response := models.Response{
+		Status:     http.StatusCreated,
+		StatusText: http.StatusText(http.StatusCreated),
+		Data:       collection,
+	}
+
+	json.NewEncoder(w).Encode(response)
+}
+
+func (o *OrganizationHandlers) UpdateCollection(w http.ResponseWriter, r *http.Request) {
+	if r.Method != "PUT" {
+		httpError(w, http.StatusMethodNotAllowed, nil)
+		return
+	}
+
+	claims, ok := r.Context().Value("claims").(jwt.MapClaims)
+	if !ok {
+		data := map[string]string{"message": "An error occurred during token decryption."}
+		httpError(w, http.StatusInternalServerError, data)
+		return
+	}
+
+	userID, ok := claims["sub"].(float64)
+	if !ok {
+		data := map[string]string{"message": "Token does not contain a userID."}
+		httpError(w, http.StatusBadRequest, data)
+		return
+	}
+
+	organizationID, err := strconv.Atoi(r.PathValue("orgId"))
+	if err != nil {
+		httpError(w, http.StatusBadRequest, nil)
+		return
+	}
+
+	collectionID, err := strconv.Atoi(r.PathValue("collectionId"))
+	if err != nil {
+		httpError(w, http.StatusBadRequest, nil)
+		return
+	}
+
+	var collectionRequest *organization.CollectionRequest
+	err = json.NewDecoder(r.Body).Decode(&collectionRequest)
+	if err != nil {
+		httpError(w, http.StatusBadRequest, nil)
+		return
+	}
+
+	validate := validator.New()
+	err = validate.Struct(collectionRequest)
+	if err != nil {
+		httpError(w, http.StatusBadRequest, nil)
+		return
+	}
+
+	collection, merr := o.organizationServices.UpdateCollection(organizationID, collectionID, int(userID), collectionRequest)
+	if merr != nil {
+		data := map[string]string{"message": merr.Description}
+		httpError(w, merr.Code, data)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+
+	response := models.Response{
+		Status:     http.StatusOK,
+		StatusText: http.StatusText(http.StatusOK),
+		Data:       collection,
+	}
+
+	json.NewEncoder(w).Encode(response)
+}
+
+func (o *OrganizationHandlers) DeleteCollection(w http.ResponseWriter, r *http.Request) {
+	if r.Method != "DELETE" {
+		httpError(w, http.StatusMethodNotAllowed, nil)
+		return
+	}
+
+	claims, ok := r.Context().Value("claims").(jwt.MapClaims)
+	if !ok {
+		data := map[string]string{"message": "An error occurred during token decryption."}
+		httpError(w, http.StatusInternalServerError, data)
+		return
+	}
+
+	userID, ok := claims["sub"].(float64)
+	if !ok {
+		data := map[string]string{"message": "Token does not contain a userID."}
+		httpError(w, http.StatusBadRequest, data)
+		return
+	}
+
+	organizationID, err := strconv.Atoi(r.PathValue("orgId"))
+	if err != nil {
+		httpError(w, http.StatusBadRequest, nil)
+		return
+	}
+
+	collectionID, err := strconv.Atoi(r.PathValue("collectionId"))
+	if err != nil {
+		httpError(w, http.StatusBadRequest, nil)
+		return
+	}
+
+	merr := o.organizationServices.DeleteCollection(organizationID, collectionID, int(userID))
+	if merr != nil {
+		data := map[string]string{"message": merr.Description}
+		httpError(w, merr.Code, data)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+
+	response := models.Response{
+		Status:     http.StatusOK,
+		StatusText: http.StatusText(http.StatusOK),
+		Data:       map[string]any{"id": collectionID, "succeeded": true, "operation": "delete"},
+	}
+
+	json.NewEncoder(w).Encode(response)
+}
+
+func (o *OrganizationHandlers) GetItems(w http.ResponseWriter, r *http.Request) {
+	if r.Method != "GET" {
+		httpError(w, http.StatusMethodNotAllowed, nil)
+		return
+	}
+
+	claims, ok := r.Context().Value("claims").(jwt.MapClaims)
+	if !ok {
+		data := map[string]string{"message": "An error occurred during token decryption."}
+		httpError(w, http.StatusInternalServerError, data)
+		return
+	}
+
+	userID, ok := claims["sub"].(float64)
+	if !ok {
+		data := map[string]string{"message": "Token does not contain a userID."}
+		httpError(w, http.StatusBadRequest, data)
+		return
+	}
+
+	organizationID, err := strconv.Atoi(r.PathValue("orgId"))
+	if err != nil {
+		httpError(w, http.StatusBadRequest, nil)
+		return
+	}
+
+	items, merr := o.organizationServices.GetItems(organizationID, int(userID))
+	if merr != nil {
+		data := map[string]string{"message": merr.Description}
+		httpError(w, merr.Code, data)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+
+	response := models.Response{
+		Status:     http.StatusOK,
+		StatusText: http.StatusText(http.StatusOK),
+		Data:       items,
+	}
+
+	json.NewEncoder(w).Encode(response)
+}
+
+func (o *OrganizationHandlers) GetCollectionItems(w http.ResponseWriter, r *http.Request) {
+	if r.Method != "GET" {
+		httpError(w, http.StatusMethodNotAllowed, nil)
+		return
+	}
+
+	claims, ok := r.Context().Value("claims").(jwt.MapClaims)
+	if !ok {
+		data := map[string]string{"message": "An error occurred during token decryption."}
+		httpError(w, http.StatusInternalServerError, data)
+		return
+	}
+
+	userID, ok := claims["sub"].(float64)
+	if !ok {
+		data := map[string]string{"message": "Token does not contain a userID."}
+		httpError(w, http.StatusBadRequest, data)
+		return
+	}
+
+	organizationID, err := strconv.Atoi(r.PathValue("orgId"))
+	if err != nil {
+		httpError(w, http.StatusBadRequest, nil)
+		return
+	}
+
+	collectionID, err := strconv.Atoi(r.PathValue("collectionId"))
+	if err != nil {
+		httpError(w, http.StatusBadRequest, nil)
+		return
+	}
+
+	items, merr := o.organizationServices.GetCollectionItems(organizationID, collectionID, int(userID))
+	if merr != nil {
+		data := map[string]string{"message": merr.Description}
+		httpError(w, merr.Code, data)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+
+	response := models.Response{
+		Status:     http.StatusOK,
+		StatusText: http.StatusText(http.StatusOK),
+		Data:       items,
+	}
+
+	json.NewEncoder(w).Encode(response)
+}
+
+func (o *OrganizationHandlers) CreateItem(w http.ResponseWriter, r *http.Request) {
+	if r.Method != "POST" {
+		httpError(w, http.StatusMethodNotAllowed, nil)
+		return
+	}
+
+	claims, ok := r.Context().Value("claims").(jwt.MapClaims)
+	if !ok {
+		data := map[string]string{"message": "An error occurred during token decryption."}
+		httpError(w, http.StatusInternalServerError, data)
+		return
+	}
+
+	userID, ok := claims["sub"].(float64)
+	if !ok {
+		data := map[string]string{"message": "Token does not contain a userID."}
+		httpError(w, http.StatusBadRequest, data)
+		return
+	}
+
+	organizationID, err := strconv.Atoi(r.PathValue("orgId"))
+	if err != nil {
+		httpError(w, http.StatusBadRequest, nil)
+		return
+	}
+
+	collectionID, err := strconv.Atoi(r.PathValue("collectionId"))
+	if err != nil {
+		httpError(w, http.StatusBadRequest, nil)
+		return
+	}
+
+	var createPasswordRequest *password.CreatePasswordRequest
+	err = json.NewDecoder(r.Body).Decode(&createPasswordRequest)
+	if err != nil {
+		httpError(w, http.StatusBadRequest, nil)
+		return
+	}
+
+	validate := validator.New()
+	err = validate.Struct(createPasswordRequest)
+	if err != nil {
+		httpError(w, http.StatusBadRequest, nil)
+		return
+	}
+
+	item, merr := o.organizationServices.CreateItem(organizationID, collectionID, int(userID), createPasswordRequest)
+	if merr != nil {
+		data := map[string]string{"message": merr.Description}
+		httpError(w, merr.Code, data)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+
+	response := models.Response{
+		Status:     http.StatusCreated,
+		StatusText: http.StatusText(http.StatusCreated),
+		Data:       item,
+	}
+
+	json.NewEncoder(w).Encode(response)
+}
+
+func (o *OrganizationHandlers) UpdateItem(w http.ResponseWriter, r *http.Request) {
+	if r.Method != "PUT" {
+		httpError(w, http.StatusMethodNotAllowed, nil)
+		return
+	}
+
+	claims, ok := r.Context().Value("claims").(jwt.MapClaims)
+	if !ok {
+		data := map[string]string{"message": "An error occurred during token decryption."}
+		httpError(w, http.StatusInternalServerError, data)
+		return
+	}
+
+	userID, ok := claims["sub"].(float64)
+	if !ok {
+		data := map[string]string{"message": "Token does not contain a userID."}
+		httpError(w, http.StatusBadRequest, data)
+		return
+	}
+
+	organizationID, err := strconv.Atoi(r.PathValue("orgId"))
+	if err != nil {
+		httpError(w, http.StatusBadRequest, nil)
+		return
+	}
+
+	collectionID, err := strconv.Atoi(r.PathValue("collectionId"))
+	if err != nil {
+		httpError(w, http.StatusBadRequest, nil)
+		return
+	}
+
+	itemID, err := strconv.Atoi(r.PathValue("itemId"))
+	if err != nil {
+		httpError(w, http.StatusBadRequest, nil)
+		return
+	}
+
+	var createPasswordRequest *password.CreatePasswordRequest
+	err = json.NewDecoder(r.Body).Decode(&createPasswordRequest)
+	if err != nil {
+		httpError(w, http.StatusBadRequest, nil)
+		return
+	}
+
+	validate := validator.New()
+	err = validate.Struct(createPasswordRequest)
+	if err != nil {
+		httpError(w, http.StatusBadRequest, nil)
+		return
+	}
+
+	item, merr := o.organizationServices.UpdateItem(organizationID, collectionID, itemID, int(userID), createPasswordRequest)
+	if merr != nil {
+		data := map[string]string{"message": merr.Description}
+		httpError(w, merr.Code, data)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+
+	response := models.Response{
+		Status:     http.StatusOK,
+		StatusText: http.StatusText(http.StatusOK),
+		Data:       item,
+	}
+
+	json.NewEncoder(w).Encode(response)
+}
+
+func (o *OrganizationHandlers) DeleteItem(w http.ResponseWriter, r *http.Request) {
+	if r.Method != "DELETE" {
+		httpError(w, http.StatusMethodNotAllowed, nil)
+		return
+	}
+
+	claims, ok := r.Context().Value("claims").(jwt.MapClaims)
+	if !ok {
+		data := map[string]string{"message": "An error occurred during token decryption."}
+		httpError(w, http.StatusInternalServerError, data)
+		return
+	}
+
+	userID, ok := claims["sub"].(float64)
+	if !ok {
+		data := map[string]string{"message": "Token does not contain a userID."}
+		httpError(w, http.StatusBadRequest, data)
+		return
+	}
+
+	organizationID, err := strconv.Atoi(r.PathValue("orgId"))
+	if err != nil {
+		httpError(w, http.StatusBadRequest, nil)
+		return
+	}
+
+	collectionID, err := strconv.Atoi(r.PathValue("collectionId"))
+	if err != nil {
+		httpError(w, http.StatusBadRequest, nil)
+		return
+	}
+
+	itemID, err := strconv.Atoi(r.PathValue("itemId"))
+	if err != nil {
+		httpError(w, http.StatusBadRequest, nil)
+		return
+	}
+
+	merr := o.organizationServices.DeleteItem(organizationID, collectionID, itemID, int(userID))
+	if merr != nil {
+		data := map[string]string{"message": merr.Description}
+		httpError(w, merr.Code, data)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+
+	response := models.Response{
+		Status:     http.StatusOK,
+		StatusText: http.StatusText(http.StatusOK),
+		Data:       map[string]any{"id": itemID, "succeeded": true, "operation": "delete"},
+	}
+
+	json.NewEncoder(w).Encode(response)
+}
+
+func (o *OrganizationHandlers) GetPolicies(w http.ResponseWriter, r *http.Request) {
+	if r.Method != "GET" {
+		httpError(w, http.StatusMethodNotAllowed, nil)
+		return
+	}
+
+	claims, ok := r.Context().Value("claims").(jwt.MapClaims)
+	if !ok {
+		data := map[string]string{"message": "An error occurred during token decryption."}
+		httpError(w, http.StatusInternalServerError, data)
+		return
+	}
+
+	userID, ok := claims["sub"].(float64)
+	if !ok {
+		data := map[string]string{"message": "Token does not contain a userID."}
+		httpError(w, http.StatusBadRequest, data)
+		return
+	}
+
+	organizationID, err := strconv.Atoi(r.PathValue("orgId"))
+	if err != nil {
+		httpError(w, http.StatusBadRequest, nil)
+		return
+	}
+
+	policies, merr := o.organizationServices.GetPolicies(organizationID, int(userID))
+	if merr != nil {
+		data := map[string]string{"message": merr.Description}
+		httpError(w, merr.Code, data)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+
+	response := models.Response{
+		Status:     http.StatusOK,
+		StatusText: http.StatusText(http.StatusOK),
+		Data:       policies,
+	}
+
+	json.NewEncoder(w).Encode(response)
+}
+
+func (o *OrganizationHandlers) SetPolicy(w http.ResponseWriter, r *http.Request) {
+	if r.Method != "PUT" {
+		httpError(w, http.StatusMethodNotAllowed, nil)
+		return
+	}
+
+	claims, ok := r.Context().Value("claims").(jwt.MapClaims)
+	if !ok {
+		data := map[string]string{"message": "An error occurred during token decryption."}
+		httpError(w, http.StatusInternalServerError, data)
+		return
+	}
+
+	userID, ok := claims["sub"].(float64)
+	if !ok {
+		data := map[string]string{"message": "Token does not contain a userID."}
+		httpError(w, http.StatusBadRequest, data)
+		return
+	}
+
+	organizationID, err := strconv.Atoi(r.PathValue("orgId"))
+	if err != nil {
+		httpError(w, http.StatusBadRequest, nil)
+		return
+	}
+
+	var setPolicyRequest *organization.SetPolicyRequest
+	err = json.NewDecoder(r.Body).Decode(&setPolicyRequest)
+	if err != nil {
+		httpError(w, http.StatusBadRequest, nil)
+		return
+	}
+
+	validate := validator.New()
+	err = validate.Struct(setPolicyRequest)
+	if err != nil {
+		httpError(w, http.StatusBadRequest, nil)
+		return
+	}
+
+	policy, merr := o.organizationServices.SetPolicy(organizationID, r.PathValue("type"), int(userID), setPolicyRequest)
+	if merr != nil {
+		data := map[string]string{"message": merr.Description}
+		httpError(w, merr.Code, data)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+
+	response := models.Response{
+		Status:     http.StatusOK,
+		StatusText: http.StatusText(http.StatusOK),
+		Data:       policy,
+	}
+
+	json.NewEncoder(w).Encode(response)
+}
@@ -0,0 +1,430 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"net"
+	"net/http"
+	"strconv"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/safepass/server/internal/consts"
+	"github.com/safepass/server/internal/services"
+	"github.com/safepass/server/pkg/dtos/send"
+	"github.com/safepass/server/pkg/models"
+)
+
+type SendHandlersFuncs interface {
+	Sends(w http.ResponseWriter, r *http.Request)
+	Send(w http.ResponseWriter, r *http.Request)
+	GetSends(w http.ResponseWriter, r *http.Request)
+	GetSend(w http.ResponseWriter, r *http.Request)
+	CreateSend(w http.ResponseWriter, r *http.Request)
+	CreateFileSend(w http.ResponseWriter, r *http.Request)
+	DeleteSend(w http.ResponseWriter, r *http.Request)
+	AccessSend(w http.ResponseWriter, r *http.Request)
+	DownloadSendFile(w http.ResponseWriter, r *http.Request)
+}
+
+type SendHandlers struct {
+	sendServices services.SendServices
+
+	SendHandlersFuncs
+}
+
+func NewSendHandlers(sendServices services.SendServices) *SendHandlers {
+	return &SendHandlers{
+		sendServices: sendServices,
+	}
+}
+
+// Sends dispatches /api/v1/sends to the handler for the request method.
+func (s *SendHandlers) Sends(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+		s.GetSends(w, r)
+	case http.MethodPost:
+		s.CreateSend(w, r)
+	default:
+		httpError(w, http.StatusMethodNotAllowed, nil)
+	}
+}
+
+// Send dispatches /api/v1/sends/{sendId} to the handler for the request method.
+func (s *SendHandlers) Send(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+		s.GetSend(w, r)
+	case http.MethodDelete:
+		s.DeleteSend(w, r)
+	default:
+		httpError(w, http.StatusMethodNotAllowed, nil)
+	}
+}
+
+func (s *SendHandlers) GetSends(w http.ResponseWriter, r *http.Request) {
+	if r.Method != "GET" {
+		httpError(w, http.StatusMethodNotAllowed, nil)
+		return
+	}
+
+	claims, ok := r.Context().Value("claims").(jwt.MapClaims)
+	if !ok {
+		data := map[string]string{"message": "An error occurred during token decryption."}
+		httpError(w, http.StatusInternalServerError, data)
+		return
+	}
+
+	userID, ok := claims["sub"].(float64)
+	if !ok {
+		data := map[string]string{"message": "Token does not contain a userID."}
+		httpError(w, http.StatusBadRequest, data)
+		return
+	}
+
+	sends, merr := s.sendServices.GetSends(int(userID))
+	if merr != nil {
+		data := map[string]string{"message": merr.Description}
+		httpError(w, merr.Code, data)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+
+	response := models.Response{
+		Status:     http.StatusOK,
+		StatusText: http.StatusText(http.StatusOK),
+		Data:       sends,
+	}
+
+	json.NewEncoder(w).Encode(response)
+}
+
+func (s *SendHandlers) GetSend(w http.ResponseWriter, r *http.Request) {
+	if r.Method != "GET" {
+		httpError(w, http.StatusMethodNotAllowed, nil)
+		return
+	}
+
+	claims, ok := r.Context().Value("claims").(jwt.MapClaims)
+	if !ok {
+		data := map[string]string{"message": "An error occurred during token decryption."}
+		httpError(w, http.StatusInternalServerError, data)
+		return
+	}
+
+	userID, ok := claims["sub"].(float64)
+	if !ok {
+		data := map[string]string{"message": "Token does not contain a userID."}
+		httpError(w, http.StatusBadRequest, data)
+		return
+	}
+
+	sendID, err := strconv.Atoi(r.PathValue("sendId"))
+	if err != nil {
+		httpError(w, http.StatusBadRequest, nil)
+		return
+	}
+
+	found, merr := s.sendServices.GetSend(sendID, int(userID))
+	if merr != nil {
+		data := map[string]string{"message": merr.Description}
+		httpError(w, merr.Code, data)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+
+	response := models.Response{
+		Status:     http.StatusOK,
+		StatusText: http.StatusText(http.StatusOK),
+		Data:       found,
+	}
+
+	json.NewEncoder(w).Encode(response)
+}
+
+// CreateSend creates a text Send.
+func (s *SendHandlers) CreateSend(w http.ResponseWriter, r *http.Request) {
+	if r.Method != "POST" {
+		httpError(w, http.StatusMethodNotAllowed, nil)
+		return
+	}
+
+	claims, ok := r.Context().Value("claims").(jwt.MapClaims)
+	if !ok {
+		data := map[string]string{"message": "An error occurred during token decryption."}
+		httpError(w, http.StatusInternalServerError, data)
+		return
+	}
+
+	userID, ok := claims["sub"].(float64)
+	if !ok {
+		data := map[string]string{"message": "Token does not contain a userID."}
+		httpError(w, http.StatusBadRequest, data)
+		return
+	}
+
+	var createRequest *send.CreateSendRequest
+	err := json.NewDecoder(r.Body).Decode(&createRequest)
+	if err != nil {
+		httpError(w, http.StatusBadRequest, nil)
+		return
+	}
+
+	validate := validator.New()
+	err = validate.Struct(createRequest)
+	if err != nil {
+		httpError(w, http.StatusBadRequest, nil)
+		return
+	}
+
+	created, merr := s.sendServices.CreateSend(int(userID), consts.SendTypes.TEXT, createRequest)
+	if merr != nil {
+		data := map[string]string{"message": merr.Description}
+		httpError(w, merr.Code, data)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+
+	response := models.Response{
+		Status:     http.StatusCreated,
+		StatusText: http.StatusText(http.StatusCreated),
+		Data:       created,
+	}
+
+	json.NewEncoder(w).Encode(response)
+}
+
+// CreateFileSend takes a multipart form with the Send as JSON in send
+// followed by the encrypted file in data.
+func (s *SendHandlers) CreateFileSend(w http.ResponseWriter, r *http.Request) {
+	if r.Method != "POST" {
+		httpError(w, http.StatusMethodNotAllowed, nil)
+		return
+	}
+
+	claims, ok := r.Context().Value("claims").(jwt.MapClaims)
+	if !ok {
+		data := map[string]string{"message": "An error occurred during token decryption."}
+		httpError(w, http.StatusInternalServerError, data)
+		return
+	}
+
+	userID, ok := claims["sub"].(float64)
+	if !ok {
+		data := map[string]string{"message": "Token does not contain a userID."}
+		httpError(w, http.StatusBadRequest, data)
+		return
+	}
+
+	createRequest, err := readFileSendRequest(r)
+	if err != nil {
+		data := map[string]string{"message": err.Error()}
+		httpError(w, http.StatusBadRequest, data)
+		return
+	}
+
+	created, merr := s.sendServices.CreateSend(int(userID), consts.SendTypes.FILE, createRequest)
+	if merr != nil {
+		data := map[string]string{"message": merr.Description}
+		httpError(w, merr.Code, data)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+
+	response := models.Response{
+		Status:     http.StatusCreated,
+		StatusText: http.StatusText(http.StatusCreated),
+		Data:       created,
+	}
+
+	json.NewEncoder(w).Encode(response)
+}
+
+func (s *SendHandlers) DeleteSend(w http.ResponseWriter, r *http.Request) {
+	if r.Method != "DELETE" {
+		httpError(w, http.StatusMethodNotAllowed, nil)
+		return
+	}
+
+	claims, ok := r.Context().Value("claims").(jwt.MapClaims)
+	if !ok {
+		data := map[string]string{"message": "An error occurred during token decryption."}
+		httpError(w, http.StatusInternalServerError, data)
+		return
+	}
+
+	userID, ok := claims["sub"].(float64)
+	if !ok {
+		data := map[string]string{"message": "Token does not contain a userID."}
+		httpError(w, http.StatusBadRequest, data)
+		return
+	}
+
+	sendID, err := strconv.Atoi(r.PathValue("sendId"))
+	if err != nil {
+		httpError(w, http.StatusBadRequest, nil)
+		return
+	}
+
+	merr := s.sendServices.DeleteSend(sendID, int(userID))
+	if merr != nil {
+		data := map[string]string{"message": merr.Description}
+		httpError(w, merr.Code, data)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+
+	response := models.Response{
+		Status:     http.StatusOK,
+		StatusText: http.StatusText(http.StatusOK),
+		Data:       map[string]any{"id": sendID, "succeeded": true, "operation": "delete"},
+	}
+
+	json.NewEncoder(w).Encode(response)
+}
+
+// AccessSend opens a Send for anyone with its link. The body, which holds the
+// password of protected Sends, may be empty.
+func (s *SendHandlers) AccessSend(w http.ResponseWriter, r *http.Request) {
+	if r.Method != "POST" {
+		httpError(w, http.StatusMethodNotAllowed, nil)
+		return
+	}
+
+	accessRequest, err := readAccessSendRequest(r)
+	if err != nil {
+		httpError(w, http.StatusBadRequest, nil)
+		return
+	}
+
+	found, merr := s.sendServices.AccessSend(r.PathValue("accessId"), clientIP(r), accessRequest)
+	if merr != nil {
+		data := map[string]string{"message": merr.Description}
+		httpError(w, merr.Code, data)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+
+	response := models.Response{
+		Status:     http.StatusOK,
+		StatusText: http.StatusText(http.StatusOK),
+		Data:       found,
+	}
+
+	json.NewEncoder(w).Encode(response)
+}
+
+// DownloadSendFile streams the encrypted file of a file Send to anyone with
+// its link.
+func (s *SendHandlers) DownloadSendFile(w http.ResponseWriter, r *http.Request) {
+	if r.Method != "POST" {
+		httpError(w, http.StatusMethodNotAllowed, nil)
+		return
+	}
+
+	accessRequest, err := readAccessSendRequest(r)
+	if err != nil {
+		httpError(w, http.StatusBadRequest, nil)
+		return
+	}
+
+	found, content, merr := s.sendServices.OpenSendFile(r.PathValue("accessId"), clientIP(r), accessRequest)
+	if merr != nil {
+		data := map[string]string{"message": merr.Description}
+		httpError(w, merr.Code, data)
+		return
+	}
+
+	defer content.Close()
+
+	w.Header().Set("Content-Type", "application/octet-stream")
+	w.Header().Set("Content-Length", strconv.FormatInt(found.Size, 10))
+	w.WriteHeader(http.StatusOK)
+
+	io.Copy(w, content)
+}
+
+// readFileSendRequest reads the "send" part of a multipart file Send up to
+// the "data" part holding the encrypted file, which the returned request
+// streams. The send part has to be sent before the file.
+func readFileSendRequest(r *http.Request) (*send.CreateSendRequest, error) {
+	reader, err := r.MultipartReader()
+	if err != nil {
+		return nil, err
+	}
+
+	var createRequest *send.CreateSendRequest
+	for {
+		part, err := reader.NextPart()
+		if err == io.EOF {
+			return nil, errors.New("The upload has no data part.")
+		}
+
+		if err != nil {
+			return nil, err
+		}
+
+		switch part.FormName() {
+		case "send":
+			err = json.NewDecoder(io.LimitReader(part, 16384)).Decode(&createRequest)
+			if err != nil {
+				return nil, errors.New("The send part is not a valid Send.")
+			}
+		case "data":
+			if createRequest == nil {
+				return nil, errors.New("The upload needs the send part before data.")
+			}
+
+			validate := validator.New()
+			err = validate.Struct(createRequest)
+			if err != nil {
+				return nil, errors.New("The send part is not a valid Send.")
+			}
+
+			createRequest.Data = part
+			return createRequest, nil
+		}
+	}
+}
+
+// readAccessSendRequest decodes the optional body of a Send access.
+// clientIP returns the address the request came from, without the port.
+func clientIP(r *http.Request) string {
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return r.RemoteAddr
+	}
+
+	return host
+}
+
+func readAccessSendRequest(r *http.Request) (*send.AccessSendRequest, error) {
+	accessRequest := &send.AccessSendRequest{}
+
+	err := json.NewDecoder(r.Body).Decode(accessRequest)
+	if err != nil && err != io.EOF {
+		return nil, err
+	}
+
+	validate := validator.New()
+	err = validate.Struct(accessRequest)
+	if err != nil {
+		return nil, err
+	}
+
+	return accessRequest, nil
+}
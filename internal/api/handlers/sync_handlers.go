@@ -0,0 +1,148 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/safepass/server/internal/services"
+	"github.com/safepass/server/pkg/dtos/vault"
+	"github.com/safepass/server/pkg/models"
+)
+
+type SyncHandlersFuncs interface {
+	Sync(w http.ResponseWriter, r *http.Request)
+	Batch(w http.ResponseWriter, r *http.Request)
+}
+
+type SyncHandlers struct {
+	syncServices services.SyncServices
+
+	SyncHandlersFuncs
+}
+
+func NewSyncHandlers(syncServices services.SyncServices) *SyncHandlers {
+	return &SyncHandlers{
+		syncServices: syncServices,
+	}
+}
+
+// Sync returns the changes to a vault after the revision in ?since=. The
+// vault is given by ?vault= and defaults to the default vault.
+func (s *SyncHandlers) Sync(w http.ResponseWriter, r *http.Request) {
+	if r.Method != "GET" {
+		httpError(w, http.StatusMethodNotAllowed, nil)
+		return
+	}
+
+	claims, ok := r.Context().Value("claims").(jwt.MapClaims)
+	if !ok {
+		data := map[string]string{"message": "An error occurred during token decryption."}
+		httpError(w, http.StatusInternalServerError, data)
+		return
+	}
+
+	userID, ok := claims["sub"].(float64)
+	if !ok {
+		data := map[string]string{"message": "Token does not contain a userID."}
+		httpError(w, http.StatusBadRequest, data)
+		return
+	}
+
+	query := r.URL.Query()
+
+	vaultID := query.Get("vault")
+	if vaultID == "" {
+		vaultID = "@me"
+	}
+
+	var since int64
+	if query.Get("since") != "" {
+		var err error
+		since, err = strconv.ParseInt(query.Get("since"), 10, 64)
+		if err != nil || since < 0 {
+			data := map[string]string{"message": "since must be a revision."}
+			httpError(w, http.StatusBadRequest, data)
+			return
+		}
+	}
+
+	sync, merr := s.syncServices.Sync(vaultID, int(userID), since)
+	if merr != nil {
+		data := map[string]string{"message": merr.Description}
+		httpError(w, merr.Code, data)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+
+	response := models.Response{
+		Status:     http.StatusOK,
+		StatusText: http.StatusText(http.StatusOK),
+		Data:       sync,
+	}
+
+	json.NewEncoder(w).Encode(response)
+}
+
+// Batch applies the item changes a client queued while offline to the vault
+// given by ?vault=, the default vault when it is left out.
+func (s *SyncHandlers) Batch(w http.ResponseWriter, r *http.Request) {
+	if r.Method != "POST" {
+		httpError(w, http.StatusMethodNotAllowed, nil)
+		return
+	}
+
+	claims, ok := r.Context().Value("claims").(jwt.MapClaims)
+	if !ok {
+		data := map[string]string{"message": "An error occurred during token decryption."}
+		httpError(w, http.StatusInternalServerError, data)
+		return
+	}
+
+	userID, ok := claims["sub"].(float64)
+	if !ok {
+		data := map[string]string{"message": "Token does not contain a userID."}
+		httpError(w, http.StatusBadRequest, data)
+		return
+	}
+
+	vaultID := r.URL.Query().Get("vault")
+	if vaultID == "" {
+		vaultID = "@me"
+	}
+
+	var batchRequest *vault.BatchRequest
+	err := json.NewDecoder(r.Body).Decode(&batchRequest)
+	if err != nil {
+		httpError(w, http.StatusBadRequest, nil)
+		return
+	}
+
+	validate := validator.New()
+	err = validate.Struct(batchRequest)
+	if err != nil {
+		httpError(w, http.StatusBadRequest, nil)
+		return
+	}
+
+	batch, merr := s.syncServices.ApplyBatch(vaultID, int(userID), batchRequest)
+	if merr != nil {
+		httpError(w, merr.Code, errorData(merr))
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+
+	response := models.Response{
+		Status:     http.StatusOK,
+		StatusText: http.StatusText(http.StatusOK),
+		Data:       batch,
+	}
+
+	json.NewEncoder(w).Encode(response)
+}
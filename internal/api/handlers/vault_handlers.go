@@ -10,6 +10,7 @@ import (
 	"github.com/golang-jwt/jwt/v5"
 	"github.com/safepass/server/internal/services"
 	"github.com/safepass/server/pkg/dtos/password"
+	vaultdto "github.com/safepass/server/pkg/dtos/vault"
 	"github.com/safepass/server/pkg/models"
 )
 
@@ -61,7 +62,7 @@ func (v *VaultHandlers) GetVault(w http.ResponseWriter, r *http.Request) {
 	response := models.Response{
 		Status:     http.StatusOK,
 		StatusText: http.StatusText(http.StatusOK),
-		Data:       vault,
+		Data:       vaultdto.NewVaultResponse(vault),
 	}
 
 	json.NewEncoder(w).Encode(response)
@@ -94,7 +95,13 @@ func (v *VaultHandlers) GetPasswords(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	passwords, merr := v.vaultServices.GetPasswords(strconv.Itoa(vault.ID))
+	filter, err := passwordFilter(r)
+	if err != nil {
+		httpError(w, http.StatusBadRequest, nil)
+		return
+	}
+
+	passwords, merr := v.vaultServices.GetAccessiblePasswords(vault, filter)
 	if merr != nil {
 		data := map[string]string{"message": merr.Description}
 		httpError(w, merr.Code, data)
@@ -146,20 +153,21 @@ func (v *VaultHandlers) GetPassword(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	passwords, merr := v.vaultServices.GetPassword(id, vault.ID)
+	password, merr := v.vaultServices.GetPassword(id, vault)
 	if merr != nil {
 		data := map[string]string{"message": merr.Description}
 		httpError(w, merr.Code, data)
 		return
 	}
 
+	w.Header().Set("ETag", itemETag(password))
 	w.Header().Set("Content-Type", "application/json")
 	w.WriteHeader(http.StatusOK)
 
 	response := models.Response{
 		Status:     http.StatusOK,
 		StatusText: http.StatusText(http.StatusOK),
-		Data:       passwords,
+		Data:       password,
 	}
 
 	json.NewEncoder(w).Encode(response)
@@ -192,6 +200,13 @@ func (v *VaultHandlers) CreatePassword(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	merr = v.vaultServices.CanWrite(vault)
+	if merr != nil {
+		data := map[string]string{"message": merr.Description}
+		httpError(w, merr.Code, data)
+		return
+	}
+
 	var passwordRequest *password.CreatePasswordRequest
 	err := json.NewDecoder(r.Body).Decode(&passwordRequest)
 	if err != nil {
@@ -270,6 +285,13 @@ func (v *VaultHandlers) UpdatePassword(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	merr = v.vaultServices.CanWrite(vault)
+	if merr != nil {
+		data := map[string]string{"message": merr.Description}
+		httpError(w, merr.Code, data)
+		return
+	}
+
 	var passwordRequest *password.CreatePasswordRequest
 	err := json.NewDecoder(r.Body).Decode(&passwordRequest)
 	if err != nil {
@@ -314,13 +336,19 @@ func (v *VaultHandlers) UpdatePassword(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	password, merr := v.vaultServices.UpdatePassword(id, vault.ID, passwordRequest)
+	err = ifMatchRevision(r, passwordRequest)
+	if err != nil {
+		httpError(w, http.StatusBadRequest, nil)
+		return
+	}
+
+	password, merr := v.vaultServices.UpdatePassword(id, vault, passwordRequest)
 	if merr != nil {
-		data := map[string]string{"message": merr.Description}
-		httpError(w, merr.Code, data)
+		httpError(w, merr.Code, errorData(merr))
 		return
 	}
 
+	w.Header().Set("ETag", itemETag(password))
 	w.Header().Set("Content-Type", "application/json")
 	w.WriteHeader(http.StatusOK)
 
@@ -360,6 +388,13 @@ func (v *VaultHandlers) DeletePassword(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	merr = v.vaultServices.CanWrite(vault)
+	if merr != nil {
+		data := map[string]string{"message": merr.Description}
+		httpError(w, merr.Code, data)
+		return
+	}
+
 	path := strings.TrimPrefix(r.URL.Path, "/api/v1/vault/password/delete/")
 	if path == "" || strings.Contains(path, "/") {
 		httpError(w, http.StatusBadRequest, nil)
@@ -391,6 +426,201 @@ func (v *VaultHandlers) DeletePassword(w http.ResponseWriter, r *http.Request) {
 	json.NewEncoder(w).Encode(response)
 }
 
+// Shares dispatches /api/v1/vault/passwords/{id}/shares to the handler for the
+// request method.
+func (v *VaultHandlers) Shares(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+		v.GetShares(w, r)
+	case http.MethodPost:
+		v.SharePassword(w, r)
+	default:
+		httpError(w, http.StatusMethodNotAllowed, nil)
+	}
+}
+
+func (v *VaultHandlers) GetShares(w http.ResponseWriter, r *http.Request) {
+	if r.Method != "GET" {
+		httpError(w, http.StatusMethodNotAllowed, nil)
+		return
+	}
+
+	claims, ok := r.Context().Value("claims").(jwt.MapClaims)
+	if !ok {
+		data := map[string]string{"message": "An error occurred during token decryption."}
+		httpError(w, http.StatusInternalServerError, data)
+		return
+	}
+
+	userID, ok := claims["sub"].(float64)
+	if !ok {
+		data := map[string]string{"message": "Token does not contain a userID."}
+		httpError(w, http.StatusBadRequest, data)
+		return
+	}
+
+	vault, merr := v.vaultServices.GetVaultByUserID(strconv.Itoa(int(userID)))
+	if merr != nil {
+		data := map[string]string{"message": merr.Description}
+		httpError(w, merr.Code, data)
+		return
+	}
+
+	id, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil {
+		httpError(w, http.StatusBadRequest, nil)
+		return
+	}
+
+	shares, merr := v.vaultServices.GetShares(id, vault)
+	if merr != nil {
+		data := map[string]string{"message": merr.Description}
+		httpError(w, merr.Code, data)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+
+	response := models.Response{
+		Status:     http.StatusOK,
+		StatusText: http.StatusText(http.StatusOK),
+		Data:       shares,
+	}
+
+	json.NewEncoder(w).Encode(response)
+}
+
+func (v *VaultHandlers) SharePassword(w http.ResponseWriter, r *http.Request) {
+	if r.Method != "POST" {
+		httpError(w, http.StatusMethodNotAllowed, nil)
+		return
+	}
+
+	claims, ok := r.Context().Value("claims").(jwt.MapClaims)
+	if !ok {
+		data := map[string]string{"message": "An error occurred during token decryption."}
+		httpError(w, http.StatusInternalServerError, data)
+		return
+	}
+
+	userID, ok := claims["sub"].(float64)
+	if !ok {
+		data := map[string]string{"message": "Token does not contain a userID."}
+		httpError(w, http.StatusBadRequest, data)
+		return
+	}
+
+	vault, merr := v.vaultServices.GetVaultByUserID(strconv.Itoa(int(userID)))
+	if merr != nil {
+		data := map[string]string{"message": merr.Description}
+		httpError(w, merr.Code, data)
+		return
+	}
+
+	merr = v.vaultServices.CanWrite(vault)
+	if merr != nil {
+		data := map[string]string{"message": merr.Description}
+		httpError(w, merr.Code, data)
+		return
+	}
+
+	id, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil {
+		httpError(w, http.StatusBadRequest, nil)
+		return
+	}
+
+	var shareRequest *password.CreateShareRequest
+	err = json.NewDecoder(r.Body).Decode(&shareRequest)
+	if err != nil {
+		httpError(w, http.StatusBadRequest, nil)
+		return
+	}
+
+	validate := validator.New()
+	err = validate.Struct(shareRequest)
+	if err != nil {
+		httpError(w, http.StatusBadRequest, nil)
+		return
+	}
+
+	share, merr := v.vaultServices.SharePassword(id, vault, shareRequest)
+	if merr != nil {
+		httpError(w, merr.Code, errorData(merr))
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+
+	response := models.Response{
+		Status:     http.StatusOK,
+		StatusText: http.StatusText(http.StatusOK),
+		Data:       share,
+	}
+
+	json.NewEncoder(w).Encode(response)
+}
+
+func (v *VaultHandlers) RevokeShare(w http.ResponseWriter, r *http.Request) {
+	if r.Method != "DELETE" {
+		httpError(w, http.StatusMethodNotAllowed, nil)
+		return
+	}
+
+	claims, ok := r.Context().Value("claims").(jwt.MapClaims)
+	if !ok {
+		data := map[string]string{"message": "An error occurred during token decryption."}
+		httpError(w, http.StatusInternalServerError, data)
+		return
+	}
+
+	userID, ok := claims["sub"].(float64)
+	if !ok {
+		data := map[string]string{"message": "Token does not contain a userID."}
+		httpError(w, http.StatusBadRequest, data)
+		return
+	}
+
+	vault, merr := v.vaultServices.GetVaultByUserID(strconv.Itoa(int(userID)))
+	if merr != nil {
+		data := map[string]string{"message": merr.Description}
+		httpError(w, merr.Code, data)
+		return
+	}
+
+	id, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil {
+		httpError(w, http.StatusBadRequest, nil)
+		return
+	}
+
+	shareID, err := strconv.Atoi(r.PathValue("shareId"))
+	if err != nil {
+		httpError(w, http.StatusBadRequest, nil)
+		return
+	}
+
+	merr = v.vaultServices.RevokeShare(id, shareID, vault)
+	if merr != nil {
+		data := map[string]string{"message": merr.Description}
+		httpError(w, merr.Code, data)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+
+	response := models.Response{
+		Status:     http.StatusOK,
+		StatusText: http.StatusText(http.StatusOK),
+		Data:       map[string]any{"id": shareID, "succeeded": true, "operation": "revoke"},
+	}
+
+	json.NewEncoder(w).Encode(response)
+}
+
 func httpError(w http.ResponseWriter, code int, data any) {
 	w.Header().Set("Content-Type", "application/json")
 	w.WriteHeader(code)
@@ -403,3 +633,1374 @@ func httpError(w http.ResponseWriter, code int, data any) {
 
 	json.NewEncoder(w).Encode(response)
 }
+
+// Vaults dispatches /api/v1/vaults to the handler for the request
+// method.
+func (v *VaultHandlers) Vaults(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+		v.ListVaults(w, r)
+	case http.MethodPost:
+		v.CreateVault(w, r)
+	default:
+		httpError(w, http.StatusMethodNotAllowed, nil)
+	}
+}
+
+// Vault dispatches /api/v1/vaults/{vaultId} to the handler for the request
+// method.
+func (v *VaultHandlers) Vault(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+		v.GetVaultByID(w, r)
+	case http.MethodPatch:
+		v.RenameVault(w, r)
+	case http.MethodDelete:
+		v.DeleteVault(w, r)
+	default:
+		httpError(w, http.StatusMethodNotAllowed, nil)
+	}
+}
+
+// Items dispatches /api/v1/vaults/{vaultId}/items to the handler for the request
+// method.
+func (v *VaultHandlers) Items(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+		v.GetItems(w, r)
+	case http.MethodPost:
+		v.CreateItem(w, r)
+	default:
+		httpError(w, http.StatusMethodNotAllowed, nil)
+	}
+}
+
+// Item dispatches /api/v1/vaults/{vaultId}/items/{itemId} to the handler for the request
+// method.
+func (v *VaultHandlers) Item(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+		v.GetItem(w, r)
+	case http.MethodPut:
+		v.UpdateItem(w, r)
+	case http.MethodDelete:
+		v.DeleteItem(w, r)
+	default:
+		httpError(w, http.StatusMethodNotAllowed, nil)
+	}
+}
+
+func (v *VaultHandlers) ListVaults(w http.ResponseWriter, r *http.Request) {
+	if r.Method != "GET" {
+		httpError(w, http.StatusMethodNotAllowed, nil)
+		return
+	}
+
+	claims, ok := r.Context().Value("claims").(jwt.MapClaims)
+	if !ok {
+		data := map[string]string{"message": "An error occurred during token decryption."}
+		httpError(w, http.StatusInternalServerError, data)
+		return
+	}
+
+	userID, ok := claims["sub"].(float64)
+	if !ok {
+		data := map[string]string{"message": "Token does not contain a userID."}
+		httpError(w, http.StatusBadRequest, data)
+		return
+	}
+
+	vaults, merr := v.vaultServices.GetVaults(int(userID))
+	if merr != nil {
+		data := map[string]string{"message": merr.Description}
+		httpError(w, merr.Code, data)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+
+	response := models.Response{
+		Status:     http.StatusOK,
+		StatusText: http.StatusText(http.StatusOK),
+		Data:       vaultResponses(vaults),
+	}
+
+	json.NewEncoder(w).Encode(response)
+}
+
+func (v *VaultHandlers) CreateVault(w http.ResponseWriter, r *http.Request) {
+	if r.Method != "POST" {
+		httpError(w, http.StatusMethodNotAllowed, nil)
+		return
+	}
+
+	claims, ok := r.Context().Value("claims").(jwt.MapClaims)
+	if !ok {
+		data := map[string]string{"message": "An error occurred during token decryption."}
+		httpError(w, http.StatusInternalServerError, data)
+		return
+	}
+
+	userID, ok := claims["sub"].(float64)
+	if !ok {
+		data := map[string]string{"message": "Token does not contain a userID."}
+		httpError(w, http.StatusBadRequest, data)
+		return
+	}
+
+	// Creating a vault is a vault modification, checked against the default
+	// vault's owner.
+	defaultVault, merr := v.vaultServices.GetUserVault("@me", int(userID))
+	if merr != nil {
+		data := map[string]string{"message": merr.Description}
+		httpError(w, merr.Code, data)
+		return
+	}
+
+	merr = v.vaultServices.CanWrite(defaultVault)
+	if merr != nil {
+		data := map[string]string{"message": merr.Description}
+		httpError(w, merr.Code, data)
+		return
+	}
+
+	var createVaultRequest *vaultdto.CreateVaultRequest
+	err := json.NewDecoder(r.Body).Decode(&createVaultRequest)
+	if err != nil {
+		httpError(w, http.StatusBadRequest, nil)
+		return
+	}
+
+	validate := validator.New()
+	err = validate.Struct(createVaultRequest)
+	if err != nil {
+		httpError(w, http.StatusBadRequest, nil)
+		return
+	}
+
+	vault, merr := v.vaultServices.AddVault(int(userID), createVaultRequest)
+	if merr != nil {
+		data := map[string]string{"message": merr.Description}
+		httpError(w, merr.Code, data)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+
+	response := models.Response{
+		Status:     http.StatusCreated,
+		StatusText: http.StatusText(http.StatusCreated),
+		Data:       vaultdto.NewVaultResponse(vault),
+	}
+
+	json.NewEncoder(w).Encode(response)
+}
+
+func (v *VaultHandlers) GetVaultByID(w http.ResponseWriter, r *http.Request) {
+	if r.Method != "GET" {
+		httpError(w, http.StatusMethodNotAllowed, nil)
+		return
+	}
+
+	claims, ok := r.Context().Value("claims").(jwt.MapClaims)
+	if !ok {
+		data := map[string]string{"message": "An error occurred during token decryption."}
+		httpError(w, http.StatusInternalServerError, data)
+		return
+	}
+
+	userID, ok := claims["sub"].(float64)
+	if !ok {
+		data := map[string]string{"message": "Token does not contain a userID."}
+		httpError(w, http.StatusBadRequest, data)
+		return
+	}
+
+	vault, merr := v.vaultServices.GetUserVault(r.PathValue("vaultId"), int(userID))
+	if merr != nil {
+		data := map[string]string{"message": merr.Description}
+		httpError(w, merr.Code, data)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+
+	response := models.Response{
+		Status:     http.StatusOK,
+		StatusText: http.StatusText(http.StatusOK),
+		Data:       vaultdto.NewVaultResponse(vault),
+	}
+
+	json.NewEncoder(w).Encode(response)
+}
+
+func (v *VaultHandlers) RenameVault(w http.ResponseWriter, r *http.Request) {
+	if r.Method != "PATCH" {
+		httpError(w, http.StatusMethodNotAllowed, nil)
+		return
+	}
+
+	claims, ok := r.Context().Value("claims").(jwt.MapClaims)
+	if !ok {
+		data := map[string]string{"message": "An error occurred during token decryption."}
+		httpError(w, http.StatusInternalServerError, data)
+		return
+	}
+
+	userID, ok := claims["sub"].(float64)
+	if !ok {
+		data := map[string]string{"message": "Token does not contain a userID."}
+		httpError(w, http.StatusBadRequest, data)
+		return
+	}
+
+	var updateVaultRequest *vaultdto.UpdateVaultRequest
+	err := json.NewDecoder(r.Body).Decode(&updateVaultRequest)
+	if err != nil {
+		httpError(w, http.StatusBadRequest, nil)
+		return
+	}
+
+	validate := validator.New()
+	err = validate.Struct(updateVaultRequest)
+	if err != nil {
+		httpError(w, http.StatusBadRequest, nil)
+		return
+	}
+
+	vault, merr := v.vaultServices.RenameVault(r.PathValue("vaultId"), int(userID), updateVaultRequest)
+	if merr != nil {
+		data := map[string]string{"message": merr.Description}
+		httpError(w, merr.Code, data)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+
+	response := models.Response{
+		Status:     http.StatusOK,
+		StatusText: http.StatusText(http.StatusOK),
+		Data:       vaultdto.NewVaultResponse(vault),
+	}
+
+	json.NewEncoder(w).Encode(response)
+}
+
+func (v *VaultHandlers) DeleteVault(w http.ResponseWriter, r *http.Request) {
+	if r.Method != "DELETE" {
+		httpError(w, http.StatusMethodNotAllowed, nil)
+		return
+	}
+
+	claims, ok := r.Context().Value("claims").(jwt.MapClaims)
+	if !ok {
+		data := map[string]string{"message": "An error occurred during token decryption."}
+		httpError(w, http.StatusInternalServerError, data)
+		return
+	}
+
+	userID, ok := claims["sub"].(float64)
+	if !ok {
+		data := map[string]string{"message": "Token does not contain a userID."}
+		httpError(w, http.StatusBadRequest, data)
+		return
+	}
+
+	merr := v.vaultServices.DeleteVault(r.PathValue("vaultId"), int(userID))
+	if merr != nil {
+		data := map[string]string{"message": merr.Description}
+		httpError(w, merr.Code, data)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+
+	response := models.Response{
+		Status:     http.StatusOK,
+		StatusText: http.StatusText(http.StatusOK),
+		Data:       map[string]any{"id": r.PathValue("vaultId"), "succeeded": true, "operation": "delete"},
+	}
+
+	json.NewEncoder(w).Encode(response)
+}
+
+func (v *VaultHandlers) GetItems(w http.ResponseWriter, r *http.Request) {
+	if r.Method != "GET" {
+		httpError(w, http.StatusMethodNotAllowed, nil)
+		return
+	}
+
+	claims, ok := r.Context().Value("claims").(jwt.MapClaims)
+	if !ok {
+		data := map[string]string{"message": "An error occurred during token decryption."}
+		httpError(w, http.StatusInternalServerError, data)
+		return
+	}
+
+	userID, ok := claims["sub"].(float64)
+	if !ok {
+		data := map[string]string{"message": "Token does not contain a userID."}
+		httpError(w, http.StatusBadRequest, data)
+		return
+	}
+
+	vault, merr := v.vaultServices.GetUserVault(r.PathValue("vaultId"), int(userID))
+	if merr != nil {
+		data := map[string]string{"message": merr.Description}
+		httpError(w, merr.Code, data)
+		return
+	}
+
+	filter, err := passwordFilter(r)
+	if err != nil {
+		httpError(w, http.StatusBadRequest, nil)
+		return
+	}
+
+	var passwords []*models.Password
+	if vault.IsDefault {
+		passwords, merr = v.vaultServices.GetAccessiblePasswords(vault, filter)
+	} else {
+		passwords, merr = v.vaultServices.GetPasswords(strconv.Itoa(vault.ID), filter)
+	}
+	if merr != nil {
+		data := map[string]string{"message": merr.Description}
+		httpError(w, merr.Code, data)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+
+	response := models.Response{
+		Status:     http.StatusOK,
+		StatusText: http.StatusText(http.StatusOK),
+		Data:       passwords,
+	}
+
+	json.NewEncoder(w).Encode(response)
+}
+
+func (v *VaultHandlers) CreateItem(w http.ResponseWriter, r *http.Request) {
+	if r.Method != "POST" {
+		httpError(w, http.StatusMethodNotAllowed, nil)
+		return
+	}
+
+	claims, ok := r.Context().Value("claims").(jwt.MapClaims)
+	if !ok {
+		data := map[string]string{"message": "An error occurred during token decryption."}
+		httpError(w, http.StatusInternalServerError, data)
+		return
+	}
+
+	userID, ok := claims["sub"].(float64)
+	if !ok {
+		data := map[string]string{"message": "Token does not contain a userID."}
+		httpError(w, http.StatusBadRequest, data)
+		return
+	}
+
+	vault, merr := v.vaultServices.GetUserVault(r.PathValue("vaultId"), int(userID))
+	if merr != nil {
+		data := map[string]string{"message": merr.Description}
+		httpError(w, merr.Code, data)
+		return
+	}
+
+	merr = v.vaultServices.CanWrite(vault)
+	if merr != nil {
+		data := map[string]string{"message": merr.Description}
+		httpError(w, merr.Code, data)
+		return
+	}
+
+	var passwordRequest *password.CreatePasswordRequest
+	err := json.NewDecoder(r.Body).Decode(&passwordRequest)
+	if err != nil {
+		httpError(w, http.StatusBadRequest, nil)
+		return
+	}
+
+	validate := validator.New()
+	err = validate.Struct(passwordRequest)
+	if err != nil {
+		httpError(w, http.StatusBadRequest, nil)
+		return
+	}
+
+	password, merr := v.vaultServices.CreatePassword(vault.ID, passwordRequest)
+	if merr != nil {
+		data := map[string]string{"message": merr.Description}
+		httpError(w, merr.Code, data)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+
+	response := models.Response{
+		Status:     http.StatusCreated,
+		StatusText: http.StatusText(http.StatusCreated),
+		Data:       password,
+	}
+
+	json.NewEncoder(w).Encode(response)
+}
+
+func (v *VaultHandlers) GetItem(w http.ResponseWriter, r *http.Request) {
+	if r.Method != "GET" {
+		httpError(w, http.StatusMethodNotAllowed, nil)
+		return
+	}
+
+	claims, ok := r.Context().Value("claims").(jwt.MapClaims)
+	if !ok {
+		data := map[string]string{"message": "An error occurred during token decryption."}
+		httpError(w, http.StatusInternalServerError, data)
+		return
+	}
+
+	userID, ok := claims["sub"].(float64)
+	if !ok {
+		data := map[string]string{"message": "Token does not contain a userID."}
+		httpError(w, http.StatusBadRequest, data)
+		return
+	}
+
+	vault, merr := v.vaultServices.GetUserVault(r.PathValue("vaultId"), int(userID))
+	if merr != nil {
+		data := map[string]string{"message": merr.Description}
+		httpError(w, merr.Code, data)
+		return
+	}
+
+	id, err := strconv.Atoi(r.PathValue("itemId"))
+	if err != nil {
+		httpError(w, http.StatusBadRequest, nil)
+		return
+	}
+
+	password, merr := v.vaultServices.GetPassword(strconv.Itoa(id), vault)
+	if merr != nil {
+		data := map[string]string{"message": merr.Description}
+		httpError(w, merr.Code, data)
+		return
+	}
+
+	w.Header().Set("ETag", itemETag(password))
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+
+	response := models.Response{
+		Status:     http.StatusOK,
+		StatusText: http.StatusText(http.StatusOK),
+		Data:       password,
+	}
+
+	json.NewEncoder(w).Encode(response)
+}
+
+func (v *VaultHandlers) UpdateItem(w http.ResponseWriter, r *http.Request) {
+	if r.Method != "PUT" {
+		httpError(w, http.StatusMethodNotAllowed, nil)
+		return
+	}
+
+	claims, ok := r.Context().Value("claims").(jwt.MapClaims)
+	if !ok {
+		data := map[string]string{"message": "An error occurred during token decryption."}
+		httpError(w, http.StatusInternalServerError, data)
+		return
+	}
+
+	userID, ok := claims["sub"].(float64)
+	if !ok {
+		data := map[string]string{"message": "Token does not contain a userID."}
+		httpError(w, http.StatusBadRequest, data)
+		return
+	}
+
+	vault, merr := v.vaultServices.GetUserVault(r.PathValue("vaultId"), int(userID))
+	if merr != nil {
+		data := map[string]string{"message": merr.Description}
+		httpError(w, merr.Code, data)
+		return
+	}
+
+	merr = v.vaultServices.CanWrite(vault)
+	if merr != nil {
+		data := map[string]string{"message": merr.Description}
+		httpError(w, merr.Code, data)
+		return
+	}
+
+	id, err := strconv.Atoi(r.PathValue("itemId"))
+	if err != nil {
+		httpError(w, http.StatusBadRequest, nil)
+		return
+	}
+
+	var passwordRequest *password.CreatePasswordRequest
+	err = json.NewDecoder(r.Body).Decode(&passwordRequest)
+	if err != nil {
+		httpError(w, http.StatusBadRequest, nil)
+		return
+	}
+
+	validate := validator.New()
+	err = validate.Struct(passwordRequest)
+	if err != nil {
+		httpError(w, http.StatusBadRequest, nil)
+		return
+	}
+
+	err = ifMatchRevision(r, passwordRequest)
+	if err != nil {
+		httpError(w, http.StatusBadRequest, nil)
+		return
+	}
+
+	password, merr := v.vaultServices.UpdatePassword(id, vault, passwordRequest)
+	if merr != nil {
+		httpError(w, merr.Code, errorData(merr))
+		return
+	}
+
+	w.Header().Set("ETag", itemETag(password))
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+
+	response := models.Response{
+		Status:     http.StatusOK,
+		StatusText: http.StatusText(http.StatusOK),
+		Data:       password,
+	}
+
+	json.NewEncoder(w).Encode(response)
+}
+
+func (v *VaultHandlers) DeleteItem(w http.ResponseWriter, r *http.Request) {
+	if r.Method != "DELETE" {
+		httpError(w, http.StatusMethodNotAllowed, nil)
+		return
+	}
+
+	claims, ok := r.Context().Value("claims").(jwt.MapClaims)
+	if !ok {
+		data := map[string]string{"message": "An error occurred during token decryption."}
+		httpError(w, http.StatusInternalServerError, data)
+		return
+	}
+
+	userID, ok := claims["sub"].(float64)
+	if !ok {
+		data := map[string]string{"message": "Token does not contain a userID."}
+		httpError(w, http.StatusBadRequest, data)
+		return
+	}
+
+	vault, merr := v.vaultServices.GetUserVault(r.PathValue("vaultId"), int(userID))
+	if merr != nil {
+		data := map[string]string{"message": merr.Description}
+		httpError(w, merr.Code, data)
+		return
+	}
+
+	merr = v.vaultServices.CanWrite(vault)
+	if merr != nil {
+		data := map[string]string{"message": merr.Description}
+		httpError(w, merr.Code, data)
+		return
+	}
+
+	id, err := strconv.Atoi(r.PathValue("itemId"))
+	if err != nil {
+		httpError(w, http.StatusBadRequest, nil)
+		return
+	}
+
+	_, merr = v.vaultServices.DeletePassword(id, vault.ID)
+	if merr != nil {
+		data := map[string]string{"message": merr.Description}
+		httpError(w, merr.Code, data)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+
+	response := models.Response{
+		Status:     http.StatusOK,
+		StatusText: http.StatusText(http.StatusOK),
+		Data:       map[string]any{"id": id, "succeeded": true, "operation": "delete"},
+	}
+
+	json.NewEncoder(w).Encode(response)
+}
+
+func (v *VaultHandlers) MoveItem(w http.ResponseWriter, r *http.Request) {
+	if r.Method != "PUT" {
+		httpError(w, http.StatusMethodNotAllowed, nil)
+		return
+	}
+
+	claims, ok := r.Context().Value("claims").(jwt.MapClaims)
+	if !ok {
+		data := map[string]string{"message": "An error occurred during token decryption."}
+		httpError(w, http.StatusInternalServerError, data)
+		return
+	}
+
+	userID, ok := claims["sub"].(float64)
+	if !ok {
+		data := map[string]string{"message": "Token does not contain a userID."}
+		httpError(w, http.StatusBadRequest, data)
+		return
+	}
+
+	vault, merr := v.vaultServices.GetUserVault(r.PathValue("vaultId"), int(userID))
+	if merr != nil {
+		data := map[string]string{"message": merr.Description}
+		httpError(w, merr.Code, data)
+		return
+	}
+
+	merr = v.vaultServices.CanWrite(vault)
+	if merr != nil {
+		data := map[string]string{"message": merr.Description}
+		httpError(w, merr.Code, data)
+		return
+	}
+
+	id, err := strconv.Atoi(r.PathValue("itemId"))
+	if err != nil {
+		httpError(w, http.StatusBadRequest, nil)
+		return
+	}
+
+	var moveRequest *password.MoveRequest
+	err = json.NewDecoder(r.Body).Decode(&moveRequest)
+	if err != nil {
+		httpError(w, http.StatusBadRequest, nil)
+		return
+	}
+
+	validate := validator.New()
+	err = validate.Struct(moveRequest)
+	if err != nil {
+		httpError(w, http.StatusBadRequest, nil)
+		return
+	}
+
+	password, merr := v.vaultServices.MovePassword(id, vault, moveRequest.FolderID)
+	if merr != nil {
+		data := map[string]string{"message": merr.Description}
+		httpError(w, merr.Code, data)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+
+	response := models.Response{
+		Status:     http.StatusOK,
+		StatusText: http.StatusText(http.StatusOK),
+		Data:       password,
+	}
+
+	json.NewEncoder(w).Encode(response)
+}
+
+func (v *VaultHandlers) MoveItems(w http.ResponseWriter, r *http.Request) {
+	if r.Method != "POST" {
+		httpError(w, http.StatusMethodNotAllowed, nil)
+		return
+	}
+
+	claims, ok := r.Context().Value("claims").(jwt.MapClaims)
+	if !ok {
+		data := map[string]string{"message": "An error occurred during token decryption."}
+		httpError(w, http.StatusInternalServerError, data)
+		return
+	}
+
+	userID, ok := claims["sub"].(float64)
+	if !ok {
+		data := map[string]string{"message": "Token does not contain a userID."}
+		httpError(w, http.StatusBadRequest, data)
+		return
+	}
+
+	vault, merr := v.vaultServices.GetUserVault(r.PathValue("vaultId"), int(userID))
+	if merr != nil {
+		data := map[string]string{"message": merr.Description}
+		httpError(w, merr.Code, data)
+		return
+	}
+
+	merr = v.vaultServices.CanWrite(vault)
+	if merr != nil {
+		data := map[string]string{"message": merr.Description}
+		httpError(w, merr.Code, data)
+		return
+	}
+
+	var bulkMoveRequest *password.BulkMoveRequest
+	err := json.NewDecoder(r.Body).Decode(&bulkMoveRequest)
+	if err != nil {
+		httpError(w, http.StatusBadRequest, nil)
+		return
+	}
+
+	validate := validator.New()
+	err = validate.Struct(bulkMoveRequest)
+	if err != nil {
+		httpError(w, http.StatusBadRequest, nil)
+		return
+	}
+
+	passwords, merr := v.vaultServices.MovePasswords(vault, bulkMoveRequest.PasswordIDs, bulkMoveRequest.FolderID)
+	if merr != nil {
+		data := map[string]string{"message": merr.Description}
+		httpError(w, merr.Code, data)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+
+	response := models.Response{
+		Status:     http.StatusOK,
+		StatusText: http.StatusText(http.StatusOK),
+		Data:       passwords,
+	}
+
+	json.NewEncoder(w).Encode(response)
+}
+
+func (v *VaultHandlers) TagItems(w http.ResponseWriter, r *http.Request) {
+	if r.Method != "POST" {
+		httpError(w, http.StatusMethodNotAllowed, nil)
+		return
+	}
+
+	claims, ok := r.Context().Value("claims").(jwt.MapClaims)
+	if !ok {
+		data := map[string]string{"message": "An error occurred during token decryption."}
+		httpError(w, http.StatusInternalServerError, data)
+		return
+	}
+
+	userID, ok := claims["sub"].(float64)
+	if !ok {
+		data := map[string]string{"message": "Token does not contain a userID."}
+		httpError(w, http.StatusBadRequest, data)
+		return
+	}
+
+	vault, merr := v.vaultServices.GetUserVault(r.PathValue("vaultId"), int(userID))
+	if merr != nil {
+		data := map[string]string{"message": merr.Description}
+		httpError(w, merr.Code, data)
+		return
+	}
+
+	merr = v.vaultServices.CanWrite(vault)
+	if merr != nil {
+		data := map[string]string{"message": merr.Description}
+		httpError(w, merr.Code, data)
+		return
+	}
+
+	var bulkTagRequest *password.BulkTagRequest
+	err := json.NewDecoder(r.Body).Decode(&bulkTagRequest)
+	if err != nil {
+		httpError(w, http.StatusBadRequest, nil)
+		return
+	}
+
+	validate := validator.New()
+	err = validate.Struct(bulkTagRequest)
+	if err != nil {
+		httpError(w, http.StatusBadRequest, nil)
+		return
+	}
+
+	passwords, merr := v.vaultServices.TagPasswords(vault, bulkTagRequest)
+	if merr != nil {
+		data := map[string]string{"message": merr.Description}
+		httpError(w, merr.Code, data)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+
+	response := models.Response{
+		Status:     http.StatusOK,
+		StatusText: http.StatusText(http.StatusOK),
+		Data:       passwords,
+	}
+
+	json.NewEncoder(w).Encode(response)
+}
+
+func (v *VaultHandlers) UntagItems(w http.ResponseWriter, r *http.Request) {
+	if r.Method != "POST" {
+		httpError(w, http.StatusMethodNotAllowed, nil)
+		return
+	}
+
+	claims, ok := r.Context().Value("claims").(jwt.MapClaims)
+	if !ok {
+		data := map[string]string{"message": "An error occurred during token decryption."}
+		httpError(w, http.StatusInternalServerError, data)
+		return
+	}
+
+	userID, ok := claims["sub"].(float64)
+	if !ok {
+		data := map[string]string{"message": "Token does not contain a userID."}
+		httpError(w, http.StatusBadRequest, data)
+		return
+	}
+
+	vault, merr := v.vaultServices.GetUserVault(r.PathValue("vaultId"), int(userID))
+	if merr != nil {
+		data := map[string]string{"message": merr.Description}
+		httpError(w, merr.Code, data)
+		return
+	}
+
+	merr = v.vaultServices.CanWrite(vault)
+	if merr != nil {
+		data := map[string]string{"message": merr.Description}
+		httpError(w, merr.Code, data)
+		return
+	}
+
+	var bulkTagRequest *password.BulkTagRequest
+	err := json.NewDecoder(r.Body).Decode(&bulkTagRequest)
+	if err != nil {
+		httpError(w, http.StatusBadRequest, nil)
+		return
+	}
+
+	validate := validator.New()
+	err = validate.Struct(bulkTagRequest)
+	if err != nil {
+		httpError(w, http.StatusBadRequest, nil)
+		return
+	}
+
+	passwords, merr := v.vaultServices.UntagPasswords(vault, bulkTagRequest)
+	if merr != nil {
+		data := map[string]string{"message": merr.Description}
+		httpError(w, merr.Code, data)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+
+	response := models.Response{
+		Status:     http.StatusOK,
+		StatusText: http.StatusText(http.StatusOK),
+		Data:       passwords,
+	}
+
+	json.NewEncoder(w).Encode(response)
+}
+
+func (v *VaultHandlers) FavoriteItem(w http.ResponseWriter, r *http.Request) {
+	if r.Method != "PUT" {
+		httpError(w, http.StatusMethodNotAllowed, nil)
+		return
+	}
+
+	claims, ok := r.Context().Value("claims").(jwt.MapClaims)
+	if !ok {
+		data := map[string]string{"message": "An error occurred during token decryption."}
+		httpError(w, http.StatusInternalServerError, data)
+		return
+	}
+
+	userID, ok := claims["sub"].(float64)
+	if !ok {
+		data := map[string]string{"message": "Token does not contain a userID."}
+		httpError(w, http.StatusBadRequest, data)
+		return
+	}
+
+	vault, merr := v.vaultServices.GetUserVault(r.PathValue("vaultId"), int(userID))
+	if merr != nil {
+		data := map[string]string{"message": merr.Description}
+		httpError(w, merr.Code, data)
+		return
+	}
+
+	merr = v.vaultServices.CanWrite(vault)
+	if merr != nil {
+		data := map[string]string{"message": merr.Description}
+		httpError(w, merr.Code, data)
+		return
+	}
+
+	id, err := strconv.Atoi(r.PathValue("itemId"))
+	if err != nil {
+		httpError(w, http.StatusBadRequest, nil)
+		return
+	}
+
+	var favoriteRequest *password.FavoriteRequest
+	err = json.NewDecoder(r.Body).Decode(&favoriteRequest)
+	if err != nil {
+		httpError(w, http.StatusBadRequest, nil)
+		return
+	}
+
+	validate := validator.New()
+	err = validate.Struct(favoriteRequest)
+	if err != nil {
+		httpError(w, http.StatusBadRequest, nil)
+		return
+	}
+
+	password, merr := v.vaultServices.SetFavorite(id, vault, favoriteRequest.Favorite)
+	if merr != nil {
+		data := map[string]string{"message": merr.Description}
+		httpError(w, merr.Code, data)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+
+	response := models.Response{
+		Status:     http.StatusOK,
+		StatusText: http.StatusText(http.StatusOK),
+		Data:       password,
+	}
+
+	json.NewEncoder(w).Encode(response)
+}
+
+func (v *VaultHandlers) GetItemHistory(w http.ResponseWriter, r *http.Request) {
+	if r.Method != "GET" {
+		httpError(w, http.StatusMethodNotAllowed, nil)
+		return
+	}
+
+	claims, ok := r.Context().Value("claims").(jwt.MapClaims)
+	if !ok {
+		data := map[string]string{"message": "An error occurred during token decryption."}
+		httpError(w, http.StatusInternalServerError, data)
+		return
+	}
+
+	userID, ok := claims["sub"].(float64)
+	if !ok {
+		data := map[string]string{"message": "Token does not contain a userID."}
+		httpError(w, http.StatusBadRequest, data)
+		return
+	}
+
+	vault, merr := v.vaultServices.GetUserVault(r.PathValue("vaultId"), int(userID))
+	if merr != nil {
+		data := map[string]string{"message": merr.Description}
+		httpError(w, merr.Code, data)
+		return
+	}
+
+	id, err := strconv.Atoi(r.PathValue("itemId"))
+	if err != nil {
+		httpError(w, http.StatusBadRequest, nil)
+		return
+	}
+
+	history, merr := v.vaultServices.GetPasswordHistory(id, vault)
+	if merr != nil {
+		data := map[string]string{"message": merr.Description}
+		httpError(w, merr.Code, data)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+
+	response := models.Response{
+		Status:     http.StatusOK,
+		StatusText: http.StatusText(http.StatusOK),
+		Data:       history,
+	}
+
+	json.NewEncoder(w).Encode(response)
+}
+
+func (v *VaultHandlers) RestoreItemPassword(w http.ResponseWriter, r *http.Request) {
+	if r.Method != "POST" {
+		httpError(w, http.StatusMethodNotAllowed, nil)
+		return
+	}
+
+	claims, ok := r.Context().Value("claims").(jwt.MapClaims)
+	if !ok {
+		data := map[string]string{"message": "An error occurred during token decryption."}
+		httpError(w, http.StatusInternalServerError, data)
+		return
+	}
+
+	userID, ok := claims["sub"].(float64)
+	if !ok {
+		data := map[string]string{"message": "Token does not contain a userID."}
+		httpError(w, http.StatusBadRequest, data)
+		return
+	}
+
+	vault, merr := v.vaultServices.GetUserVault(r.PathValue("vaultId"), int(userID))
+	if merr != nil {
+		data := map[string]string{"message": merr.Description}
+		httpError(w, merr.Code, data)
+		return
+	}
+
+	merr = v.vaultServices.CanWrite(vault)
+	if merr != nil {
+		data := map[string]string{"message": merr.Description}
+		httpError(w, merr.Code, data)
+		return
+	}
+
+	id, err := strconv.Atoi(r.PathValue("itemId"))
+	if err != nil {
+		httpError(w, http.StatusBadRequest, nil)
+		return
+	}
+
+	historyID, err := strconv.Atoi(r.PathValue("historyId"))
+	if err != nil {
+		httpError(w, http.StatusBadRequest, nil)
+		return
+	}
+
+	password, merr := v.vaultServices.RestorePassword(id, historyID, vault)
+	if merr != nil {
+		data := map[string]string{"message": merr.Description}
+		httpError(w, merr.Code, data)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+
+	response := models.Response{
+		Status:     http.StatusOK,
+		StatusText: http.StatusText(http.StatusOK),
+		Data:       password,
+	}
+
+	json.NewEncoder(w).Encode(response)
+}
+
+func (v *VaultHandlers) GetTrash(w http.ResponseWriter, r *http.Request) {
+	if r.Method != "GET" {
+		httpError(w, http.StatusMethodNotAllowed, nil)
+		return
+	}
+
+	claims, ok := r.Context().Value("claims").(jwt.MapClaims)
+	if !ok {
+		data := map[string]string{"message": "An error occurred during token decryption."}
+		httpError(w, http.StatusInternalServerError, data)
+		return
+	}
+
+	userID, ok := claims["sub"].(float64)
+	if !ok {
+		data := map[string]string{"message": "Token does not contain a userID."}
+		httpError(w, http.StatusBadRequest, data)
+		return
+	}
+
+	vault, merr := v.vaultServices.GetUserVault(r.PathValue("vaultId"), int(userID))
+	if merr != nil {
+		data := map[string]string{"message": merr.Description}
+		httpError(w, merr.Code, data)
+		return
+	}
+
+	passwords, merr := v.vaultServices.GetTrash(vault)
+	if merr != nil {
+		data := map[string]string{"message": merr.Description}
+		httpError(w, merr.Code, data)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+
+	response := models.Response{
+		Status:     http.StatusOK,
+		StatusText: http.StatusText(http.StatusOK),
+		Data:       passwords,
+	}
+
+	json.NewEncoder(w).Encode(response)
+}
+
+func (v *VaultHandlers) RestoreTrashItem(w http.ResponseWriter, r *http.Request) {
+	if r.Method != "POST" {
+		httpError(w, http.StatusMethodNotAllowed, nil)
+		return
+	}
+
+	claims, ok := r.Context().Value("claims").(jwt.MapClaims)
+	if !ok {
+		data := map[string]string{"message": "An error occurred during token decryption."}
+		httpError(w, http.StatusInternalServerError, data)
+		return
+	}
+
+	userID, ok := claims["sub"].(float64)
+	if !ok {
+		data := map[string]string{"message": "Token does not contain a userID."}
+		httpError(w, http.StatusBadRequest, data)
+		return
+	}
+
+	vault, merr := v.vaultServices.GetUserVault(r.PathValue("vaultId"), int(userID))
+	if merr != nil {
+		data := map[string]string{"message": merr.Description}
+		httpError(w, merr.Code, data)
+		return
+	}
+
+	merr = v.vaultServices.CanWrite(vault)
+	if merr != nil {
+		data := map[string]string{"message": merr.Description}
+		httpError(w, merr.Code, data)
+		return
+	}
+
+	id, err := strconv.Atoi(r.PathValue("itemId"))
+	if err != nil {
+		httpError(w, http.StatusBadRequest, nil)
+		return
+	}
+
+	password, merr := v.vaultServices.RestoreTrashedPassword(id, vault)
+	if merr != nil {
+		data := map[string]string{"message": merr.Description}
+		httpError(w, merr.Code, data)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+
+	response := models.Response{
+		Status:     http.StatusOK,
+		StatusText: http.StatusText(http.StatusOK),
+		Data:       password,
+	}
+
+	json.NewEncoder(w).Encode(response)
+}
+
+func (v *VaultHandlers) DeleteTrashItem(w http.ResponseWriter, r *http.Request) {
+	if r.Method != "DELETE" {
+		httpError(w, http.StatusMethodNotAllowed, nil)
+		return
+	}
+
+	claims, ok := r.Context().Value("claims").(jwt.MapClaims)
+	if !ok {
+		data := map[string]string{"message": "An error occurred during token decryption."}
+		httpError(w, http.StatusInternalServerError, data)
+		return
+	}
+
+	userID, ok := claims["sub"].(float64)
+	if !ok {
+		data := map[string]string{"message": "Token does not contain a userID."}
+		httpError(w, http.StatusBadRequest, data)
+		return
+	}
+
+	vault, merr := v.vaultServices.GetUserVault(r.PathValue("vaultId"), int(userID))
+	if merr != nil {
+		data := map[string]string{"message": merr.Description}
+		httpError(w, merr.Code, data)
+		return
+	}
+
+	id, err := strconv.Atoi(r.PathValue("itemId"))
+	if err != nil {
+		httpError(w, http.StatusBadRequest, nil)
+		return
+	}
+
+	merr = v.vaultServices.DeletePasswordPermanently(id, vault)
+	if merr != nil {
+		data := map[string]string{"message": merr.Description}
+		httpError(w, merr.Code, data)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+
+	response := models.Response{
+		Status:     http.StatusOK,
+		StatusText: http.StatusText(http.StatusOK),
+		Data:       map[string]any{"id": id, "succeeded": true, "operation": "delete"},
+	}
+
+	json.NewEncoder(w).Encode(response)
+}
+
+// ImportItems creates the folders and items of an export from another
+// password manager, encrypted by the client, in one transaction.
+func (v *VaultHandlers) ImportItems(w http.ResponseWriter, r *http.Request) {
+	if r.Method != "POST" {
+		httpError(w, http.StatusMethodNotAllowed, nil)
+		return
+	}
+
+	claims, ok := r.Context().Value("claims").(jwt.MapClaims)
+	if !ok {
+		data := map[string]string{"message": "An error occurred during token decryption."}
+		httpError(w, http.StatusInternalServerError, data)
+		return
+	}
+
+	userID, ok := claims["sub"].(float64)
+	if !ok {
+		data := map[string]string{"message": "Token does not contain a userID."}
+		httpError(w, http.StatusBadRequest, data)
+		return
+	}
+
+	vault, merr := v.vaultServices.GetUserVault(r.PathValue("vaultId"), int(userID))
+	if merr != nil {
+		data := map[string]string{"message": merr.Description}
+		httpError(w, merr.Code, data)
+		return
+	}
+
+	merr = v.vaultServices.CanWrite(vault)
+	if merr != nil {
+		data := map[string]string{"message": merr.Description}
+		httpError(w, merr.Code, data)
+		return
+	}
+
+	var importRequest *password.ImportRequest
+	err := json.NewDecoder(r.Body).Decode(&importRequest)
+	if err != nil {
+		httpError(w, http.StatusBadRequest, nil)
+		return
+	}
+
+	validate := validator.New()
+	err = validate.Struct(importRequest)
+	if err != nil {
+		httpError(w, http.StatusBadRequest, nil)
+		return
+	}
+
+	imported, merr := v.vaultServices.ImportPasswords(vault, importRequest)
+	if merr != nil {
+		data := map[string]string{"message": merr.Description}
+		httpError(w, merr.Code, data)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+
+	response := models.Response{
+		Status:     http.StatusCreated,
+		StatusText: http.StatusText(http.StatusCreated),
+		Data:       imported,
+	}
+
+	json.NewEncoder(w).Encode(response)
+}
+
+// passwordFilter reads the password list filters from the query string.
+func passwordFilter(r *http.Request) (*password.PasswordFilter, error) {
+	query := r.URL.Query()
+
+	filter := &password.PasswordFilter{
+		Type:     query.Get("type"),
+		Folder:   query.Get("folder"),
+		Tag:      query.Get("tag"),
+		Favorite: query.Get("favorite") == "true",
+	}
+
+	validate := validator.New()
+	err := validate.Struct(filter)
+	if err != nil {
+		return nil, err
+	}
+
+	return filter, nil
+}
+
+func vaultResponses(vaults []*models.Vault) []*vaultdto.VaultResponse {
+	responses := make([]*vaultdto.VaultResponse, 0, len(vaults))
+	for _, vault := range vaults {
+		responses = append(responses, vaultdto.NewVaultResponse(vault))
+	}
+
+	return responses
+}
+
+// errorData is the response data of a service error. Policy violations and
+// the current copy of a conflicting item are returned next to the message.
+func errorData(merr *models.Error) any {
+	if merr.Current != nil {
+		return map[string]any{"message": merr.Description, "current": merr.Current}
+	}
+
+	if len(merr.Violations) == 0 {
+		return map[string]string{"message": merr.Description}
+	}
+
+	return map[string]any{"message": merr.Description, "violations": merr.Violations}
+}
+
+// itemETag is the ETag of an item: its revision.
+func itemETag(pw *models.Password) string {
+	return `"` + strconv.FormatInt(pw.Revision, 10) + `"`
+}
+
+// ifMatchRevision takes the revision an update is based on from the If-Match
+// header. When the header is sent it replaces the revision in the body; "*"
+// matches any revision.
+func ifMatchRevision(r *http.Request, passwordRequest *password.CreatePasswordRequest) error {
+	ifMatch := strings.TrimSpace(r.Header.Get("If-Match"))
+	if ifMatch == "" {
+		return nil
+	}
+
+	if ifMatch == "*" {
+		passwordRequest.Revision = nil
+		return nil
+	}
+
+	revision, err := strconv.ParseInt(strings.Trim(strings.TrimPrefix(ifMatch, "W/"), `"`), 10, 64)
+	if err != nil {
+		return err
+	}
+
+	passwordRequest.Revision = &revision
+	return nil
+}
@@ -3,26 +3,31 @@ package middlewares
 import (
 	"context"
 	"crypto/ecdsa"
+	"crypto/subtle"
 	"encoding/json"
 	"fmt"
 	"net/http"
+	"strconv"
 	"strings"
 
 	"github.com/golang-jwt/jwt/v5"
 	"github.com/safepass/server/internal/config"
 	"github.com/safepass/server/internal/logging"
+	"github.com/safepass/server/internal/repositories"
 	"github.com/safepass/server/pkg/models"
 )
 
 type AuthMiddleware struct {
-	logger *logging.Logger
-	config config.Config
+	logger         *logging.Logger
+	userRepository *repositories.UserRepository
+	config         config.Config
 }
 
-func NewAuthMiddleware(logger *logging.Logger, config config.Config) *AuthMiddleware {
+func NewAuthMiddleware(logger *logging.Logger, userRepository *repositories.UserRepository, config config.Config) *AuthMiddleware {
 	return &AuthMiddleware{
-		logger: logger,
-		config: config,
+		logger:         logger,
+		userRepository: userRepository,
+		config:         config,
 	}
 }
 
@@ -65,15 +70,43 @@ func (m *AuthMiddleware) AuthMiddlewareFunc(next http.Handler) http.Handler {
 			return
 		}
 
+		// Sessions are revoked by changing the user's security stamp, for
+		// example when an emergency contact takes the account over.
+		if !m.sessionValid(claims) {
+			httpError(w, "Session has been revoked", http.StatusUnauthorized)
+			return
+		}
+
 		ctx := context.WithValue(r.Context(), "claims", claims)
 		next.ServeHTTP(w, r.WithContext(ctx))
 	})
 }
 
+func (m *AuthMiddleware) sessionValid(claims jwt.MapClaims) bool {
+	userID, ok := claims["sub"].(float64)
+	if !ok {
+		return false
+	}
+
+	stamp, ok := claims["stamp"].(string)
+	if !ok {
+		return false
+	}
+
+	user, merr := m.userRepository.GetUserByID(strconv.Itoa(int(userID)))
+	if merr != nil {
+		return false
+	}
+
+	return subtle.ConstantTimeCompare([]byte(stamp), []byte(user.SecurityStamp)) == 1
+}
+
 func validateToken(tokenString string, secretKey *ecdsa.PrivateKey) (jwt.MapClaims, error) {
+	// Only session tokens are accepted here, tokens issued for other purposes
+	// such as email verification carry a different audience.
 	token, err := jwt.Parse(tokenString, func(token *jwt.Token) (interface{}, error) {
 		return &secretKey.PublicKey, nil
-	})
+	}, jwt.WithAudience("safepass-mobile"))
 
 	fmt.Println("Girdi0")
 
@@ -1,7 +1,9 @@
 package middlewares
 
 import (
+	"bufio"
 	"fmt"
+	"net"
 	"net/http"
 	"time"
 
@@ -22,6 +24,24 @@ func (w *ResponseCaptureWriter) WriteHeader(code int) {
 	w.ResponseWriter.WriteHeader(code)
 }
 
+// Flush and Hijack pass through to the wrapped writer, for event streams and
+// WebSocket upgrades.
+func (w *ResponseCaptureWriter) Flush() {
+	http.NewResponseController(w.ResponseWriter).Flush()
+}
+
+func (w *ResponseCaptureWriter) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	if w.statusCode == 0 {
+		w.statusCode = http.StatusSwitchingProtocols
+	}
+
+	return http.NewResponseController(w.ResponseWriter).Hijack()
+}
+
+func (w *ResponseCaptureWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
+
 func NewLogMiddleware(logger *logging.Logger) *LogMiddleware {
 	return &LogMiddleware{
 		logger: logger,
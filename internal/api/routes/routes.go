@@ -11,18 +11,50 @@ type Router struct {
 	authMiddleware *middlewares.AuthMiddleware
 
 	authHandlers  *handlers.AuthHandlers
+	userHandlers  *handlers.UserHandlers
 	vaultHandlers *handlers.VaultHandlers
+
+	emergencyAccessHandlers *handlers.EmergencyAccessHandlers
+	organizationHandlers    *handlers.OrganizationHandlers
+	folderHandlers          *handlers.FolderHandlers
+	tagHandlers             *handlers.TagHandlers
+	attachmentHandlers      *handlers.AttachmentHandlers
+	syncHandlers            *handlers.SyncHandlers
+	notificationHandlers    *handlers.NotificationHandlers
+	exportHandlers          *handlers.ExportHandlers
+	sendHandlers            *handlers.SendHandlers
 }
 
 func NewRouter(
 	autMiddleware *middlewares.AuthMiddleware,
 	authHandlers *handlers.AuthHandlers,
+	userHandlers *handlers.UserHandlers,
 	vaultHandlers *handlers.VaultHandlers,
+	emergencyAccessHandlers *handlers.EmergencyAccessHandlers,
+	organizationHandlers *handlers.OrganizationHandlers,
+	folderHandlers *handlers.FolderHandlers,
+	tagHandlers *handlers.TagHandlers,
+	attachmentHandlers *handlers.AttachmentHandlers,
+	syncHandlers *handlers.SyncHandlers,
+	notificationHandlers *handlers.NotificationHandlers,
+	exportHandlers *handlers.ExportHandlers,
+	sendHandlers *handlers.SendHandlers,
 ) *Router {
 	return &Router{
 		authMiddleware: autMiddleware,
 		authHandlers:   authHandlers,
+		userHandlers:   userHandlers,
 		vaultHandlers:  vaultHandlers,
+
+		emergencyAccessHandlers: emergencyAccessHandlers,
+		organizationHandlers:    organizationHandlers,
+		folderHandlers:          folderHandlers,
+		tagHandlers:             tagHandlers,
+		attachmentHandlers:      attachmentHandlers,
+		syncHandlers:            syncHandlers,
+		notificationHandlers:    notificationHandlers,
+		exportHandlers:          exportHandlers,
+		sendHandlers:            sendHandlers,
 	}
 }
 
@@ -31,14 +63,86 @@ func (r *Router) NewServer() *http.ServeMux {
 
 	mux.HandleFunc("/api/v1/auth/login", r.authHandlers.Login)
 	mux.HandleFunc("/api/v1/auth/register", r.authHandlers.Register)
+	mux.HandleFunc("/api/v1/auth/verify-email", r.authHandlers.VerifyEmail)
+	mux.HandleFunc("/api/v1/auth/password-hint", r.authHandlers.PasswordHint)
+	mux.Handle("/api/v1/auth/verify-email/resend", r.authMiddleware.AuthMiddlewareFunc(http.HandlerFunc(r.authHandlers.ResendVerificationEmail)))
+
+	mux.Handle("/api/v1/users/@me", r.authMiddleware.AuthMiddlewareFunc(http.HandlerFunc(r.userHandlers.Me)))
+	mux.Handle("/api/v1/users/@me/keys", r.authMiddleware.AuthMiddlewareFunc(http.HandlerFunc(r.userHandlers.Keys)))
+	mux.Handle("/api/v1/users/public-key", r.authMiddleware.AuthMiddlewareFunc(http.HandlerFunc(r.userHandlers.GetPublicKey)))
 
 	mux.Handle("/api/v1/vault/@me", r.authMiddleware.AuthMiddlewareFunc(http.HandlerFunc(r.vaultHandlers.GetVault)))
 
+	mux.Handle("/api/v1/vault/export", r.authMiddleware.AuthMiddlewareFunc(http.HandlerFunc(r.exportHandlers.Export)))
+	mux.Handle("/api/v1/vault/import", r.authMiddleware.AuthMiddlewareFunc(http.HandlerFunc(r.exportHandlers.Import)))
+
 	mux.Handle("/api/v1/vault/passwords", r.authMiddleware.AuthMiddlewareFunc(http.HandlerFunc(r.vaultHandlers.GetPasswords)))
 	mux.Handle("/api/v1/vault/password", r.authMiddleware.AuthMiddlewareFunc(http.HandlerFunc(r.vaultHandlers.GetPassword)))
 	mux.Handle("/api/v1/vault/password/create", r.authMiddleware.AuthMiddlewareFunc(http.HandlerFunc(r.vaultHandlers.CreatePassword)))
 	mux.Handle("/api/v1/vault/password/update/", r.authMiddleware.AuthMiddlewareFunc(http.HandlerFunc(r.vaultHandlers.UpdatePassword)))
 	mux.Handle("/api/v1/vault/password/delete/", r.authMiddleware.AuthMiddlewareFunc(http.HandlerFunc(r.vaultHandlers.DeletePassword)))
+	mux.Handle("/api/v1/vault/passwords/{id}/shares", r.authMiddleware.AuthMiddlewareFunc(http.HandlerFunc(r.vaultHandlers.Shares)))
+	mux.Handle("/api/v1/vault/passwords/{id}/shares/{shareId}", r.authMiddleware.AuthMiddlewareFunc(http.HandlerFunc(r.vaultHandlers.RevokeShare)))
+
+	mux.Handle("/api/v1/vaults", r.authMiddleware.AuthMiddlewareFunc(http.HandlerFunc(r.vaultHandlers.Vaults)))
+	mux.Handle("/api/v1/vaults/{vaultId}", r.authMiddleware.AuthMiddlewareFunc(http.HandlerFunc(r.vaultHandlers.Vault)))
+	mux.Handle("/api/v1/vaults/{vaultId}/items", r.authMiddleware.AuthMiddlewareFunc(http.HandlerFunc(r.vaultHandlers.Items)))
+	mux.Handle("/api/v1/vaults/{vaultId}/items/{itemId}", r.authMiddleware.AuthMiddlewareFunc(http.HandlerFunc(r.vaultHandlers.Item)))
+	mux.Handle("/api/v1/vaults/{vaultId}/items/{itemId}/folder", r.authMiddleware.AuthMiddlewareFunc(http.HandlerFunc(r.vaultHandlers.MoveItem)))
+	mux.Handle("/api/v1/vaults/{vaultId}/items/move", r.authMiddleware.AuthMiddlewareFunc(http.HandlerFunc(r.vaultHandlers.MoveItems)))
+	mux.Handle("/api/v1/vaults/{vaultId}/items/import", r.authMiddleware.AuthMiddlewareFunc(http.HandlerFunc(r.vaultHandlers.ImportItems)))
+	mux.Handle("/api/v1/vaults/{vaultId}/items/{itemId}/favorite", r.authMiddleware.AuthMiddlewareFunc(http.HandlerFunc(r.vaultHandlers.FavoriteItem)))
+	mux.Handle("/api/v1/vaults/{vaultId}/items/tag", r.authMiddleware.AuthMiddlewareFunc(http.HandlerFunc(r.vaultHandlers.TagItems)))
+	mux.Handle("/api/v1/vaults/{vaultId}/items/untag", r.authMiddleware.AuthMiddlewareFunc(http.HandlerFunc(r.vaultHandlers.UntagItems)))
+	mux.Handle("/api/v1/vaults/{vaultId}/trash", r.authMiddleware.AuthMiddlewareFunc(http.HandlerFunc(r.vaultHandlers.GetTrash)))
+	mux.Handle("/api/v1/vaults/{vaultId}/trash/{itemId}", r.authMiddleware.AuthMiddlewareFunc(http.HandlerFunc(r.vaultHandlers.DeleteTrashItem)))
+	mux.Handle("/api/v1/vaults/{vaultId}/trash/{itemId}/restore", r.authMiddleware.AuthMiddlewareFunc(http.HandlerFunc(r.vaultHandlers.RestoreTrashItem)))
+	mux.Handle("/api/v1/vaults/{vaultId}/items/{itemId}/history", r.authMiddleware.AuthMiddlewareFunc(http.HandlerFunc(r.vaultHandlers.GetItemHistory)))
+	mux.Handle("/api/v1/vaults/{vaultId}/items/{itemId}/history/{historyId}/restore", r.authMiddleware.AuthMiddlewareFunc(http.HandlerFunc(r.vaultHandlers.RestoreItemPassword)))
+	mux.Handle("/api/v1/vaults/{vaultId}/items/{itemId}/attachments", r.authMiddleware.AuthMiddlewareFunc(http.HandlerFunc(r.attachmentHandlers.Attachments)))
+	mux.Handle("/api/v1/vaults/{vaultId}/items/{itemId}/attachments/{attachmentId}", r.authMiddleware.AuthMiddlewareFunc(http.HandlerFunc(r.attachmentHandlers.Attachment)))
+
+	mux.Handle("/api/v1/sync", r.authMiddleware.AuthMiddlewareFunc(http.HandlerFunc(r.syncHandlers.Sync)))
+	mux.Handle("/api/v1/sync/batch", r.authMiddleware.AuthMiddlewareFunc(http.HandlerFunc(r.syncHandlers.Batch)))
+	mux.Handle("/api/v1/notifications/stream", r.authMiddleware.AuthMiddlewareFunc(http.HandlerFunc(r.notificationHandlers.Stream)))
+	mux.Handle("/api/v1/notifications/ws", r.authMiddleware.AuthMiddlewareFunc(http.HandlerFunc(r.notificationHandlers.WebSocket)))
+	mux.Handle("/api/v1/folders", r.authMiddleware.AuthMiddlewareFunc(http.HandlerFunc(r.folderHandlers.Folders)))
+	mux.Handle("/api/v1/folders/{folderId}", r.authMiddleware.AuthMiddlewareFunc(http.HandlerFunc(r.folderHandlers.Folder)))
+	mux.Handle("/api/v1/tags", r.authMiddleware.AuthMiddlewareFunc(http.HandlerFunc(r.tagHandlers.Tags)))
+	mux.Handle("/api/v1/tags/{tagId}", r.authMiddleware.AuthMiddlewareFunc(http.HandlerFunc(r.tagHandlers.Tag)))
+
+	mux.Handle("/api/v1/sends", r.authMiddleware.AuthMiddlewareFunc(http.HandlerFunc(r.sendHandlers.Sends)))
+	mux.Handle("/api/v1/sends/file", r.authMiddleware.AuthMiddlewareFunc(http.HandlerFunc(r.sendHandlers.CreateFileSend)))
+	mux.Handle("/api/v1/sends/{sendId}", r.authMiddleware.AuthMiddlewareFunc(http.HandlerFunc(r.sendHandlers.Send)))
+	mux.HandleFunc("/api/v1/sends/access/{accessId}", r.sendHandlers.AccessSend)
+	mux.HandleFunc("/api/v1/sends/access/{accessId}/file", r.sendHandlers.DownloadSendFile)
+
+	mux.Handle("/api/v1/emergency-access/trusted", r.authMiddleware.AuthMiddlewareFunc(http.HandlerFunc(r.emergencyAccessHandlers.GetTrustedContacts)))
+	mux.Handle("/api/v1/emergency-access/granted", r.authMiddleware.AuthMiddlewareFunc(http.HandlerFunc(r.emergencyAccessHandlers.GetGrantedAccesses)))
+	mux.Handle("/api/v1/emergency-access/invite", r.authMiddleware.AuthMiddlewareFunc(http.HandlerFunc(r.emergencyAccessHandlers.Invite)))
+	mux.Handle("/api/v1/emergency-access/{id}", r.authMiddleware.AuthMiddlewareFunc(http.HandlerFunc(r.emergencyAccessHandlers.Delete)))
+	mux.Handle("/api/v1/emergency-access/{id}/accept", r.authMiddleware.AuthMiddlewareFunc(http.HandlerFunc(r.emergencyAccessHandlers.Accept)))
+	mux.Handle("/api/v1/emergency-access/{id}/confirm", r.authMiddleware.AuthMiddlewareFunc(http.HandlerFunc(r.emergencyAccessHandlers.Confirm)))
+	mux.Handle("/api/v1/emergency-access/{id}/initiate", r.authMiddleware.AuthMiddlewareFunc(http.HandlerFunc(r.emergencyAccessHandlers.InitiateRecovery)))
+	mux.Handle("/api/v1/emergency-access/{id}/approve", r.authMiddleware.AuthMiddlewareFunc(http.HandlerFunc(r.emergencyAccessHandlers.ApproveRecovery)))
+	mux.Handle("/api/v1/emergency-access/{id}/reject", r.authMiddleware.AuthMiddlewareFunc(http.HandlerFunc(r.emergencyAccessHandlers.RejectRecovery)))
+	mux.Handle("/api/v1/emergency-access/{id}/view", r.authMiddleware.AuthMiddlewareFunc(http.HandlerFunc(r.emergencyAccessHandlers.View)))
+	mux.Handle("/api/v1/emergency-access/{id}/takeover", r.authMiddleware.AuthMiddlewareFunc(http.HandlerFunc(r.emergencyAccessHandlers.Takeover)))
+
+	mux.Handle("/api/v1/organizations", r.authMiddleware.AuthMiddlewareFunc(http.HandlerFunc(r.organizationHandlers.Organizations)))
+	mux.Handle("/api/v1/organizations/{orgId}", r.authMiddleware.AuthMiddlewareFunc(http.HandlerFunc(r.organizationHandlers.Organization)))
+	mux.Handle("/api/v1/organizations/{orgId}/members", r.authMiddleware.AuthMiddlewareFunc(http.HandlerFunc(r.organizationHandlers.GetMembers)))
+	mux.Handle("/api/v1/organizations/{orgId}/members/invite", r.authMiddleware.AuthMiddlewareFunc(http.HandlerFunc(r.organizationHandlers.InviteMember)))
+	mux.Handle("/api/v1/organizations/{orgId}/members/accept", r.authMiddleware.AuthMiddlewareFunc(http.HandlerFunc(r.organizationHandlers.AcceptInvite)))
+	mux.Handle("/api/v1/organizations/{orgId}/members/{memberId}", r.authMiddleware.AuthMiddlewareFunc(http.HandlerFunc(r.organizationHandlers.Member)))
+	mux.Handle("/api/v1/organizations/{orgId}/members/{memberId}/confirm", r.authMiddleware.AuthMiddlewareFunc(http.HandlerFunc(r.organizationHandlers.ConfirmMember)))
+	mux.Handle("/api/v1/organizations/{orgId}/collections", r.authMiddleware.AuthMiddlewareFunc(http.HandlerFunc(r.organizationHandlers.Collections)))
+	mux.Handle("/api/v1/organizations/{orgId}/collections/{collectionId}", r.authMiddleware.AuthMiddlewareFunc(http.HandlerFunc(r.organizationHandlers.Collection)))
+	mux.Handle("/api/v1/organizations/{orgId}/collections/{collectionId}/items", r.authMiddleware.AuthMiddlewareFunc(http.HandlerFunc(r.organizationHandlers.CollectionItems)))
+	mux.Handle("/api/v1/organizations/{orgId}/collections/{collectionId}/items/{itemId}", r.authMiddleware.AuthMiddlewareFunc(http.HandlerFunc(r.organizationHandlers.CollectionItem)))
+	mux.Handle("/api/v1/organizations/{orgId}/items", r.authMiddleware.AuthMiddlewareFunc(http.HandlerFunc(r.organizationHandlers.GetItems)))
+	mux.Handle("/api/v1/organizations/{orgId}/policies", r.authMiddleware.AuthMiddlewareFunc(http.HandlerFunc(r.organizationHandlers.GetPolicies)))
+	mux.Handle("/api/v1/organizations/{orgId}/policies/{type}", r.authMiddleware.AuthMiddlewareFunc(http.HandlerFunc(r.organizationHandlers.SetPolicy)))
 
 	return mux
 }
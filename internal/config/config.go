@@ -15,6 +15,9 @@ type ServerConfig struct {
 	Host  string
 	Port  int
 	Debug bool
+	// PublicURL is the address clients reach the server at. It is used to
+	// build links that are sent by email.
+	PublicURL string `yaml:"public_url"`
 }
 
 type JWTConfig struct {
@@ -29,10 +32,106 @@ type LogConfig struct {
 	Output string
 }
 
+type AccountConfig struct {
+	// DeletionGracePeriod is the number of hours a scheduled account
+	// deletion waits before it is carried out.
+	DeletionGracePeriod int `yaml:"deletion_grace_period"`
+	// RequireVerifiedEmail blocks vault writes until the email is verified.
+	RequireVerifiedEmail bool `yaml:"require_verified_email"`
+	// VerificationTokenTTL is the number of hours an email verification
+	// link stays valid.
+	VerificationTokenTTL int `yaml:"verification_token_ttl"`
+	// PasswordHintRateLimit is the number of hint emails that may be
+	// requested for one address within PasswordHintRateWindow seconds.
+	PasswordHintRateLimit  int    `yaml:"password_hint_rate_limit"`
+	PasswordHintRateWindow int    `yaml:"password_hint_rate_window"`
+	HintEncryptionKey      string `yaml:"-"`
+}
+
+type EmergencyAccessConfig struct {
+	// DefaultWaitTimeDays is used when an invite does not specify how long
+	// a recovery request waits before it is approved automatically.
+	DefaultWaitTimeDays int `yaml:"default_wait_time_days"`
+}
+
+type MailConfig struct {
+	// Driver is either "smtp" or "log".
+	Driver   string `yaml:"driver"`
+	From     string `yaml:"from"`
+	Host     string `yaml:"host"`
+	Port     int    `yaml:"port"`
+	Username string `yaml:"username"`
+	Password string `yaml:"-"`
+	LogFile  string `yaml:"log_file"`
+}
+
+type VaultConfig struct {
+	// PasswordHistoryLimit is the number of previous passwords kept per
+	// item. Zero turns the history off.
+	PasswordHistoryLimit int `yaml:"password_history_limit"`
+	// TrashRetentionDays is the number of days deleted items stay in the
+	// trash before they are purged. Zero keeps them until they are deleted
+	// from the trash.
+	TrashRetentionDays int `yaml:"trash_retention_days"`
+	// TombstoneRetentionDays is the number of days deletions are kept for
+	// delta sync. Clients that have not synced for longer get a full sync.
+	TombstoneRetentionDays int `yaml:"tombstone_retention_days"`
+	// ExportMacKey keys the integrity MAC of vault exports. It is required
+	// and independent of the JWT key, so rotating that key does not
+	// invalidate exports.
+	ExportMacKey string `yaml:"-"`
+}
+
+type NotificationsConfig struct {
+	// HeartbeatSeconds is how often an idle notification stream is sent a
+	// heartbeat, so proxies do not close it.
+	HeartbeatSeconds int `yaml:"heartbeat_seconds"`
+	// HistorySize is the number of recent events kept per user, replayed to
+	// devices that reconnect.
+	HistorySize int `yaml:"history_size"`
+	// HistoryIdleMinutes is how long the events of a user without
+	// connected devices are kept after the last one.
+	HistoryIdleMinutes int `yaml:"history_idle_minutes"`
+}
+
+type AttachmentsConfig struct {
+	// Driver selects the blob store attachments are kept in. Only "local"
+	// is available.
+	Driver string `yaml:"driver"`
+	// Path is the directory of the local blob store.
+	Path string `yaml:"path"`
+	// MaxSize is the largest attachment in bytes.
+	MaxSize int64 `yaml:"max_size"`
+	// UserQuota is the total size in bytes of the attachments and Send
+	// files a user can store.
+	UserQuota int64 `yaml:"user_quota"`
+}
+
+type SendsConfig struct {
+	// MaxFileSize is the largest file a Send can hold, in bytes.
+	MaxFileSize int64 `yaml:"max_file_size"`
+	// MaxDeletionDays is how many days ahead the deletion date of a Send
+	// can be.
+	MaxDeletionDays int `yaml:"max_deletion_days"`
+	// AccessRateLimit is the number of times one client may access one
+	// Send within AccessRateWindow seconds, which slows down guessing its
+	// password.
+	AccessRateLimit  int `yaml:"access_rate_limit"`
+	AccessRateWindow int `yaml:"access_rate_window"`
+}
+
 type Config struct {
 	Server    ServerConfig
 	JWT       JWTConfig
 	LogConfig LogConfig
+	Account   AccountConfig
+	Mail      MailConfig
+
+	EmergencyAccess EmergencyAccessConfig `yaml:"emergency_access"`
+	Attachments     AttachmentsConfig     `yaml:"attachments"`
+	Vault           VaultConfig           `yaml:"vault"`
+	Notifications   NotificationsConfig   `yaml:"notifications"`
+	Sends           SendsConfig           `yaml:"sends"`
 }
 
 // LoadConfig loads the configuration values from the environment variables
@@ -51,6 +150,9 @@ func LoadConfig(appConfig *Config) {
 	}
 
 	appConfig.JWT.SecretKey = os.Getenv("JWT_SECRET_KEY")
+	appConfig.Mail.Password = os.Getenv("SMTP_PASSWORD")
+	appConfig.Account.HintEncryptionKey = os.Getenv("HINT_ENCRYPTION_KEY")
+	appConfig.Vault.ExportMacKey = os.Getenv("EXPORT_MAC_KEY")
 }
 
 func (c *Config) GetJWTSecretKey() (*ecdsa.PrivateKey, error) {
@@ -66,3 +168,41 @@ func (c *Config) GetJWTSecretKey() (*ecdsa.PrivateKey, error) {
 
 	return privateKey, nil
 }
+
+// GetHintEncryptionKey returns the AES-256 key master password hints are
+// encrypted with, or nil when hints are stored in plaintext.
+func (c *Config) GetHintEncryptionKey() ([]byte, error) {
+	if c.Account.HintEncryptionKey == "" {
+		return nil, nil
+	}
+
+	key, err := base64.StdEncoding.DecodeString(c.Account.HintEncryptionKey)
+	if err != nil {
+		return nil, fmt.Errorf("500: Error decoding hint encryption key")
+	}
+
+	if len(key) != 32 {
+		return nil, fmt.Errorf("500: Hint encryption key must be 32 bytes")
+	}
+
+	return key, nil
+}
+
+// GetExportMacKey returns the key vault exports are authenticated with, or an
+// error when EXPORT_MAC_KEY is missing or too short.
+func (c *Config) GetExportMacKey() ([]byte, error) {
+	if c.Vault.ExportMacKey == "" {
+		return nil, fmt.Errorf("500: EXPORT_MAC_KEY is not set")
+	}
+
+	key, err := base64.StdEncoding.DecodeString(c.Vault.ExportMacKey)
+	if err != nil {
+		return nil, fmt.Errorf("500: Error decoding export MAC key")
+	}
+
+	if len(key) < 32 {
+		return nil, fmt.Errorf("500: Export MAC key must be at least 32 bytes")
+	}
+
+	return key, nil
+}
@@ -7,3 +7,191 @@ var Roles = struct {
 	ADMIN: 1,
 	USER:  2,
 }
+
+var EmergencyAccessTypes = struct {
+	VIEW     string
+	TAKEOVER string
+}{
+	VIEW:     "view",
+	TAKEOVER: "takeover",
+}
+
+// EmergencyAccessStatuses are the states of an emergency access grant:
+// invited -> accepted -> confirmed -> recovery_initiated -> recovery_approved.
+// A rejected recovery goes back to confirmed. A takeover ends the grant in
+// recovery_completed.
+var EmergencyAccessStatuses = struct {
+	INVITED            string
+	ACCEPTED           string
+	CONFIRMED          string
+	RECOVERY_INITIATED string
+	RECOVERY_APPROVED  string
+	RECOVERY_COMPLETED string
+}{
+	INVITED:            "invited",
+	ACCEPTED:           "accepted",
+	CONFIRMED:          "confirmed",
+	RECOVERY_INITIATED: "recovery_initiated",
+	RECOVERY_APPROVED:  "recovery_approved",
+	RECOVERY_COMPLETED: "recovery_completed",
+}
+
+var SharePermissions = struct {
+	READ  string
+	WRITE string
+}{
+	READ:  "read",
+	WRITE: "write",
+}
+
+// OrganizationRoles are ordered from most to least privileged.
+var OrganizationRoles = struct {
+	OWNER     string
+	ADMIN     string
+	MEMBER    string
+	READ_ONLY string
+}{
+	OWNER:     "owner",
+	ADMIN:     "admin",
+	MEMBER:    "member",
+	READ_ONLY: "read_only",
+}
+
+// OrganizationMemberStatuses: invited -> accepted -> confirmed. A member is
+// confirmed once an admin has wrapped the organization key for them.
+var OrganizationMemberStatuses = struct {
+	INVITED   string
+	ACCEPTED  string
+	CONFIRMED string
+}{
+	INVITED:   "invited",
+	ACCEPTED:  "accepted",
+	CONFIRMED: "confirmed",
+}
+
+// PolicyTypes are the rules an organization can enforce on its members.
+var PolicyTypes = struct {
+	REQUIRE_TWO_FACTOR       string
+	MIN_HASH_ITERATIONS      string
+	DISABLE_PERSONAL_EXPORT  string
+	MAX_SESSION_TIMEOUT      string
+	DISABLE_EXTERNAL_SHARING string
+}{
+	REQUIRE_TWO_FACTOR:       "require_two_factor",
+	MIN_HASH_ITERATIONS:      "min_hash_iterations",
+	DISABLE_PERSONAL_EXPORT:  "disable_personal_export",
+	MAX_SESSION_TIMEOUT:      "max_session_timeout",
+	DISABLE_EXTERNAL_SHARING: "disable_external_sharing",
+}
+
+// ItemTypes are the kinds of vault items. Every type but login carries its
+// encrypted fields in a payload of its own.
+var ItemTypes = struct {
+	LOGIN          string
+	SECURE_NOTE    string
+	CARD           string
+	IDENTITY       string
+	SSH_KEY        string
+	API_CREDENTIAL string
+}{
+	LOGIN:          "login",
+	SECURE_NOTE:    "secure_note",
+	CARD:           "card",
+	IDENTITY:       "identity",
+	SSH_KEY:        "ssh_key",
+	API_CREDENTIAL: "api_credential",
+}
+
+// CustomFieldTypes are the kinds of custom fields on vault items. Linked
+// fields carry no value of their own but point at another field of the item.
+var CustomFieldTypes = struct {
+	TEXT    string
+	HIDDEN  string
+	BOOLEAN string
+	LINKED  string
+}{
+	TEXT:    "text",
+	HIDDEN:  "hidden",
+	BOOLEAN: "boolean",
+	LINKED:  "linked",
+}
+
+// UriMatchTypes are the ways a login URI is matched against the page a client
+// autofills. An empty match leaves the choice to the client's default.
+var UriMatchTypes = struct {
+	BASE_DOMAIN string
+	HOST        string
+	STARTS_WITH string
+	EXACT       string
+	REGEX       string
+	NEVER       string
+}{
+	BASE_DOMAIN: "base_domain",
+	HOST:        "host",
+	STARTS_WITH: "starts_with",
+	EXACT:       "exact",
+	REGEX:       "regex",
+	NEVER:       "never",
+}
+
+// VaultEventTypes are the kinds of change notifications pushed to a user's
+// devices. RESYNC tells a device that it missed events and has to sync.
+var VaultEventTypes = struct {
+	VAULT_CREATED string
+	VAULT_UPDATED string
+	VAULT_DELETED string
+	ITEM_CREATED  string
+	ITEM_UPDATED  string
+	ITEM_TRASHED  string
+	ITEM_RESTORED string
+	ITEM_DELETED  string
+	SHARE_CREATED string
+	SHARE_REVOKED string
+	RESYNC        string
+	HEARTBEAT     string
+}{
+	VAULT_CREATED: "vault_created",
+	VAULT_UPDATED: "vault_updated",
+	VAULT_DELETED: "vault_deleted",
+	ITEM_CREATED:  "item_created",
+	ITEM_UPDATED:  "item_updated",
+	ITEM_TRASHED:  "item_trashed",
+	ITEM_RESTORED: "item_restored",
+	ITEM_DELETED:  "item_deleted",
+	SHARE_CREATED: "share_created",
+	SHARE_REVOKED: "share_revoked",
+	RESYNC:        "resync",
+	HEARTBEAT:     "heartbeat",
+}
+
+// BatchActions are the operations of a sync batch.
+var BatchActions = struct {
+	CREATE string
+	UPDATE string
+	DELETE string
+}{
+	CREATE: "create",
+	UPDATE: "update",
+	DELETE: "delete",
+}
+
+// BatchStatuses are the outcomes of the operations of a sync batch. A
+// duplicate is a create whose item the server already has.
+var BatchStatuses = struct {
+	APPLIED   string
+	DUPLICATE string
+	CONFLICT  string
+}{
+	APPLIED:   "applied",
+	DUPLICATE: "duplicate",
+	CONFLICT:  "conflict",
+}
+
+// SendTypes are the kinds of content a Send holds.
+var SendTypes = struct {
+	TEXT string
+	FILE string
+}{
+	TEXT: "text",
+	FILE: "file",
+}
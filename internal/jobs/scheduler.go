@@ -0,0 +1,85 @@
+package jobs
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/safepass/server/internal/logging"
+)
+
+// Job is a unit of background work that is run periodically.
+type Job struct {
+	Name     string
+	Interval time.Duration
+	Run      func() error
+}
+
+// Scheduler runs registered jobs on their own interval until stopped.
+type Scheduler struct {
+	logger *logging.Logger
+	jobs   []Job
+
+	stop chan struct{}
+	wg   sync.WaitGroup
+}
+
+func NewScheduler(logger *logging.Logger) *Scheduler {
+	return &Scheduler{
+		logger: logger,
+		stop:   make(chan struct{}),
+	}
+}
+
+// Register adds a job. Jobs must be registered before Start is called.
+func (s *Scheduler) Register(name string, interval time.Duration, run func() error) {
+	s.jobs = append(s.jobs, Job{
+		Name:     name,
+		Interval: interval,
+		Run:      run,
+	})
+}
+
+// Start runs every job once and then on each tick of its interval.
+func (s *Scheduler) Start() {
+	for _, job := range s.jobs {
+		s.wg.Add(1)
+		go s.loop(job)
+	}
+}
+
+// Stop signals all jobs to finish and waits for the running ones.
+func (s *Scheduler) Stop() {
+	close(s.stop)
+	s.wg.Wait()
+}
+
+func (s *Scheduler) loop(job Job) {
+	defer s.wg.Done()
+
+	ticker := time.NewTicker(job.Interval)
+	defer ticker.Stop()
+
+	s.run(job)
+	for {
+		select {
+		case <-ticker.C:
+			s.run(job)
+		case <-s.stop:
+			return
+		}
+	}
+}
+
+func (s *Scheduler) run(job Job) {
+	defer func() {
+		if r := recover(); r != nil {
+			s.logger.Error(fmt.Sprintf("job %s panicked: %v", job.Name, r))
+		}
+	}()
+
+	err := job.Run()
+	if err != nil {
+		s.logger.Error(fmt.Sprintf("job %s failed: %s", job.Name, err.Error()))
+	}
+}
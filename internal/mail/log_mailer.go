@@ -0,0 +1,49 @@
+package mail
+
+import (
+	"os"
+	"sync"
+	"time"
+)
+
+// LogMailer writes emails to a file instead of sending them. It is meant for
+// local development, where links in the emails can be copied from the file.
+type LogMailer struct {
+	from string
+	file *os.File
+	mu   sync.Mutex
+}
+
+func NewLogMailer(from string, logFile string) (*LogMailer, error) {
+	if logFile == "" {
+		logFile = "mail.txt"
+	}
+
+	file, err := os.OpenFile(logFile, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0600)
+	if err != nil {
+		return nil, err
+	}
+
+	return &LogMailer{
+		from: from,
+		file: file,
+	}, nil
+}
+
+func (l *LogMailer) Send(message *Message) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	_, err := l.file.WriteString("Date: " + time.Now().Format(time.RFC1123Z) + "\n")
+	if err != nil {
+		return err
+	}
+
+	_, err = l.file.Write(buildMessage(l.from, message))
+	if err != nil {
+		return err
+	}
+
+	_, err = l.file.WriteString("\n\n")
+	return err
+}
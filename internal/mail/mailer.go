@@ -0,0 +1,33 @@
+package mail
+
+import (
+	"fmt"
+
+	"github.com/safepass/server/internal/config"
+)
+
+// Message is a plain text email.
+type Message struct {
+	To      string
+	Subject string
+	Body    string
+}
+
+// Mailer delivers emails to users.
+type Mailer interface {
+	// Send delivers the message or returns an error describing why it could
+	// not be delivered.
+	Send(message *Message) error
+}
+
+// NewMailer returns the mailer selected by the mail driver in the config.
+func NewMailer(mailConfig config.MailConfig) (Mailer, error) {
+	switch mailConfig.Driver {
+	case "smtp":
+		return NewSMTPMailer(mailConfig), nil
+	case "log", "":
+		return NewLogMailer(mailConfig.From, mailConfig.LogFile)
+	default:
+		return nil, fmt.Errorf("unknown mail driver: %s", mailConfig.Driver)
+	}
+}
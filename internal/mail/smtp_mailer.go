@@ -0,0 +1,64 @@
+package mail
+
+import (
+	"fmt"
+	"net"
+	netmail "net/mail"
+	"net/smtp"
+	"strconv"
+	"strings"
+
+	"github.com/safepass/server/internal/config"
+)
+
+// SMTPMailer sends emails through an SMTP relay.
+type SMTPMailer struct {
+	from     string
+	addr     string
+	host     string
+	username string
+	password string
+}
+
+func NewSMTPMailer(mailConfig config.MailConfig) *SMTPMailer {
+	return &SMTPMailer{
+		from:     mailConfig.From,
+		addr:     net.JoinHostPort(mailConfig.Host, strconv.Itoa(mailConfig.Port)),
+		host:     mailConfig.Host,
+		username: mailConfig.Username,
+		password: mailConfig.Password,
+	}
+}
+
+func (s *SMTPMailer) Send(message *Message) error {
+	var auth smtp.Auth
+	if s.username != "" {
+		auth = smtp.PlainAuth("", s.username, s.password, s.host)
+	}
+
+	envelopeFrom := s.from
+	if address, err := netmail.ParseAddress(s.from); err == nil {
+		envelopeFrom = address.Address
+	}
+
+	err := smtp.SendMail(s.addr, auth, envelopeFrom, []string{message.To}, buildMessage(s.from, message))
+	if err != nil {
+		return fmt.Errorf("sending mail to %s: %w", message.To, err)
+	}
+
+	return nil
+}
+
+func buildMessage(from string, message *Message) []byte {
+	var b strings.Builder
+
+	b.WriteString("From: " + from + "\r\n")
+	b.WriteString("To: " + message.To + "\r\n")
+	b.WriteString("Subject: " + message.Subject + "\r\n")
+	b.WriteString("MIME-Version: 1.0\r\n")
+	b.WriteString("Content-Type: text/plain; charset=\"utf-8\"\r\n")
+	b.WriteString("\r\n")
+	b.WriteString(strings.ReplaceAll(message.Body, "\n", "\r\n"))
+
+	return []byte(b.String())
+}
@@ -0,0 +1,195 @@
+package notifications
+
+import (
+	"sync"
+	"time"
+
+	"github.com/safepass/server/internal/consts"
+	"github.com/safepass/server/pkg/models"
+)
+
+// subscriberBuffer is the number of events a subscriber can fall behind. A
+// subscriber that falls further behind is dropped and replays on reconnect.
+const subscriberBuffer = 64
+
+// Hub fans events out to the connected devices of each user. It keeps the
+// latest events of every user so that a device reconnecting with the id of
+// the last event it saw gets the events it missed. State is kept in memory,
+// so devices only receive events published on the instance they are
+// connected to.
+type Hub struct {
+	historySize int
+
+	mu          sync.Mutex
+	firstID     int64
+	lastID      int64
+	evicted     int64
+	subscribers map[int]map[*Subscription]struct{}
+	histories   map[int]*history
+}
+
+// history holds the latest events of a user. Trimmed is the id of the newest
+// event that no longer fits.
+type history struct {
+	events    []*models.VaultEvent
+	trimmed   int64
+	updatedAt time.Time
+}
+
+// Subscription receives the events of a user until it is closed, or until the
+// hub drops it for falling behind, which closes Events.
+type Subscription struct {
+	Events <-chan *models.VaultEvent
+
+	events chan *models.VaultEvent
+	userID int
+	hub    *Hub
+}
+
+// NewHub creates a hub keeping historySize events per user. Event ids start
+// at the current time in microseconds, so they keep increasing across
+// restarts and ids from before a restart are recognized as unknown.
+func NewHub(historySize int) *Hub {
+	firstID := time.Now().UnixMicro()
+
+	return &Hub{
+		historySize: historySize,
+		firstID:     firstID,
+		lastID:      firstID,
+		subscribers: make(map[int]map[*Subscription]struct{}),
+		histories:   make(map[int]*history),
+	}
+}
+
+// Publish sends an event to every device of the user. The event gets the
+// next id and the current time.
+func (h *Hub) Publish(userID int, event models.VaultEvent) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	h.lastID++
+	event.ID = h.lastID
+	event.CreatedAt = time.Now().UTC().Format(time.RFC3339)
+
+	if h.historySize > 0 {
+		hist, ok := h.histories[userID]
+		if !ok {
+			hist = &history{}
+			h.histories[userID] = hist
+		}
+
+		hist.events = append(hist.events, &event)
+		hist.updatedAt = time.Now()
+		if over := len(hist.events) - h.historySize; over > 0 {
+			hist.trimmed = hist.events[over-1].ID
+			hist.events = append([]*models.VaultEvent(nil), hist.events[over:]...)
+		}
+	}
+
+	for sub := range h.subscribers[userID] {
+		select {
+		case sub.events <- &event:
+		default:
+			h.remove(sub)
+		}
+	}
+}
+
+// Subscribe registers a device of the user. With the id of the last event the
+// device saw, it also returns the events published after it. When some of
+// those are no longer kept, or the id is unknown, a single resync event is
+// returned instead.
+func (h *Hub) Subscribe(userID int, lastEventID int64) (*Subscription, []*models.VaultEvent) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	events := make(chan *models.VaultEvent, subscriberBuffer)
+	sub := &Subscription{
+		Events: events,
+		events: events,
+		userID: userID,
+		hub:    h,
+	}
+
+	if h.subscribers[userID] == nil {
+		h.subscribers[userID] = make(map[*Subscription]struct{})
+	}
+	h.subscribers[userID][sub] = struct{}{}
+
+	if lastEventID == 0 {
+		return sub, nil
+	}
+
+	// Without a history the user's events may have been evicted, so ids
+	// from before the last eviction cannot be caught up on.
+	hist := h.histories[userID]
+	if hist == nil {
+		hist = &history{trimmed: h.evicted}
+	}
+
+	if lastEventID < h.firstID || lastEventID > h.lastID || lastEventID < hist.trimmed {
+		return sub, []*models.VaultEvent{{
+			ID:        h.lastID,
+			Type:      consts.VaultEventTypes.RESYNC,
+			CreatedAt: time.Now().UTC().Format(time.RFC3339),
+		}}
+	}
+
+	var missed []*models.VaultEvent
+	for _, event := range hist.events {
+		if event.ID > lastEventID {
+			missed = append(missed, event)
+		}
+	}
+
+	return sub, missed
+}
+
+// Evict drops the events kept for users without connected devices who have
+// had no event for maxIdle, so the histories of users who left do not pile
+// up. Devices of those users that reconnect get a resync event.
+func (h *Hub) Evict(maxIdle time.Duration) int {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	cutoff := time.Now().Add(-maxIdle)
+	evicted := 0
+
+	for userID, hist := range h.histories {
+		if len(h.subscribers[userID]) > 0 || hist.updatedAt.After(cutoff) {
+			continue
+		}
+
+		delete(h.histories, userID)
+		h.evicted = h.lastID
+		evicted++
+	}
+
+	return evicted
+}
+
+// Close unregisters the subscription. It is safe to call more than once.
+func (s *Subscription) Close() {
+	s.hub.mu.Lock()
+	defer s.hub.mu.Unlock()
+
+	s.hub.remove(s)
+}
+
+func (h *Hub) remove(sub *Subscription) {
+	subs, ok := h.subscribers[sub.userID]
+	if !ok {
+		return
+	}
+
+	if _, ok := subs[sub]; !ok {
+		return
+	}
+
+	delete(subs, sub)
+	close(sub.events)
+
+	if len(subs) == 0 {
+		delete(h.subscribers, sub.userID)
+	}
+}
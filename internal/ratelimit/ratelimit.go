@@ -0,0 +1,70 @@
+package ratelimit
+
+import (
+	"sync"
+	"time"
+)
+
+// sweepThreshold is the number of tracked keys after which stale keys are
+// dropped, so the limiter does not grow without bound.
+const sweepThreshold = 10000
+
+// Limiter allows at most limit events per key within a sliding window. State
+// is kept in memory, so limits are per server instance.
+type Limiter struct {
+	limit  int
+	window time.Duration
+
+	mu   sync.Mutex
+	hits map[string][]time.Time
+}
+
+func NewLimiter(limit int, window time.Duration) *Limiter {
+	return &Limiter{
+		limit:  limit,
+		window: window,
+		hits:   make(map[string][]time.Time),
+	}
+}
+
+// Allow records an event for key and reports whether it is within the limit.
+// Rejected events are not recorded.
+func (l *Limiter) Allow(key string) bool {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := time.Now()
+	if len(l.hits) > sweepThreshold {
+		l.sweep(now)
+	}
+
+	recent := prune(l.hits[key], now.Add(-l.window))
+	if len(recent) >= l.limit {
+		l.hits[key] = recent
+		return false
+	}
+
+	l.hits[key] = append(recent, now)
+	return true
+}
+
+func (l *Limiter) sweep(now time.Time) {
+	cutoff := now.Add(-l.window)
+	for key, hits := range l.hits {
+		recent := prune(hits, cutoff)
+		if len(recent) == 0 {
+			delete(l.hits, key)
+		} else {
+			l.hits[key] = recent
+		}
+	}
+}
+
+func prune(hits []time.Time, cutoff time.Time) []time.Time {
+	i := 0
+	for i < len(hits) && !hits[i].After(cutoff) {
+		i++
+	}
+
+	return hits[i:]
+}
@@ -0,0 +1,190 @@
+package repositories
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/safepass/server/internal/logging"
+	"github.com/safepass/server/pkg/dtos/attachment"
+	"github.com/safepass/server/pkg/models"
+	"github.com/supabase-community/supabase-go"
+)
+
+type AttachmentRepositoryMethods interface {
+	GetAttachment(id string) (*models.Attachment, *models.Error)
+	GetAttachmentsByPasswordID(passwordID string) ([]*models.Attachment, *models.Error)
+	GetUsedStorage(userID string) (int64, *models.Error)
+	CreateAttachment(createAttachment *attachment.CreateAttachment, quota int64) (*models.Attachment, *models.Error)
+	DeleteAttachment(id string) (*models.Attachment, *models.Error)
+
+	GetDeletedBlobs(limit int) ([]*models.DeletedBlob, *models.Error)
+	DeleteDeletedBlobs(ids []string) *models.Error
+}
+
+type AttachmentRepository struct {
+	client *supabase.Client
+	logger *logging.Logger
+
+	AttachmentRepositoryMethods
+}
+
+func NewAttachmentRepository(client *supabase.Client, logger *logging.Logger) *AttachmentRepository {
+	return &AttachmentRepository{
+		client: client,
+		logger: logger,
+	}
+}
+
+func (a *AttachmentRepository) GetAttachment(id string) (*models.Attachment, *models.Error) {
+	res, _, err := a.client.From("attachments").Select("*", "", false).Eq("id", id).Execute()
+	if err != nil {
+		description := "An error occurred while retrieving the attachment."
+		a.logger.Error(err.Error())
+
+		return nil, models.NewError(500, "InternalServerError", description)
+	}
+
+	var attachments []*models.Attachment
+	err = json.Unmarshal(res, &attachments)
+	if err != nil {
+		description := "An error occurred while retrieving the attachment."
+		a.logger.Error(err.Error())
+
+		return nil, models.NewError(500, "InternalServerError", description)
+	}
+
+	if len(attachments) == 0 {
+		description := "Attachment not found with id=" + id
+		return nil, models.NewError(404, "NotFound", description)
+	}
+
+	return attachments[0], nil
+}
+
+func (a *AttachmentRepository) GetAttachmentsByPasswordID(passwordID string) ([]*models.Attachment, *models.Error) {
+	res, _, err := a.client.From("attachments").Select("*", "", false).Eq("password_id", passwordID).Execute()
+	if err != nil {
+		description := "An error occurred while retrieving attachments."
+		a.logger.Error(err.Error())
+
+		return nil, models.NewError(500, "InternalServerError", description)
+	}
+
+	var attachments []*models.Attachment
+	err = json.Unmarshal(res, &attachments)
+	if err != nil {
+		description := "An error occurred while retrieving attachments."
+		a.logger.Error(err.Error())
+
+		return nil, models.NewError(500, "InternalServerError", description)
+	}
+
+	return attachments, nil
+}
+
+// GetUsedStorage returns the total size in bytes of the user's attachments
+// and Send files, which share the storage quota.
+func (a *AttachmentRepository) GetUsedStorage(userID string) (int64, *models.Error) {
+	params := map[string]interface{}{
+		"p_user_id": userID,
+	}
+
+	var used int64
+	err := callRpc(a.client, "user_storage_used", params, &used)
+	if err != nil {
+		description := "An error occurred while retrieving the used storage."
+		a.logger.Error(err.Error())
+
+		return 0, models.NewError(500, "InternalServerError", description)
+	}
+
+	return used, nil
+}
+
+// CreateAttachment records an attachment when it fits in the user's storage
+// quota, checked under a per-user lock so that concurrent uploads cannot
+// exceed it together. Otherwise a 507 is returned.
+func (a *AttachmentRepository) CreateAttachment(createAttachment *attachment.CreateAttachment, quota int64) (*models.Attachment, *models.Error) {
+	params := map[string]interface{}{
+		"p_attachment": createAttachment,
+		"p_quota":      quota,
+	}
+
+	var attachments []*models.Attachment
+	err := callRpc(a.client, "create_attachment", params, &attachments)
+	if err != nil && strings.Contains(err.Error(), "(53100)") {
+		return nil, models.NewError(507, "InsufficientStorage", "The attachment does not fit in the remaining storage quota.")
+	}
+
+	if err != nil || len(attachments) == 0 {
+		description := "An error occurred while creating the attachment."
+		if err != nil {
+			a.logger.Error(err.Error())
+		}
+
+		return nil, models.NewError(500, "InternalServerError", description)
+	}
+
+	return attachments[0], nil
+}
+
+// DeleteAttachment deletes the attachment row. Its blob is queued for
+// deletion by the database.
+func (a *AttachmentRepository) DeleteAttachment(id string) (*models.Attachment, *models.Error) {
+	res, _, err := a.client.From("attachments").Delete("", "").Eq("id", id).Execute()
+	if err != nil {
+		description := fmt.Sprintf("Error deleting attachment: %s", err.Error())
+		return nil, models.NewError(500, "InternalError", description)
+	}
+
+	var attachments []*models.Attachment
+	err = json.Unmarshal(res, &attachments)
+	if err != nil {
+		description := fmt.Sprintf("Error unmarshalling response: %s", err.Error())
+		return nil, models.NewError(500, "InternalError", description)
+	}
+
+	if len(attachments) == 0 {
+		description := "Attachment not found with id=" + id
+		return nil, models.NewError(404, "NotFound", description)
+	}
+
+	return attachments[0], nil
+}
+
+// GetDeletedBlobs returns up to limit blobs that are queued for deletion.
+func (a *AttachmentRepository) GetDeletedBlobs(limit int) ([]*models.DeletedBlob, *models.Error) {
+	res, _, err := a.client.From("deleted_blobs").Select("*", "", false).Limit(limit, "").Execute()
+	if err != nil {
+		description := "An error occurred while retrieving deleted blobs."
+		a.logger.Error(err.Error())
+
+		return nil, models.NewError(500, "InternalServerError", description)
+	}
+
+	var blobs []*models.DeletedBlob
+	err = json.Unmarshal(res, &blobs)
+	if err != nil {
+		description := "An error occurred while retrieving deleted blobs."
+		a.logger.Error(err.Error())
+
+		return nil, models.NewError(500, "InternalServerError", description)
+	}
+
+	return blobs, nil
+}
+
+// DeleteDeletedBlobs removes blobs from the deletion queue once they are gone
+// from the blob store.
+func (a *AttachmentRepository) DeleteDeletedBlobs(ids []string) *models.Error {
+	_, _, err := a.client.From("deleted_blobs").Delete("minimal", "").In("id", ids).Execute()
+	if err != nil {
+		description := "An error occurred while deleting blobs."
+		a.logger.Error(err.Error())
+
+		return models.NewError(500, "InternalServerError", description)
+	}
+
+	return nil
+}
@@ -0,0 +1,38 @@
+package repositories
+
+import (
+	"github.com/safepass/server/internal/logging"
+	"github.com/safepass/server/pkg/dtos/audit"
+	"github.com/safepass/server/pkg/models"
+	"github.com/supabase-community/supabase-go"
+)
+
+type AuditRepositoryMethods interface {
+	CreateAuditLog(*audit.CreateAuditLog) *models.Error
+}
+
+type AuditRepository struct {
+	client *supabase.Client
+	logger *logging.Logger
+
+	AuditRepositoryMethods
+}
+
+func NewAuditRepository(client *supabase.Client, logger *logging.Logger) *AuditRepository {
+	return &AuditRepository{
+		client: client,
+		logger: logger,
+	}
+}
+
+func (a *AuditRepository) CreateAuditLog(auditLog *audit.CreateAuditLog) *models.Error {
+	_, _, err := a.client.From("audit_logs").Insert(auditLog, false, "", "minimal", "").Execute()
+	if err != nil {
+		description := "An error occurred while writing the audit log."
+		a.logger.Error(err.Error())
+
+		return models.NewError(500, "InternalServerError", description)
+	}
+
+	return nil
+}
@@ -0,0 +1,146 @@
+package repositories
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/safepass/server/internal/logging"
+	"github.com/safepass/server/pkg/dtos/organization"
+	"github.com/safepass/server/pkg/models"
+	"github.com/supabase-community/supabase-go"
+)
+
+type CollectionRepositoryMethods interface {
+	GetCollection(id string) (*models.Collection, *models.Error)
+	GetCollectionsByOrganizationID(organizationID string) ([]*models.Collection, *models.Error)
+	CreateCollection(*organization.CreateCollection) (*models.Collection, *models.Error)
+	UpdateCollection(id string, update *organization.CreateCollection) (*models.Collection, *models.Error)
+	DeleteCollection(id string) (*models.Collection, *models.Error)
+}
+
+type CollectionRepository struct {
+	client *supabase.Client
+	logger *logging.Logger
+
+	CollectionRepositoryMethods
+}
+
+func NewCollectionRepository(client *supabase.Client, logger *logging.Logger) *CollectionRepository {
+	return &CollectionRepository{
+		client: client,
+		logger: logger,
+	}
+}
+
+func (c *CollectionRepository) GetCollection(id string) (*models.Collection, *models.Error) {
+	res, _, err := c.client.From("collections").Select("*", "", false).Eq("id", id).Execute()
+	if err != nil {
+		description := "An error occurred while retrieving the collection."
+		c.logger.Error(err.Error())
+
+		return nil, models.NewError(500, "InternalServerError", description)
+	}
+
+	var collections []*models.Collection
+	err = json.Unmarshal(res, &collections)
+	if err != nil {
+		description := "An error occurred while retrieving the collection."
+		c.logger.Error(err.Error())
+
+		return nil, models.NewError(500, "InternalServerError", description)
+	}
+
+	if len(collections) == 0 {
+		description := "Collection not found with id=" + id
+		return nil, models.NewError(404, "NotFound", description)
+	}
+
+	return collections[0], nil
+}
+
+func (c *CollectionRepository) GetCollectionsByOrganizationID(organizationID string) ([]*models.Collection, *models.Error) {
+	res, _, err := c.client.From("collections").Select("*", "", false).Eq("organization_id", organizationID).Execute()
+	if err != nil {
+		description := "An error occurred while retrieving collections."
+		c.logger.Error(err.Error())
+
+		return nil, models.NewError(500, "InternalServerError", description)
+	}
+
+	var collections []*models.Collection
+	err = json.Unmarshal(res, &collections)
+	if err != nil {
+		description := "An error occurred while retrieving collections."
+		c.logger.Error(err.Error())
+
+		return nil, models.NewError(500, "InternalServerError", description)
+	}
+
+	return collections, nil
+}
+
+func (c *CollectionRepository) CreateCollection(createCollection *organization.CreateCollection) (*models.Collection, *models.Error) {
+	res, _, err := c.client.From("collections").Insert(createCollection, false, "", "", "").Execute()
+	if err != nil {
+		description := "An error occurred while creating the collection."
+		c.logger.Error(err.Error())
+
+		return nil, models.NewError(500, "InternalServerError", description)
+	}
+
+	var collections []*models.Collection
+	err = json.Unmarshal(res, &collections)
+	if err != nil {
+		description := fmt.Sprintf("Error unmarshalling response: %s", err.Error())
+		return nil, models.NewError(500, "InternalError", description)
+	}
+
+	return collections[0], nil
+}
+
+func (c *CollectionRepository) UpdateCollection(id string, update *organization.CreateCollection) (*models.Collection, *models.Error) {
+	res, _, err := c.client.From("collections").Update(update, "", "").Eq("id", id).Eq("organization_id", fmt.Sprint(update.OrganizationID)).Execute()
+	if err != nil {
+		description := "An error occurred while updating the collection."
+		c.logger.Error(err.Error())
+
+		return nil, models.NewError(500, "InternalServerError", description)
+	}
+
+	var collections []*models.Collection
+	err = json.Unmarshal(res, &collections)
+	if err != nil {
+		description := fmt.Sprintf("Error unmarshalling response: %s", err.Error())
+		return nil, models.NewError(500, "InternalError", description)
+	}
+
+	if len(collections) == 0 {
+		description := "Collection not found with id=" + id
+		return nil, models.NewError(404, "NotFound", description)
+	}
+
+	return collections[0], nil
+}
+
+// DeleteCollection deletes the collection and the items in it.
+func (c *CollectionRepository) DeleteCollection(id string) (*models.Collection, *models.Error) {
+	res, _, err := c.client.From("collections").Delete("", "").Eq("id", id).Execute()
+	if err != nil {
+		description := fmt.Sprintf("Error deleting collection: %s", err.Error())
+		return nil, models.NewError(500, "InternalError", description)
+	}
+
+	var collections []*models.Collection
+	err = json.Unmarshal(res, &collections)
+	if err != nil {
+		description := fmt.Sprintf("Error unmarshalling response: %s", err.Error())
+		return nil, models.NewError(500, "InternalError", description)
+	}
+
+	if len(collections) == 0 {
+		description := "No collection found"
+		return nil, models.NewError(404, "NotFound", description)
+	}
+
+	return collections[0], nil
+}
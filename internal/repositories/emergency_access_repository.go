@@ -0,0 +1,234 @@
+package repositories
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/safepass/server/internal/logging"
+	"github.com/safepass/server/pkg/dtos/emergency"
+	"github.com/safepass/server/pkg/models"
+	"github.com/supabase-community/supabase-go"
+)
+
+// emergencyAccessColumns embeds the public fields of both parties.
+const emergencyAccessColumns = "*, grantor:users!emergency_access_grantor_id_fkey (id, username, email), grantee:users!emergency_access_grantee_id_fkey (id, username, email)"
+
+type EmergencyAccessRepositoryMethods interface {
+	GetEmergencyAccess(id string) (*models.EmergencyAccess, *models.Error)
+	GetEmergencyAccessesByGrantorID(grantorID string) ([]*models.EmergencyAccess, *models.Error)
+	GetEmergencyAccessesByGranteeID(granteeID string) ([]*models.EmergencyAccess, *models.Error)
+	GetEmergencyAccessesByStatus(status string) ([]*models.EmergencyAccess, *models.Error)
+	ClaimEmergencyAccesses(granteeID int, email string) *models.Error
+	CreateEmergencyAccess(*emergency.CreateEmergencyAccess) (*models.EmergencyAccess, *models.Error)
+	UpdateEmergencyAccess(id string, status string, update *emergency.UpdateEmergencyAccess) (*models.EmergencyAccess, *models.Error)
+	DeleteEmergencyAccess(id string) (*models.EmergencyAccess, *models.Error)
+	Takeover(id int, granteeID int, takeover *emergency.Takeover) (*models.EmergencyAccess, *models.Error)
+}
+
+type EmergencyAccessRepository struct {
+	client *supabase.Client
+	logger *logging.Logger
+
+	EmergencyAccessRepositoryMethods
+}
+
+func NewEmergencyAccessRepository(client *supabase.Client, logger *logging.Logger) *EmergencyAccessRepository {
+	return &EmergencyAccessRepository{
+		client: client,
+		logger: logger,
+	}
+}
+
+func (e *EmergencyAccessRepository) GetEmergencyAccess(id string) (*models.EmergencyAccess, *models.Error) {
+	res, _, err := e.client.From("emergency_access").Select(emergencyAccessColumns, "", false).Eq("id", id).Execute()
+	if err != nil {
+		description := "An error occurred while retrieving emergency access."
+		e.logger.Error(err.Error())
+
+		return nil, models.NewError(500, "InternalServerError", description)
+	}
+
+	var accesses []*models.EmergencyAccess
+	err = json.Unmarshal(res, &accesses)
+	if err != nil {
+		description := "An error occurred while retrieving emergency access."
+		e.logger.Error(err.Error())
+
+		return nil, models.NewError(500, "InternalServerError", description)
+	}
+
+	if len(accesses) == 0 {
+		description := "Emergency access not found with id=" + id
+		return nil, models.NewError(404, "NotFound", description)
+	}
+
+	return accesses[0], nil
+}
+
+func (e *EmergencyAccessRepository) GetEmergencyAccessesByGrantorID(grantorID string) ([]*models.EmergencyAccess, *models.Error) {
+	return e.getEmergencyAccessesBy("grantor_id", grantorID)
+}
+
+func (e *EmergencyAccessRepository) GetEmergencyAccessesByGranteeID(granteeID string) ([]*models.EmergencyAccess, *models.Error) {
+	return e.getEmergencyAccessesBy("grantee_id", granteeID)
+}
+
+func (e *EmergencyAccessRepository) GetEmergencyAccessesByStatus(status string) ([]*models.EmergencyAccess, *models.Error) {
+	return e.getEmergencyAccessesBy("status", status)
+}
+
+func (e *EmergencyAccessRepository) getEmergencyAccessesBy(column string, value string) ([]*models.EmergencyAccess, *models.Error) {
+	res, _, err := e.client.From("emergency_access").Select(emergencyAccessColumns, "", false).Eq(column, value).Execute()
+	if err != nil {
+		description := "An error occurred while retrieving emergency access."
+		e.logger.Error(err.Error())
+
+		return nil, models.NewError(500, "InternalServerError", description)
+	}
+
+	var accesses []*models.EmergencyAccess
+	err = json.Unmarshal(res, &accesses)
+	if err != nil {
+		description := "An error occurred while retrieving emergency access."
+		e.logger.Error(err.Error())
+
+		return nil, models.NewError(500, "InternalServerError", description)
+	}
+
+	return accesses, nil
+}
+
+// ClaimEmergencyAccesses makes the grantee the trusted contact of the pending
+// invitations sent to the email address.
+func (e *EmergencyAccessRepository) ClaimEmergencyAccesses(granteeID int, email string) *models.Error {
+	params := map[string]interface{}{
+		"p_grantee_id": granteeID,
+		"p_email":      email,
+	}
+
+	var accesses []*models.EmergencyAccess
+	err := callRpc(e.client, "claim_emergency_access", params, &accesses)
+	if err != nil {
+		description := "An error occurred while claiming emergency access invitations."
+		e.logger.Error(err.Error())
+
+		return models.NewError(500, "InternalServerError", description)
+	}
+
+	return nil
+}
+
+func (e *EmergencyAccessRepository) CreateEmergencyAccess(createEmergencyAccess *emergency.CreateEmergencyAccess) (*models.EmergencyAccess, *models.Error) {
+	res, _, err := e.client.From("emergency_access").Insert(createEmergencyAccess, false, "", "", "").Execute()
+	if err != nil {
+		description := "An error occurred while creating emergency access."
+		statusCode := 500
+		statusText := "InternalServerError"
+
+		if strings.Contains(err.Error(), "duplicate") {
+			description = "This email address is already a trusted contact"
+			statusCode = 409
+			statusText = "Conflict"
+		}
+
+		e.logger.Error(err.Error())
+
+		return nil, models.NewError(statusCode, statusText, description)
+	}
+
+	var accesses []*models.EmergencyAccess
+	err = json.Unmarshal(res, &accesses)
+	if err != nil {
+		description := fmt.Sprintf("Error unmarshalling response: %s", err.Error())
+		return nil, models.NewError(500, "InternalError", description)
+	}
+
+	return accesses[0], nil
+}
+
+// UpdateEmergencyAccess updates the grant only while it still has the given
+// status, so a transition made in the meantime is not overwritten.
+func (e *EmergencyAccessRepository) UpdateEmergencyAccess(id string, status string, update *emergency.UpdateEmergencyAccess) (*models.EmergencyAccess, *models.Error) {
+	res, _, err := e.client.From("emergency_access").Update(update, "", "").Eq("id", id).Eq("status", status).Execute()
+	if err != nil {
+		description := "An error occurred while updating emergency access."
+		e.logger.Error(err.Error())
+
+		return nil, models.NewError(500, "InternalServerError", description)
+	}
+
+	var accesses []*models.EmergencyAccess
+	err = json.Unmarshal(res, &accesses)
+	if err != nil {
+		description := fmt.Sprintf("Error unmarshalling response: %s", err.Error())
+		return nil, models.NewError(500, "InternalError", description)
+	}
+
+	if len(accesses) == 0 {
+		description := "Emergency access with id=" + id + " was changed in the meantime."
+		return nil, models.NewError(409, "Conflict", description)
+	}
+
+	return accesses[0], nil
+}
+
+func (e *EmergencyAccessRepository) DeleteEmergencyAccess(id string) (*models.EmergencyAccess, *models.Error) {
+	res, _, err := e.client.From("emergency_access").Delete("", "").Eq("id", id).Execute()
+	if err != nil {
+		description := fmt.Sprintf("Error deleting emergency access: %s", err.Error())
+		return nil, models.NewError(500, "InternalError", description)
+	}
+
+	var accesses []*models.EmergencyAccess
+	err = json.Unmarshal(res, &accesses)
+	if err != nil {
+		description := fmt.Sprintf("Error unmarshalling response: %s", err.Error())
+		return nil, models.NewError(500, "InternalError", description)
+	}
+
+	if len(accesses) == 0 {
+		description := "No emergency access found"
+		return nil, models.NewError(404, "NotFound", description)
+	}
+
+	return accesses[0], nil
+}
+
+// Takeover replaces the grantor's master password and vault key, revokes the
+// grantor's sessions and completes the grant in a single transaction. A grant
+// that is no longer an approved takeover, or a grantor with other vaults when
+// they are not discarded, returns 409.
+func (e *EmergencyAccessRepository) Takeover(id int, granteeID int, takeover *emergency.Takeover) (*models.EmergencyAccess, *models.Error) {
+	params := map[string]interface{}{
+		"p_access_id":            id,
+		"p_grantee_id":           granteeID,
+		"p_master_password_hash": takeover.MasterPasswordHash,
+		"p_salt":                 takeover.Salt,
+		"p_iteration_count":      takeover.IterationCount,
+		"p_protected_key":        takeover.ProtectedSymmetricKey,
+		"p_mac":                  takeover.Mac,
+		"p_discard_vaults":       takeover.DiscardVaults,
+	}
+
+	var accesses []*models.EmergencyAccess
+	err := callRpc(e.client, "emergency_takeover", params, &accesses)
+	if err != nil && strings.Contains(err.Error(), "(40001)") {
+		return nil, models.NewError(409, "Conflict", "The emergency access can no longer be used for a takeover, or the account has vaults it does not cover.")
+	}
+
+	if err != nil {
+		description := "An error occurred during the takeover."
+		e.logger.Error(err.Error())
+
+		return nil, models.NewError(500, "InternalServerError", description)
+	}
+
+	if len(accesses) == 0 {
+		description := "Emergency access not found with id=" + strconv.Itoa(id)
+		return nil, models.NewError(404, "NotFound", description)
+	}
+
+	return accesses[0], nil
+}
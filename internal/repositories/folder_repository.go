@@ -0,0 +1,147 @@
+package repositories
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/safepass/server/internal/logging"
+	"github.com/safepass/server/pkg/dtos/folder"
+	"github.com/safepass/server/pkg/models"
+	"github.com/supabase-community/supabase-go"
+)
+
+type FolderRepositoryMethods interface {
+	GetFolder(id string) (*models.Folder, *models.Error)
+	GetFoldersByUserID(userID string) ([]*models.Folder, *models.Error)
+	CreateFolder(*folder.CreateFolder) (*models.Folder, *models.Error)
+	UpdateFolder(id string, update *folder.CreateFolder) (*models.Folder, *models.Error)
+	DeleteFolder(id string, userID string) (*models.Folder, *models.Error)
+}
+
+type FolderRepository struct {
+	client *supabase.Client
+	logger *logging.Logger
+
+	FolderRepositoryMethods
+}
+
+func NewFolderRepository(client *supabase.Client, logger *logging.Logger) *FolderRepository {
+	return &FolderRepository{
+		client: client,
+		logger: logger,
+	}
+}
+
+func (f *FolderRepository) GetFolder(id string) (*models.Folder, *models.Error) {
+	res, _, err := f.client.From("folders").Select("*", "", false).Eq("id", id).Execute()
+	if err != nil {
+		description := "An error occurred while retrieving the folder."
+		f.logger.Error(err.Error())
+
+		return nil, models.NewError(500, "InternalServerError", description)
+	}
+
+	var folders []*models.Folder
+	err = json.Unmarshal(res, &folders)
+	if err != nil {
+		description := "An error occurred while retrieving the folder."
+		f.logger.Error(err.Error())
+
+		return nil, models.NewError(500, "InternalServerError", description)
+	}
+
+	if len(folders) == 0 {
+		description := "Folder not found with id=" + id
+		return nil, models.NewError(404, "NotFound", description)
+	}
+
+	return folders[0], nil
+}
+
+func (f *FolderRepository) GetFoldersByUserID(userID string) ([]*models.Folder, *models.Error) {
+	res, _, err := f.client.From("folders").Select("*", "", false).Eq("user_id", userID).Execute()
+	if err != nil {
+		description := "An error occurred while retrieving folders."
+		f.logger.Error(err.Error())
+
+		return nil, models.NewError(500, "InternalServerError", description)
+	}
+
+	var folders []*models.Folder
+	err = json.Unmarshal(res, &folders)
+	if err != nil {
+		description := "An error occurred while retrieving folders."
+		f.logger.Error(err.Error())
+
+		return nil, models.NewError(500, "InternalServerError", description)
+	}
+
+	return folders, nil
+}
+
+func (f *FolderRepository) CreateFolder(createFolder *folder.CreateFolder) (*models.Folder, *models.Error) {
+	res, _, err := f.client.From("folders").Insert(createFolder, false, "", "", "").Execute()
+	if err != nil {
+		description := "An error occurred while creating the folder."
+		f.logger.Error(err.Error())
+
+		return nil, models.NewError(500, "InternalServerError", description)
+	}
+
+	var folders []*models.Folder
+	err = json.Unmarshal(res, &folders)
+	if err != nil {
+		description := fmt.Sprintf("Error unmarshalling response: %s", err.Error())
+		return nil, models.NewError(500, "InternalError", description)
+	}
+
+	return folders[0], nil
+}
+
+func (f *FolderRepository) UpdateFolder(id string, update *folder.CreateFolder) (*models.Folder, *models.Error) {
+	res, _, err := f.client.From("folders").Update(update, "", "").Eq("id", id).Eq("user_id", fmt.Sprint(update.UserID)).Execute()
+	if err != nil {
+		description := "An error occurred while updating the folder."
+		f.logger.Error(err.Error())
+
+		return nil, models.NewError(500, "InternalServerError", description)
+	}
+
+	var folders []*models.Folder
+	err = json.Unmarshal(res, &folders)
+	if err != nil {
+		description := fmt.Sprintf("Error unmarshalling response: %s", err.Error())
+		return nil, models.NewError(500, "InternalError", description)
+	}
+
+	if len(folders) == 0 {
+		description := "Folder not found with id=" + id
+		return nil, models.NewError(404, "NotFound", description)
+	}
+
+	return folders[0], nil
+}
+
+// DeleteFolder deletes a folder of the user. Its passwords are kept and
+// moved out of the folder by the database.
+func (f *FolderRepository) DeleteFolder(id string, userID string) (*models.Folder, *models.Error) {
+	res, _, err := f.client.From("folders").Delete("", "").Eq("id", id).Eq("user_id", userID).Execute()
+	if err != nil {
+		description := fmt.Sprintf("Error deleting folder: %s", err.Error())
+		return nil, models.NewError(500, "InternalError", description)
+	}
+
+	var folders []*models.Folder
+	err = json.Unmarshal(res, &folders)
+	if err != nil {
+		description := fmt.Sprintf("Error unmarshalling response: %s", err.Error())
+		return nil, models.NewError(500, "InternalError", description)
+	}
+
+	if len(folders) == 0 {
+		description := "Folder not found with id=" + id
+		return nil, models.NewError(404, "NotFound", description)
+	}
+
+	return folders[0], nil
+}
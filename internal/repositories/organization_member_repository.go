@@ -0,0 +1,198 @@
+package repositories
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/safepass/server/internal/logging"
+	"github.com/safepass/server/pkg/dtos/organization"
+	"github.com/safepass/server/pkg/models"
+	"github.com/supabase-community/supabase-go"
+)
+
+const organizationMemberColumns = "*, user:users (id, username, email), collections:collection_members (*)"
+
+type OrganizationMemberRepositoryMethods interface {
+	GetMember(id string) (*models.OrganizationMember, *models.Error)
+	GetMemberByUserID(organizationID string, userID string) (*models.OrganizationMember, *models.Error)
+	GetMembersByOrganizationID(organizationID string) ([]*models.OrganizationMember, *models.Error)
+	GetMembershipsByUserID(userID string) ([]*models.OrganizationMember, *models.Error)
+	CreateMember(*organization.CreateMember) (*models.OrganizationMember, *models.Error)
+	UpdateMember(id string, update *organization.UpdateMember) (*models.OrganizationMember, *models.Error)
+	DeleteMember(id string) (*models.OrganizationMember, *models.Error)
+	SetCollectionAccess(memberID int, access []*models.CollectionAccess) *models.Error
+}
+
+type OrganizationMemberRepository struct {
+	client *supabase.Client
+	logger *logging.Logger
+
+	OrganizationMemberRepositoryMethods
+}
+
+func NewOrganizationMemberRepository(client *supabase.Client, logger *logging.Logger) *OrganizationMemberRepository {
+	return &OrganizationMemberRepository{
+		client: client,
+		logger: logger,
+	}
+}
+
+func (o *OrganizationMemberRepository) GetMember(id string) (*models.OrganizationMember, *models.Error) {
+	members, merr := o.getMembersBy(organizationMemberColumns, map[string]string{"id": id})
+	if merr != nil {
+		return nil, merr
+	}
+
+	if len(members) == 0 {
+		description := "Member not found with id=" + id
+		return nil, models.NewError(404, "NotFound", description)
+	}
+
+	return members[0], nil
+}
+
+func (o *OrganizationMemberRepository) GetMemberByUserID(organizationID string, userID string) (*models.OrganizationMember, *models.Error) {
+	members, merr := o.getMembersBy(organizationMemberColumns, map[string]string{"organization_id": organizationID, "user_id": userID})
+	if merr != nil {
+		return nil, merr
+	}
+
+	if len(members) == 0 {
+		description := "You are not a member of organization id=" + organizationID
+		return nil, models.NewError(404, "NotFound", description)
+	}
+
+	return members[0], nil
+}
+
+func (o *OrganizationMemberRepository) GetMembersByOrganizationID(organizationID string) ([]*models.OrganizationMember, *models.Error) {
+	return o.getMembersBy(organizationMemberColumns, map[string]string{"organization_id": organizationID})
+}
+
+// GetMembershipsByUserID returns the user's memberships with the
+// organization embedded.
+func (o *OrganizationMemberRepository) GetMembershipsByUserID(userID string) ([]*models.OrganizationMember, *models.Error) {
+	return o.getMembersBy("*, organization:organizations (*), collections:collection_members (*)", map[string]string{"user_id": userID})
+}
+
+func (o *OrganizationMemberRepository) getMembersBy(columns string, filters map[string]string) ([]*models.OrganizationMember, *models.Error) {
+	res, _, err := o.client.From("organization_members").Select(columns, "", false).Match(filters).Execute()
+	if err != nil {
+		description := "An error occurred while retrieving organization members."
+		o.logger.Error(err.Error())
+
+		return nil, models.NewError(500, "InternalServerError", description)
+	}
+
+	var members []*models.OrganizationMember
+	err = json.Unmarshal(res, &members)
+	if err != nil {
+		description := "An error occurred while retrieving organization members."
+		o.logger.Error(err.Error())
+
+		return nil, models.NewError(500, "InternalServerError", description)
+	}
+
+	return members, nil
+}
+
+func (o *OrganizationMemberRepository) CreateMember(createMember *organization.CreateMember) (*models.OrganizationMember, *models.Error) {
+	res, _, err := o.client.From("organization_members").Insert(createMember, false, "", "", "").Execute()
+	if err != nil {
+		description := "An error occurred while adding the member."
+		statusCode := 500
+		statusText := "InternalServerError"
+
+		if strings.Contains(err.Error(), "duplicate") {
+			description = "The user is already a member of the organization"
+			statusCode = 409
+			statusText = "Conflict"
+		}
+
+		o.logger.Error(err.Error())
+
+		return nil, models.NewError(statusCode, statusText, description)
+	}
+
+	var members []*models.OrganizationMember
+	err = json.Unmarshal(res, &members)
+	if err != nil {
+		description := fmt.Sprintf("Error unmarshalling response: %s", err.Error())
+		return nil, models.NewError(500, "InternalError", description)
+	}
+
+	return members[0], nil
+}
+
+func (o *OrganizationMemberRepository) UpdateMember(id string, update *organization.UpdateMember) (*models.OrganizationMember, *models.Error) {
+	res, _, err := o.client.From("organization_members").Update(update, "", "").Eq("id", id).Execute()
+	if err != nil {
+		description := "An error occurred while updating the member."
+		o.logger.Error(err.Error())
+
+		return nil, models.NewError(500, "InternalServerError", description)
+	}
+
+	var members []*models.OrganizationMember
+	err = json.Unmarshal(res, &members)
+	if err != nil {
+		description := fmt.Sprintf("Error unmarshalling response: %s", err.Error())
+		return nil, models.NewError(500, "InternalError", description)
+	}
+
+	if len(members) == 0 {
+		description := "Member not found with id=" + id
+		return nil, models.NewError(404, "NotFound", description)
+	}
+
+	return members[0], nil
+}
+
+func (o *OrganizationMemberRepository) DeleteMember(id string) (*models.OrganizationMember, *models.Error) {
+	res, _, err := o.client.From("organization_members").Delete("", "").Eq("id", id).Execute()
+	if err != nil {
+		description := fmt.Sprintf("Error deleting member: %s", err.Error())
+		return nil, models.NewError(500, "InternalError", description)
+	}
+
+	var members []*models.OrganizationMember
+	err = json.Unmarshal(res, &members)
+	if err != nil {
+		description := fmt.Sprintf("Error unmarshalling response: %s", err.Error())
+		return nil, models.NewError(500, "InternalError", description)
+	}
+
+	if len(members) == 0 {
+		description := "No member found"
+		return nil, models.NewError(404, "NotFound", description)
+	}
+
+	return members[0], nil
+}
+
+// SetCollectionAccess replaces the collections a member has access to.
+func (o *OrganizationMemberRepository) SetCollectionAccess(memberID int, access []*models.CollectionAccess) *models.Error {
+	_, _, err := o.client.From("collection_members").Delete("minimal", "").Eq("member_id", strconv.Itoa(memberID)).Execute()
+	if err != nil {
+		description := "An error occurred while updating collection access."
+		o.logger.Error(err.Error())
+
+		return models.NewError(500, "InternalServerError", description)
+	}
+
+	if len(access) == 0 {
+		return nil
+	}
+
+	_, _, err = o.client.From("collection_members").Insert(access, false, "", "minimal", "").Execute()
+	if err != nil {
+		description := "An error occurred while updating collection access."
+		o.logger.Error(err.Error())
+
+		return models.NewError(500, "InternalServerError", description)
+	}
+
+	return nil
+}
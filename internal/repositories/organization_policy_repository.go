@@ -0,0 +1,104 @@
+package repositories
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/safepass/server/internal/logging"
+	"github.com/safepass/server/pkg/dtos/organization"
+	"github.com/safepass/server/pkg/models"
+	"github.com/supabase-community/supabase-go"
+)
+
+type OrganizationPolicyRepositoryMethods interface {
+	GetPoliciesByOrganizationID(organizationID string) ([]*models.OrganizationPolicy, *models.Error)
+	GetEnabledPolicies(organizationIDs []string, policyType string) ([]*models.OrganizationPolicy, *models.Error)
+	SetPolicy(*organization.SetPolicy) (*models.OrganizationPolicy, *models.Error)
+}
+
+type OrganizationPolicyRepository struct {
+	client *supabase.Client
+	logger *logging.Logger
+
+	OrganizationPolicyRepositoryMethods
+}
+
+func NewOrganizationPolicyRepository(client *supabase.Client, logger *logging.Logger) *OrganizationPolicyRepository {
+	return &OrganizationPolicyRepository{
+		client: client,
+		logger: logger,
+	}
+}
+
+func (o *OrganizationPolicyRepository) GetPoliciesByOrganizationID(organizationID string) ([]*models.OrganizationPolicy, *models.Error) {
+	res, _, err := o.client.From("organization_policies").Select("*", "", false).Eq("organization_id", organizationID).Execute()
+	if err != nil {
+		description := "An error occurred while retrieving policies."
+		o.logger.Error(err.Error())
+
+		return nil, models.NewError(500, "InternalServerError", description)
+	}
+
+	var policies []*models.OrganizationPolicy
+	err = json.Unmarshal(res, &policies)
+	if err != nil {
+		description := "An error occurred while retrieving policies."
+		o.logger.Error(err.Error())
+
+		return nil, models.NewError(500, "InternalServerError", description)
+	}
+
+	return policies, nil
+}
+
+// GetEnabledPolicies returns the enabled policies of the given type across
+// the given organizations. An empty policyType matches every type.
+func (o *OrganizationPolicyRepository) GetEnabledPolicies(organizationIDs []string, policyType string) ([]*models.OrganizationPolicy, *models.Error) {
+	if len(organizationIDs) == 0 {
+		return []*models.OrganizationPolicy{}, nil
+	}
+
+	query := o.client.From("organization_policies").Select("*", "", false).In("organization_id", organizationIDs).Eq("enabled", "true")
+	if policyType != "" {
+		query = query.Eq("type", policyType)
+	}
+
+	res, _, err := query.Execute()
+	if err != nil {
+		description := "An error occurred while retrieving policies."
+		o.logger.Error(err.Error())
+
+		return nil, models.NewError(500, "InternalServerError", description)
+	}
+
+	var policies []*models.OrganizationPolicy
+	err = json.Unmarshal(res, &policies)
+	if err != nil {
+		description := "An error occurred while retrieving policies."
+		o.logger.Error(err.Error())
+
+		return nil, models.NewError(500, "InternalServerError", description)
+	}
+
+	return policies, nil
+}
+
+// SetPolicy creates or replaces the organization's policy of the given type.
+func (o *OrganizationPolicyRepository) SetPolicy(setPolicy *organization.SetPolicy) (*models.OrganizationPolicy, *models.Error) {
+	res, _, err := o.client.From("organization_policies").Upsert(setPolicy, "organization_id,type", "", "").Execute()
+	if err != nil {
+		description := "An error occurred while saving the policy."
+		o.logger.Error(err.Error())
+
+		return nil, models.NewError(500, "InternalServerError", description)
+	}
+
+	var policies []*models.OrganizationPolicy
+	err = json.Unmarshal(res, &policies)
+	if err != nil {
+		description := fmt.Sprintf("Error unmarshalling response: %s", err.Error())
+		return nil, models.NewError(500, "InternalError", description)
+	}
+
+	return policies[0], nil
+}
@@ -0,0 +1,125 @@
+package repositories
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/safepass/server/internal/logging"
+	"github.com/safepass/server/pkg/dtos/organization"
+	"github.com/safepass/server/pkg/models"
+	"github.com/supabase-community/supabase-go"
+)
+
+type OrganizationRepositoryMethods interface {
+	GetOrganization(id string) (*models.Organization, *models.Error)
+	CreateOrganization(*organization.CreateOrganization) (*models.Organization, *models.Error)
+	UpdateOrganization(id string, update *organization.UpdateOrganization) (*models.Organization, *models.Error)
+	DeleteOrganization(id string) (*models.Organization, *models.Error)
+}
+
+type OrganizationRepository struct {
+	client *supabase.Client
+	logger *logging.Logger
+
+	OrganizationRepositoryMethods
+}
+
+func NewOrganizationRepository(client *supabase.Client, logger *logging.Logger) *OrganizationRepository {
+	return &OrganizationRepository{
+		client: client,
+		logger: logger,
+	}
+}
+
+func (o *OrganizationRepository) GetOrganization(id string) (*models.Organization, *models.Error) {
+	res, _, err := o.client.From("organizations").Select("*", "", false).Eq("id", id).Execute()
+	if err != nil {
+		description := "An error occurred while retrieving the organization."
+		o.logger.Error(err.Error())
+
+		return nil, models.NewError(500, "InternalServerError", description)
+	}
+
+	var organizations []*models.Organization
+	err = json.Unmarshal(res, &organizations)
+	if err != nil {
+		description := "An error occurred while retrieving the organization."
+		o.logger.Error(err.Error())
+
+		return nil, models.NewError(500, "InternalServerError", description)
+	}
+
+	if len(organizations) == 0 {
+		description := "Organization not found with id=" + id
+		return nil, models.NewError(404, "NotFound", description)
+	}
+
+	return organizations[0], nil
+}
+
+func (o *OrganizationRepository) CreateOrganization(createOrganization *organization.CreateOrganization) (*models.Organization, *models.Error) {
+	res, _, err := o.client.From("organizations").Insert(createOrganization, false, "", "", "").Execute()
+	if err != nil {
+		description := "An error occurred while creating the organization."
+		o.logger.Error(err.Error())
+
+		return nil, models.NewError(500, "InternalServerError", description)
+	}
+
+	var organizations []*models.Organization
+	err = json.Unmarshal(res, &organizations)
+	if err != nil {
+		description := fmt.Sprintf("Error unmarshalling response: %s", err.Error())
+		return nil, models.NewError(500, "InternalError", description)
+	}
+
+	return organizations[0], nil
+}
+
+func (o *OrganizationRepository) UpdateOrganization(id string, update *organization.UpdateOrganization) (*models.Organization, *models.Error) {
+	res, _, err := o.client.From("organizations").Update(update, "", "").Eq("id", id).Execute()
+	if err != nil {
+		description := "An error occurred while updating the organization."
+		o.logger.Error(err.Error())
+
+		return nil, models.NewError(500, "InternalServerError", description)
+	}
+
+	var organizations []*models.Organization
+	err = json.Unmarshal(res, &organizations)
+	if err != nil {
+		description := fmt.Sprintf("Error unmarshalling response: %s", err.Error())
+		return nil, models.NewError(500, "InternalError", description)
+	}
+
+	if len(organizations) == 0 {
+		description := "Organization not found with id=" + id
+		return nil, models.NewError(404, "NotFound", description)
+	}
+
+	return organizations[0], nil
+}
+
+// DeleteOrganization deletes the organization together with its members,
+// collections and items.
+func (o *OrganizationRepository) DeleteOrganization(id string) (*models.Organization, *models.Error) {
+	res, _, err := o.client.From("organizations").Delete("", "").Eq("id", id).Execute()
+	if err != nil {
+		description := fmt.Sprintf("Error deleting organization: %s", err.Error())
+		return nil, models.NewError(500, "InternalError", description)
+	}
+
+	var organizations []*models.Organization
+	err = json.Unmarshal(res, &organizations)
+	if err != nil {
+		description := fmt.Sprintf("Error unmarshalling response: %s", err.Error())
+		return nil, models.NewError(500, "InternalError", description)
+	}
+
+	if len(organizations) == 0 {
+		description := "No organization found"
+		return nil, models.NewError(404, "NotFound", description)
+	}
+
+	return organizations[0], nil
+}
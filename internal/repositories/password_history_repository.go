@@ -0,0 +1,50 @@
+package repositories
+
+import (
+	"encoding/json"
+
+	"github.com/safepass/server/internal/logging"
+	"github.com/safepass/server/pkg/models"
+	"github.com/supabase-community/postgrest-go"
+	"github.com/supabase-community/supabase-go"
+)
+
+type PasswordHistoryRepositoryMethods interface {
+	GetHistory(passwordID string) ([]*models.PasswordHistory, *models.Error)
+}
+
+type PasswordHistoryRepository struct {
+	client *supabase.Client
+	logger *logging.Logger
+
+	PasswordHistoryRepositoryMethods
+}
+
+func NewPasswordHistoryRepository(client *supabase.Client, logger *logging.Logger) *PasswordHistoryRepository {
+	return &PasswordHistoryRepository{
+		client: client,
+		logger: logger,
+	}
+}
+
+// GetHistory returns the previous passwords of a password, newest first.
+func (p *PasswordHistoryRepository) GetHistory(passwordID string) ([]*models.PasswordHistory, *models.Error) {
+	res, _, err := p.client.From("password_history").Select("*", "", false).Eq("password_id", passwordID).Order("created_at", &postgrest.OrderOpts{Ascending: false}).Execute()
+	if err != nil {
+		description := "An error occurred while retrieving the password history."
+		p.logger.Error(err.Error())
+
+		return nil, models.NewError(500, "InternalServerError", description)
+	}
+
+	var history []*models.PasswordHistory
+	err = json.Unmarshal(res, &history)
+	if err != nil {
+		description := "An error occurred while retrieving the password history."
+		p.logger.Error(err.Error())
+
+		return nil, models.NewError(500, "InternalServerError", description)
+	}
+
+	return history, nil
+}
@@ -4,20 +4,52 @@ import (
 	"encoding/json"
 	"fmt"
 	"strconv"
+	"strings"
+	"time"
 
 	"github.com/safepass/server/internal/logging"
+	"github.com/safepass/server/pkg/dtos/organization"
 	"github.com/safepass/server/pkg/dtos/password"
 	"github.com/safepass/server/pkg/models"
+	"github.com/supabase-community/postgrest-go"
 	"github.com/supabase-community/supabase-go"
 )
 
+// passwordColumns selects a password with the ids of its tags and its
+// attachments.
+const passwordColumns = "*, tags:password_tags (tag_id), attachments (id, encrypted_file_name, encrypted_key, size, created_at)"
+
 type PasswordRepositoryMethods interface {
 	GetPasswords() ([]*models.Password, *models.Error)
 	GetPassword(string) (*models.Password, *models.Error)
-	GetPasswordsByVaultID(string) ([]*models.Password, *models.Error)
+	GetPasswordsByVaultID(string, *password.PasswordFilter) ([]*models.Password, *models.Error)
+	GetPasswordsChangedSince(vaultID string, since int64) ([]*models.Password, *models.Error)
+	GetPasswordsWithTrash(passwordIDs []string) ([]*models.Password, *models.Error)
+	GetPasswordsByClientIDs(vaultID string, clientIDs []string) ([]*models.Password, *models.Error)
+	ApplyBatch(vaultID int, writes []*password.BatchWrite, historyLimit int) (*password.BatchWriteResult, *models.Error)
+	ImportPasswords(userID int, vaultID int, folderNames []string, writes []*password.ImportWrite) (*password.ImportResponse, *models.Error)
+	GetTrashedPasswords(vaultID string) ([]*models.Password, *models.Error)
+	GetTrashedPassword(passwordID string, vaultID string) (*models.Password, *models.Error)
+	TrashPassword(passwordID string, vaultID string) (*models.Password, *models.Error)
+	UntrashPassword(passwordID string, vaultID string) (*models.Password, *models.Error)
+	PurgeTrashedPasswords(before time.Time) *models.Error
+	MovePasswords(vaultID string, passwordIDs []string, folderID *int) ([]*models.Password, *models.Error)
+	GetPasswordsByIDs(vaultID string, passwordIDs []string) ([]*models.Password, *models.Error)
+	GetPasswordsByFolderID(folderID string) ([]*models.Password, *models.Error)
+	GetPasswordsByTagID(tagID string) ([]*models.Password, *models.Error)
+	SetFavorite(passwordID string, vaultID string, favorite bool) (*models.Password, *models.Error)
+	RestorePasswordHistory(passwordID int, vaultID int, historyID int, historyLimit int) (*models.Password, *models.Error)
+	SetTags(passwordID int, tagIDs []int) *models.Error
+	AddTags(tags []*models.PasswordTag) *models.Error
+	RemoveTags(passwordIDs []string, tagIDs []string) *models.Error
 	CreatePassword(*password.CreatePassword) (*models.Password, *models.Error)
-	UpdatePassword(string, *password.CreatePassword) (*models.Password, *models.Error)
+	UpdatePassword(string, *password.CreatePassword, *int64, int) (*models.Password, *models.Error)
 	DeletePassword(string, string) (*models.Password, *models.Error)
+
+	GetPasswordsByCollectionIDs([]string) ([]*models.Password, *models.Error)
+	CreateOrganizationPassword(*organization.CreateOrganizationPassword) (*models.Password, *models.Error)
+	UpdateOrganizationPassword(string, *organization.CreateOrganizationPassword) (*models.Password, *models.Error)
+	DeleteOrganizationPassword(string, string) (*models.Password, *models.Error)
 }
 
 type PasswordRepository struct {
@@ -60,7 +92,7 @@ func (p *PasswordRepository) GetPasswords() ([]*models.Password, *models.Error)
 }
 
 func (p *PasswordRepository) GetPassword(id string) (*models.Password, *models.Error) {
-	res, _, err := p.client.From("passwords").Select("*", "1", false).Eq("id", id).Execute()
+	res, _, err := p.client.From("passwords").Select(passwordColumns, "1", false).Eq("id", id).Is("deleted_at", "null").Execute()
 	if err != nil {
 		description := "An error occurred while retrieving password."
 		p.logger.Error(err.Error())
@@ -85,8 +117,36 @@ func (p *PasswordRepository) GetPassword(id string) (*models.Password, *models.E
 	return password[0], nil
 }
 
-func (p *PasswordRepository) GetPasswordsByVaultID(vaultID string) ([]*models.Password, *models.Error) {
-	res, n, err := p.client.From("passwords").Select("*", "exact", false).Eq("vault_id", vaultID).Execute()
+func (p *PasswordRepository) GetPasswordsByVaultID(vaultID string, filter *password.PasswordFilter) ([]*models.Password, *models.Error) {
+	query := p.client.From("passwords").Select(passwordColumns, "exact", false).Eq("vault_id", vaultID).Is("deleted_at", "null")
+	if filter != nil && filter.Type != "" {
+		query = query.Eq("type", filter.Type)
+	}
+
+	if filter != nil && filter.Favorite {
+		query = query.Eq("favorite", "true")
+	}
+
+	if filter != nil && filter.Tag != "" {
+		ids, merr := p.getPasswordIDsByTag(filter.Tag)
+		if merr != nil {
+			return nil, merr
+		}
+
+		if len(ids) == 0 {
+			return []*models.Password{}, nil
+		}
+
+		query = query.In("id", ids)
+	}
+
+	if filter != nil && filter.Folder == "none" {
+		query = query.Is("folder_id", "null")
+	} else if filter != nil && filter.Folder != "" {
+		query = query.Eq("folder_id", filter.Folder)
+	}
+
+	res, n, err := query.Execute()
 	if err != nil {
 		description := "An error occurred while retrieving passwords."
 		p.logger.Error(err.Error())
@@ -131,8 +191,323 @@ func (p *PasswordRepository) CreatePassword(createPassword *password.CreatePassw
 	return passwords[0], nil
 }
 
-func (p *PasswordRepository) UpdatePassword(passwordID string, createPassword *password.CreatePassword) (*models.Password, *models.Error) {
-	res, _, err := p.client.From("passwords").Update(createPassword, "", "1").Eq("id", passwordID).Eq("vault_id", strconv.Itoa(createPassword.VaultID)).Execute()
+// UpdatePassword replaces a password of the vault and records the replaced
+// encrypted password in its history, up to historyLimit entries, in a single
+// transaction. With a revision the update only applies to that revision and a
+// 409 is returned when the password has changed since.
+func (p *PasswordRepository) UpdatePassword(passwordID string, createPassword *password.CreatePassword, revision *int64, historyLimit int) (*models.Password, *models.Error) {
+	params := map[string]interface{}{
+		"p_password_id":   passwordID,
+		"p_vault_id":      createPassword.VaultID,
+		"p_item":          createPassword,
+		"p_revision":      revision,
+		"p_history_limit": historyLimit,
+	}
+
+	var passwords []*models.Password
+	err := callRpc(p.client, "update_password", params, &passwords)
+	if err != nil && strings.Contains(err.Error(), "(40001)") {
+		description := "Password id=" + passwordID + " has changed since the given revision."
+		if revision != nil {
+			description = "Password id=" + passwordID + " has changed since revision " + strconv.FormatInt(*revision, 10) + "."
+		}
+
+		return nil, models.NewError(409, "Conflict", description)
+	}
+
+	if err != nil && strings.Contains(err.Error(), "(P0002)") {
+		description := "No password found with id=" + passwordID
+		return nil, models.NewError(404, "NotFound", description)
+	}
+
+	if err != nil || len(passwords) == 0 {
+		description := "An error occurred while updating the password."
+		if err != nil {
+			p.logger.Error(err.Error())
+		}
+
+		return nil, models.NewError(500, "InternalServerError", description)
+	}
+
+	return passwords[0], nil
+}
+
+// MovePasswords sets the folder of the vault's passwords with the given ids,
+// a nil folder moves them out of their folder. Passwords of other vaults are
+// left untouched; the moved passwords are returned.
+func (p *PasswordRepository) MovePasswords(vaultID string, passwordIDs []string, folderID *int) ([]*models.Password, *models.Error) {
+	update := map[string]interface{}{"folder_id": folderID}
+
+	res, _, err := p.client.From("passwords").Update(update, "", "").In("id", passwordIDs).Eq("vault_id", vaultID).Is("deleted_at", "null").Execute()
+	if err != nil {
+		description := "An error occurred while moving passwords."
+		p.logger.Error(err.Error())
+
+		return nil, models.NewError(500, "InternalServerError", description)
+	}
+
+	var passwords []*models.Password
+	err = json.Unmarshal(res, &passwords)
+	if err != nil {
+		description := "An error occurred while moving passwords."
+		p.logger.Error(err.Error())
+
+		return nil, models.NewError(500, "InternalServerError", description)
+	}
+
+	return passwords, nil
+}
+
+func (p *PasswordRepository) GetPasswordsByIDs(vaultID string, passwordIDs []string) ([]*models.Password, *models.Error) {
+	res, _, err := p.client.From("passwords").Select(passwordColumns, "", false).In("id", passwordIDs).Eq("vault_id", vaultID).Is("deleted_at", "null").Execute()
+	if err != nil {
+		description := "An error occurred while retrieving passwords."
+		p.logger.Error(err.Error())
+
+		return nil, models.NewError(500, "InternalServerError", description)
+	}
+
+	var passwords []*models.Password
+	err = json.Unmarshal(res, &passwords)
+	if err != nil {
+		description := "An error occurred while retrieving passwords."
+		p.logger.Error(err.Error())
+
+		return nil, models.NewError(500, "InternalServerError", description)
+	}
+
+	return passwords, nil
+}
+
+func (p *PasswordRepository) SetFavorite(passwordID string, vaultID string, favorite bool) (*models.Password, *models.Error) {
+	update := map[string]interface{}{"favorite": favorite}
+
+	res, _, err := p.client.From("passwords").Update(update, "", "").Eq("id", passwordID).Eq("vault_id", vaultID).Execute()
+	if err != nil {
+		description := "An error occurred while updating the password."
+		p.logger.Error(err.Error())
+
+		return nil, models.NewError(500, "InternalServerError", description)
+	}
+
+	var passwords []*models.Password
+	err = json.Unmarshal(res, &passwords)
+	if err != nil {
+		description := "An error occurred while updating the password."
+		p.logger.Error(err.Error())
+
+		return nil, models.NewError(500, "InternalServerError", description)
+	}
+
+	if len(passwords) == 0 {
+		description := "Password not found with id=" + passwordID
+		return nil, models.NewError(404, "NotFound", description)
+	}
+
+	return passwords[0], nil
+}
+
+// RestorePasswordHistory makes the encrypted password of a history entry the
+// current one and records the replaced one in the history, in a single
+// transaction.
+func (p *PasswordRepository) RestorePasswordHistory(passwordID int, vaultID int, historyID int, historyLimit int) (*models.Password, *models.Error) {
+	params := map[string]interface{}{
+		"p_password_id":   passwordID,
+		"p_vault_id":      vaultID,
+		"p_history_id":    historyID,
+		"p_history_limit": historyLimit,
+	}
+
+	var passwords []*models.Password
+	err := callRpc(p.client, "restore_password_history", params, &passwords)
+	if err != nil && strings.Contains(err.Error(), "(P0002)") {
+		description := "Password history entry not found with id=" + strconv.Itoa(historyID)
+		return nil, models.NewError(404, "NotFound", description)
+	}
+
+	if err != nil || len(passwords) == 0 {
+		description := "An error occurred while updating the password."
+		if err != nil {
+			p.logger.Error(err.Error())
+		}
+
+		return nil, models.NewError(500, "InternalServerError", description)
+	}
+
+	return passwords[0], nil
+}
+
+// SetTags replaces the tags of a password.
+func (p *PasswordRepository) SetTags(passwordID int, tagIDs []int) *models.Error {
+	_, _, err := p.client.From("password_tags").Delete("minimal", "").Eq("password_id", strconv.Itoa(passwordID)).Execute()
+	if err != nil {
+		description := "An error occurred while updating tags."
+		p.logger.Error(err.Error())
+
+		return models.NewError(500, "InternalServerError", description)
+	}
+
+	tags := make([]*models.PasswordTag, 0, len(tagIDs))
+	for _, tagID := range tagIDs {
+		tags = append(tags, &models.PasswordTag{PasswordID: passwordID, TagID: tagID})
+	}
+
+	return p.AddTags(tags)
+}
+
+// AddTags puts tags on passwords. Tags a password already has are kept.
+func (p *PasswordRepository) AddTags(tags []*models.PasswordTag) *models.Error {
+	if len(tags) == 0 {
+		return nil
+	}
+
+	_, _, err := p.client.From("password_tags").Upsert(tags, "password_id,tag_id", "minimal", "").Execute()
+	if err != nil {
+		description := "An error occurred while updating tags."
+		p.logger.Error(err.Error())
+
+		return models.NewError(500, "InternalServerError", description)
+	}
+
+	return nil
+}
+
+// RemoveTags takes the tags off the passwords.
+func (p *PasswordRepository) RemoveTags(passwordIDs []string, tagIDs []string) *models.Error {
+	_, _, err := p.client.From("password_tags").Delete("minimal", "").In("password_id", passwordIDs).In("tag_id", tagIDs).Execute()
+	if err != nil {
+		description := "An error occurred while updating tags."
+		p.logger.Error(err.Error())
+
+		return models.NewError(500, "InternalServerError", description)
+	}
+
+	return nil
+}
+
+// GetPasswordsByFolderID returns the id and vault of the passwords in the
+// folder, including the ones in the trash.
+func (p *PasswordRepository) GetPasswordsByFolderID(folderID string) ([]*models.Password, *models.Error) {
+	return p.getPasswordRefs(p.client.From("passwords").Select("id, vault_id", "", false).Eq("folder_id", folderID))
+}
+
+// GetPasswordsByTagID returns the id and vault of the passwords with the tag,
+// including the ones in the trash.
+func (p *PasswordRepository) GetPasswordsByTagID(tagID string) ([]*models.Password, *models.Error) {
+	ids, merr := p.getPasswordIDsByTag(tagID)
+	if merr != nil || len(ids) == 0 {
+		return []*models.Password{}, merr
+	}
+
+	return p.getPasswordRefs(p.client.From("passwords").Select("id, vault_id", "", false).In("id", ids))
+}
+
+func (p *PasswordRepository) getPasswordRefs(query *postgrest.FilterBuilder) ([]*models.Password, *models.Error) {
+	res, _, err := query.Execute()
+	if err != nil {
+		description := "An error occurred while retrieving passwords."
+		p.logger.Error(err.Error())
+
+		return nil, models.NewError(500, "InternalServerError", description)
+	}
+
+	var passwords []*models.Password
+	err = json.Unmarshal(res, &passwords)
+	if err != nil {
+		description := "An error occurred while retrieving passwords."
+		p.logger.Error(err.Error())
+
+		return nil, models.NewError(500, "InternalServerError", description)
+	}
+
+	return passwords, nil
+}
+
+func (p *PasswordRepository) getPasswordIDsByTag(tagID string) ([]string, *models.Error) {
+	res, _, err := p.client.From("password_tags").Select("password_id", "", false).Eq("tag_id", tagID).Execute()
+	if err != nil {
+		description := "An error occurred while retrieving passwords."
+		p.logger.Error(err.Error())
+
+		return nil, models.NewError(500, "InternalServerError", description)
+	}
+
+	var tags []*models.PasswordTag
+	err = json.Unmarshal(res, &tags)
+	if err != nil {
+		description := "An error occurred while retrieving passwords."
+		p.logger.Error(err.Error())
+
+		return nil, models.NewError(500, "InternalServerError", description)
+	}
+
+	ids := make([]string, 0, len(tags))
+	for _, tag := range tags {
+		ids = append(ids, strconv.Itoa(tag.PasswordID))
+	}
+
+	return ids, nil
+}
+
+// GetPasswordsChangedSince returns the passwords of the vault changed after the
+// revision, including the ones in the trash.
+func (p *PasswordRepository) GetPasswordsChangedSince(vaultID string, since int64) ([]*models.Password, *models.Error) {
+	passwords := []*models.Password{}
+	lastID := 0
+	for {
+		res, _, err := p.client.From("passwords").Select(passwordColumns, "", false).Eq("vault_id", vaultID).Gt("revision", strconv.FormatInt(since, 10)).Gt("id", strconv.Itoa(lastID)).Order("id", &postgrest.OrderOpts{Ascending: true}).Limit(syncPageSize, "").Execute()
+		if err != nil {
+			description := "An error occurred while retrieving passwords."
+			p.logger.Error(err.Error())
+
+			return nil, models.NewError(500, "InternalServerError", description)
+		}
+
+		var page []*models.Password
+		err = json.Unmarshal(res, &page)
+		if err != nil {
+			description := "An error occurred while retrieving passwords."
+			p.logger.Error(err.Error())
+
+			return nil, models.NewError(500, "InternalServerError", description)
+		}
+
+		if len(page) == 0 {
+			return passwords, nil
+		}
+
+		passwords = append(passwords, page...)
+		lastID = page[len(page)-1].ID
+	}
+}
+
+// GetPasswordsWithTrash returns the passwords with the given ids, including
+// the ones in the trash.
+func (p *PasswordRepository) GetPasswordsWithTrash(passwordIDs []string) ([]*models.Password, *models.Error) {
+	res, _, err := p.client.From("passwords").Select(passwordColumns, "", false).In("id", passwordIDs).Execute()
+	if err != nil {
+		description := "An error occurred while retrieving passwords."
+		p.logger.Error(err.Error())
+
+		return nil, models.NewError(500, "InternalServerError", description)
+	}
+
+	var passwords []*models.Password
+	err = json.Unmarshal(res, &passwords)
+	if err != nil {
+		description := "An error occurred while retrieving passwords."
+		p.logger.Error(err.Error())
+
+		return nil, models.NewError(500, "InternalServerError", description)
+	}
+
+	return passwords, nil
+}
+
+// GetPasswordsByClientIDs returns the vault's passwords that were created with
+// the given client ids, including the ones in the trash.
+func (p *PasswordRepository) GetPasswordsByClientIDs(vaultID string, clientIDs []string) ([]*models.Password, *models.Error) {
+	res, _, err := p.client.From("passwords").Select(passwordColumns, "", false).Eq("vault_id", vaultID).In("client_id", clientIDs).Execute()
 	if err != nil {
 		description := "An error occurred while retrieving passwords."
 		p.logger.Error(err.Error())
@@ -149,9 +524,168 @@ func (p *PasswordRepository) UpdatePassword(passwordID string, createPassword *p
 		return nil, models.NewError(500, "InternalServerError", description)
 	}
 
+	return passwords, nil
+}
+
+// ApplyBatch applies the writes of a sync batch in a single transaction. When
+// an item has changed since the writes were planned nothing is applied and a
+// 409 is returned.
+func (p *PasswordRepository) ApplyBatch(vaultID int, writes []*password.BatchWrite, historyLimit int) (*password.BatchWriteResult, *models.Error) {
+	params := map[string]interface{}{
+		"p_vault_id":      vaultID,
+		"p_writes":        writes,
+		"p_history_limit": historyLimit,
+	}
+
+	var result password.BatchWriteResult
+	err := callRpc(p.client, "apply_password_batch", params, &result)
+	if err != nil && strings.Contains(err.Error(), "(40001)") {
+		return nil, models.NewError(409, "Conflict", "The vault changed while the batch was applied.")
+	}
+
+	if err != nil {
+		description := "An error occurred while applying the batch."
+		p.logger.Error(err.Error())
+
+		return nil, models.NewError(500, "InternalServerError", description)
+	}
+
+	return &result, nil
+}
+
+// ImportPasswords creates the folders and the items of an import in a single
+// transaction.
+func (p *PasswordRepository) ImportPasswords(userID int, vaultID int, folderNames []string, writes []*password.ImportWrite) (*password.ImportResponse, *models.Error) {
+	params := map[string]interface{}{
+		"p_user_id":  userID,
+		"p_vault_id": vaultID,
+		"p_folders":  folderNames,
+		"p_items":    writes,
+	}
+
+	var result password.ImportResponse
+	err := callRpc(p.client, "import_passwords", params, &result)
+	if err != nil {
+		description := "An error occurred while importing the items."
+		p.logger.Error(err.Error())
+
+		return nil, models.NewError(500, "InternalServerError", description)
+	}
+
+	return &result, nil
+}
+
+// GetTrashedPasswords returns the passwords in the vault's trash.
+func (p *PasswordRepository) GetTrashedPasswords(vaultID string) ([]*models.Password, *models.Error) {
+	res, _, err := p.client.From("passwords").Select(passwordColumns, "", false).Eq("vault_id", vaultID).Not("deleted_at", "is", "null").Execute()
+	if err != nil {
+		description := "An error occurred while retrieving the trash."
+		p.logger.Error(err.Error())
+
+		return nil, models.NewError(500, "InternalServerError", description)
+	}
+
+	var passwords []*models.Password
+	err = json.Unmarshal(res, &passwords)
+	if err != nil {
+		description := "An error occurred while retrieving the trash."
+		p.logger.Error(err.Error())
+
+		return nil, models.NewError(500, "InternalServerError", description)
+	}
+
+	return passwords, nil
+}
+
+func (p *PasswordRepository) GetTrashedPassword(passwordID string, vaultID string) (*models.Password, *models.Error) {
+	res, _, err := p.client.From("passwords").Select(passwordColumns, "", false).Eq("id", passwordID).Eq("vault_id", vaultID).Not("deleted_at", "is", "null").Execute()
+	if err != nil {
+		description := "An error occurred while retrieving the trash."
+		p.logger.Error(err.Error())
+
+		return nil, models.NewError(500, "InternalServerError", description)
+	}
+
+	var passwords []*models.Password
+	err = json.Unmarshal(res, &passwords)
+	if err != nil {
+		description := "An error occurred while retrieving the trash."
+		p.logger.Error(err.Error())
+
+		return nil, models.NewError(500, "InternalServerError", description)
+	}
+
+	if len(passwords) == 0 {
+		description := "No password found in the trash with id=" + passwordID
+		return nil, models.NewError(404, "NotFound", description)
+	}
+
+	return passwords[0], nil
+}
+
+// TrashPassword moves a password of the vault to the trash.
+func (p *PasswordRepository) TrashPassword(passwordID string, vaultID string) (*models.Password, *models.Error) {
+	deletedAt := time.Now().UTC().Format(time.RFC3339)
+
+	return p.setDeletedAt(passwordID, vaultID, &deletedAt)
+}
+
+// UntrashPassword moves a password of the vault out of the trash.
+func (p *PasswordRepository) UntrashPassword(passwordID string, vaultID string) (*models.Password, *models.Error) {
+	return p.setDeletedAt(passwordID, vaultID, nil)
+}
+
+// setDeletedAt moves a password into the trash, or out of it when deletedAt
+// is nil. Passwords that already are where they would be moved are not found.
+func (p *PasswordRepository) setDeletedAt(passwordID string, vaultID string, deletedAt *string) (*models.Password, *models.Error) {
+	update := map[string]interface{}{"deleted_at": deletedAt}
+
+	query := p.client.From("passwords").Update(update, "", "").Eq("id", passwordID).Eq("vault_id", vaultID)
+	if deletedAt != nil {
+		query = query.Is("deleted_at", "null")
+	} else {
+		query = query.Not("deleted_at", "is", "null")
+	}
+
+	res, _, err := query.Execute()
+	if err != nil {
+		description := "An error occurred while updating the password."
+		p.logger.Error(err.Error())
+
+		return nil, models.NewError(500, "InternalServerError", description)
+	}
+
+	var passwords []*models.Password
+	err = json.Unmarshal(res, &passwords)
+	if err != nil {
+		description := "An error occurred while updating the password."
+		p.logger.Error(err.Error())
+
+		return nil, models.NewError(500, "InternalServerError", description)
+	}
+
+	if len(passwords) == 0 {
+		description := "No password found with id=" + passwordID
+		return nil, models.NewError(404, "NotFound", description)
+	}
+
 	return passwords[0], nil
 }
 
+// PurgeTrashedPasswords deletes the passwords that were moved to the trash
+// before the given time.
+func (p *PasswordRepository) PurgeTrashedPasswords(before time.Time) *models.Error {
+	_, _, err := p.client.From("passwords").Delete("minimal", "").Lt("deleted_at", before.UTC().Format(time.RFC3339)).Execute()
+	if err != nil {
+		description := "An error occurred while purging the trash."
+		p.logger.Error(err.Error())
+
+		return models.NewError(500, "InternalServerError", description)
+	}
+
+	return nil
+}
+
 func (v *PasswordRepository) DeletePassword(passwordID string, vaultID string) (*models.Password, *models.Error) {
 	res, _, err := v.client.From("passwords").Delete("", "1").Eq("id", passwordID).Eq("vault_id", vaultID).Execute()
 	if err != nil {
@@ -179,3 +713,103 @@ func (v *PasswordRepository) DeletePassword(passwordID string, vaultID string) (
 
 	return response[0], nil
 }
+
+func (p *PasswordRepository) GetPasswordsByCollectionIDs(collectionIDs []string) ([]*models.Password, *models.Error) {
+	if len(collectionIDs) == 0 {
+		return []*models.Password{}, nil
+	}
+
+	res, _, err := p.client.From("passwords").Select("*", "", false).In("collection_id", collectionIDs).Execute()
+	if err != nil {
+		description := "An error occurred while retrieving passwords."
+		p.logger.Error(err.Error())
+
+		return nil, models.NewError(500, "InternalServerError", description)
+	}
+
+	var passwords []*models.Password
+	err = json.Unmarshal(res, &passwords)
+	if err != nil {
+		description := "An error occurred while retrieving passwords."
+		p.logger.Error(err.Error())
+
+		return nil, models.NewError(500, "InternalServerError", description)
+	}
+
+	return passwords, nil
+}
+
+func (p *PasswordRepository) CreateOrganizationPassword(createPassword *organization.CreateOrganizationPassword) (*models.Password, *models.Error) {
+	res, _, err := p.client.From("passwords").Insert(createPassword, false, "", "", "1").Execute()
+	if err != nil {
+		description := "An error occurred while creating the password."
+		p.logger.Error(err.Error())
+
+		return nil, models.NewError(500, "InternalServerError", description)
+	}
+
+	var passwords []*models.Password
+	err = json.Unmarshal(res, &passwords)
+	if err != nil {
+		description := "An error occurred while creating the password."
+		p.logger.Error(err.Error())
+
+		return nil, models.NewError(500, "InternalServerError", description)
+	}
+
+	return passwords[0], nil
+}
+
+func (p *PasswordRepository) UpdateOrganizationPassword(passwordID string, createPassword *organization.CreateOrganizationPassword) (*models.Password, *models.Error) {
+	res, _, err := p.client.From("passwords").Update(createPassword, "", "1").Eq("id", passwordID).Eq("organization_id", strconv.Itoa(createPassword.OrganizationID)).Execute()
+	if err != nil {
+		description := "An error occurred while updating the password."
+		p.logger.Error(err.Error())
+
+		return nil, models.NewError(500, "InternalServerError", description)
+	}
+
+	var passwords []*models.Password
+	err = json.Unmarshal(res, &passwords)
+	if err != nil {
+		description := "An error occurred while updating the password."
+		p.logger.Error(err.Error())
+
+		return nil, models.NewError(500, "InternalServerError", description)
+	}
+
+	if len(passwords) == 0 {
+		description := "Password not found with id=" + passwordID
+		return nil, models.NewError(404, "NotFound", description)
+	}
+
+	return passwords[0], nil
+}
+
+func (p *PasswordRepository) DeleteOrganizationPassword(passwordID string, collectionID string) (*models.Password, *models.Error) {
+	res, _, err := p.client.From("passwords").Delete("", "1").Eq("id", passwordID).Eq("collection_id", collectionID).Execute()
+	if err != nil {
+		description := fmt.Sprintf("Error deleting password: %s", err.Error())
+		errModel := models.NewError(500, "InternalError", description)
+
+		return nil, errModel
+	}
+
+	var response []*models.Password
+	err = json.Unmarshal(res, &response)
+	if err != nil {
+		description := fmt.Sprintf("Error unmarshalling response: %s", err.Error())
+		errModel := models.NewError(500, "InternalError", description)
+
+		return nil, errModel
+	}
+
+	if len(response) == 0 {
+		description := "No password found"
+		errModel := models.NewError(404, "NotFound", description)
+
+		return nil, errModel
+	}
+
+	return response[0], nil
+}
@@ -0,0 +1,235 @@
+package repositories
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+
+	"github.com/safepass/server/internal/logging"
+	"github.com/safepass/server/pkg/dtos/password"
+	"github.com/safepass/server/pkg/models"
+	"github.com/supabase-community/postgrest-go"
+	"github.com/supabase-community/supabase-go"
+)
+
+const passwordShareColumns = "*, owner:users!password_shares_owner_id_fkey (id, username, email), recipient:users!password_shares_recipient_id_fkey (id, username, email)"
+
+type PasswordShareRepositoryMethods interface {
+	GetShare(id string) (*models.PasswordShare, *models.Error)
+	GetShareByRecipient(passwordID string, recipientID string) (*models.PasswordShare, *models.Error)
+	GetSharesByPasswordID(passwordID string) ([]*models.PasswordShare, *models.Error)
+	GetSharesByPasswordIDs(passwordIDs []string) ([]*models.PasswordShare, *models.Error)
+	GetSharesByRecipientID(recipientID string) ([]*models.PasswordShare, *models.Error)
+	GetSharesByRecipientChangedSince(recipientID string, since int64) ([]*models.PasswordShare, *models.Error)
+	UpsertShare(*password.CreateShare) (*models.PasswordShare, *models.Error)
+	DeleteShare(id string) (*models.PasswordShare, *models.Error)
+}
+
+type PasswordShareRepository struct {
+	client *supabase.Client
+	logger *logging.Logger
+
+	PasswordShareRepositoryMethods
+}
+
+func NewPasswordShareRepository(client *supabase.Client, logger *logging.Logger) *PasswordShareRepository {
+	return &PasswordShareRepository{
+		client: client,
+		logger: logger,
+	}
+}
+
+func (p *PasswordShareRepository) GetShare(id string) (*models.PasswordShare, *models.Error) {
+	res, _, err := p.client.From("password_shares").Select(passwordShareColumns, "", false).Eq("id", id).Execute()
+	if err != nil {
+		description := "An error occurred while retrieving the share."
+		p.logger.Error(err.Error())
+
+		return nil, models.NewError(500, "InternalServerError", description)
+	}
+
+	var shares []*models.PasswordShare
+	err = json.Unmarshal(res, &shares)
+	if err != nil {
+		description := "An error occurred while retrieving the share."
+		p.logger.Error(err.Error())
+
+		return nil, models.NewError(500, "InternalServerError", description)
+	}
+
+	if len(shares) == 0 {
+		description := "Share not found with id=" + id
+		return nil, models.NewError(404, "NotFound", description)
+	}
+
+	return shares[0], nil
+}
+
+func (p *PasswordShareRepository) GetShareByRecipient(passwordID string, recipientID string) (*models.PasswordShare, *models.Error) {
+	res, _, err := p.client.From("password_shares").Select("*", "", false).Eq("password_id", passwordID).Eq("recipient_id", recipientID).Execute()
+	if err != nil {
+		description := "An error occurred while retrieving the share."
+		p.logger.Error(err.Error())
+
+		return nil, models.NewError(500, "InternalServerError", description)
+	}
+
+	var shares []*models.PasswordShare
+	err = json.Unmarshal(res, &shares)
+	if err != nil {
+		description := "An error occurred while retrieving the share."
+		p.logger.Error(err.Error())
+
+		return nil, models.NewError(500, "InternalServerError", description)
+	}
+
+	if len(shares) == 0 {
+		description := "Share not found"
+		return nil, models.NewError(404, "NotFound", description)
+	}
+
+	return shares[0], nil
+}
+
+func (p *PasswordShareRepository) GetSharesByPasswordID(passwordID string) ([]*models.PasswordShare, *models.Error) {
+	res, _, err := p.client.From("password_shares").Select(passwordShareColumns, "", false).Eq("password_id", passwordID).Execute()
+	if err != nil {
+		description := "An error occurred while retrieving shares."
+		p.logger.Error(err.Error())
+
+		return nil, models.NewError(500, "InternalServerError", description)
+	}
+
+	var shares []*models.PasswordShare
+	err = json.Unmarshal(res, &shares)
+	if err != nil {
+		description := "An error occurred while retrieving shares."
+		p.logger.Error(err.Error())
+
+		return nil, models.NewError(500, "InternalServerError", description)
+	}
+
+	return shares, nil
+}
+
+// GetSharesByPasswordIDs returns the shares of the passwords with the given
+// ids.
+func (p *PasswordShareRepository) GetSharesByPasswordIDs(passwordIDs []string) ([]*models.PasswordShare, *models.Error) {
+	res, _, err := p.client.From("password_shares").Select("*", "", false).In("password_id", passwordIDs).Execute()
+	if err != nil {
+		description := "An error occurred while retrieving shares."
+		p.logger.Error(err.Error())
+
+		return nil, models.NewError(500, "InternalServerError", description)
+	}
+
+	var shares []*models.PasswordShare
+	err = json.Unmarshal(res, &shares)
+	if err != nil {
+		description := "An error occurred while retrieving shares."
+		p.logger.Error(err.Error())
+
+		return nil, models.NewError(500, "InternalServerError", description)
+	}
+
+	return shares, nil
+}
+
+// GetSharesByRecipientID returns the shares made to a user with the shared
+// password embedded.
+func (p *PasswordShareRepository) GetSharesByRecipientID(recipientID string) ([]*models.PasswordShare, *models.Error) {
+	res, _, err := p.client.From("password_shares").Select(passwordShareColumns+", password:passwords (*)", "", false).Eq("recipient_id", recipientID).Execute()
+	if err != nil {
+		description := "An error occurred while retrieving shares."
+		p.logger.Error(err.Error())
+
+		return nil, models.NewError(500, "InternalServerError", description)
+	}
+
+	var shares []*models.PasswordShare
+	err = json.Unmarshal(res, &shares)
+	if err != nil {
+		description := "An error occurred while retrieving shares."
+		p.logger.Error(err.Error())
+
+		return nil, models.NewError(500, "InternalServerError", description)
+	}
+
+	return shares, nil
+}
+
+// GetSharesByRecipientChangedSince returns the shares made to a user that
+// changed after the revision of the user's default vault, with the shared
+// password embedded.
+func (p *PasswordShareRepository) GetSharesByRecipientChangedSince(recipientID string, since int64) ([]*models.PasswordShare, *models.Error) {
+	shares := []*models.PasswordShare{}
+	lastID := 0
+	for {
+		res, _, err := p.client.From("password_shares").Select(passwordShareColumns+", password:passwords (*)", "", false).Eq("recipient_id", recipientID).Gt("revision", strconv.FormatInt(since, 10)).Gt("id", strconv.Itoa(lastID)).Order("id", &postgrest.OrderOpts{Ascending: true}).Limit(syncPageSize, "").Execute()
+		if err != nil {
+			description := "An error occurred while retrieving shares."
+			p.logger.Error(err.Error())
+
+			return nil, models.NewError(500, "InternalServerError", description)
+		}
+
+		var page []*models.PasswordShare
+		err = json.Unmarshal(res, &page)
+		if err != nil {
+			description := "An error occurred while retrieving shares."
+			p.logger.Error(err.Error())
+
+			return nil, models.NewError(500, "InternalServerError", description)
+		}
+
+		if len(page) == 0 {
+			return shares, nil
+		}
+
+		shares = append(shares, page...)
+		lastID = page[len(page)-1].ID
+	}
+}
+
+// UpsertShare creates the share or replaces the permission and wrapped key of
+// an existing share with the same recipient.
+func (p *PasswordShareRepository) UpsertShare(createShare *password.CreateShare) (*models.PasswordShare, *models.Error) {
+	res, _, err := p.client.From("password_shares").Upsert(createShare, "password_id,recipient_id", "", "").Execute()
+	if err != nil {
+		description := "An error occurred while sharing the password."
+		p.logger.Error(err.Error())
+
+		return nil, models.NewError(500, "InternalServerError", description)
+	}
+
+	var shares []*models.PasswordShare
+	err = json.Unmarshal(res, &shares)
+	if err != nil {
+		description := fmt.Sprintf("Error unmarshalling response: %s", err.Error())
+		return nil, models.NewError(500, "InternalError", description)
+	}
+
+	return shares[0], nil
+}
+
+func (p *PasswordShareRepository) DeleteShare(id string) (*models.PasswordShare, *models.Error) {
+	res, _, err := p.client.From("password_shares").Delete("", "").Eq("id", id).Execute()
+	if err != nil {
+		description := fmt.Sprintf("Error deleting share: %s", err.Error())
+		return nil, models.NewError(500, "InternalError", description)
+	}
+
+	var shares []*models.PasswordShare
+	err = json.Unmarshal(res, &shares)
+	if err != nil {
+		description := fmt.Sprintf("Error unmarshalling response: %s", err.Error())
+		return nil, models.NewError(500, "InternalError", description)
+	}
+
+	if len(shares) == 0 {
+		description := "No share found"
+		return nil, models.NewError(404, "NotFound", description)
+	}
+
+	return shares[0], nil
+}
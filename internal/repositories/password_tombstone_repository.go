@@ -0,0 +1,87 @@
+package repositories
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/safepass/server/internal/logging"
+	"github.com/safepass/server/pkg/models"
+	"github.com/supabase-community/postgrest-go"
+	"github.com/supabase-community/supabase-go"
+)
+
+type PasswordTombstoneRepositoryMethods interface {
+	GetTombstones(vaultID string, since int64) ([]*models.PasswordTombstone, *models.Error)
+	PurgeTombstones(before time.Time) (int, *models.Error)
+}
+
+// PasswordTombstoneRepository reads the tombstones the database leaves for
+// deleted vault items.
+type PasswordTombstoneRepository struct {
+	client *supabase.Client
+	logger *logging.Logger
+
+	PasswordTombstoneRepositoryMethods
+}
+
+func NewPasswordTombstoneRepository(client *supabase.Client, logger *logging.Logger) *PasswordTombstoneRepository {
+	return &PasswordTombstoneRepository{
+		client: client,
+		logger: logger,
+	}
+}
+
+// syncPageSize is how many rows the sync queries read per request. The
+// queries page until a page comes back empty, so a PostgREST max-rows limit
+// below it only means more requests.
+const syncPageSize = 500
+
+// GetTombstones returns the items of the vault deleted after the revision.
+func (p *PasswordTombstoneRepository) GetTombstones(vaultID string, since int64) ([]*models.PasswordTombstone, *models.Error) {
+	tombstones := []*models.PasswordTombstone{}
+	lastID := 0
+	for {
+		res, _, err := p.client.From("password_tombstones").Select("*", "", false).Eq("vault_id", vaultID).Gt("revision", strconv.FormatInt(since, 10)).Gt("id", strconv.Itoa(lastID)).Order("id", &postgrest.OrderOpts{Ascending: true}).Limit(syncPageSize, "").Execute()
+		if err != nil {
+			description := "An error occurred while retrieving deleted items."
+			p.logger.Error(err.Error())
+
+			return nil, models.NewError(500, "InternalServerError", description)
+		}
+
+		var page []*models.PasswordTombstone
+		err = json.Unmarshal(res, &page)
+		if err != nil {
+			description := "An error occurred while retrieving deleted items."
+			p.logger.Error(err.Error())
+
+			return nil, models.NewError(500, "InternalServerError", description)
+		}
+
+		if len(page) == 0 {
+			return tombstones, nil
+		}
+
+		tombstones = append(tombstones, page...)
+		lastID = page[len(page)-1].ID
+	}
+}
+
+// PurgeTombstones deletes the tombstones created before the given time and
+// returns how many were deleted.
+func (p *PasswordTombstoneRepository) PurgeTombstones(before time.Time) (int, *models.Error) {
+	params := map[string]interface{}{
+		"p_before": before.UTC().Format(time.RFC3339),
+	}
+
+	var count int
+	err := callRpc(p.client, "purge_password_tombstones", params, &count)
+	if err != nil {
+		description := fmt.Sprintf("Error purging deleted items: %s", err.Error())
+		return 0, models.NewError(500, "InternalError", description)
+	}
+
+	return count, nil
+}
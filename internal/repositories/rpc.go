@@ -0,0 +1,37 @@
+package repositories
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/supabase-community/supabase-go"
+)
+
+// rpcError is the body PostgREST returns when a database function fails.
+type rpcError struct {
+	Code    string `json:"code"`
+	Message string `json:"message"`
+	Details string `json:"details"`
+	Hint    string `json:"hint"`
+}
+
+// callRpc invokes a Postgres function through PostgREST. Functions that have to
+// run as a single unit of work live in the database, since the REST API does
+// not expose transactions. The result is decoded into out when it is not nil.
+func callRpc(client *supabase.Client, name string, params interface{}, out interface{}) error {
+	res := client.Rpc(name, "", params)
+	if res == "" {
+		return fmt.Errorf("rpc %s: empty response", name)
+	}
+
+	var rerr rpcError
+	if json.Unmarshal([]byte(res), &rerr) == nil && rerr.Code != "" && rerr.Message != "" {
+		return fmt.Errorf("rpc %s: (%s) %s", name, rerr.Code, rerr.Message)
+	}
+
+	if out == nil {
+		return nil
+	}
+
+	return json.Unmarshal([]byte(res), out)
+}
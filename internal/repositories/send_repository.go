@@ -0,0 +1,197 @@
+package repositories
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/safepass/server/internal/logging"
+	"github.com/safepass/server/pkg/dtos/send"
+	"github.com/safepass/server/pkg/models"
+	"github.com/supabase-community/supabase-go"
+)
+
+type SendRepositoryMethods interface {
+	GetSend(id string, userID string) (*models.Send, *models.Error)
+	GetSendByAccessID(accessID string) (*models.Send, *models.Error)
+	GetSendsByUserID(userID string) ([]*models.Send, *models.Error)
+	CreateSend(createSend *send.CreateSend, quota int64) (*models.Send, *models.Error)
+	DeleteSend(id string, userID string) (*models.Send, *models.Error)
+	RecordAccess(id int) (*models.Send, *models.Error)
+	PurgeSends(before time.Time) *models.Error
+}
+
+type SendRepository struct {
+	client *supabase.Client
+	logger *logging.Logger
+
+	SendRepositoryMethods
+}
+
+func NewSendRepository(client *supabase.Client, logger *logging.Logger) *SendRepository {
+	return &SendRepository{
+		client: client,
+		logger: logger,
+	}
+}
+
+// GetSend returns one of the user's Sends.
+func (s *SendRepository) GetSend(id string, userID string) (*models.Send, *models.Error) {
+	res, _, err := s.client.From("sends").Select("*", "", false).Eq("id", id).Eq("user_id", userID).Execute()
+	if err != nil {
+		description := "An error occurred while retrieving the Send."
+		s.logger.Error(err.Error())
+
+		return nil, models.NewError(500, "InternalServerError", description)
+	}
+
+	var sends []*models.Send
+	err = json.Unmarshal(res, &sends)
+	if err != nil {
+		description := "An error occurred while retrieving the Send."
+		s.logger.Error(err.Error())
+
+		return nil, models.NewError(500, "InternalServerError", description)
+	}
+
+	if len(sends) == 0 {
+		description := "Send not found with id=" + id
+		return nil, models.NewError(404, "NotFound", description)
+	}
+
+	return sends[0], nil
+}
+
+func (s *SendRepository) GetSendByAccessID(accessID string) (*models.Send, *models.Error) {
+	res, _, err := s.client.From("sends").Select("*", "", false).Eq("access_id", accessID).Execute()
+	if err != nil {
+		description := "An error occurred while retrieving the Send."
+		s.logger.Error(err.Error())
+
+		return nil, models.NewError(500, "InternalServerError", description)
+	}
+
+	var sends []*models.Send
+	err = json.Unmarshal(res, &sends)
+	if err != nil {
+		description := "An error occurred while retrieving the Send."
+		s.logger.Error(err.Error())
+
+		return nil, models.NewError(500, "InternalServerError", description)
+	}
+
+	if len(sends) == 0 {
+		return nil, models.NewError(404, "NotFound", "Send not found.")
+	}
+
+	return sends[0], nil
+}
+
+func (s *SendRepository) GetSendsByUserID(userID string) ([]*models.Send, *models.Error) {
+	res, _, err := s.client.From("sends").Select("*", "", false).Eq("user_id", userID).Execute()
+	if err != nil {
+		description := "An error occurred while retrieving Sends."
+		s.logger.Error(err.Error())
+
+		return nil, models.NewError(500, "InternalServerError", description)
+	}
+
+	var sends []*models.Send
+	err = json.Unmarshal(res, &sends)
+	if err != nil {
+		description := "An error occurred while retrieving Sends."
+		s.logger.Error(err.Error())
+
+		return nil, models.NewError(500, "InternalServerError", description)
+	}
+
+	return sends, nil
+}
+
+// CreateSend records a Send when its file fits in the user's storage quota,
+// checked under a per-user lock like attachments. Otherwise a 507 is
+// returned.
+func (s *SendRepository) CreateSend(createSend *send.CreateSend, quota int64) (*models.Send, *models.Error) {
+	params := map[string]interface{}{
+		"p_send":  createSend,
+		"p_quota": quota,
+	}
+
+	var sends []*models.Send
+	err := callRpc(s.client, "create_send", params, &sends)
+	if err != nil && strings.Contains(err.Error(), "(53100)") {
+		return nil, models.NewError(507, "InsufficientStorage", "The file does not fit in the remaining storage quota.")
+	}
+
+	if err != nil || len(sends) == 0 {
+		description := "An error occurred while creating the Send."
+		if err != nil {
+			s.logger.Error(err.Error())
+		}
+
+		return nil, models.NewError(500, "InternalServerError", description)
+	}
+
+	return sends[0], nil
+}
+
+// DeleteSend deletes one of the user's Sends. The blob of a file Send is
+// queued for deletion by the database.
+func (s *SendRepository) DeleteSend(id string, userID string) (*models.Send, *models.Error) {
+	res, _, err := s.client.From("sends").Delete("", "").Eq("id", id).Eq("user_id", userID).Execute()
+	if err != nil {
+		description := fmt.Sprintf("Error deleting Send: %s", err.Error())
+		return nil, models.NewError(500, "InternalError", description)
+	}
+
+	var sends []*models.Send
+	err = json.Unmarshal(res, &sends)
+	if err != nil {
+		description := fmt.Sprintf("Error unmarshalling response: %s", err.Error())
+		return nil, models.NewError(500, "InternalError", description)
+	}
+
+	if len(sends) == 0 {
+		description := "Send not found with id=" + id
+		return nil, models.NewError(404, "NotFound", description)
+	}
+
+	return sends[0], nil
+}
+
+// RecordAccess counts an access to the Send and returns it. A Send that can
+// no longer be accessed is not found.
+func (s *SendRepository) RecordAccess(id int) (*models.Send, *models.Error) {
+	params := map[string]interface{}{
+		"p_send_id": id,
+	}
+
+	var sends []*models.Send
+	err := callRpc(s.client, "record_send_access", params, &sends)
+	if err != nil {
+		description := "An error occurred while accessing the Send."
+		s.logger.Error(err.Error())
+
+		return nil, models.NewError(500, "InternalServerError", description)
+	}
+
+	if len(sends) == 0 {
+		return nil, models.NewError(404, "NotFound", "Send not found.")
+	}
+
+	return sends[0], nil
+}
+
+// PurgeSends deletes the Sends whose deletion date is before the given time.
+func (s *SendRepository) PurgeSends(before time.Time) *models.Error {
+	_, _, err := s.client.From("sends").Delete("minimal", "").Lt("deletion_date", before.UTC().Format(time.RFC3339)).Execute()
+	if err != nil {
+		description := "An error occurred while purging Sends."
+		s.logger.Error(err.Error())
+
+		return models.NewError(500, "InternalServerError", description)
+	}
+
+	return nil
+}
@@ -0,0 +1,168 @@
+package repositories
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/safepass/server/internal/logging"
+	"github.com/safepass/server/pkg/dtos/tag"
+	"github.com/safepass/server/pkg/models"
+	"github.com/supabase-community/supabase-go"
+)
+
+type TagRepositoryMethods interface {
+	GetTag(id string) (*models.Tag, *models.Error)
+	GetTagsByUserID(userID string) ([]*models.Tag, *models.Error)
+	GetTagsByIDs(ids []string) ([]*models.Tag, *models.Error)
+	CreateTag(*tag.CreateTag) (*models.Tag, *models.Error)
+	UpdateTag(id string, update *tag.CreateTag) (*models.Tag, *models.Error)
+	DeleteTag(id string, userID string) (*models.Tag, *models.Error)
+}
+
+type TagRepository struct {
+	client *supabase.Client
+	logger *logging.Logger
+
+	TagRepositoryMethods
+}
+
+func NewTagRepository(client *supabase.Client, logger *logging.Logger) *TagRepository {
+	return &TagRepository{
+		client: client,
+		logger: logger,
+	}
+}
+
+func (t *TagRepository) GetTag(id string) (*models.Tag, *models.Error) {
+	res, _, err := t.client.From("tags").Select("*", "", false).Eq("id", id).Execute()
+	if err != nil {
+		description := "An error occurred while retrieving the tag."
+		t.logger.Error(err.Error())
+
+		return nil, models.NewError(500, "InternalServerError", description)
+	}
+
+	var tags []*models.Tag
+	err = json.Unmarshal(res, &tags)
+	if err != nil {
+		description := "An error occurred while retrieving the tag."
+		t.logger.Error(err.Error())
+
+		return nil, models.NewError(500, "InternalServerError", description)
+	}
+
+	if len(tags) == 0 {
+		description := "Tag not found with id=" + id
+		return nil, models.NewError(404, "NotFound", description)
+	}
+
+	return tags[0], nil
+}
+
+func (t *TagRepository) GetTagsByUserID(userID string) ([]*models.Tag, *models.Error) {
+	res, _, err := t.client.From("tags").Select("*", "", false).Eq("user_id", userID).Execute()
+	if err != nil {
+		description := "An error occurred while retrieving tags."
+		t.logger.Error(err.Error())
+
+		return nil, models.NewError(500, "InternalServerError", description)
+	}
+
+	var tags []*models.Tag
+	err = json.Unmarshal(res, &tags)
+	if err != nil {
+		description := "An error occurred while retrieving tags."
+		t.logger.Error(err.Error())
+
+		return nil, models.NewError(500, "InternalServerError", description)
+	}
+
+	return tags, nil
+}
+
+func (t *TagRepository) GetTagsByIDs(ids []string) ([]*models.Tag, *models.Error) {
+	res, _, err := t.client.From("tags").Select("*", "", false).In("id", ids).Execute()
+	if err != nil {
+		description := "An error occurred while retrieving tags."
+		t.logger.Error(err.Error())
+
+		return nil, models.NewError(500, "InternalServerError", description)
+	}
+
+	var tags []*models.Tag
+	err = json.Unmarshal(res, &tags)
+	if err != nil {
+		description := "An error occurred while retrieving tags."
+		t.logger.Error(err.Error())
+
+		return nil, models.NewError(500, "InternalServerError", description)
+	}
+
+	return tags, nil
+}
+
+func (t *TagRepository) CreateTag(createTag *tag.CreateTag) (*models.Tag, *models.Error) {
+	res, _, err := t.client.From("tags").Insert(createTag, false, "", "", "").Execute()
+	if err != nil {
+		description := "An error occurred while creating the tag."
+		t.logger.Error(err.Error())
+
+		return nil, models.NewError(500, "InternalServerError", description)
+	}
+
+	var tags []*models.Tag
+	err = json.Unmarshal(res, &tags)
+	if err != nil {
+		description := fmt.Sprintf("Error unmarshalling response: %s", err.Error())
+		return nil, models.NewError(500, "InternalError", description)
+	}
+
+	return tags[0], nil
+}
+
+func (t *TagRepository) UpdateTag(id string, update *tag.CreateTag) (*models.Tag, *models.Error) {
+	res, _, err := t.client.From("tags").Update(update, "", "").Eq("id", id).Eq("user_id", fmt.Sprint(update.UserID)).Execute()
+	if err != nil {
+		description := "An error occurred while updating the tag."
+		t.logger.Error(err.Error())
+
+		return nil, models.NewError(500, "InternalServerError", description)
+	}
+
+	var tags []*models.Tag
+	err = json.Unmarshal(res, &tags)
+	if err != nil {
+		description := fmt.Sprintf("Error unmarshalling response: %s", err.Error())
+		return nil, models.NewError(500, "InternalError", description)
+	}
+
+	if len(tags) == 0 {
+		description := "Tag not found with id=" + id
+		return nil, models.NewError(404, "NotFound", description)
+	}
+
+	return tags[0], nil
+}
+
+// DeleteTag deletes a tag of the user, which removes it from its passwords.
+func (t *TagRepository) DeleteTag(id string, userID string) (*models.Tag, *models.Error) {
+	res, _, err := t.client.From("tags").Delete("", "").Eq("id", id).Eq("user_id", userID).Execute()
+	if err != nil {
+		description := fmt.Sprintf("Error deleting tag: %s", err.Error())
+		return nil, models.NewError(500, "InternalError", description)
+	}
+
+	var tags []*models.Tag
+	err = json.Unmarshal(res, &tags)
+	if err != nil {
+		description := fmt.Sprintf("Error unmarshalling response: %s", err.Error())
+		return nil, models.NewError(500, "InternalError", description)
+	}
+
+	if len(tags) == 0 {
+		description := "Tag not found with id=" + id
+		return nil, models.NewError(404, "NotFound", description)
+	}
+
+	return tags[0], nil
+}
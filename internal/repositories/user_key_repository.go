@@ -0,0 +1,83 @@
+package repositories
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/safepass/server/internal/logging"
+	"github.com/safepass/server/pkg/dtos/user"
+	"github.com/safepass/server/pkg/models"
+	"github.com/supabase-community/supabase-go"
+)
+
+type UserKeyRepositoryMethods interface {
+	GetUserKey(userID string) (*models.UserKey, *models.Error)
+	CreateUserKey(*user.SetUserKey) (*models.UserKey, *models.Error)
+}
+
+type UserKeyRepository struct {
+	client *supabase.Client
+	logger *logging.Logger
+
+	UserKeyRepositoryMethods
+}
+
+func NewUserKeyRepository(client *supabase.Client, logger *logging.Logger) *UserKeyRepository {
+	return &UserKeyRepository{
+		client: client,
+		logger: logger,
+	}
+}
+
+func (u *UserKeyRepository) GetUserKey(userID string) (*models.UserKey, *models.Error) {
+	res, _, err := u.client.From("user_keys").Select("*", "", false).Eq("user_id", userID).Execute()
+	if err != nil {
+		description := "An error occurred while retrieving the key pair."
+		u.logger.Error(err.Error())
+
+		return nil, models.NewError(500, "InternalServerError", description)
+	}
+
+	var keys []*models.UserKey
+	err = json.Unmarshal(res, &keys)
+	if err != nil {
+		description := "An error occurred while retrieving the key pair."
+		u.logger.Error(err.Error())
+
+		return nil, models.NewError(500, "InternalServerError", description)
+	}
+
+	if len(keys) == 0 {
+		description := "No key pair found for user_id=" + userID
+		return nil, models.NewError(404, "NotFound", description)
+	}
+
+	return keys[0], nil
+}
+
+// CreateUserKey stores the user's key pair. A user who already has one gets a
+// 409 and the stored pair is left as it is.
+func (u *UserKeyRepository) CreateUserKey(setUserKey *user.SetUserKey) (*models.UserKey, *models.Error) {
+	res, _, err := u.client.From("user_keys").Insert(setUserKey, false, "", "", "").Execute()
+	if err != nil && strings.HasPrefix(err.Error(), "(23505)") {
+		description := "A key pair is already set. It cannot be replaced, since shares are wrapped with its public key."
+		return nil, models.NewError(409, "Conflict", description)
+	}
+
+	if err != nil {
+		description := "An error occurred while saving the key pair."
+		u.logger.Error(err.Error())
+
+		return nil, models.NewError(500, "InternalServerError", description)
+	}
+
+	var keys []*models.UserKey
+	err = json.Unmarshal(res, &keys)
+	if err != nil {
+		description := fmt.Sprintf("Error unmarshalling response: %s", err.Error())
+		return nil, models.NewError(500, "InternalError", description)
+	}
+
+	return keys[0], nil
+}
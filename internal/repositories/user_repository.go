@@ -3,6 +3,9 @@ package repositories
 import (
 	"encoding/json"
 	"fmt"
+	"strconv"
+	"strings"
+	"time"
 
 	"github.com/safepass/server/pkg/dtos/user"
 	"github.com/safepass/server/pkg/models"
@@ -16,6 +19,10 @@ type UserRepositoryMethods interface {
 	CreateUser(*user.CreateUser) *models.IdentityResult
 	UpdateUser(string *user.UpdateUser) (*models.User, *models.IdentityResult)
 	DeleteUser(id string) (*models.User, *models.Error)
+	DeleteAccount(id int, reason string) *models.Error
+	SetDeletionScheduledAt(id int, at *time.Time) *models.Error
+	SetVerifiedAt(id int, at *time.Time) *models.Error
+	GetUsersScheduledForDeletion(before time.Time) ([]*models.User, *models.Error)
 }
 
 type UserRepository struct {
@@ -79,7 +86,11 @@ func (u *UserRepository) GetUserByID(id string) (*models.User, *models.Error) {
 	return user, nil
 }
 
+// GetUserByEmail looks the user up by email address. Addresses are stored
+// lowercased, so the lookup ignores case.
 func (u *UserRepository) GetUserByEmail(email string) (*models.User, *models.Error) {
+	email = strings.ToLower(strings.TrimSpace(email))
+
 	res, _, err := u.client.From("users").Select("*", "1", false).Eq("email", email).Single().Execute()
 	if err != nil {
 		description := "No user found"
@@ -233,3 +244,91 @@ func (u *UserRepository) DeleteUser(id string) (*models.User, *models.Error) {
 
 	return response[0], nil
 }
+
+// DeleteAccount removes the user together with the vault, passwords, sessions
+// and 2FA material in a single database transaction. It refuses to delete the
+// last owner of an organization that has other members.
+func (u *UserRepository) DeleteAccount(id int, reason string) *models.Error {
+	params := map[string]interface{}{
+		"p_user_id": id,
+		"p_reason":  reason,
+	}
+
+	var deleted bool
+	err := callRpc(u.client, "delete_user_account", params, &deleted)
+	if err != nil && strings.Contains(err.Error(), "(40001)") {
+		description := "You are the only owner of an organization with other members. Transfer ownership or delete the organization first."
+		errModel := models.NewError(409, "Conflict", description)
+
+		return errModel
+	}
+
+	if err != nil {
+		description := fmt.Sprintf("Error deleting account: %s", err.Error())
+		errModel := models.NewError(500, "InternalError", description)
+
+		return errModel
+	}
+
+	if !deleted {
+		description := "No user found"
+		errModel := models.NewError(404, "NotFound", description)
+
+		return errModel
+	}
+
+	return nil
+}
+
+func (u *UserRepository) SetDeletionScheduledAt(id int, at *time.Time) *models.Error {
+	update := map[string]interface{}{
+		"deletion_scheduled_at": at,
+	}
+
+	_, _, err := u.client.From("users").Update(update, "minimal", "").Eq("id", strconv.Itoa(id)).Execute()
+	if err != nil {
+		description := fmt.Sprintf("Error updating user: %s", err.Error())
+		errModel := models.NewError(500, "InternalError", description)
+
+		return errModel
+	}
+
+	return nil
+}
+
+func (u *UserRepository) GetUsersScheduledForDeletion(before time.Time) ([]*models.User, *models.Error) {
+	res, _, err := u.client.From("users").Select("*", "exact", false).Lte("deletion_scheduled_at", before.UTC().Format(time.RFC3339)).Execute()
+	if err != nil {
+		description := fmt.Sprintf("Error retrieving users: %s", err.Error())
+		errModel := models.NewError(500, "InternalError", description)
+
+		return nil, errModel
+	}
+
+	var users []*models.User
+	err = json.Unmarshal(res, &users)
+	if err != nil {
+		description := fmt.Sprintf("Error unmarshalling users: %s", err.Error())
+		errModel := models.NewError(500, "InternalError", description)
+
+		return nil, errModel
+	}
+
+	return users, nil
+}
+
+func (u *UserRepository) SetVerifiedAt(id int, at *time.Time) *models.Error {
+	update := map[string]interface{}{
+		"verified_at": at,
+	}
+
+	_, _, err := u.client.From("users").Update(update, "minimal", "").Eq("id", strconv.Itoa(id)).Execute()
+	if err != nil {
+		description := fmt.Sprintf("Error updating user: %s", err.Error())
+		errModel := models.NewError(500, "InternalError", description)
+
+		return errModel
+	}
+
+	return nil
+}
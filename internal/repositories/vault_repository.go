@@ -3,6 +3,7 @@ package repositories
 import (
 	"encoding/json"
 	"fmt"
+	"strconv"
 	"strings"
 
 	"github.com/safepass/server/internal/logging"
@@ -15,8 +16,12 @@ type VaultRepositoryMethods interface {
 	GetVaults() ([]*models.Vault, *models.Error)
 	GetVault(string) (*models.Vault, *models.Error)
 	GetVaultByUserId(string) (*models.Vault, *models.Error)
-	CreateVault(*vault.CreateVault) *models.Error
-	UpdateVault(*vault.CreateVault) (*models.Vault, *models.Error)
+	GetVaultsByUserId(string) ([]*models.Vault, *models.Error)
+	CreateVault(*vault.CreateVault) (*models.Vault, *models.Error)
+	UpdateVault(string, *vault.CreateVault) (*models.Vault, *models.Error)
+	RenameVault(string, *vault.UpdateVault) (*models.Vault, *models.Error)
+	DeleteVault(id int, userID int) *models.Error
+	ImportVault(userID int, vaultID int, key *vault.CreateVault, folderNames []string, tagLabels []string, writes []*vault.VaultImportWrite) (*vault.VaultImportResult, *models.Error)
 }
 
 type VaultRepository struct {
@@ -60,7 +65,7 @@ func (v *VaultRepository) GetVaults() ([]*models.Vault, *models.Error) {
 }
 
 func (v *VaultRepository) GetVault(id string) (*models.Vault, *models.Error) {
-	res, n, err := v.client.From("vaults").Select("*", "1", false).Eq("id", id).Execute()
+	res, _, err := v.client.From("vaults").Select("*, users (*)", "", false).Eq("id", id).Execute()
 	if err != nil {
 		description := fmt.Sprintf("An error occurred while retrieving the vault with id=%s.", id)
 		v.logger.Error(err.Error())
@@ -68,13 +73,8 @@ func (v *VaultRepository) GetVault(id string) (*models.Vault, *models.Error) {
 		return nil, models.NewError(500, "InternalServerError", description)
 	}
 
-	if n <= 0 {
-		description := "No vault found with id=" + id
-		return nil, models.NewError(404, "NotFound", description)
-	}
-
-	var vault *models.Vault
-	err = json.Unmarshal(res, &vault)
+	var vaults []*models.Vault
+	err = json.Unmarshal(res, &vaults)
 	if err != nil {
 		description := fmt.Sprintf("An error occurred while retrieving the vault with id=%s.", id)
 		v.logger.Error(err.Error())
@@ -82,11 +82,17 @@ func (v *VaultRepository) GetVault(id string) (*models.Vault, *models.Error) {
 		return nil, models.NewError(500, "InternalServerError", description)
 	}
 
-	return vault, nil
+	if len(vaults) == 0 {
+		description := "No vault found with id=" + id
+		return nil, models.NewError(404, "NotFound", description)
+	}
+
+	return vaults[0], nil
 }
 
+// GetVaultByUserId returns the user's default vault.
 func (v *VaultRepository) GetVaultByUserId(id string) (*models.Vault, *models.Error) {
-	res, _, err := v.client.From("vaults").Select("*, users (*)", "1", false).Eq("user_id", id).Execute()
+	res, _, err := v.client.From("vaults").Select("*, users (*)", "1", false).Eq("user_id", id).Eq("is_default", "true").Execute()
 	if err != nil {
 		description := fmt.Sprintf("An error occurred while retrieving the vault with user_id=%s.", id)
 		v.logger.Error(err.Error())
@@ -103,10 +109,36 @@ func (v *VaultRepository) GetVaultByUserId(id string) (*models.Vault, *models.Er
 		return nil, models.NewError(500, "InternalServerError", description)
 	}
 
+	if len(vaults) == 0 {
+		description := "No vault found with user_id=" + id
+		return nil, models.NewError(404, "NotFound", description)
+	}
+
 	return vaults[0], nil
 }
 
-func (v *VaultRepository) CreateVault(vault *vault.CreateVault) *models.Error {
+func (v *VaultRepository) GetVaultsByUserId(id string) ([]*models.Vault, *models.Error) {
+	res, _, err := v.client.From("vaults").Select("*", "", false).Eq("user_id", id).Execute()
+	if err != nil {
+		description := fmt.Sprintf("An error occurred while retrieving the vaults with user_id=%s.", id)
+		v.logger.Error(err.Error())
+
+		return nil, models.NewError(500, "InternalServerError", description)
+	}
+
+	var vaults []*models.Vault
+	err = json.Unmarshal(res, &vaults)
+	if err != nil {
+		description := fmt.Sprintf("An error occurred while retrieving the vaults with user_id=%s.", id)
+		v.logger.Error(err.Error())
+
+		return nil, models.NewError(500, "InternalServerError", description)
+	}
+
+	return vaults, nil
+}
+
+func (v *VaultRepository) CreateVault(vault *vault.CreateVault) (*models.Vault, *models.Error) {
 	res, _, err := v.client.From("vaults").Insert(vault, false, "", "", "1").Execute()
 	if err != nil {
 		description := "An error occurred while creating the vault."
@@ -121,17 +153,17 @@ func (v *VaultRepository) CreateVault(vault *vault.CreateVault) *models.Error {
 
 		v.logger.Error(err.Error())
 
-		return models.NewError(statusCode, statusText, description)
+		return nil, models.NewError(statusCode, statusText, description)
 	}
 
 	var response []*models.Vault
 	err = json.Unmarshal(res, &response)
 	if err != nil {
 		description := fmt.Sprintf("Error unmarshalling response: %s", err.Error())
-		return models.NewError(500, "InternalError", description)
+		return nil, models.NewError(500, "InternalError", description)
 	}
 
-	return nil
+	return response[0], nil
 }
 
 func (v *VaultRepository) UpdateVault(id string, vault *vault.CreateVault) (*models.Vault, *models.Error) {
@@ -161,3 +193,86 @@ func (v *VaultRepository) UpdateVault(id string, vault *vault.CreateVault) (*mod
 
 	return response[0], nil
 }
+
+func (v *VaultRepository) RenameVault(id string, update *vault.UpdateVault) (*models.Vault, *models.Error) {
+	res, _, err := v.client.From("vaults").Update(update, "", "").Eq("id", id).Execute()
+	if err != nil {
+		description := "An error occurred while updating the vault."
+		v.logger.Error(err.Error())
+
+		return nil, models.NewError(500, "InternalServerError", description)
+	}
+
+	var response []*models.Vault
+	err = json.Unmarshal(res, &response)
+	if err != nil {
+		description := fmt.Sprintf("Error unmarshalling response: %s", err.Error())
+		return nil, models.NewError(500, "InternalError", description)
+	}
+
+	if len(response) == 0 {
+		description := "No vault found with id=" + id
+		return nil, models.NewError(404, "NotFound", description)
+	}
+
+	return response[0], nil
+}
+
+// DeleteVault deletes a non-default vault of the user and its passwords in a
+// single transaction.
+func (v *VaultRepository) DeleteVault(id int, userID int) *models.Error {
+	params := map[string]interface{}{
+		"p_vault_id": id,
+		"p_user_id":  userID,
+	}
+
+	var deleted bool
+	err := callRpc(v.client, "delete_vault", params, &deleted)
+	if err != nil {
+		description := fmt.Sprintf("Error deleting vault: %s", err.Error())
+		return models.NewError(500, "InternalError", description)
+	}
+
+	if !deleted {
+		description := "No vault found with id=" + strconv.Itoa(id)
+		return models.NewError(404, "NotFound", description)
+	}
+
+	return nil
+}
+
+// ImportVault restores the folders, tags and items of an export into the vault
+// in a single transaction. With a key, the vault's key is replaced as well;
+// when the vault got items in the meantime nothing is imported and a 409 is
+// returned.
+func (v *VaultRepository) ImportVault(userID int, vaultID int, key *vault.CreateVault, folderNames []string, tagLabels []string, writes []*vault.VaultImportWrite) (*vault.VaultImportResult, *models.Error) {
+	params := map[string]interface{}{
+		"p_user_id":       userID,
+		"p_vault_id":      vaultID,
+		"p_mac":           nil,
+		"p_protected_key": nil,
+		"p_folders":       folderNames,
+		"p_tags":          tagLabels,
+		"p_items":         writes,
+	}
+
+	if key != nil {
+		params["p_mac"] = key.Mac
+		params["p_protected_key"] = key.ProtectedSymmetricKey
+	}
+
+	var result vault.VaultImportResult
+	err := callRpc(v.client, "import_vault", params, &result)
+	if err != nil && strings.Contains(err.Error(), "(40001)") {
+		return nil, models.NewError(409, "Conflict", "The vault is no longer empty, so its key cannot be replaced.")
+	}
+
+	if err != nil {
+		description := "An error occurred while importing the vault."
+		v.logger.Error(err.Error())
+
+		return nil, models.NewError(500, "InternalServerError", description)
+	}
+
+	return &result, nil
+}
@@ -0,0 +1,282 @@
+package services
+
+import (
+	"encoding/hex"
+	"errors"
+	"io"
+	"strconv"
+
+	"github.com/safepass/server/internal/config"
+	"github.com/safepass/server/internal/consts"
+	"github.com/safepass/server/internal/logging"
+	"github.com/safepass/server/internal/repositories"
+	"github.com/safepass/server/internal/storage"
+	"github.com/safepass/server/pkg/crypto"
+	"github.com/safepass/server/pkg/dtos/attachment"
+	"github.com/safepass/server/pkg/models"
+)
+
+// PURGE_BLOBS_BATCH_SIZE is the number of queued blobs deleted per batch.
+const PURGE_BLOBS_BATCH_SIZE = 100
+
+type AttachmentServicesMethods interface {
+	GetAttachments(vaultID string, userID int, passwordID int) ([]*models.Attachment, *models.Error)
+	UploadAttachment(vaultID string, userID int, passwordID int, request *attachment.UploadRequest) (*models.Attachment, *models.Error)
+	OpenAttachment(vaultID string, userID int, passwordID int, attachmentID int) (*models.Attachment, io.ReadCloser, *models.Error)
+	DeleteAttachment(vaultID string, userID int, passwordID int, attachmentID int) *models.Error
+	PurgeDeletedBlobs() error
+}
+
+type AttachmentServices struct {
+	attachmentRepository *repositories.AttachmentRepository
+	passwordRepository   *repositories.PasswordRepository
+	vaultServices        *VaultServices
+	blobStore            storage.BlobStore
+	logger               *logging.Logger
+	appConfig            *config.Config
+
+	AttachmentServicesMethods
+}
+
+func NewAttachmentServices(attachmentRepository *repositories.AttachmentRepository, passwordRepository *repositories.PasswordRepository, vaultServices *VaultServices, blobStore storage.BlobStore, logger *logging.Logger, config *config.Config) *AttachmentServices {
+	return &AttachmentServices{
+		attachmentRepository: attachmentRepository,
+		passwordRepository:   passwordRepository,
+		vaultServices:        vaultServices,
+		blobStore:            blobStore,
+		logger:               logger,
+		appConfig:            config,
+	}
+}
+
+// GetAttachments lists the attachments of a password the vault can read,
+// including passwords shared with the vault's owner.
+func (a *AttachmentServices) GetAttachments(vaultID string, userID int, passwordID int) ([]*models.Attachment, *models.Error) {
+	_, merr := a.authorizePassword(vaultID, userID, passwordID)
+	if merr != nil {
+		return nil, merr
+	}
+
+	attachments, merr := a.attachmentRepository.GetAttachmentsByPasswordID(strconv.Itoa(passwordID))
+	if merr != nil {
+		return nil, merr
+	}
+
+	for _, attachment := range attachments {
+		attachment.BlobKey = ""
+	}
+
+	return attachments, nil
+}
+
+// UploadAttachment stores an encrypted file on one of the vault's passwords.
+// The content is streamed to the blob store and counted against the owner's
+// quota; uploads over the maximum size or the remaining quota are discarded.
+// The quota is checked again when the attachment is recorded, since other
+// uploads may have used it up in the meantime.
+func (a *AttachmentServices) UploadAttachment(vaultID string, userID int, passwordID int, request *attachment.UploadRequest) (*models.Attachment, *models.Error) {
+	vault, merr := a.vaultServices.GetUserVault(vaultID, userID)
+	if merr != nil {
+		return nil, merr
+	}
+
+	merr = a.vaultServices.CanWrite(vault)
+	if merr != nil {
+		return nil, merr
+	}
+
+	pw, merr := a.vaultServices.getOwnPassword(passwordID, vault)
+	if merr != nil {
+		return nil, merr
+	}
+
+	used, merr := a.attachmentRepository.GetUsedStorage(strconv.Itoa(userID))
+	if merr != nil {
+		return nil, merr
+	}
+
+	remaining := a.appConfig.Attachments.UserQuota - used
+	if remaining <= 0 {
+		return nil, models.NewError(507, "InsufficientStorage", "The attachment storage quota is used up.")
+	}
+
+	limit := min(a.appConfig.Attachments.MaxSize, remaining)
+
+	key, err := crypto.CreateRandomSalt(32)
+	if err != nil {
+		return nil, models.NewError(500, "InternalServerError", "Could not generate the attachment key.")
+	}
+
+	blobKey := hex.EncodeToString(key)
+
+	size, err := a.blobStore.Put(blobKey, io.LimitReader(request.Data, limit+1))
+	if err != nil {
+		a.logger.Error(err.Error())
+		return nil, models.NewError(500, "InternalServerError", "An error occurred while storing the attachment.")
+	}
+
+	if size > limit {
+		a.deleteBlob(blobKey)
+
+		if limit < a.appConfig.Attachments.MaxSize {
+			return nil, models.NewError(507, "InsufficientStorage", "The attachment does not fit in the remaining storage quota.")
+		}
+
+		return nil, models.NewError(413, "PayloadTooLarge", "Attachments can be at most "+strconv.FormatInt(a.appConfig.Attachments.MaxSize, 10)+" bytes.")
+	}
+
+	created, merr := a.attachmentRepository.CreateAttachment(&attachment.CreateAttachment{
+		PasswordID:        pw.ID,
+		UserID:            userID,
+		BlobKey:           blobKey,
+		EncryptedFileName: request.EncryptedFileName,
+		EncryptedKey:      request.EncryptedKey,
+		Size:              size,
+	}, a.appConfig.Attachments.UserQuota)
+	if merr != nil {
+		a.deleteBlob(blobKey)
+		return nil, merr
+	}
+
+	a.vaultServices.notifyItems(consts.VaultEventTypes.ITEM_UPDATED, vault.ID, vault.UserID, pw.ID)
+
+	created.BlobKey = ""
+	return created, nil
+}
+
+// OpenAttachment opens the content of an attachment for download. The caller
+// closes the returned reader.
+func (a *AttachmentServices) OpenAttachment(vaultID string, userID int, passwordID int, attachmentID int) (*models.Attachment, io.ReadCloser, *models.Error) {
+	_, merr := a.authorizePassword(vaultID, userID, passwordID)
+	if merr != nil {
+		return nil, nil, merr
+	}
+
+	found, merr := a.getAttachment(passwordID, attachmentID)
+	if merr != nil {
+		return nil, nil, merr
+	}
+
+	content, err := a.blobStore.Get(found.BlobKey)
+	if err != nil {
+		a.logger.Error(err.Error())
+
+		if errors.Is(err, storage.ErrBlobNotFound) {
+			return nil, nil, models.NewError(404, "NotFound", "The content of the attachment is missing.")
+		}
+
+		return nil, nil, models.NewError(500, "InternalServerError", "An error occurred while reading the attachment.")
+	}
+
+	found.BlobKey = ""
+	return found, content, nil
+}
+
+// DeleteAttachment deletes an attachment of one of the vault's passwords. The
+// blob is removed by PurgeDeletedBlobs.
+func (a *AttachmentServices) DeleteAttachment(vaultID string, userID int, passwordID int, attachmentID int) *models.Error {
+	vault, merr := a.vaultServices.GetUserVault(vaultID, userID)
+	if merr != nil {
+		return merr
+	}
+
+	merr = a.vaultServices.CanWrite(vault)
+	if merr != nil {
+		return merr
+	}
+
+	_, merr = a.vaultServices.getOwnPassword(passwordID, vault)
+	if merr != nil {
+		return merr
+	}
+
+	_, merr = a.getAttachment(passwordID, attachmentID)
+	if merr != nil {
+		return merr
+	}
+
+	_, merr = a.attachmentRepository.DeleteAttachment(strconv.Itoa(attachmentID))
+	if merr != nil {
+		return merr
+	}
+
+	a.vaultServices.notifyItems(consts.VaultEventTypes.ITEM_UPDATED, vault.ID, vault.UserID, passwordID)
+	return nil
+}
+
+// PurgeDeletedBlobs removes the blobs of deleted attachments and file Sends
+// from the blob store. Attachments go away with their item, vault or account
+// in the database, which queues their blobs for this job.
+func (a *AttachmentServices) PurgeDeletedBlobs() error {
+	for {
+		blobs, merr := a.attachmentRepository.GetDeletedBlobs(PURGE_BLOBS_BATCH_SIZE)
+		if merr != nil {
+			return errors.New(merr.Description)
+		}
+
+		if len(blobs) == 0 {
+			return nil
+		}
+
+		ids := make([]string, 0, len(blobs))
+		for _, blob := range blobs {
+			err := a.blobStore.Delete(blob.BlobKey)
+			if err != nil {
+				return err
+			}
+
+			ids = append(ids, strconv.Itoa(blob.ID))
+		}
+
+		merr = a.attachmentRepository.DeleteDeletedBlobs(ids)
+		if merr != nil {
+			return errors.New(merr.Description)
+		}
+
+		if len(blobs) < PURGE_BLOBS_BATCH_SIZE {
+			return nil
+		}
+	}
+}
+
+// authorizePassword returns the password when the user's vault may access it,
+// as an item of the vault or one shared with the user.
+func (a *AttachmentServices) authorizePassword(vaultID string, userID int, passwordID int) (*models.Password, *models.Error) {
+	vault, merr := a.vaultServices.GetUserVault(vaultID, userID)
+	if merr != nil {
+		return nil, merr
+	}
+
+	pw, merr := a.passwordRepository.GetPassword(strconv.Itoa(passwordID))
+	if merr != nil {
+		return nil, merr
+	}
+
+	_, merr = a.vaultServices.authorizePassword(pw, vault, false)
+	if merr != nil {
+		return nil, merr
+	}
+
+	return pw, nil
+}
+
+func (a *AttachmentServices) getAttachment(passwordID int, attachmentID int) (*models.Attachment, *models.Error) {
+	found, merr := a.attachmentRepository.GetAttachment(strconv.Itoa(attachmentID))
+	if merr != nil {
+		return nil, merr
+	}
+
+	if found.PasswordID != passwordID {
+		return nil, models.NewError(404, "NotFound", "Attachment not found with id="+strconv.Itoa(attachmentID))
+	}
+
+	return found, nil
+}
+
+// deleteBlob removes a blob that never got an attachment row.
+func (a *AttachmentServices) deleteBlob(blobKey string) {
+	err := a.blobStore.Delete(blobKey)
+	if err != nil {
+		a.logger.Error(err.Error())
+	}
+}
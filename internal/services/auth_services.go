@@ -2,6 +2,7 @@ package services
 
 import (
 	"crypto/ecdsa"
+	"crypto/subtle"
 	"encoding/base64"
 	"strconv"
 	"time"
@@ -22,21 +23,30 @@ const (
 type AuthServicesMethods interface {
 	Login(userRequest *user.LoginRequest) (*models.TokenResponse, *models.Error)
 	Register(userRequest *user.CreateUserRequest) (*models.TokenResponse, *models.Error)
+	VerifyEmail(token string) *models.Error
+	ResendVerificationEmail(userID string) *models.Error
+	SendPasswordHint(email string) *models.Error
 }
 
 type AuthServices struct {
-	userServices  *UserServices
-	vaultServices *VaultServices
-	appConfig     *config.Config
+	userServices              *UserServices
+	vaultServices             *VaultServices
+	emailVerificationServices *EmailVerificationServices
+	passwordHintServices      *PasswordHintServices
+	policyServices            *PolicyServices
+	appConfig                 *config.Config
 
 	AuthServicesMethods
 }
 
-func NewAuthServices(userServices *UserServices, vaultServices *VaultServices, config *config.Config) *AuthServices {
+func NewAuthServices(userServices *UserServices, vaultServices *VaultServices, emailVerificationServices *EmailVerificationServices, passwordHintServices *PasswordHintServices, policyServices *PolicyServices, config *config.Config) *AuthServices {
 	return &AuthServices{
-		userServices:  userServices,
-		vaultServices: vaultServices,
-		appConfig:     config,
+		userServices:              userServices,
+		vaultServices:             vaultServices,
+		emailVerificationServices: emailVerificationServices,
+		passwordHintServices:      passwordHintServices,
+		policyServices:            policyServices,
+		appConfig:                 config,
 	}
 }
 
@@ -46,28 +56,41 @@ func (a *AuthServices) Login(userRequest *user.LoginRequest) (*models.TokenRespo
 		return nil, merr
 	}
 
-	salt, err := base64.StdEncoding.DecodeString(user.Salt)
-	if err != nil {
-		description := "Error decoding salt"
-		return nil, models.NewError(500, "InternalError", description)
+	merr = verifyMasterPasswordHash(user, userRequest.MasterPasswordHash)
+	if merr != nil {
+		return nil, merr
 	}
 
-	masterPasswordHash, err := base64.StdEncoding.DecodeString(userRequest.MasterPasswordHash)
-	if err != nil {
-		description := "Password is not valid Base64"
-		return nil, models.NewError(422, "UnprocessableContent", description)
+	sessionPolicy, merr := a.policyServices.EvaluateLogin(user)
+	if merr != nil {
+		return nil, merr
 	}
-	newMasterPasswordHash := crypto.DeriveKeySha256([]byte(masterPasswordHash), salt, user.IterationCount, MASTER_PASSWORD_HASH_LENGTH)
 
-	if user.MasterPasswordHash != base64.StdEncoding.EncodeToString(newMasterPasswordHash) {
-		description := "Invalid master password or email"
-		return nil, models.NewError(401, "Unauthorized", description)
+	// The master password hash is only known at login, so this is where the
+	// stored hash is re-derived when a policy requires more iterations. This
+	// hardens the server-side hash only; the client key derivation is untouched.
+	if sessionPolicy.MinIterations > user.IterationCount {
+		merr = a.userServices.SetMasterPasswordHash(strconv.Itoa(user.ID), userRequest.MasterPasswordHash, sessionPolicy.MinIterations)
+		if merr != nil {
+			return nil, merr
+		}
+	}
+
+	merr = a.userServices.CancelAccountDeletion(user)
+	if merr != nil {
+		return nil, merr
+	}
+
+	expiration := a.appConfig.JWT.Expiration
+	if sessionPolicy.MaxSessionTimeout > 0 && sessionPolicy.MaxSessionTimeout < expiration {
+		expiration = sessionPolicy.MaxSessionTimeout
 	}
 
 	var (
 		key *ecdsa.PrivateKey
 		t   *jwt.Token
 		s   string
+		err error
 	)
 
 	key, err = a.appConfig.GetJWTSecretKey()
@@ -80,7 +103,7 @@ func (a *AuthServices) Login(userRequest *user.LoginRequest) (*models.TokenRespo
 		"iss": "safepass",
 		"sub": user.ID,
 		"iat": time.Now().Unix(),
-		"exp": time.Now().Add(time.Second * time.Duration(a.appConfig.JWT.Expiration)).Unix(),
+		"exp": time.Now().Add(time.Second * time.Duration(expiration)).Unix(),
 		"aud": "safepass-mobile",
 		"roles": []string{
 			"user",
@@ -88,6 +111,7 @@ func (a *AuthServices) Login(userRequest *user.LoginRequest) (*models.TokenRespo
 		"username":    user.Username,
 		"email":       user.Email,
 		"auth_method": "master_password",
+		"stamp":       user.SecurityStamp,
 	})
 
 	s, err = t.SignedString(key)
@@ -105,38 +129,28 @@ func (a *AuthServices) Login(userRequest *user.LoginRequest) (*models.TokenRespo
 }
 
 func (a *AuthServices) Register(userRequest *user.CreateUserRequest) []*models.Error {
-	salt, err := crypto.CreateRandomSalt(32)
-	if err != nil {
-		var errors []*models.Error
-
-		description := "Creating salt error"
-		errModel := models.NewError(500, "InternalError", description)
-		errors = append(errors, errModel)
-
-		return errors
+	newMasterPasswordHash, salt, merr := hashMasterPasswordHash(userRequest.MasterPasswordHash, MASTER_PASSWORD_HASH_ITERATION_COUNT)
+	if merr != nil {
+		return []*models.Error{merr}
 	}
 
-	masterPasswordHash, err := base64.StdEncoding.DecodeString(userRequest.MasterPasswordHash)
-	if err != nil {
-		var errors []*models.Error
-		description := "Password is not valid Base64"
-		errModel := models.NewError(422, "UnprocessableContent", description)
-		errors = append(errors, errModel)
-
-		return errors
+	hint, hintEncrypted, merr := a.passwordHintServices.ProtectHint(userRequest.MasterPasswordHint)
+	if merr != nil {
+		return []*models.Error{merr}
 	}
 
-	newMasterPasswordHash := crypto.DeriveKeySha256(masterPasswordHash, salt, MASTER_PASSWORD_HASH_ITERATION_COUNT, MASTER_PASSWORD_HASH_LENGTH)
-
 	user := &user.CreateUser{
 		Username:           userRequest.Username,
-		Email:              userRequest.Email,
+		Email:              normalizeEmail(userRequest.Email),
 		Name:               userRequest.Name,
 		Surname:            userRequest.Surname,
-		MasterPasswordHash: base64.StdEncoding.EncodeToString(newMasterPasswordHash),
-		Salt:               base64.StdEncoding.EncodeToString(salt),
+		MasterPasswordHash: newMasterPasswordHash,
+		Salt:               salt,
 		IterationCount:     MASTER_PASSWORD_HASH_ITERATION_COUNT,
 		RoleId:             consts.Roles.USER,
+
+		MasterPasswordHint:          hint,
+		MasterPasswordHintEncrypted: hintEncrypted,
 	}
 
 	identityResult := a.userServices.CreateUser(user)
@@ -149,11 +163,77 @@ func (a *AuthServices) Register(userRequest *user.CreateUserRequest) []*models.E
 		return []*models.Error{models.NewError(500, "InternalServerError", "Unexpected error occurred.")}
 	}
 
-	merr := a.vaultServices.CreateVault(createdUser.ID, userRequest.ProtectedSymmetricKey)
+	merr = a.vaultServices.CreateVault(createdUser.ID, userRequest.ProtectedSymmetricKey)
 	if merr != nil {
 		a.userServices.DeleteUser(strconv.Itoa(createdUser.ID))
 		return []*models.Error{models.NewError(merr.Code, merr.CodeString, merr.Description)}
 	}
 
+	// A failed delivery does not undo the registration, the user can ask for
+	// the email again once logged in.
+	a.emailVerificationServices.SendVerificationEmail(createdUser)
+
+	return nil
+}
+
+func (a *AuthServices) VerifyEmail(token string) *models.Error {
+	return a.emailVerificationServices.VerifyEmail(token)
+}
+
+func (a *AuthServices) ResendVerificationEmail(userID string) *models.Error {
+	user, merr := a.userServices.GetUserByID(userID)
+	if merr != nil {
+		return merr
+	}
+
+	return a.emailVerificationServices.SendVerificationEmail(user)
+}
+
+// hashMasterPasswordHash derives the hash that is stored for a client supplied
+// master password hash with the given iteration count. It returns the stored
+// hash and the salt, both Base64.
+func hashMasterPasswordHash(masterPasswordHash string, iterationCount int) (string, string, *models.Error) {
+	salt, err := crypto.CreateRandomSalt(32)
+	if err != nil {
+		description := "Creating salt error"
+		return "", "", models.NewError(500, "InternalError", description)
+	}
+
+	hash, err := base64.StdEncoding.DecodeString(masterPasswordHash)
+	if err != nil {
+		description := "Password is not valid Base64"
+		return "", "", models.NewError(422, "UnprocessableContent", description)
+	}
+
+	newMasterPasswordHash := crypto.DeriveKeySha256(hash, salt, iterationCount, MASTER_PASSWORD_HASH_LENGTH)
+
+	return base64.StdEncoding.EncodeToString(newMasterPasswordHash), base64.StdEncoding.EncodeToString(salt), nil
+}
+
+// verifyMasterPasswordHash re-derives the stored hash from the client supplied
+// master password hash and compares it with the one saved for the user.
+func verifyMasterPasswordHash(user *models.User, masterPasswordHash string) *models.Error {
+	salt, err := base64.StdEncoding.DecodeString(user.Salt)
+	if err != nil {
+		description := "Error decoding salt"
+		return models.NewError(500, "InternalError", description)
+	}
+
+	hash, err := base64.StdEncoding.DecodeString(masterPasswordHash)
+	if err != nil {
+		description := "Password is not valid Base64"
+		return models.NewError(422, "UnprocessableContent", description)
+	}
+	newMasterPasswordHash := crypto.DeriveKeySha256(hash, salt, user.IterationCount, MASTER_PASSWORD_HASH_LENGTH)
+
+	if subtle.ConstantTimeCompare([]byte(user.MasterPasswordHash), []byte(base64.StdEncoding.EncodeToString(newMasterPasswordHash))) != 1 {
+		description := "Invalid master password or email"
+		return models.NewError(401, "Unauthorized", description)
+	}
+
 	return nil
 }
+
+func (a *AuthServices) SendPasswordHint(email string) *models.Error {
+	return a.passwordHintServices.SendPasswordHint(email)
+}
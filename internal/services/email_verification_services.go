@@ -0,0 +1,136 @@
+package services
+
+import (
+	"fmt"
+	"net/url"
+	"strconv"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/safepass/server/internal/config"
+	"github.com/safepass/server/internal/logging"
+	"github.com/safepass/server/internal/mail"
+	"github.com/safepass/server/internal/repositories"
+	"github.com/safepass/server/pkg/models"
+)
+
+const EMAIL_VERIFICATION_AUDIENCE = "safepass-email-verification"
+
+type EmailVerificationServicesMethods interface {
+	CreateVerificationToken(user *models.User) (string, *models.Error)
+	SendVerificationEmail(user *models.User) *models.Error
+	VerifyEmail(token string) *models.Error
+}
+
+type EmailVerificationServices struct {
+	userRepository *repositories.UserRepository
+	mailer         mail.Mailer
+	logger         *logging.Logger
+	appConfig      *config.Config
+
+	EmailVerificationServicesMethods
+}
+
+func NewEmailVerificationServices(userRepository *repositories.UserRepository, mailer mail.Mailer, logger *logging.Logger, config *config.Config) *EmailVerificationServices {
+	return &EmailVerificationServices{
+		userRepository: userRepository,
+		mailer:         mailer,
+		logger:         logger,
+		appConfig:      config,
+	}
+}
+
+// CreateVerificationToken signs a token that proves ownership of the user's
+// current email address. The address is part of the token, so changing the
+// email invalidates links that were sent before.
+func (e *EmailVerificationServices) CreateVerificationToken(user *models.User) (string, *models.Error) {
+	key, err := e.appConfig.GetJWTSecretKey()
+	if err != nil {
+		description := "Config internal error"
+		return "", models.NewError(500, "InternalError", description)
+	}
+
+	t := jwt.NewWithClaims(jwt.SigningMethodES256, jwt.MapClaims{
+		"iss":   "safepass",
+		"sub":   strconv.Itoa(user.ID),
+		"aud":   EMAIL_VERIFICATION_AUDIENCE,
+		"iat":   time.Now().Unix(),
+		"exp":   time.Now().Add(time.Hour * time.Duration(e.appConfig.Account.VerificationTokenTTL)).Unix(),
+		"email": user.Email,
+	})
+
+	s, err := t.SignedString(key)
+	if err != nil {
+		description := "Error signing verification token"
+		return "", models.NewError(500, "InternalError", description)
+	}
+
+	return s, nil
+}
+
+func (e *EmailVerificationServices) SendVerificationEmail(user *models.User) *models.Error {
+	if user.VerifiedAt != nil {
+		description := "Email is already verified"
+		return models.NewError(409, "Conflict", description)
+	}
+
+	token, merr := e.CreateVerificationToken(user)
+	if merr != nil {
+		return merr
+	}
+
+	link := fmt.Sprintf("%s/api/v1/auth/verify-email?token=%s", e.appConfig.Server.PublicURL, url.QueryEscape(token))
+	message := &mail.Message{
+		To:      user.Email,
+		Subject: "Verify your SafePass email address",
+		Body: fmt.Sprintf("Hello %s,\n\nPlease confirm your email address by opening the link below:\n\n%s\n\nThe link expires in %d hours. If you did not create a SafePass account you can ignore this email.\n",
+			user.Username, link, e.appConfig.Account.VerificationTokenTTL),
+	}
+
+	err := e.mailer.Send(message)
+	if err != nil {
+		e.logger.Error(err.Error())
+
+		description := "Verification email could not be sent"
+		return models.NewError(502, "BadGateway", description)
+	}
+
+	return nil
+}
+
+func (e *EmailVerificationServices) VerifyEmail(token string) *models.Error {
+	key, err := e.appConfig.GetJWTSecretKey()
+	if err != nil {
+		description := "Config internal error"
+		return models.NewError(500, "InternalError", description)
+	}
+
+	parsed, err := jwt.Parse(token, func(token *jwt.Token) (interface{}, error) {
+		return &key.PublicKey, nil
+	}, jwt.WithValidMethods([]string{jwt.SigningMethodES256.Alg()}), jwt.WithAudience(EMAIL_VERIFICATION_AUDIENCE), jwt.WithIssuer("safepass"))
+	if err != nil || !parsed.Valid {
+		description := "Verification link is invalid or has expired"
+		return models.NewError(400, "BadRequest", description)
+	}
+
+	claims := parsed.Claims.(jwt.MapClaims)
+	userID, _ := claims["sub"].(string)
+	email, _ := claims["email"].(string)
+
+	user, merr := e.userRepository.GetUserByID(userID)
+	if merr != nil {
+		return merr
+	}
+
+	if user.Email != email {
+		description := "Verification link is invalid or has expired"
+		return models.NewError(400, "BadRequest", description)
+	}
+
+	if user.VerifiedAt != nil {
+		return nil
+	}
+
+	now := time.Now().UTC()
+	return e.userRepository.SetVerifiedAt(user.ID, &now)
+}
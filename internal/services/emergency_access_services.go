@@ -0,0 +1,588 @@
+package services
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/safepass/server/internal/config"
+	"github.com/safepass/server/internal/consts"
+	"github.com/safepass/server/internal/logging"
+	"github.com/safepass/server/internal/mail"
+	"github.com/safepass/server/internal/repositories"
+	"github.com/safepass/server/pkg/dtos/audit"
+	"github.com/safepass/server/pkg/dtos/emergency"
+	"github.com/safepass/server/pkg/models"
+)
+
+type EmergencyAccessServicesMethods interface {
+	GetTrustedContacts(grantorID int) ([]*models.EmergencyAccess, *models.Error)
+	GetGrantedAccesses(granteeID int) ([]*models.EmergencyAccess, *models.Error)
+	Invite(grantorID int, request *emergency.InviteRequest) (*models.EmergencyAccess, *models.Error)
+	Accept(id int, granteeID int, request *emergency.AcceptRequest) (*models.EmergencyAccess, *models.Error)
+	Confirm(id int, grantorID int, request *emergency.ConfirmRequest) (*models.EmergencyAccess, *models.Error)
+	InitiateRecovery(id int, granteeID int) (*models.EmergencyAccess, *models.Error)
+	ApproveRecovery(id int, grantorID int) (*models.EmergencyAccess, *models.Error)
+	RejectRecovery(id int, grantorID int) (*models.EmergencyAccess, *models.Error)
+	Delete(id int, userID int) *models.Error
+	View(id int, granteeID int) (*emergency.EmergencyView, *models.Error)
+	GetTakeoverKey(id int, granteeID int) (*emergency.EmergencyView, *models.Error)
+	Takeover(id int, granteeID int, request *emergency.TakeoverRequest) *models.Error
+	ApproveDueRecoveries() error
+}
+
+type EmergencyAccessServices struct {
+	emergencyAccessRepository *repositories.EmergencyAccessRepository
+	auditRepository           *repositories.AuditRepository
+	userServices              *UserServices
+	vaultServices             *VaultServices
+	mailer                    mail.Mailer
+	logger                    *logging.Logger
+	appConfig                 *config.Config
+
+	EmergencyAccessServicesMethods
+}
+
+func NewEmergencyAccessServices(
+	emergencyAccessRepository *repositories.EmergencyAccessRepository,
+	auditRepository *repositories.AuditRepository,
+	userServices *UserServices,
+	vaultServices *VaultServices,
+	mailer mail.Mailer,
+	logger *logging.Logger,
+	config *config.Config,
+) *EmergencyAccessServices {
+	return &EmergencyAccessServices{
+		emergencyAccessRepository: emergencyAccessRepository,
+		auditRepository:           auditRepository,
+		userServices:              userServices,
+		vaultServices:             vaultServices,
+		mailer:                    mailer,
+		logger:                    logger,
+		appConfig:                 config,
+	}
+}
+
+func (e *EmergencyAccessServices) GetTrustedContacts(grantorID int) ([]*models.EmergencyAccess, *models.Error) {
+	accesses, merr := e.emergencyAccessRepository.GetEmergencyAccessesByGrantorID(strconv.Itoa(grantorID))
+	if merr != nil {
+		return nil, merr
+	}
+
+	for _, access := range accesses {
+		hideInvitee(access)
+	}
+
+	return accesses, nil
+}
+
+func (e *EmergencyAccessServices) GetGrantedAccesses(granteeID int) ([]*models.EmergencyAccess, *models.Error) {
+	merr := e.claimInvitations(granteeID)
+	if merr != nil {
+		return nil, merr
+	}
+
+	accesses, merr := e.emergencyAccessRepository.GetEmergencyAccessesByGranteeID(strconv.Itoa(granteeID))
+	if merr != nil {
+		return nil, merr
+	}
+
+	for _, access := range accesses {
+		hideKey(access)
+	}
+
+	return accesses, nil
+}
+
+// Invite addresses the invitation to the email address, whether or not it
+// belongs to an account, so the response does not tell. The owner of the
+// address becomes the grantee once they have verified it and list their
+// grants.
+func (e *EmergencyAccessServices) Invite(grantorID int, request *emergency.InviteRequest) (*models.EmergencyAccess, *models.Error) {
+	grantor, merr := e.userServices.GetUserByID(strconv.Itoa(grantorID))
+	if merr != nil {
+		return nil, merr
+	}
+
+	email := normalizeEmail(request.Email)
+	if email == grantor.Email {
+		return nil, models.NewError(400, "BadRequest", "You cannot be your own trusted contact.")
+	}
+
+	waitTimeDays := request.WaitTimeDays
+	if waitTimeDays == 0 {
+		waitTimeDays = e.appConfig.EmergencyAccess.DefaultWaitTimeDays
+	}
+
+	access, merr := e.emergencyAccessRepository.CreateEmergencyAccess(&emergency.CreateEmergencyAccess{
+		GrantorID:    grantorID,
+		GranteeEmail: email,
+		Type:         request.Type,
+		Status:       consts.EmergencyAccessStatuses.INVITED,
+		WaitTimeDays: waitTimeDays,
+	})
+	if merr != nil {
+		return nil, merr
+	}
+
+	e.notify(email, "You have been invited as a SafePass emergency contact",
+		"A SafePass user has invited you to be their emergency contact. Sign in to SafePass with this email address, or create an account with it, to accept or decline the invitation.\n")
+
+	hideInvitee(access)
+	return access, nil
+}
+
+// Accept stores the public key the grantor will wrap the vault key with.
+func (e *EmergencyAccessServices) Accept(id int, granteeID int, request *emergency.AcceptRequest) (*models.EmergencyAccess, *models.Error) {
+	merr := e.claimInvitations(granteeID)
+	if merr != nil {
+		return nil, merr
+	}
+
+	access, merr := e.getAsGrantee(id, granteeID)
+	if merr != nil {
+		return nil, merr
+	}
+
+	merr = requireStatus(access, consts.EmergencyAccessStatuses.INVITED)
+	if merr != nil {
+		return nil, merr
+	}
+
+	publicKey := request.PublicKey
+	if publicKey == "" {
+		key, merr := e.userServices.GetUserKey(strconv.Itoa(granteeID))
+		if merr != nil {
+			return nil, models.NewError(422, "UnprocessableContent", "A public key is required to accept the invitation.")
+		}
+
+		publicKey = key.PublicKey
+	}
+
+	access, merr = e.update(access, &emergency.UpdateEmergencyAccess{
+		Status:           consts.EmergencyAccessStatuses.ACCEPTED,
+		GranteePublicKey: publicKey,
+	})
+	if merr != nil {
+		return nil, merr
+	}
+
+	hideKey(access)
+	return access, nil
+}
+
+// Confirm stores the grantor's vault key wrapped with the grantee's public key.
+// The server cannot unwrap it, it is only handed out once recovery is approved.
+func (e *EmergencyAccessServices) Confirm(id int, grantorID int, request *emergency.ConfirmRequest) (*models.EmergencyAccess, *models.Error) {
+	access, merr := e.getAsGrantor(id, grantorID)
+	if merr != nil {
+		return nil, merr
+	}
+
+	merr = requireStatus(access, consts.EmergencyAccessStatuses.ACCEPTED)
+	if merr != nil {
+		return nil, merr
+	}
+
+	return e.update(access, &emergency.UpdateEmergencyAccess{
+		Status:       consts.EmergencyAccessStatuses.CONFIRMED,
+		KeyEncrypted: request.KeyEncrypted,
+	})
+}
+
+func (e *EmergencyAccessServices) InitiateRecovery(id int, granteeID int) (*models.EmergencyAccess, *models.Error) {
+	access, merr := e.getAsGrantee(id, granteeID)
+	if merr != nil {
+		return nil, merr
+	}
+
+	merr = requireStatus(access, consts.EmergencyAccessStatuses.CONFIRMED)
+	if merr != nil {
+		return nil, merr
+	}
+
+	now := time.Now().UTC()
+	access, merr = e.update(access, &emergency.UpdateEmergencyAccess{
+		Status:              consts.EmergencyAccessStatuses.RECOVERY_INITIATED,
+		RecoveryInitiatedAt: &now,
+	})
+	if merr != nil {
+		return nil, merr
+	}
+
+	e.audit(access.GrantorID, "emergency_access_recovery_initiated", access)
+	if access.Grantor != nil {
+		e.notify(access.Grantor.Email, "Emergency access to your SafePass vault was requested",
+			fmt.Sprintf("Your emergency contact has requested %s access to your vault. Access will be granted automatically in %d days unless you reject the request in SafePass.\n",
+				access.Type, access.WaitTimeDays))
+	}
+
+	hideKey(access)
+	return access, nil
+}
+
+func (e *EmergencyAccessServices) ApproveRecovery(id int, grantorID int) (*models.EmergencyAccess, *models.Error) {
+	access, merr := e.getAsGrantor(id, grantorID)
+	if merr != nil {
+		return nil, merr
+	}
+
+	merr = requireStatus(access, consts.EmergencyAccessStatuses.RECOVERY_INITIATED)
+	if merr != nil {
+		return nil, merr
+	}
+
+	return e.approve(access)
+}
+
+// RejectRecovery returns the grant to the confirmed state, the grantee may
+// request access again later.
+func (e *EmergencyAccessServices) RejectRecovery(id int, grantorID int) (*models.EmergencyAccess, *models.Error) {
+	access, merr := e.getAsGrantor(id, grantorID)
+	if merr != nil {
+		return nil, merr
+	}
+
+	if access.Status != consts.EmergencyAccessStatuses.RECOVERY_INITIATED && access.Status != consts.EmergencyAccessStatuses.RECOVERY_APPROVED {
+		return nil, models.NewError(409, "Conflict", "There is no recovery request to reject.")
+	}
+
+	access, merr = e.update(access, &emergency.UpdateEmergencyAccess{
+		Status: consts.EmergencyAccessStatuses.CONFIRMED,
+	})
+	if merr != nil {
+		return nil, merr
+	}
+
+	e.audit(access.GrantorID, "emergency_access_recovery_rejected", access)
+	if access.Grantee != nil {
+		e.notify(access.Grantee.Email, "Your SafePass emergency access request was rejected",
+			"The owner of the vault has rejected your emergency access request.\n")
+	}
+
+	return access, nil
+}
+
+// Delete removes the grant. Both the grantor and the grantee may do this.
+func (e *EmergencyAccessServices) Delete(id int, userID int) *models.Error {
+	access, merr := e.emergencyAccessRepository.GetEmergencyAccess(strconv.Itoa(id))
+	if merr != nil {
+		return merr
+	}
+
+	if access.GrantorID != userID && access.GranteeID != userID {
+		return models.NewError(404, "NotFound", "Emergency access not found with id="+strconv.Itoa(id))
+	}
+
+	_, merr = e.emergencyAccessRepository.DeleteEmergencyAccess(strconv.Itoa(id))
+	return merr
+}
+
+// View returns the wrapped vault key and the grantor's encrypted passwords.
+func (e *EmergencyAccessServices) View(id int, granteeID int) (*emergency.EmergencyView, *models.Error) {
+	access, merr := e.getApproved(id, granteeID, consts.EmergencyAccessTypes.VIEW)
+	if merr != nil {
+		return nil, merr
+	}
+
+	vault, merr := e.vaultServices.GetVaultByUserID(strconv.Itoa(access.GrantorID))
+	if merr != nil {
+		return nil, merr
+	}
+
+	passwords, merr := e.vaultServices.GetPasswords(strconv.Itoa(vault.ID), nil)
+	if merr != nil {
+		return nil, merr
+	}
+
+	uncovered, merr := e.uncoveredVaults(access.GrantorID)
+	if merr != nil {
+		return nil, merr
+	}
+
+	e.audit(access.GrantorID, "emergency_access_viewed", access)
+
+	return &emergency.EmergencyView{
+		KeyEncrypted:    access.KeyEncrypted,
+		Passwords:       passwords,
+		UncoveredVaults: vaultNames(uncovered),
+	}, nil
+}
+
+// GetTakeoverKey returns the wrapped vault key, and the vaults a takeover
+// would discard.
+func (e *EmergencyAccessServices) GetTakeoverKey(id int, granteeID int) (*emergency.EmergencyView, *models.Error) {
+	access, merr := e.getApproved(id, granteeID, consts.EmergencyAccessTypes.TAKEOVER)
+	if merr != nil {
+		return nil, merr
+	}
+
+	uncovered, merr := e.uncoveredVaults(access.GrantorID)
+	if merr != nil {
+		return nil, merr
+	}
+
+	return &emergency.EmergencyView{
+		KeyEncrypted:    access.KeyEncrypted,
+		UncoveredVaults: vaultNames(uncovered),
+	}, nil
+}
+
+// Takeover sets a new master password on the grantor's account. The client
+// unwraps the vault key and protects it again under the new master key. The
+// key, the master password and the grant change in one transaction that also
+// signs the grantor out everywhere, and the grant cannot be used again.
+// Vaults other than the default one cannot be re-wrapped by the grantee, so
+// the takeover is refused while the grantor has any unless the request
+// agrees to discard them.
+func (e *EmergencyAccessServices) Takeover(id int, granteeID int, request *emergency.TakeoverRequest) *models.Error {
+	access, merr := e.getApproved(id, granteeID, consts.EmergencyAccessTypes.TAKEOVER)
+	if merr != nil {
+		return merr
+	}
+
+	parts := strings.Split(request.ProtectedSymmetricKey, ":")
+	if len(parts) != 2 {
+		return models.NewError(422, "Unprocessable Content", "Protected symmetric key is not valid.")
+	}
+
+	uncovered, merr := e.uncoveredVaults(access.GrantorID)
+	if merr != nil {
+		return merr
+	}
+
+	if len(uncovered) > 0 && !request.DiscardUncoveredVaults {
+		description := fmt.Sprintf("Emergency access does not cover the vaults %s, they would be lost. Set discard_uncovered_vaults to take over anyway.", strings.Join(vaultNames(uncovered), ", "))
+		return models.NewError(409, "Conflict", description)
+	}
+
+	hash, salt, merr := hashMasterPasswordHash(request.MasterPasswordHash, MASTER_PASSWORD_HASH_ITERATION_COUNT)
+	if merr != nil {
+		return merr
+	}
+
+	_, merr = e.emergencyAccessRepository.Takeover(id, granteeID, &emergency.Takeover{
+		MasterPasswordHash:    hash,
+		Salt:                  salt,
+		IterationCount:        MASTER_PASSWORD_HASH_ITERATION_COUNT,
+		ProtectedSymmetricKey: parts[1],
+		Mac:                   parts[0],
+		DiscardVaults:         request.DiscardUncoveredVaults,
+	})
+	if merr != nil {
+		return merr
+	}
+
+	vault, merr := e.vaultServices.GetVaultByUserID(strconv.Itoa(access.GrantorID))
+	if merr == nil {
+		e.vaultServices.notifyVault(consts.VaultEventTypes.VAULT_UPDATED, vault)
+	}
+
+	e.audit(access.GrantorID, "emergency_access_takeover", access)
+	if access.Grantor != nil {
+		body := "Your emergency contact has taken over your SafePass account and set a new master password. You have been signed out of all devices.\n"
+		if len(uncovered) > 0 {
+			body += fmt.Sprintf("Emergency access only covered your default vault. Your other vaults (%s) were deleted.\n", strings.Join(vaultNames(uncovered), ", "))
+		}
+
+		e.notify(access.Grantor.Email, "Your SafePass master password was changed by your emergency contact", body)
+	}
+
+	return nil
+}
+
+// ApproveDueRecoveries approves every recovery request whose wait period has
+// passed without the grantor rejecting it.
+func (e *EmergencyAccessServices) ApproveDueRecoveries() error {
+	accesses, merr := e.emergencyAccessRepository.GetEmergencyAccessesByStatus(consts.EmergencyAccessStatuses.RECOVERY_INITIATED)
+	if merr != nil {
+		return fmt.Errorf("%s", merr.Description)
+	}
+
+	now := time.Now()
+	for _, access := range accesses {
+		if access.RecoveryInitiatedAt == nil {
+			continue
+		}
+
+		due := access.RecoveryInitiatedAt.Add(time.Hour * 24 * time.Duration(access.WaitTimeDays))
+		if now.Before(due) {
+			continue
+		}
+
+		_, merr = e.approve(access)
+		if merr != nil && merr.Code != 409 {
+			return fmt.Errorf("%s", merr.Description)
+		}
+	}
+
+	return nil
+}
+
+func (e *EmergencyAccessServices) approve(access *models.EmergencyAccess) (*models.EmergencyAccess, *models.Error) {
+	access, merr := e.update(access, &emergency.UpdateEmergencyAccess{
+		Status: consts.EmergencyAccessStatuses.RECOVERY_APPROVED,
+	})
+	if merr != nil {
+		return nil, merr
+	}
+
+	e.audit(access.GrantorID, "emergency_access_recovery_approved", access)
+	if access.Grantee != nil {
+		e.notify(access.Grantee.Email, "Your SafePass emergency access request was approved",
+			"You can now open SafePass to access the vault you are an emergency contact for.\n")
+	}
+
+	return access, nil
+}
+
+// update applies a transition to the grant as it was read. When the grant
+// left that status in the meantime, for example because the grantor rejected
+// a recovery the job is approving, nothing is changed and 409 is returned.
+func (e *EmergencyAccessServices) update(access *models.EmergencyAccess, update *emergency.UpdateEmergencyAccess) (*models.EmergencyAccess, *models.Error) {
+	update.UpdatedAt = time.Now()
+
+	_, merr := e.emergencyAccessRepository.UpdateEmergencyAccess(strconv.Itoa(access.ID), access.Status, update)
+	if merr != nil {
+		return nil, merr
+	}
+
+	return e.emergencyAccessRepository.GetEmergencyAccess(strconv.Itoa(access.ID))
+}
+
+// claimInvitations hands the pending invitations sent to the grantee's email
+// address to the grantee. Only a verified address can claim them.
+func (e *EmergencyAccessServices) claimInvitations(granteeID int) *models.Error {
+	grantee, merr := e.userServices.GetUserByID(strconv.Itoa(granteeID))
+	if merr != nil {
+		return merr
+	}
+
+	if grantee.VerifiedAt == nil {
+		return nil
+	}
+
+	return e.emergencyAccessRepository.ClaimEmergencyAccesses(grantee.ID, grantee.Email)
+}
+
+// getAsGrantor and getAsGrantee return not found for grants the user is not
+// part of, so ids of other users' grants cannot be probed.
+func (e *EmergencyAccessServices) getAsGrantor(id int, grantorID int) (*models.EmergencyAccess, *models.Error) {
+	access, merr := e.emergencyAccessRepository.GetEmergencyAccess(strconv.Itoa(id))
+	if merr != nil {
+		return nil, merr
+	}
+
+	if access.GrantorID != grantorID {
+		return nil, models.NewError(404, "NotFound", "Emergency access not found with id="+strconv.Itoa(id))
+	}
+
+	return access, nil
+}
+
+func (e *EmergencyAccessServices) getAsGrantee(id int, granteeID int) (*models.EmergencyAccess, *models.Error) {
+	access, merr := e.emergencyAccessRepository.GetEmergencyAccess(strconv.Itoa(id))
+	if merr != nil {
+		return nil, merr
+	}
+
+	if access.GranteeID != granteeID {
+		return nil, models.NewError(404, "NotFound", "Emergency access not found with id="+strconv.Itoa(id))
+	}
+
+	return access, nil
+}
+
+func (e *EmergencyAccessServices) getApproved(id int, granteeID int, accessType string) (*models.EmergencyAccess, *models.Error) {
+	access, merr := e.getAsGrantee(id, granteeID)
+	if merr != nil {
+		return nil, merr
+	}
+
+	merr = requireStatus(access, consts.EmergencyAccessStatuses.RECOVERY_APPROVED)
+	if merr != nil {
+		return nil, merr
+	}
+
+	if access.Type != accessType {
+		return nil, models.NewError(403, "Forbidden", "This emergency access does not allow "+accessType+".")
+	}
+
+	return access, nil
+}
+
+// uncoveredVaults returns the grantor's vaults other than the default one,
+// which emergency access does not cover.
+func (e *EmergencyAccessServices) uncoveredVaults(grantorID int) ([]*models.Vault, *models.Error) {
+	vaults, merr := e.vaultServices.GetVaults(grantorID)
+	if merr != nil {
+		return nil, merr
+	}
+
+	var uncovered []*models.Vault
+	for _, vault := range vaults {
+		if !vault.IsDefault {
+			uncovered = append(uncovered, vault)
+		}
+	}
+
+	return uncovered, nil
+}
+
+func vaultNames(vaults []*models.Vault) []string {
+	names := make([]string, 0, len(vaults))
+	for _, vault := range vaults {
+		names = append(names, vault.Name)
+	}
+
+	return names
+}
+
+func (e *EmergencyAccessServices) audit(userID int, event string, access *models.EmergencyAccess) {
+	e.auditRepository.CreateAuditLog(&audit.CreateAuditLog{
+		UserID: userID,
+		Event:  event,
+		Metadata: map[string]interface{}{
+			"emergency_access_id": access.ID,
+			"grantee_id":          access.GranteeID,
+			"type":                access.Type,
+		},
+	})
+}
+
+func (e *EmergencyAccessServices) notify(to string, subject string, body string) {
+	err := e.mailer.Send(&mail.Message{
+		To:      to,
+		Subject: subject,
+		Body:    body,
+	})
+	if err != nil {
+		e.logger.Error(err.Error())
+	}
+}
+
+func requireStatus(access *models.EmergencyAccess, status string) *models.Error {
+	if access.Status != status {
+		description := fmt.Sprintf("Emergency access is %s, expected %s.", access.Status, status)
+		return models.NewError(409, "Conflict", description)
+	}
+
+	return nil
+}
+
+// hideInvitee removes the grantee's account from invitations they have not
+// accepted, so the grantor cannot learn whether the address is registered.
+func hideInvitee(access *models.EmergencyAccess) {
+	if access.Status == consts.EmergencyAccessStatuses.INVITED {
+		access.GranteeID = 0
+		access.Grantee = nil
+	}
+}
+
+// hideKey removes the wrapped vault key from grants the grantee may not use yet.
+func hideKey(access *models.EmergencyAccess) {
+	if access.Status != consts.EmergencyAccessStatuses.RECOVERY_APPROVED {
+		access.KeyEncrypted = ""
+	}
+}
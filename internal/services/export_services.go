@@ -0,0 +1,384 @@
+package services
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/safepass/server/internal/config"
+	"github.com/safepass/server/internal/consts"
+	"github.com/safepass/server/internal/repositories"
+	"github.com/safepass/server/pkg/dtos/password"
+	"github.com/safepass/server/pkg/dtos/vault"
+	"github.com/safepass/server/pkg/models"
+)
+
+// The format and the version of SafePass exports. The version goes up when
+// the format changes in a way older servers cannot import.
+const (
+	EXPORT_FORMAT  = "safepass"
+	EXPORT_VERSION = 1
+)
+
+type ExportServicesMethods interface {
+	ExportVault(vaultID string, userID int) (*vault.VaultExport, *models.Error)
+	ImportVault(vaultID string, userID int, request *vault.VaultImportRequest) (*vault.VaultImportResponse, *models.Error)
+}
+
+type ExportServices struct {
+	vaultRepository    *repositories.VaultRepository
+	passwordRepository *repositories.PasswordRepository
+	folderRepository   *repositories.FolderRepository
+	tagRepository      *repositories.TagRepository
+	vaultServices      *VaultServices
+	policyServices     *PolicyServices
+	appConfig          *config.Config
+
+	ExportServicesMethods
+}
+
+func NewExportServices(vaultRepository *repositories.VaultRepository, passwordRepository *repositories.PasswordRepository, folderRepository *repositories.FolderRepository, tagRepository *repositories.TagRepository, vaultServices *VaultServices, policyServices *PolicyServices, config *config.Config) *ExportServices {
+	return &ExportServices{
+		vaultRepository:    vaultRepository,
+		passwordRepository: passwordRepository,
+		folderRepository:   folderRepository,
+		tagRepository:      tagRepository,
+		vaultServices:      vaultServices,
+		policyServices:     policyServices,
+		appConfig:          config,
+	}
+}
+
+// ExportVault exports the items of the vault that are not in the trash, with
+// the folders and tags they use. Organization policies can forbid exports.
+func (e *ExportServices) ExportVault(vaultID string, userID int) (*vault.VaultExport, *models.Error) {
+	current, merr := e.vaultServices.GetUserVault(vaultID, userID)
+	if merr != nil {
+		return nil, merr
+	}
+
+	merr = e.policyServices.CheckPersonalExport(userID)
+	if merr != nil {
+		return nil, merr
+	}
+
+	items, merr := e.passwordRepository.GetPasswordsByVaultID(strconv.Itoa(current.ID), nil)
+	if merr != nil {
+		return nil, merr
+	}
+
+	folders, merr := e.folderRepository.GetFoldersByUserID(strconv.Itoa(userID))
+	if merr != nil {
+		return nil, merr
+	}
+
+	tags, merr := e.tagRepository.GetTagsByUserID(strconv.Itoa(userID))
+	if merr != nil {
+		return nil, merr
+	}
+
+	usedFolders := make(map[int]bool)
+	usedTags := make(map[int]bool)
+	for _, item := range items {
+		if item.FolderID != nil {
+			usedFolders[*item.FolderID] = true
+		}
+
+		for _, tag := range item.Tags {
+			usedTags[tag.TagID] = true
+		}
+	}
+
+	export := &vault.VaultExport{
+		Format:     EXPORT_FORMAT,
+		Version:    EXPORT_VERSION,
+		ExportedAt: time.Now().UTC().Format(time.RFC3339),
+		Vault: &vault.ExportedVault{
+			ID:                    current.ID,
+			Name:                  current.Name,
+			ProtectedSymmetricKey: current.ProtectedSymmetricKey,
+			Mac:                   current.Mac,
+			Algorithm:             current.Algorithm,
+			Revision:              current.Revision,
+		},
+		Folders: []*models.Folder{},
+		Tags:    []*models.Tag{},
+		Items:   items,
+	}
+
+	for _, folder := range folders {
+		if usedFolders[folder.ID] {
+			export.Folders = append(export.Folders, folder)
+		}
+	}
+
+	for _, tag := range tags {
+		if usedTags[tag.ID] {
+			export.Tags = append(export.Tags, tag)
+		}
+	}
+
+	export.Mac, merr = e.exportMac(export)
+	if merr != nil {
+		return nil, merr
+	}
+
+	return export, nil
+}
+
+// ImportVault restores an export into the vault. Folders and tags are matched
+// to the user's by id and encrypted name, and created when there is no match.
+// Items get new ids; items the vault already has with the same encrypted
+// content are duplicates and are left out, so an export can be imported again
+// safely.
+//
+// The items are encrypted with the export's vault key. They can be imported
+// into a vault with the same key, or into a vault without items, whose key is
+// then replaced by the export's.
+func (e *ExportServices) ImportVault(vaultID string, userID int, request *vault.VaultImportRequest) (*vault.VaultImportResponse, *models.Error) {
+	current, merr := e.vaultServices.GetUserVault(vaultID, userID)
+	if merr != nil {
+		return nil, merr
+	}
+
+	merr = e.vaultServices.CanWrite(current)
+	if merr != nil {
+		return nil, merr
+	}
+
+	export := request.Export
+	if export.Format != EXPORT_FORMAT || export.Version < 1 || export.Version > EXPORT_VERSION {
+		return nil, models.NewError(422, "UnprocessableContent", "The export is not a SafePass export this server can read.")
+	}
+
+	mac, merr := e.exportMac(export)
+	if merr != nil {
+		return nil, merr
+	}
+
+	if !hmac.Equal([]byte(mac), []byte(export.Mac)) {
+		return nil, models.NewError(422, "UnprocessableContent", "The export was altered or is corrupted.")
+	}
+
+	key := &vault.CreateVault{Mac: export.Vault.Mac, ProtectedSymmetricKey: export.Vault.ProtectedSymmetricKey}
+	if request.ProtectedSymmetricKey != "" {
+		parts := strings.Split(request.ProtectedSymmetricKey, ":")
+		if len(parts) != 2 {
+			return nil, models.NewError(422, "UnprocessableContent", "Protected symmetric key is not valid.")
+		}
+
+		key = &vault.CreateVault{Mac: parts[0], ProtectedSymmetricKey: parts[1]}
+	}
+
+	existing, merr := e.passwordRepository.GetPasswordsChangedSince(strconv.Itoa(current.ID), 0)
+	if merr != nil {
+		return nil, merr
+	}
+
+	if key.Mac == current.Mac && key.ProtectedSymmetricKey == current.ProtectedSymmetricKey {
+		key = nil
+	} else if len(existing) > 0 {
+		return nil, models.NewError(409, "Conflict", "The export was made with another vault key. Import it into a vault without items, or import its items with the item import.")
+	}
+
+	response := &vault.VaultImportResponse{
+		ItemIDs:    map[int]int{},
+		FolderIDs:  map[int]int{},
+		TagIDs:     map[int]int{},
+		Duplicates: []int{},
+	}
+
+	folders, merr := e.folderRepository.GetFoldersByUserID(strconv.Itoa(userID))
+	if merr != nil {
+		return nil, merr
+	}
+
+	tags, merr := e.tagRepository.GetTagsByUserID(strconv.Itoa(userID))
+	if merr != nil {
+		return nil, merr
+	}
+
+	// Ids of the export that map to existing folders and tags, and the
+	// indexes of the ones that are created.
+	folderIDsByName := make(map[string][]int, len(folders))
+	for _, folder := range folders {
+		folderIDsByName[folder.EncryptedName] = append(folderIDsByName[folder.EncryptedName], folder.ID)
+	}
+
+	folderNames := make([]string, 0)
+	newFolders := make(map[int]int)
+	for _, exported := range export.Folders {
+		id, found := matchExported(exported.ID, folderIDsByName[exported.EncryptedName])
+		if found {
+			response.FolderIDs[exported.ID] = id
+		} else {
+			newFolders[exported.ID] = len(folderNames)
+			folderNames = append(folderNames, exported.EncryptedName)
+		}
+	}
+
+	tagIDsByLabel := make(map[string][]int, len(tags))
+	for _, tag := range tags {
+		tagIDsByLabel[tag.EncryptedLabel] = append(tagIDsByLabel[tag.EncryptedLabel], tag.ID)
+	}
+
+	tagLabels := make([]string, 0)
+	newTags := make(map[int]int)
+	for _, exported := range export.Tags {
+		id, found := matchExported(exported.ID, tagIDsByLabel[exported.EncryptedLabel])
+		if found {
+			response.TagIDs[exported.ID] = id
+		} else {
+			newTags[exported.ID] = len(tagLabels)
+			tagLabels = append(tagLabels, exported.EncryptedLabel)
+		}
+	}
+
+	seen := make(map[string]bool, len(existing))
+	for _, item := range existing {
+		if item.DeletedAt == nil {
+			seen[itemFingerprint(item)] = true
+		}
+	}
+
+	var exportedIDs []int
+	writes := make([]*vault.VaultImportWrite, 0, len(export.Items))
+	for _, item := range export.Items {
+		fingerprint := itemFingerprint(item)
+		if seen[fingerprint] {
+			response.Duplicates = append(response.Duplicates, item.ID)
+			continue
+		}
+
+		seen[fingerprint] = true
+
+		write := &vault.VaultImportWrite{Item: newExportedPassword(current.ID, item)}
+		if item.FolderID != nil {
+			if id, ok := response.FolderIDs[*item.FolderID]; ok {
+				write.Item.FolderID = &id
+			} else if index, ok := newFolders[*item.FolderID]; ok {
+				write.FolderIndex = &index
+			}
+		}
+
+		for _, tag := range item.Tags {
+			if id, ok := response.TagIDs[tag.TagID]; ok {
+				write.TagIDs = append(write.TagIDs, id)
+			} else if index, ok := newTags[tag.TagID]; ok {
+				write.TagIndexes = append(write.TagIndexes, index)
+			}
+		}
+
+		exportedIDs = append(exportedIDs, item.ID)
+		writes = append(writes, write)
+	}
+
+	result, merr := e.vaultRepository.ImportVault(userID, current.ID, key, folderNames, tagLabels, writes)
+	if merr != nil {
+		return nil, merr
+	}
+
+	for _, exported := range export.Folders {
+		if index, ok := newFolders[exported.ID]; ok && index < len(result.FolderIDs) {
+			response.FolderIDs[exported.ID] = result.FolderIDs[index]
+		}
+	}
+
+	for _, exported := range export.Tags {
+		if index, ok := newTags[exported.ID]; ok && index < len(result.TagIDs) {
+			response.TagIDs[exported.ID] = result.TagIDs[index]
+		}
+	}
+
+	for n, id := range result.PasswordIDs {
+		if n < len(exportedIDs) {
+			response.ItemIDs[exportedIDs[n]] = id
+		}
+	}
+
+	response.KeyReplaced = key != nil
+	if response.KeyReplaced {
+		e.vaultServices.notifyVault(consts.VaultEventTypes.VAULT_UPDATED, current)
+	}
+
+	e.vaultServices.notifyItems(consts.VaultEventTypes.ITEM_CREATED, current.ID, userID, result.PasswordIDs...)
+	return response, nil
+}
+
+// exportMac returns the MAC of the export without its Mac, over its JSON
+// encoding so that exports that went through a client's JSON parser still
+// verify.
+func (e *ExportServices) exportMac(export *vault.VaultExport) (string, *models.Error) {
+	key, err := e.appConfig.GetExportMacKey()
+	if err != nil {
+		return "", models.NewError(500, "InternalServerError", "Vault exports are not available.")
+	}
+
+	unsigned := *export
+	unsigned.Mac = ""
+
+	data, err := json.Marshal(&unsigned)
+	if err != nil {
+		return "", models.NewError(500, "InternalServerError", "An error occurred while encoding the export.")
+	}
+
+	mac := hmac.New(sha256.New, key)
+	mac.Write(data)
+
+	return base64.StdEncoding.EncodeToString(mac.Sum(nil)), nil
+}
+
+// matchExported picks the user's folder or tag an exported one stands for,
+// among the ids of the ones with the same encrypted name: the one with the
+// exported id, or else the first.
+func matchExported(id int, candidates []int) (int, bool) {
+	if len(candidates) == 0 {
+		return 0, false
+	}
+
+	for _, candidate := range candidates {
+		if candidate == id {
+			return candidate, true
+		}
+	}
+
+	return candidates[0], true
+}
+
+// itemFingerprint identifies an item by its encrypted content, which is the
+// same for an item and its copy in an export. Whether it is a favorite does
+// not count.
+func itemFingerprint(pw *models.Password) string {
+	content := newExportedPassword(0, pw)
+	content.Favorite = false
+
+	data, _ := json.Marshal(content)
+	sum := sha256.Sum256(data)
+
+	return hex.EncodeToString(sum[:])
+}
+
+func newExportedPassword(vaultID int, pw *models.Password) *password.CreatePassword {
+	return &password.CreatePassword{
+		VaultID:           vaultID,
+		Type:              pw.Type,
+		AppName:           pw.AppName,
+		Uri:               pw.Uri,
+		Username:          pw.Username,
+		EncryptedPassword: pw.EncryptedPassword,
+		ItemKey:           pw.ItemKey,
+		Favorite:          pw.Favorite,
+		SecureNote:        pw.SecureNote,
+		Card:              pw.Card,
+		Identity:          pw.Identity,
+		SshKey:            pw.SshKey,
+		ApiCredential:     pw.ApiCredential,
+		Fields:            pw.Fields,
+		Uris:              pw.Uris,
+	}
+}
@@ -0,0 +1,69 @@
+package services
+
+import (
+	"strconv"
+	"time"
+
+	"github.com/safepass/server/internal/consts"
+	"github.com/safepass/server/internal/repositories"
+	"github.com/safepass/server/pkg/dtos/folder"
+	"github.com/safepass/server/pkg/models"
+)
+
+type FolderServicesMethods interface {
+	GetFolders(userID int) ([]*models.Folder, *models.Error)
+	CreateFolder(userID int, request *folder.FolderRequest) (*models.Folder, *models.Error)
+	UpdateFolder(folderID int, userID int, request *folder.FolderRequest) (*models.Folder, *models.Error)
+	DeleteFolder(folderID int, userID int) *models.Error
+}
+
+type FolderServices struct {
+	folderRepository *repositories.FolderRepository
+	vaultServices    *VaultServices
+
+	FolderServicesMethods
+}
+
+func NewFolderServices(folderRepository *repositories.FolderRepository, vaultServices *VaultServices) *FolderServices {
+	return &FolderServices{
+		folderRepository: folderRepository,
+		vaultServices:    vaultServices,
+	}
+}
+
+func (f *FolderServices) GetFolders(userID int) ([]*models.Folder, *models.Error) {
+	return f.folderRepository.GetFoldersByUserID(strconv.Itoa(userID))
+}
+
+func (f *FolderServices) CreateFolder(userID int, request *folder.FolderRequest) (*models.Folder, *models.Error) {
+	return f.folderRepository.CreateFolder(&folder.CreateFolder{
+		UserID:        userID,
+		EncryptedName: request.EncryptedName,
+		UpdatedAt:     time.Now(),
+	})
+}
+
+func (f *FolderServices) UpdateFolder(folderID int, userID int, request *folder.FolderRequest) (*models.Folder, *models.Error) {
+	return f.folderRepository.UpdateFolder(strconv.Itoa(folderID), &folder.CreateFolder{
+		UserID:        userID,
+		EncryptedName: request.EncryptedName,
+		UpdatedAt:     time.Now(),
+	})
+}
+
+// DeleteFolder deletes the folder. The passwords in it are kept and end up in
+// no folder, which is notified as an update of each of them.
+func (f *FolderServices) DeleteFolder(folderID int, userID int) *models.Error {
+	passwords, merr := f.vaultServices.passwordRepository.GetPasswordsByFolderID(strconv.Itoa(folderID))
+	if merr != nil {
+		return merr
+	}
+
+	_, merr = f.folderRepository.DeleteFolder(strconv.Itoa(folderID), strconv.Itoa(userID))
+	if merr != nil {
+		return merr
+	}
+
+	f.vaultServices.notifyPasswords(consts.VaultEventTypes.ITEM_UPDATED, userID, passwords)
+	return nil
+}
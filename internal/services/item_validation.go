@@ -0,0 +1,252 @@
+package services
+
+import (
+	"net/url"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/safepass/server/internal/consts"
+	"github.com/safepass/server/pkg/dtos/password"
+	"github.com/safepass/server/pkg/models"
+)
+
+// Limits on the custom fields of an item. The sizes apply to the encrypted
+// strings, which are longer than the plain text.
+const (
+	maxCustomFields         = 50
+	maxCustomFieldNameSize  = 1024
+	maxCustomFieldValueSize = 10240
+
+	maxLoginUris    = 20
+	maxLoginUriSize = 2048
+)
+
+// linkableFields are the item fields a linked custom field can stand for, by
+// item type.
+var linkableFields = map[string][]string{
+	consts.ItemTypes.LOGIN:    {"username", "password"},
+	consts.ItemTypes.CARD:     {"cardholder_name", "brand", "number", "exp_month", "exp_year", "code"},
+	consts.ItemTypes.IDENTITY: {"title", "first_name", "middle_name", "last_name", "email", "phone", "address", "company"},
+}
+
+// validateItem checks that an item request carries the encrypted fields its
+// type requires and no payload of another type. An empty type is a login.
+func validateItem(request *password.CreatePasswordRequest) *models.Error {
+	if request.Type == "" {
+		request.Type = consts.ItemTypes.LOGIN
+	}
+
+	payloads := map[string]bool{
+		consts.ItemTypes.SECURE_NOTE:    request.SecureNote != nil,
+		consts.ItemTypes.CARD:           request.Card != nil,
+		consts.ItemTypes.IDENTITY:       request.Identity != nil,
+		consts.ItemTypes.SSH_KEY:        request.SshKey != nil,
+		consts.ItemTypes.API_CREDENTIAL: request.ApiCredential != nil,
+	}
+
+	for itemType, present := range payloads {
+		if present && itemType != request.Type {
+			return models.NewError(422, "UnprocessableContent", "A "+request.Type+" item cannot have a "+itemType+" payload.")
+		}
+	}
+
+	var missing string
+	switch request.Type {
+	case consts.ItemTypes.LOGIN:
+		if request.EncryptedPassword == "" {
+			missing = "encrypted_password"
+		}
+	case consts.ItemTypes.SECURE_NOTE:
+		if request.SecureNote == nil || request.SecureNote.EncryptedNotes == "" {
+			missing = "secure_note.encrypted_notes"
+		}
+	case consts.ItemTypes.CARD:
+		if request.Card == nil || request.Card.EncryptedNumber == "" {
+			missing = "card.encrypted_number"
+		}
+	case consts.ItemTypes.IDENTITY:
+		if request.Identity == nil || request.Identity.EncryptedFirstName == "" {
+			missing = "identity.encrypted_first_name"
+		} else if request.Identity.EncryptedLastName == "" {
+			missing = "identity.encrypted_last_name"
+		}
+	case consts.ItemTypes.SSH_KEY:
+		if request.SshKey == nil || request.SshKey.EncryptedPrivateKey == "" {
+			missing = "ssh_key.encrypted_private_key"
+		} else if request.SshKey.EncryptedPublicKey == "" {
+			missing = "ssh_key.encrypted_public_key"
+		}
+	case consts.ItemTypes.API_CREDENTIAL:
+		if request.ApiCredential == nil || request.ApiCredential.EncryptedSecret == "" {
+			missing = "api_credential.encrypted_secret"
+		}
+	default:
+		return models.NewError(422, "UnprocessableContent", "Unknown item type "+request.Type)
+	}
+
+	if missing != "" {
+		return models.NewError(422, "UnprocessableContent", "A "+request.Type+" item requires "+missing+".")
+	}
+
+	merr := validateLoginUris(request)
+	if merr != nil {
+		return merr
+	}
+
+	return validateCustomFields(request.Type, request.Fields)
+}
+
+// validateLoginUris checks and normalizes the URIs of a login. A login sent
+// with only the single uri of older clients gets it as its URI list, and uri
+// is kept in sync with the first URI of the list. Updates restore the stored
+// list with keepStoredUris.
+func validateLoginUris(request *password.CreatePasswordRequest) *models.Error {
+	if request.Type != consts.ItemTypes.LOGIN {
+		if len(request.Uris) > 0 {
+			return models.NewError(422, "UnprocessableContent", "Only login items can have uris.")
+		}
+
+		return nil
+	}
+
+	request.UrisOmitted = request.Uris == nil
+	if len(request.Uris) == 0 && request.Uri != "" {
+		request.Uris = []*models.LoginUri{{Uri: request.Uri}}
+	}
+
+	if len(request.Uris) > maxLoginUris {
+		return models.NewError(422, "UnprocessableContent", "A login can have at most "+strconv.Itoa(maxLoginUris)+" uris.")
+	}
+
+	for i, uri := range request.Uris {
+		name := "uris[" + strconv.Itoa(i) + "]"
+
+		if uri == nil || strings.TrimSpace(uri.Uri) == "" {
+			return models.NewError(422, "UnprocessableContent", name+" requires uri.")
+		}
+
+		switch uri.Match {
+		case "", consts.UriMatchTypes.BASE_DOMAIN, consts.UriMatchTypes.HOST, consts.UriMatchTypes.STARTS_WITH,
+			consts.UriMatchTypes.EXACT, consts.UriMatchTypes.REGEX, consts.UriMatchTypes.NEVER:
+		default:
+			return models.NewError(422, "UnprocessableContent", name+" has an unknown match "+strconv.Quote(uri.Match)+".")
+		}
+
+		normalized, err := normalizeUri(uri.Uri, uri.Match)
+		if err != nil {
+			return models.NewError(422, "UnprocessableContent", name+" is not a valid uri: "+err.Error())
+		}
+
+		if len(normalized) > maxLoginUriSize {
+			return models.NewError(422, "UnprocessableContent", name+" is longer than "+strconv.Itoa(maxLoginUriSize)+" characters.")
+		}
+
+		uri.Uri = normalized
+	}
+
+	if len(request.Uris) > 0 {
+		request.Uri = request.Uris[0].Uri
+	}
+
+	return nil
+}
+
+// keepStoredUris keeps the URI list of a stored login when a validated update
+// comes without uris. Only the first URI is replaced, by the update's uri.
+func keepStoredUris(request *password.CreatePasswordRequest, current *models.Password) *models.Error {
+	if !request.UrisOmitted || request.Type != consts.ItemTypes.LOGIN || len(current.Uris) == 0 {
+		return nil
+	}
+
+	uris := make([]*models.LoginUri, 0, len(current.Uris))
+	for _, uri := range current.Uris {
+		stored := *uri
+		uris = append(uris, &stored)
+	}
+
+	if request.Uri != "" {
+		normalized, err := normalizeUri(request.Uri, uris[0].Match)
+		if err != nil {
+			return models.NewError(422, "UnprocessableContent", "uri is not a valid uri: "+err.Error())
+		}
+
+		uris[0].Uri = normalized
+	}
+
+	request.Uris = uris
+	request.Uri = uris[0].Uri
+	return nil
+}
+
+// normalizeUri trims the URI and, unless it is a regular expression or never
+// matched, gives it a scheme and lowercases its host so clients compare URIs
+// the same way. Regular expressions only have to compile.
+func normalizeUri(raw string, match string) (string, error) {
+	uri := strings.TrimSpace(raw)
+
+	switch match {
+	case consts.UriMatchTypes.REGEX:
+		_, err := regexp.Compile(uri)
+		return uri, err
+	case consts.UriMatchTypes.NEVER:
+		return uri, nil
+	}
+
+	if !strings.Contains(uri, "://") {
+		uri = "https://" + uri
+	}
+
+	parsed, err := url.Parse(uri)
+	if err != nil {
+		return "", err
+	}
+
+	parsed.Host = strings.ToLower(parsed.Host)
+
+	return parsed.String(), nil
+}
+
+// validateCustomFields checks the custom fields of an item of the given type
+// against the field types and the size limits.
+func validateCustomFields(itemType string, fields []*models.CustomField) *models.Error {
+	if len(fields) > maxCustomFields {
+		return models.NewError(422, "UnprocessableContent", "An item can have at most "+strconv.Itoa(maxCustomFields)+" custom fields.")
+	}
+
+	for i, field := range fields {
+		name := "fields[" + strconv.Itoa(i) + "]"
+
+		if field == nil || field.EncryptedName == "" {
+			return models.NewError(422, "UnprocessableContent", name+" requires encrypted_name.")
+		}
+
+		if len(field.EncryptedName) > maxCustomFieldNameSize {
+			return models.NewError(422, "UnprocessableContent", name+".encrypted_name is longer than "+strconv.Itoa(maxCustomFieldNameSize)+" characters.")
+		}
+
+		if len(field.EncryptedValue) > maxCustomFieldValueSize {
+			return models.NewError(422, "UnprocessableContent", name+".encrypted_value is longer than "+strconv.Itoa(maxCustomFieldValueSize)+" characters.")
+		}
+
+		switch field.Type {
+		case consts.CustomFieldTypes.TEXT, consts.CustomFieldTypes.HIDDEN, consts.CustomFieldTypes.BOOLEAN:
+			if field.LinkedField != "" {
+				return models.NewError(422, "UnprocessableContent", "Only linked fields can have linked_field, "+name+" is "+field.Type+".")
+			}
+		case consts.CustomFieldTypes.LINKED:
+			if field.EncryptedValue != "" {
+				return models.NewError(422, "UnprocessableContent", name+" is linked and cannot have encrypted_value.")
+			}
+
+			if !slices.Contains(linkableFields[itemType], field.LinkedField) {
+				return models.NewError(422, "UnprocessableContent", name+" cannot be linked to "+strconv.Quote(field.LinkedField)+" on a "+itemType+" item.")
+			}
+		default:
+			return models.NewError(422, "UnprocessableContent", name+" has an unknown type "+strconv.Quote(field.Type)+".")
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,147 @@
+package services
+
+import (
+	"strconv"
+	"time"
+
+	"github.com/safepass/server/internal/config"
+	"github.com/safepass/server/internal/consts"
+	"github.com/safepass/server/internal/notifications"
+	"github.com/safepass/server/internal/repositories"
+	"github.com/safepass/server/pkg/models"
+)
+
+type NotificationServicesMethods interface {
+	Subscribe(userID int, lastEventID int64) (*notifications.Subscription, []*models.VaultEvent)
+	HeartbeatInterval() time.Duration
+	EvictHistories() error
+
+	NotifyVault(eventType string, vault *models.Vault)
+	NotifyItems(eventType string, vaultID int, userID int, passwordIDs []int)
+	NotifyShare(eventType string, share *models.PasswordShare)
+}
+
+type NotificationServices struct {
+	hub                     *notifications.Hub
+	vaultRepository         *repositories.VaultRepository
+	passwordShareRepository *repositories.PasswordShareRepository
+	appConfig               *config.Config
+
+	NotificationServicesMethods
+}
+
+func NewNotificationServices(hub *notifications.Hub, vaultRepository *repositories.VaultRepository, passwordShareRepository *repositories.PasswordShareRepository, config *config.Config) *NotificationServices {
+	return &NotificationServices{
+		hub:                     hub,
+		vaultRepository:         vaultRepository,
+		passwordShareRepository: passwordShareRepository,
+		appConfig:               config,
+	}
+}
+
+// Subscribe registers a device of the user for events. See Hub.Subscribe for
+// the events missed since lastEventID that are returned with it.
+func (n *NotificationServices) Subscribe(userID int, lastEventID int64) (*notifications.Subscription, []*models.VaultEvent) {
+	return n.hub.Subscribe(userID, lastEventID)
+}
+
+func (n *NotificationServices) HeartbeatInterval() time.Duration {
+	seconds := n.appConfig.Notifications.HeartbeatSeconds
+	if seconds <= 0 {
+		seconds = 25
+	}
+
+	return time.Duration(seconds) * time.Second
+}
+
+// EvictHistories drops the kept events of users who have been idle for
+// notifications.history_idle_minutes.
+func (n *NotificationServices) EvictHistories() error {
+	minutes := n.appConfig.Notifications.HistoryIdleMinutes
+	if minutes <= 0 {
+		minutes = 60
+	}
+
+	n.hub.Evict(time.Duration(minutes) * time.Minute)
+	return nil
+}
+
+// NotifyVault tells the owner's devices that a vault was created, renamed,
+// re-keyed or deleted.
+func (n *NotificationServices) NotifyVault(eventType string, vault *models.Vault) {
+	n.hub.Publish(vault.UserID, models.VaultEvent{
+		Type:    eventType,
+		VaultID: vault.ID,
+	})
+}
+
+// NotifyItems tells the devices of the vault's owner, and of the users the
+// passwords are shared with, that passwords of the vault changed. When the
+// owner is not known userID is zero and the owner is looked up.
+//
+// Notifications are best effort: the write they report has already been made,
+// so lookup errors only cost the recipients their event and are not returned.
+func (n *NotificationServices) NotifyItems(eventType string, vaultID int, userID int, passwordIDs []int) {
+	if len(passwordIDs) == 0 {
+		return
+	}
+
+	if userID == 0 {
+		vault, merr := n.vaultRepository.GetVault(strconv.Itoa(vaultID))
+		if merr != nil {
+			return
+		}
+
+		userID = vault.UserID
+	}
+
+	n.hub.Publish(userID, models.VaultEvent{
+		Type:    eventType,
+		VaultID: vaultID,
+		ItemIDs: passwordIDs,
+	})
+
+	// New passwords are not shared with anyone yet.
+	if eventType == consts.VaultEventTypes.ITEM_CREATED {
+		return
+	}
+
+	ids := make([]string, 0, len(passwordIDs))
+	for _, id := range passwordIDs {
+		ids = append(ids, strconv.Itoa(id))
+	}
+
+	shares, merr := n.passwordShareRepository.GetSharesByPasswordIDs(ids)
+	if merr != nil {
+		return
+	}
+
+	shared := make(map[int][]int)
+	for _, share := range shares {
+		shared[share.RecipientID] = append(shared[share.RecipientID], share.PasswordID)
+	}
+
+	for recipientID, itemIDs := range shared {
+		n.hub.Publish(recipientID, models.VaultEvent{
+			Type:    eventType,
+			VaultID: vaultID,
+			ItemIDs: itemIDs,
+			Shared:  true,
+		})
+	}
+}
+
+// NotifyShare tells the devices of the owner and of the recipient of a share
+// that it was made or revoked.
+func (n *NotificationServices) NotifyShare(eventType string, share *models.PasswordShare) {
+	n.hub.Publish(share.OwnerID, models.VaultEvent{
+		Type:    eventType,
+		ItemIDs: []int{share.PasswordID},
+	})
+
+	n.hub.Publish(share.RecipientID, models.VaultEvent{
+		Type:    eventType,
+		ItemIDs: []int{share.PasswordID},
+		Shared:  true,
+	})
+}
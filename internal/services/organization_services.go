@@ -0,0 +1,711 @@
+package services
+
+import (
+	"strconv"
+	"time"
+
+	"github.com/safepass/server/internal/config"
+	"github.com/safepass/server/internal/consts"
+	"github.com/safepass/server/internal/logging"
+	"github.com/safepass/server/internal/mail"
+	"github.com/safepass/server/internal/repositories"
+	"github.com/safepass/server/pkg/dtos/organization"
+	"github.com/safepass/server/pkg/dtos/password"
+	"github.com/safepass/server/pkg/models"
+)
+
+type OrganizationServicesMethods interface {
+	GetOrganizations(userID int) ([]*models.OrganizationMember, *models.Error)
+	CreateOrganization(userID int, request *organization.CreateOrganizationRequest) (*models.OrganizationMember, *models.Error)
+	UpdateOrganization(organizationID int, userID int, request *organization.UpdateOrganizationRequest) (*models.Organization, *models.Error)
+	DeleteOrganization(organizationID int, userID int) *models.Error
+
+	GetMembers(organizationID int, userID int) ([]*models.OrganizationMember, *models.Error)
+	InviteMember(organizationID int, userID int, request *organization.InviteMemberRequest) (*models.OrganizationMember, *models.Error)
+	AcceptInvite(organizationID int, userID int) (*models.OrganizationMember, *models.Error)
+	ConfirmMember(organizationID int, memberID int, userID int, request *organization.ConfirmMemberRequest) (*models.OrganizationMember, *models.Error)
+	UpdateMember(organizationID int, memberID int, userID int, request *organization.UpdateMemberRequest) (*models.OrganizationMember, *models.Error)
+	RemoveMember(organizationID int, memberID int, userID int) *models.Error
+
+	GetCollections(organizationID int, userID int) ([]*models.Collection, *models.Error)
+	CreateCollection(organizationID int, userID int, request *organization.CollectionRequest) (*models.Collection, *models.Error)
+	UpdateCollection(organizationID int, collectionID int, userID int, request *organization.CollectionRequest) (*models.Collection, *models.Error)
+	DeleteCollection(organizationID int, collectionID int, userID int) *models.Error
+
+	GetItems(organizationID int, userID int) ([]*models.Password, *models.Error)
+	GetCollectionItems(organizationID int, collectionID int, userID int) ([]*models.Password, *models.Error)
+	CreateItem(organizationID int, collectionID int, userID int, request *password.CreatePasswordRequest) (*models.Password, *models.Error)
+	UpdateItem(organizationID int, collectionID int, itemID int, userID int, request *password.CreatePasswordRequest) (*models.Password, *models.Error)
+	DeleteItem(organizationID int, collectionID int, itemID int, userID int) *models.Error
+
+	GetPolicies(organizationID int, userID int) ([]*models.OrganizationPolicy, *models.Error)
+	SetPolicy(organizationID int, policyType string, userID int, request *organization.SetPolicyRequest) (*models.OrganizationPolicy, *models.Error)
+}
+
+type OrganizationServices struct {
+	organizationRepository       *repositories.OrganizationRepository
+	organizationMemberRepository *repositories.OrganizationMemberRepository
+	collectionRepository         *repositories.CollectionRepository
+	organizationPolicyRepository *repositories.OrganizationPolicyRepository
+	passwordRepository           *repositories.PasswordRepository
+	userServices                 *UserServices
+	mailer                       mail.Mailer
+	logger                       *logging.Logger
+	appConfig                    *config.Config
+
+	OrganizationServicesMethods
+}
+
+func NewOrganizationServices(
+	organizationRepository *repositories.OrganizationRepository,
+	organizationMemberRepository *repositories.OrganizationMemberRepository,
+	collectionRepository *repositories.CollectionRepository,
+	organizationPolicyRepository *repositories.OrganizationPolicyRepository,
+	passwordRepository *repositories.PasswordRepository,
+	userServices *UserServices,
+	mailer mail.Mailer,
+	logger *logging.Logger,
+	config *config.Config,
+) *OrganizationServices {
+	return &OrganizationServices{
+		organizationRepository:       organizationRepository,
+		organizationMemberRepository: organizationMemberRepository,
+		collectionRepository:         collectionRepository,
+		organizationPolicyRepository: organizationPolicyRepository,
+		passwordRepository:           passwordRepository,
+		userServices:                 userServices,
+		mailer:                       mailer,
+		logger:                       logger,
+		appConfig:                    config,
+	}
+}
+
+func (o *OrganizationServices) GetOrganizations(userID int) ([]*models.OrganizationMember, *models.Error) {
+	return o.organizationMemberRepository.GetMembershipsByUserID(strconv.Itoa(userID))
+}
+
+// CreateOrganization creates the organization with the caller as its first
+// owner. The caller generates the organization key and wraps it for itself.
+func (o *OrganizationServices) CreateOrganization(userID int, request *organization.CreateOrganizationRequest) (*models.OrganizationMember, *models.Error) {
+	org, merr := o.organizationRepository.CreateOrganization(&organization.CreateOrganization{
+		Name: request.Name,
+	})
+	if merr != nil {
+		return nil, merr
+	}
+
+	member, merr := o.organizationMemberRepository.CreateMember(&organization.CreateMember{
+		OrganizationID:  org.ID,
+		UserID:          userID,
+		Role:            consts.OrganizationRoles.OWNER,
+		Status:          consts.OrganizationMemberStatuses.CONFIRMED,
+		EncryptedOrgKey: request.EncryptedOrgKey,
+	})
+	if merr != nil {
+		o.organizationRepository.DeleteOrganization(strconv.Itoa(org.ID))
+		return nil, merr
+	}
+
+	member.Organization = org
+	return member, nil
+}
+
+func (o *OrganizationServices) UpdateOrganization(organizationID int, userID int, request *organization.UpdateOrganizationRequest) (*models.Organization, *models.Error) {
+	_, merr := o.requireRole(organizationID, userID, consts.OrganizationRoles.OWNER)
+	if merr != nil {
+		return nil, merr
+	}
+
+	return o.organizationRepository.UpdateOrganization(strconv.Itoa(organizationID), &organization.UpdateOrganization{
+		Name:      request.Name,
+		UpdatedAt: time.Now(),
+	})
+}
+
+func (o *OrganizationServices) DeleteOrganization(organizationID int, userID int) *models.Error {
+	_, merr := o.requireRole(organizationID, userID, consts.OrganizationRoles.OWNER)
+	if merr != nil {
+		return merr
+	}
+
+	_, merr = o.organizationRepository.DeleteOrganization(strconv.Itoa(organizationID))
+	return merr
+}
+
+func (o *OrganizationServices) GetMembers(organizationID int, userID int) ([]*models.OrganizationMember, *models.Error) {
+	member, merr := o.getConfirmedMember(organizationID, userID)
+	if merr != nil {
+		return nil, merr
+	}
+
+	members, merr := o.organizationMemberRepository.GetMembersByOrganizationID(strconv.Itoa(organizationID))
+	if merr != nil {
+		return nil, merr
+	}
+
+	// Wrapped keys are only of use to their owner.
+	for _, m := range members {
+		if m.ID != member.ID {
+			m.EncryptedOrgKey = ""
+		}
+	}
+
+	return members, nil
+}
+
+// InviteMember invites an existing SafePass user. The user needs a key pair
+// so that an admin can wrap the organization key for them on confirmation.
+func (o *OrganizationServices) InviteMember(organizationID int, userID int, request *organization.InviteMemberRequest) (*models.OrganizationMember, *models.Error) {
+	inviter, merr := o.requireRole(organizationID, userID, consts.OrganizationRoles.ADMIN)
+	if merr != nil {
+		return nil, merr
+	}
+
+	if request.Role == consts.OrganizationRoles.OWNER && inviter.Role != consts.OrganizationRoles.OWNER {
+		return nil, models.NewError(403, "Forbidden", "Only owners can invite owners.")
+	}
+
+	invitee, merr := o.userServices.GetPublicKeyByEmail(request.Email)
+	if merr != nil {
+		return nil, merr
+	}
+
+	member, merr := o.organizationMemberRepository.CreateMember(&organization.CreateMember{
+		OrganizationID: organizationID,
+		UserID:         invitee.UserID,
+		Role:           request.Role,
+		Status:         consts.OrganizationMemberStatuses.INVITED,
+	})
+	if merr != nil {
+		return nil, merr
+	}
+
+	merr = o.setCollectionAccess(organizationID, member.ID, request.Collections)
+	if merr != nil {
+		return nil, merr
+	}
+
+	org, merr := o.organizationRepository.GetOrganization(strconv.Itoa(organizationID))
+	if merr == nil {
+		o.notify(invitee.Email, "You have been invited to a SafePass organization",
+			"You have been invited to join the organization \""+org.Name+"\" on SafePass. Open SafePass to accept the invitation.\n")
+	}
+
+	return o.organizationMemberRepository.GetMember(strconv.Itoa(member.ID))
+}
+
+func (o *OrganizationServices) AcceptInvite(organizationID int, userID int) (*models.OrganizationMember, *models.Error) {
+	member, merr := o.organizationMemberRepository.GetMemberByUserID(strconv.Itoa(organizationID), strconv.Itoa(userID))
+	if merr != nil {
+		return nil, merr
+	}
+
+	if member.Status != consts.OrganizationMemberStatuses.INVITED {
+		return nil, models.NewError(409, "Conflict", "The invitation has already been accepted.")
+	}
+
+	return o.organizationMemberRepository.UpdateMember(strconv.Itoa(member.ID), &organization.UpdateMember{
+		Status:    consts.OrganizationMemberStatuses.ACCEPTED,
+		UpdatedAt: time.Now(),
+	})
+}
+
+// ConfirmMember stores the organization key wrapped with the member's public
+// key, which gives the member access to the organization's items.
+func (o *OrganizationServices) ConfirmMember(organizationID int, memberID int, userID int, request *organization.ConfirmMemberRequest) (*models.OrganizationMember, *models.Error) {
+	_, merr := o.requireRole(organizationID, userID, consts.OrganizationRoles.ADMIN)
+	if merr != nil {
+		return nil, merr
+	}
+
+	member, merr := o.getMember(organizationID, memberID)
+	if merr != nil {
+		return nil, merr
+	}
+
+	if member.Status != consts.OrganizationMemberStatuses.ACCEPTED {
+		return nil, models.NewError(409, "Conflict", "Only members who accepted the invitation can be confirmed.")
+	}
+
+	member, merr = o.organizationMemberRepository.UpdateMember(strconv.Itoa(memberID), &organization.UpdateMember{
+		Status:          consts.OrganizationMemberStatuses.CONFIRMED,
+		EncryptedOrgKey: request.EncryptedOrgKey,
+		UpdatedAt:       time.Now(),
+	})
+	if merr != nil {
+		return nil, merr
+	}
+
+	member.EncryptedOrgKey = ""
+	return member, nil
+}
+
+func (o *OrganizationServices) UpdateMember(organizationID int, memberID int, userID int, request *organization.UpdateMemberRequest) (*models.OrganizationMember, *models.Error) {
+	actor, merr := o.requireRole(organizationID, userID, consts.OrganizationRoles.ADMIN)
+	if merr != nil {
+		return nil, merr
+	}
+
+	member, merr := o.getMember(organizationID, memberID)
+	if merr != nil {
+		return nil, merr
+	}
+
+	touchesOwner := member.Role == consts.OrganizationRoles.OWNER || request.Role == consts.OrganizationRoles.OWNER
+	if touchesOwner && actor.Role != consts.OrganizationRoles.OWNER {
+		return nil, models.NewError(403, "Forbidden", "Only owners can change the owner role.")
+	}
+
+	if request.Role != "" && request.Role != member.Role {
+		if member.Role == consts.OrganizationRoles.OWNER {
+			merr = o.requireAnotherOwner(organizationID, member.ID)
+			if merr != nil {
+				return nil, merr
+			}
+		}
+
+		_, merr = o.organizationMemberRepository.UpdateMember(strconv.Itoa(memberID), &organization.UpdateMember{
+			Role:      request.Role,
+			UpdatedAt: time.Now(),
+		})
+		if merr != nil {
+			return nil, merr
+		}
+	}
+
+	if request.Collections != nil {
+		merr = o.setCollectionAccess(organizationID, memberID, request.Collections)
+		if merr != nil {
+			return nil, merr
+		}
+	}
+
+	member, merr = o.organizationMemberRepository.GetMember(strconv.Itoa(memberID))
+	if merr != nil {
+		return nil, merr
+	}
+
+	member.EncryptedOrgKey = ""
+	return member, nil
+}
+
+// RemoveMember removes a member. Admins can remove others, every member can
+// leave; the last owner cannot.
+func (o *OrganizationServices) RemoveMember(organizationID int, memberID int, userID int) *models.Error {
+	member, merr := o.getMember(organizationID, memberID)
+	if merr != nil {
+		return merr
+	}
+
+	if member.UserID != userID {
+		actor, merr := o.requireRole(organizationID, userID, consts.OrganizationRoles.ADMIN)
+		if merr != nil {
+			return merr
+		}
+
+		if member.Role == consts.OrganizationRoles.OWNER && actor.Role != consts.OrganizationRoles.OWNER {
+			return models.NewError(403, "Forbidden", "Only owners can remove owners.")
+		}
+	}
+
+	if member.Role == consts.OrganizationRoles.OWNER {
+		merr = o.requireAnotherOwner(organizationID, member.ID)
+		if merr != nil {
+			return merr
+		}
+	}
+
+	_, merr = o.organizationMemberRepository.DeleteMember(strconv.Itoa(memberID))
+	return merr
+}
+
+// GetCollections returns the collections the member can access. Owners and
+// admins can access every collection.
+func (o *OrganizationServices) GetCollections(organizationID int, userID int) ([]*models.Collection, *models.Error) {
+	member, merr := o.getConfirmedMember(organizationID, userID)
+	if merr != nil {
+		return nil, merr
+	}
+
+	collections, merr := o.collectionRepository.GetCollectionsByOrganizationID(strconv.Itoa(organizationID))
+	if merr != nil {
+		return nil, merr
+	}
+
+	accessible := []*models.Collection{}
+	for _, collection := range collections {
+		canRead, canWrite := collectionAccess(member, collection.ID)
+		if !canRead {
+			continue
+		}
+
+		collection.ReadOnly = !canWrite
+		accessible = append(accessible, collection)
+	}
+
+	return accessible, nil
+}
+
+func (o *OrganizationServices) CreateCollection(organizationID int, userID int, request *organization.CollectionRequest) (*models.Collection, *models.Error) {
+	_, merr := o.requireRole(organizationID, userID, consts.OrganizationRoles.ADMIN)
+	if merr != nil {
+		return nil, merr
+	}
+
+	return o.collectionRepository.CreateCollection(&organization.CreateCollection{
+		OrganizationID: organizationID,
+		Name:           request.Name,
+		UpdatedAt:      time.Now(),
+	})
+}
+
+func (o *OrganizationServices) UpdateCollection(organizationID int, collectionID int, userID int, request *organization.CollectionRequest) (*models.Collection, *models.Error) {
+	_, merr := o.requireRole(organizationID, userID, consts.OrganizationRoles.ADMIN)
+	if merr != nil {
+		return nil, merr
+	}
+
+	return o.collectionRepository.UpdateCollection(strconv.Itoa(collectionID), &organization.CreateCollection{
+		OrganizationID: organizationID,
+		Name:           request.Name,
+		UpdatedAt:      time.Now(),
+	})
+}
+
+func (o *OrganizationServices) DeleteCollection(organizationID int, collectionID int, userID int) *models.Error {
+	_, merr := o.requireRole(organizationID, userID, consts.OrganizationRoles.ADMIN)
+	if merr != nil {
+		return merr
+	}
+
+	_, merr = o.getCollection(organizationID, collectionID)
+	if merr != nil {
+		return merr
+	}
+
+	_, merr = o.collectionRepository.DeleteCollection(strconv.Itoa(collectionID))
+	return merr
+}
+
+// GetItems returns the items of every collection the member can access.
+func (o *OrganizationServices) GetItems(organizationID int, userID int) ([]*models.Password, *models.Error) {
+	collections, merr := o.GetCollections(organizationID, userID)
+	if merr != nil {
+		return nil, merr
+	}
+
+	collectionIDs := make([]string, 0, len(collections))
+	for _, collection := range collections {
+		collectionIDs = append(collectionIDs, strconv.Itoa(collection.ID))
+	}
+
+	return o.passwordRepository.GetPasswordsByCollectionIDs(collectionIDs)
+}
+
+func (o *OrganizationServices) GetCollectionItems(organizationID int, collectionID int, userID int) ([]*models.Password, *models.Error) {
+	_, merr := o.authorizeCollection(organizationID, collectionID, userID, false)
+	if merr != nil {
+		return nil, merr
+	}
+
+	return o.passwordRepository.GetPasswordsByCollectionIDs([]string{strconv.Itoa(collectionID)})
+}
+
+func (o *OrganizationServices) CreateItem(organizationID int, collectionID int, userID int, request *password.CreatePasswordRequest) (*models.Password, *models.Error) {
+	_, merr := o.authorizeCollection(organizationID, collectionID, userID, true)
+	if merr != nil {
+		return nil, merr
+	}
+
+	merr = validateItem(request)
+	if merr != nil {
+		return nil, merr
+	}
+
+	return o.passwordRepository.CreateOrganizationPassword(&organization.CreateOrganizationPassword{
+		OrganizationID:    organizationID,
+		CollectionID:      collectionID,
+		Type:              request.Type,
+		AppName:           request.AppName,
+		Uri:               request.Uri,
+		Username:          request.Username,
+		EncryptedPassword: request.EncryptedPassword,
+		ItemKey:           request.ItemKey,
+		SecureNote:        request.SecureNote,
+		Card:              request.Card,
+		Identity:          request.Identity,
+		SshKey:            request.SshKey,
+		ApiCredential:     request.ApiCredential,
+		Fields:            request.Fields,
+		Uris:              request.Uris,
+	})
+}
+
+func (o *OrganizationServices) UpdateItem(organizationID int, collectionID int, itemID int, userID int, request *password.CreatePasswordRequest) (*models.Password, *models.Error) {
+	_, merr := o.authorizeCollection(organizationID, collectionID, userID, true)
+	if merr != nil {
+		return nil, merr
+	}
+
+	merr = validateItem(request)
+	if merr != nil {
+		return nil, merr
+	}
+
+	current, merr := o.getItemInCollection(itemID, collectionID)
+	if merr != nil {
+		return nil, merr
+	}
+
+	merr = keepStoredUris(request, current)
+	if merr != nil {
+		return nil, merr
+	}
+
+	return o.passwordRepository.UpdateOrganizationPassword(strconv.Itoa(itemID), &organization.CreateOrganizationPassword{
+		OrganizationID:    organizationID,
+		CollectionID:      collectionID,
+		Type:              request.Type,
+		AppName:           request.AppName,
+		Uri:               request.Uri,
+		Username:          request.Username,
+		EncryptedPassword: request.EncryptedPassword,
+		ItemKey:           request.ItemKey,
+		SecureNote:        request.SecureNote,
+		Card:              request.Card,
+		Identity:          request.Identity,
+		SshKey:            request.SshKey,
+		ApiCredential:     request.ApiCredential,
+		Fields:            request.Fields,
+		Uris:              request.Uris,
+	})
+}
+
+func (o *OrganizationServices) DeleteItem(organizationID int, collectionID int, itemID int, userID int) *models.Error {
+	_, merr := o.authorizeCollection(organizationID, collectionID, userID, true)
+	if merr != nil {
+		return merr
+	}
+
+	_, merr = o.passwordRepository.DeleteOrganizationPassword(strconv.Itoa(itemID), strconv.Itoa(collectionID))
+	return merr
+}
+
+func (o *OrganizationServices) GetPolicies(organizationID int, userID int) ([]*models.OrganizationPolicy, *models.Error) {
+	_, merr := o.getConfirmedMember(organizationID, userID)
+	if merr != nil {
+		return nil, merr
+	}
+
+	return o.organizationPolicyRepository.GetPoliciesByOrganizationID(strconv.Itoa(organizationID))
+}
+
+func (o *OrganizationServices) SetPolicy(organizationID int, policyType string, userID int, request *organization.SetPolicyRequest) (*models.OrganizationPolicy, *models.Error) {
+	_, merr := o.requireRole(organizationID, userID, consts.OrganizationRoles.ADMIN)
+	if merr != nil {
+		return nil, merr
+	}
+
+	data := models.PolicyData{}
+	switch policyType {
+	case consts.PolicyTypes.REQUIRE_TWO_FACTOR:
+		// No member could satisfy it while the server has no second factor,
+		// so enabling it would lock all of them out. It can still be disabled.
+		if request.Enabled {
+			return nil, models.NewError(422, "UnprocessableContent", "Two-factor authentication is not available yet, so this policy cannot be enabled.")
+		}
+	case consts.PolicyTypes.DISABLE_PERSONAL_EXPORT, consts.PolicyTypes.DISABLE_EXTERNAL_SHARING:
+	case consts.PolicyTypes.MIN_HASH_ITERATIONS:
+		if request.Enabled && request.Data.MinIterations <= 0 {
+			return nil, models.NewError(422, "UnprocessableContent", "The policy requires data.min_iterations.")
+		}
+		if request.Data.MinIterations > MAX_POLICY_MIN_ITERATIONS {
+			return nil, models.NewError(422, "UnprocessableContent", "data.min_iterations can be at most "+strconv.Itoa(MAX_POLICY_MIN_ITERATIONS)+".")
+		}
+		data.MinIterations = request.Data.MinIterations
+	case consts.PolicyTypes.MAX_SESSION_TIMEOUT:
+		if request.Enabled && request.Data.MaxSessionTimeout <= 0 {
+			return nil, models.NewError(422, "UnprocessableContent", "The policy requires data.max_session_timeout.")
+		}
+		data.MaxSessionTimeout = request.Data.MaxSessionTimeout
+	default:
+		return nil, models.NewError(404, "NotFound", "Unknown policy type "+policyType)
+	}
+
+	return o.organizationPolicyRepository.SetPolicy(&organization.SetPolicy{
+		OrganizationID: organizationID,
+		Type:           policyType,
+		Enabled:        request.Enabled,
+		Data:           data,
+		UpdatedAt:      time.Now(),
+	})
+}
+
+func (o *OrganizationServices) getItemInCollection(itemID int, collectionID int) (*models.Password, *models.Error) {
+	item, merr := o.passwordRepository.GetPassword(strconv.Itoa(itemID))
+	if merr != nil {
+		return nil, merr
+	}
+
+	if item.CollectionID == nil || *item.CollectionID != collectionID {
+		return nil, models.NewError(404, "NotFound", "Password not found with id="+strconv.Itoa(itemID))
+	}
+
+	return item, nil
+}
+
+// authorizeCollection checks that the member can read the collection, and
+// write to it when write is set.
+func (o *OrganizationServices) authorizeCollection(organizationID int, collectionID int, userID int, write bool) (*models.OrganizationMember, *models.Error) {
+	member, merr := o.getConfirmedMember(organizationID, userID)
+	if merr != nil {
+		return nil, merr
+	}
+
+	_, merr = o.getCollection(organizationID, collectionID)
+	if merr != nil {
+		return nil, merr
+	}
+
+	canRead, canWrite := collectionAccess(member, collectionID)
+	if !canRead {
+		return nil, models.NewError(404, "NotFound", "Collection not found with id="+strconv.Itoa(collectionID))
+	}
+
+	if write && !canWrite {
+		return nil, models.NewError(403, "Forbidden", "You have read-only access to this collection.")
+	}
+
+	return member, nil
+}
+
+func (o *OrganizationServices) getCollection(organizationID int, collectionID int) (*models.Collection, *models.Error) {
+	collection, merr := o.collectionRepository.GetCollection(strconv.Itoa(collectionID))
+	if merr != nil {
+		return nil, merr
+	}
+
+	if collection.OrganizationID != organizationID {
+		return nil, models.NewError(404, "NotFound", "Collection not found with id="+strconv.Itoa(collectionID))
+	}
+
+	return collection, nil
+}
+
+func (o *OrganizationServices) getMember(organizationID int, memberID int) (*models.OrganizationMember, *models.Error) {
+	member, merr := o.organizationMemberRepository.GetMember(strconv.Itoa(memberID))
+	if merr != nil {
+		return nil, merr
+	}
+
+	if member.OrganizationID != organizationID {
+		return nil, models.NewError(404, "NotFound", "Member not found with id="+strconv.Itoa(memberID))
+	}
+
+	return member, nil
+}
+
+// getConfirmedMember returns the caller's membership. Only confirmed members
+// hold the organization key and can work with the organization.
+func (o *OrganizationServices) getConfirmedMember(organizationID int, userID int) (*models.OrganizationMember, *models.Error) {
+	member, merr := o.organizationMemberRepository.GetMemberByUserID(strconv.Itoa(organizationID), strconv.Itoa(userID))
+	if merr != nil {
+		return nil, merr
+	}
+
+	if member.Status != consts.OrganizationMemberStatuses.CONFIRMED {
+		return nil, models.NewError(403, "Forbidden", "Your membership has not been confirmed yet.")
+	}
+
+	return member, nil
+}
+
+// requireRole returns the caller's membership if its role is at least role.
+func (o *OrganizationServices) requireRole(organizationID int, userID int, role string) (*models.OrganizationMember, *models.Error) {
+	member, merr := o.getConfirmedMember(organizationID, userID)
+	if merr != nil {
+		return nil, merr
+	}
+
+	if roleRank(member.Role) < roleRank(role) {
+		return nil, models.NewError(403, "Forbidden", "This action requires the "+role+" role.")
+	}
+
+	return member, nil
+}
+
+func (o *OrganizationServices) requireAnotherOwner(organizationID int, memberID int) *models.Error {
+	members, merr := o.organizationMemberRepository.GetMembersByOrganizationID(strconv.Itoa(organizationID))
+	if merr != nil {
+		return merr
+	}
+
+	for _, m := range members {
+		if m.ID != memberID && m.Role == consts.OrganizationRoles.OWNER && m.Status == consts.OrganizationMemberStatuses.CONFIRMED {
+			return nil
+		}
+	}
+
+	return models.NewError(409, "Conflict", "An organization needs at least one owner.")
+}
+
+func (o *OrganizationServices) setCollectionAccess(organizationID int, memberID int, requests []*organization.CollectionAccessRequest) *models.Error {
+	access := make([]*models.CollectionAccess, 0, len(requests))
+	for _, request := range requests {
+		_, merr := o.getCollection(organizationID, request.CollectionID)
+		if merr != nil {
+			return merr
+		}
+
+		access = append(access, &models.CollectionAccess{
+			CollectionID: request.CollectionID,
+			MemberID:     memberID,
+			ReadOnly:     request.ReadOnly,
+		})
+	}
+
+	return o.organizationMemberRepository.SetCollectionAccess(memberID, access)
+}
+
+func (o *OrganizationServices) notify(to string, subject string, body string) {
+	err := o.mailer.Send(&mail.Message{
+		To:      to,
+		Subject: subject,
+		Body:    body,
+	})
+	if err != nil {
+		o.logger.Error(err.Error())
+	}
+}
+
+func roleRank(role string) int {
+	switch role {
+	case consts.OrganizationRoles.OWNER:
+		return 4
+	case consts.OrganizationRoles.ADMIN:
+		return 3
+	case consts.OrganizationRoles.MEMBER:
+		return 2
+	case consts.OrganizationRoles.READ_ONLY:
+		return 1
+	default:
+		return 0
+	}
+}
+
+// collectionAccess reports whether the member can read and write the
+// collection. Owners and admins manage every collection, read-only members
+// never write.
+func collectionAccess(member *models.OrganizationMember, collectionID int) (bool, bool) {
+	if roleRank(member.Role) >= roleRank(consts.OrganizationRoles.ADMIN) {
+		return true, true
+	}
+
+	for _, access := range member.Collections {
+		if access.CollectionID == collectionID {
+			return true, !access.ReadOnly && member.Role != consts.OrganizationRoles.READ_ONLY
+		}
+	}
+
+	return false, false
+}
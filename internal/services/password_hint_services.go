@@ -0,0 +1,137 @@
+package services
+
+import (
+	"encoding/base64"
+	"fmt"
+
+	"github.com/safepass/server/internal/config"
+	"github.com/safepass/server/internal/logging"
+	"github.com/safepass/server/internal/mail"
+	"github.com/safepass/server/internal/ratelimit"
+	"github.com/safepass/server/internal/repositories"
+	"github.com/safepass/server/pkg/crypto"
+	"github.com/safepass/server/pkg/models"
+)
+
+type PasswordHintServicesMethods interface {
+	ProtectHint(hint string) (string, bool, *models.Error)
+	SendPasswordHint(email string) *models.Error
+}
+
+type PasswordHintServices struct {
+	userRepository *repositories.UserRepository
+	mailer         mail.Mailer
+	limiter        *ratelimit.Limiter
+	logger         *logging.Logger
+	appConfig      *config.Config
+
+	PasswordHintServicesMethods
+}
+
+func NewPasswordHintServices(userRepository *repositories.UserRepository, mailer mail.Mailer, limiter *ratelimit.Limiter, logger *logging.Logger, config *config.Config) *PasswordHintServices {
+	return &PasswordHintServices{
+		userRepository: userRepository,
+		mailer:         mailer,
+		limiter:        limiter,
+		logger:         logger,
+		appConfig:      config,
+	}
+}
+
+// ProtectHint prepares a hint for storage. It returns the value to store and
+// whether it is encrypted with the server's hint key.
+func (p *PasswordHintServices) ProtectHint(hint string) (string, bool, *models.Error) {
+	if hint == "" {
+		return "", false, nil
+	}
+
+	key, err := p.appConfig.GetHintEncryptionKey()
+	if err != nil {
+		description := "Config internal error"
+		return "", false, models.NewError(500, "InternalError", description)
+	}
+
+	if key == nil {
+		return hint, false, nil
+	}
+
+	encrypted, err := crypto.EncryptAES([]byte(hint), key)
+	if err != nil {
+		description := "Error encrypting password hint"
+		return "", false, models.NewError(500, "InternalError", description)
+	}
+
+	return base64.StdEncoding.EncodeToString(encrypted), true, nil
+}
+
+// SendPasswordHint emails the hint to the address if an account with a hint
+// exists. The result does not depend on whether the account exists, and the
+// email is sent in the background so response times do not reveal it either.
+func (p *PasswordHintServices) SendPasswordHint(email string) *models.Error {
+	email = normalizeEmail(email)
+
+	if !p.limiter.Allow(email) {
+		description := "Too many password hint requests, try again later"
+		return models.NewError(429, "TooManyRequests", description)
+	}
+
+	go p.sendPasswordHint(email)
+
+	return nil
+}
+
+func (p *PasswordHintServices) sendPasswordHint(email string) {
+	user, merr := p.userRepository.GetUserByEmail(email)
+	if merr != nil {
+		return
+	}
+
+	body := "You (or someone else) requested your SafePass master password hint.\n\nYou have not set a master password hint.\n"
+	if user.MasterPasswordHint != "" {
+		hint, err := p.revealHint(user)
+		if err != nil {
+			p.logger.Error(err.Error())
+			return
+		}
+
+		body = fmt.Sprintf("You (or someone else) requested your SafePass master password hint.\n\nYour hint is: %s\n", hint)
+	}
+
+	message := &mail.Message{
+		To:      user.Email,
+		Subject: "Your SafePass master password hint",
+		Body:    body + "\nIf you did not request it you can ignore this email.\n",
+	}
+
+	err := p.mailer.Send(message)
+	if err != nil {
+		p.logger.Error(err.Error())
+	}
+}
+
+func (p *PasswordHintServices) revealHint(user *models.User) (string, error) {
+	if !user.MasterPasswordHintEncrypted {
+		return user.MasterPasswordHint, nil
+	}
+
+	key, err := p.appConfig.GetHintEncryptionKey()
+	if err != nil {
+		return "", err
+	}
+
+	if key == nil {
+		return "", fmt.Errorf("hint of user %d is encrypted but no hint key is configured", user.ID)
+	}
+
+	ciphertext, err := base64.StdEncoding.DecodeString(user.MasterPasswordHint)
+	if err != nil {
+		return "", err
+	}
+
+	hint, err := crypto.DecryptAES(ciphertext, key)
+	if err != nil {
+		return "", err
+	}
+
+	return string(hint), nil
+}
@@ -0,0 +1,168 @@
+package services
+
+import (
+	"strconv"
+
+	"github.com/safepass/server/internal/consts"
+	"github.com/safepass/server/internal/repositories"
+	"github.com/safepass/server/pkg/models"
+)
+
+// MAX_POLICY_MIN_ITERATIONS caps the min_hash_iterations policy. The master
+// password hash is re-derived at that count during login, so an unbounded
+// value would let an organization admin tie up the server on every login.
+const MAX_POLICY_MIN_ITERATIONS = 2000000
+
+type PolicyServicesMethods interface {
+	EvaluateLogin(user *models.User) (*SessionPolicy, *models.Error)
+	CheckPersonalExport(userID int) *models.Error
+	CheckSharing(ownerID int, recipientID int) *models.Error
+}
+
+// SessionPolicy is what the organization policies of a user require from a
+// new session. Zero values mean no requirement.
+type SessionPolicy struct {
+	MinIterations     int
+	MaxSessionTimeout int
+}
+
+// PolicyServices evaluates the policies of the organizations a user is a
+// confirmed member of. Owners and admins are exempt, so that an organization
+// can never lock out the members who manage its policies.
+type PolicyServices struct {
+	organizationMemberRepository *repositories.OrganizationMemberRepository
+	organizationPolicyRepository *repositories.OrganizationPolicyRepository
+
+	PolicyServicesMethods
+}
+
+func NewPolicyServices(organizationMemberRepository *repositories.OrganizationMemberRepository, organizationPolicyRepository *repositories.OrganizationPolicyRepository) *PolicyServices {
+	return &PolicyServices{
+		organizationMemberRepository: organizationMemberRepository,
+		organizationPolicyRepository: organizationPolicyRepository,
+	}
+}
+
+func (p *PolicyServices) EvaluateLogin(user *models.User) (*SessionPolicy, *models.Error) {
+	policies, merr := p.getApplicablePolicies(user.ID, "")
+	if merr != nil {
+		return nil, merr
+	}
+
+	sessionPolicy := &SessionPolicy{}
+	violations := []*models.PolicyViolation{}
+	for _, policy := range policies {
+		switch policy.Type {
+		case consts.PolicyTypes.REQUIRE_TWO_FACTOR:
+			// The server does not offer a second factor yet, so no member
+			// can satisfy this policy.
+			violations = append(violations, &models.PolicyViolation{
+				OrganizationID: policy.OrganizationID,
+				Policy:         policy.Type,
+				Message:        "Two-factor authentication is required by your organization.",
+			})
+		case consts.PolicyTypes.MIN_HASH_ITERATIONS:
+			sessionPolicy.MinIterations = max(sessionPolicy.MinIterations, min(policy.Data.MinIterations, MAX_POLICY_MIN_ITERATIONS))
+		case consts.PolicyTypes.MAX_SESSION_TIMEOUT:
+			timeout := policy.Data.MaxSessionTimeout
+			if timeout > 0 && (sessionPolicy.MaxSessionTimeout == 0 || timeout < sessionPolicy.MaxSessionTimeout) {
+				sessionPolicy.MaxSessionTimeout = timeout
+			}
+		}
+	}
+
+	if len(violations) > 0 {
+		return nil, models.NewPolicyViolationError(violations)
+	}
+
+	return sessionPolicy, nil
+}
+
+func (p *PolicyServices) CheckPersonalExport(userID int) *models.Error {
+	policies, merr := p.getApplicablePolicies(userID, consts.PolicyTypes.DISABLE_PERSONAL_EXPORT)
+	if merr != nil {
+		return merr
+	}
+
+	violations := []*models.PolicyViolation{}
+	for _, policy := range policies {
+		violations = append(violations, &models.PolicyViolation{
+			OrganizationID: policy.OrganizationID,
+			Policy:         policy.Type,
+			Message:        "Your organization does not allow exporting your personal vault.",
+		})
+	}
+
+	if len(violations) > 0 {
+		return models.NewPolicyViolationError(violations)
+	}
+
+	return nil
+}
+
+// CheckSharing rejects a share when one of the owner's organizations forbids
+// sharing with users outside of it and the recipient is not a confirmed
+// member of that organization.
+func (p *PolicyServices) CheckSharing(ownerID int, recipientID int) *models.Error {
+	policies, merr := p.getApplicablePolicies(ownerID, consts.PolicyTypes.DISABLE_EXTERNAL_SHARING)
+	if merr != nil {
+		return merr
+	}
+
+	if len(policies) == 0 {
+		return nil
+	}
+
+	memberships, merr := p.organizationMemberRepository.GetMembershipsByUserID(strconv.Itoa(recipientID))
+	if merr != nil {
+		return merr
+	}
+
+	recipientOrganizations := map[int]bool{}
+	for _, membership := range memberships {
+		if membership.Status == consts.OrganizationMemberStatuses.CONFIRMED {
+			recipientOrganizations[membership.OrganizationID] = true
+		}
+	}
+
+	violations := []*models.PolicyViolation{}
+	for _, policy := range policies {
+		if !recipientOrganizations[policy.OrganizationID] {
+			violations = append(violations, &models.PolicyViolation{
+				OrganizationID: policy.OrganizationID,
+				Policy:         policy.Type,
+				Message:        "Your organization does not allow sharing with users outside of it.",
+			})
+		}
+	}
+
+	if len(violations) > 0 {
+		return models.NewPolicyViolationError(violations)
+	}
+
+	return nil
+}
+
+// getApplicablePolicies returns the enabled policies of the given type, or
+// of every type when policyType is empty, that apply to the user.
+func (p *PolicyServices) getApplicablePolicies(userID int, policyType string) ([]*models.OrganizationPolicy, *models.Error) {
+	memberships, merr := p.organizationMemberRepository.GetMembershipsByUserID(strconv.Itoa(userID))
+	if merr != nil {
+		return nil, merr
+	}
+
+	organizationIDs := []string{}
+	for _, membership := range memberships {
+		if membership.Status != consts.OrganizationMemberStatuses.CONFIRMED {
+			continue
+		}
+
+		if roleRank(membership.Role) >= roleRank(consts.OrganizationRoles.ADMIN) {
+			continue
+		}
+
+		organizationIDs = append(organizationIDs, strconv.Itoa(membership.OrganizationID))
+	}
+
+	return p.organizationPolicyRepository.GetEnabledPolicies(organizationIDs, policyType)
+}
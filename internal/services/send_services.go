@@ -0,0 +1,368 @@
+package services
+
+import (
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"time"
+
+	"github.com/safepass/server/internal/config"
+	"github.com/safepass/server/internal/consts"
+	"github.com/safepass/server/internal/logging"
+	"github.com/safepass/server/internal/ratelimit"
+	"github.com/safepass/server/internal/repositories"
+	"github.com/safepass/server/internal/storage"
+	"github.com/safepass/server/pkg/crypto"
+	"github.com/safepass/server/pkg/dtos/send"
+	"github.com/safepass/server/pkg/models"
+)
+
+// SEND_PASSWORD_ITERATIONS is the PBKDF2 iteration count access passwords of
+// Sends are hashed with. SEND_ACCESS_ID_SIZE is the number of random bytes in
+// the id of a Send's link.
+const (
+	SEND_PASSWORD_ITERATIONS = 100000
+	SEND_ACCESS_ID_SIZE      = 16
+)
+
+type SendServicesMethods interface {
+	GetSends(userID int) ([]*models.Send, *models.Error)
+	GetSend(sendID int, userID int) (*models.Send, *models.Error)
+	CreateSend(userID int, sendType string, request *send.CreateSendRequest) (*models.Send, *models.Error)
+	DeleteSend(sendID int, userID int) *models.Error
+	AccessSend(accessID string, clientIP string, request *send.AccessSendRequest) (*send.SendAccessResponse, *models.Error)
+	OpenSendFile(accessID string, clientIP string, request *send.AccessSendRequest) (*models.Send, io.ReadCloser, *models.Error)
+	PurgeSends() error
+}
+
+type SendServices struct {
+	sendRepository       *repositories.SendRepository
+	attachmentRepository *repositories.AttachmentRepository
+	vaultServices        *VaultServices
+	blobStore            storage.BlobStore
+	limiter              *ratelimit.Limiter
+	logger               *logging.Logger
+	appConfig            *config.Config
+
+	SendServicesMethods
+}
+
+func NewSendServices(sendRepository *repositories.SendRepository, attachmentRepository *repositories.AttachmentRepository, vaultServices *VaultServices, blobStore storage.BlobStore, limiter *ratelimit.Limiter, logger *logging.Logger, config *config.Config) *SendServices {
+	return &SendServices{
+		sendRepository:       sendRepository,
+		attachmentRepository: attachmentRepository,
+		vaultServices:        vaultServices,
+		blobStore:            blobStore,
+		limiter:              limiter,
+		logger:               logger,
+		appConfig:            config,
+	}
+}
+
+// GetSends lists the user's Sends with their access counts.
+func (s *SendServices) GetSends(userID int) ([]*models.Send, *models.Error) {
+	sends, merr := s.sendRepository.GetSendsByUserID(strconv.Itoa(userID))
+	if merr != nil {
+		return nil, merr
+	}
+
+	for _, found := range sends {
+		hideSendSecrets(found)
+	}
+
+	return sends, nil
+}
+
+func (s *SendServices) GetSend(sendID int, userID int) (*models.Send, *models.Error) {
+	found, merr := s.sendRepository.GetSend(strconv.Itoa(sendID), strconv.Itoa(userID))
+	if merr != nil {
+		return nil, merr
+	}
+
+	hideSendSecrets(found)
+	return found, nil
+}
+
+// CreateSend creates a text or file Send. The file of a file Send is streamed
+// from the request to the blob store and counted against the user's storage
+// quota, which it shares with attachments.
+func (s *SendServices) CreateSend(userID int, sendType string, request *send.CreateSendRequest) (*models.Send, *models.Error) {
+	vault, merr := s.vaultServices.GetUserVault("@me", userID)
+	if merr != nil {
+		return nil, merr
+	}
+
+	merr = s.vaultServices.CanWrite(vault)
+	if merr != nil {
+		return nil, merr
+	}
+
+	now := time.Now()
+	maxDays := s.appConfig.Sends.MaxDeletionDays
+
+	if !request.DeletionDate.After(now) {
+		return nil, models.NewError(422, "UnprocessableContent", "deletion_date must be in the future.")
+	}
+
+	if maxDays > 0 && request.DeletionDate.After(now.AddDate(0, 0, maxDays)) {
+		return nil, models.NewError(422, "UnprocessableContent", fmt.Sprintf("deletion_date can be at most %d days ahead.", maxDays))
+	}
+
+	if request.ExpirationDate != nil && (!request.ExpirationDate.After(now) || request.ExpirationDate.After(request.DeletionDate)) {
+		return nil, models.NewError(422, "UnprocessableContent", "expiration_date must be in the future and not after deletion_date.")
+	}
+
+	createSend := &send.CreateSend{
+		UserID:         userID,
+		Type:           sendType,
+		EncryptedName:  request.EncryptedName,
+		EncryptedKey:   request.EncryptedKey,
+		MaxAccessCount: request.MaxAccessCount,
+		ExpirationDate: request.ExpirationDate,
+		DeletionDate:   request.DeletionDate,
+	}
+
+	switch sendType {
+	case consts.SendTypes.TEXT:
+		if request.EncryptedText == "" {
+			return nil, models.NewError(422, "UnprocessableContent", "A text Send requires encrypted_text.")
+		}
+
+		createSend.EncryptedText = request.EncryptedText
+	case consts.SendTypes.FILE:
+		if request.EncryptedFileName == "" || request.Data == nil {
+			return nil, models.NewError(422, "UnprocessableContent", "A file Send requires encrypted_file_name and a file.")
+		}
+
+		createSend.EncryptedFileName = request.EncryptedFileName
+	default:
+		return nil, models.NewError(422, "UnprocessableContent", "Unknown Send type "+sendType)
+	}
+
+	accessID, err := crypto.CreateRandomSalt(SEND_ACCESS_ID_SIZE)
+	if err != nil {
+		return nil, models.NewError(500, "InternalServerError", "Could not generate the Send id.")
+	}
+
+	createSend.AccessID = base64.RawURLEncoding.EncodeToString(accessID)
+
+	if request.Password != "" {
+		salt, err := crypto.CreateRandomSalt(32)
+		if err != nil {
+			return nil, models.NewError(500, "InternalServerError", "Could not hash the Send password.")
+		}
+
+		createSend.PasswordSalt = base64.StdEncoding.EncodeToString(salt)
+		createSend.PasswordHash = hashSendPassword(request.Password, salt)
+	}
+
+	if sendType == consts.SendTypes.FILE {
+		merr = s.storeFile(userID, createSend, request.Data)
+		if merr != nil {
+			return nil, merr
+		}
+	}
+
+	created, merr := s.sendRepository.CreateSend(createSend, s.appConfig.Attachments.UserQuota)
+	if merr != nil {
+		if createSend.BlobKey != "" {
+			s.deleteBlob(createSend.BlobKey)
+		}
+
+		return nil, merr
+	}
+
+	hideSendSecrets(created)
+	return created, nil
+}
+
+// DeleteSend deletes one of the user's Sends. The file of a file Send is
+// removed by PurgeDeletedBlobs.
+func (s *SendServices) DeleteSend(sendID int, userID int) *models.Error {
+	_, merr := s.sendRepository.DeleteSend(strconv.Itoa(sendID), strconv.Itoa(userID))
+	return merr
+}
+
+// AccessSend opens a Send for anyone with its link. Opening a text Send
+// counts as an access; file Sends are counted when the file is downloaded.
+func (s *SendServices) AccessSend(accessID string, clientIP string, request *send.AccessSendRequest) (*send.SendAccessResponse, *models.Error) {
+	found, merr := s.authorizeAccess(accessID, clientIP, request)
+	if merr != nil {
+		return nil, merr
+	}
+
+	if found.Type == consts.SendTypes.TEXT {
+		found, merr = s.sendRepository.RecordAccess(found.ID)
+		if merr != nil {
+			return nil, merr
+		}
+	}
+
+	return send.NewSendAccessResponse(found), nil
+}
+
+// OpenSendFile counts an access to a file Send and opens its file for
+// download. The caller closes the returned reader.
+func (s *SendServices) OpenSendFile(accessID string, clientIP string, request *send.AccessSendRequest) (*models.Send, io.ReadCloser, *models.Error) {
+	found, merr := s.authorizeAccess(accessID, clientIP, request)
+	if merr != nil {
+		return nil, nil, merr
+	}
+
+	if found.Type != consts.SendTypes.FILE {
+		return nil, nil, models.NewError(404, "NotFound", "Send not found.")
+	}
+
+	found, merr = s.sendRepository.RecordAccess(found.ID)
+	if merr != nil {
+		return nil, nil, merr
+	}
+
+	content, err := s.blobStore.Get(found.BlobKey)
+	if err != nil {
+		s.logger.Error(err.Error())
+
+		if errors.Is(err, storage.ErrBlobNotFound) {
+			return nil, nil, models.NewError(404, "NotFound", "The file of the Send is missing.")
+		}
+
+		return nil, nil, models.NewError(500, "InternalServerError", "An error occurred while reading the Send.")
+	}
+
+	hideSendSecrets(found)
+	return found, content, nil
+}
+
+// PurgeSends deletes the Sends past their deletion date.
+func (s *SendServices) PurgeSends() error {
+	merr := s.sendRepository.PurgeSends(time.Now())
+	if merr != nil {
+		return fmt.Errorf("%s", merr.Description)
+	}
+
+	return nil
+}
+
+// authorizeAccess returns the Send of a link when it can still be accessed
+// and the password, if it has one, is right. Sends that are expired, out of
+// accesses or deleted are not found, without telling which. Attempts are
+// limited per Send and client, so one client guessing the password does not
+// lock everyone else out of the Send.
+func (s *SendServices) authorizeAccess(accessID string, clientIP string, request *send.AccessSendRequest) (*models.Send, *models.Error) {
+	if !s.limiter.Allow(accessID + "|" + clientIP) {
+		return nil, models.NewError(429, "TooManyRequests", "Too many attempts to access this Send. Try again later.")
+	}
+
+	found, merr := s.sendRepository.GetSendByAccessID(accessID)
+	if merr != nil {
+		return nil, merr
+	}
+
+	if !sendAvailable(found, time.Now()) {
+		return nil, models.NewError(404, "NotFound", "Send not found.")
+	}
+
+	if found.PasswordHash != "" {
+		if request.Password == "" {
+			return nil, models.NewError(401, "Unauthorized", "This Send is protected by a password.")
+		}
+
+		salt, err := base64.StdEncoding.DecodeString(found.PasswordSalt)
+		if err != nil {
+			return nil, models.NewError(500, "InternalServerError", "An error occurred while checking the password.")
+		}
+
+		hash := hashSendPassword(request.Password, salt)
+		if subtle.ConstantTimeCompare([]byte(hash), []byte(found.PasswordHash)) != 1 {
+			return nil, models.NewError(401, "Unauthorized", "The password is wrong.")
+		}
+	}
+
+	return found, nil
+}
+
+// storeFile streams the file of a Send to the blob store, up to the maximum
+// file size and the remaining storage quota.
+func (s *SendServices) storeFile(userID int, createSend *send.CreateSend, data io.Reader) *models.Error {
+	used, merr := s.attachmentRepository.GetUsedStorage(strconv.Itoa(userID))
+	if merr != nil {
+		return merr
+	}
+
+	remaining := s.appConfig.Attachments.UserQuota - used
+	if remaining <= 0 {
+		return models.NewError(507, "InsufficientStorage", "The storage quota is used up.")
+	}
+
+	maxSize := s.appConfig.Sends.MaxFileSize
+	limit := min(maxSize, remaining)
+
+	key, err := crypto.CreateRandomSalt(32)
+	if err != nil {
+		return models.NewError(500, "InternalServerError", "Could not generate the Send key.")
+	}
+
+	blobKey := hex.EncodeToString(key)
+
+	size, err := s.blobStore.Put(blobKey, io.LimitReader(data, limit+1))
+	if err != nil {
+		s.logger.Error(err.Error())
+		return models.NewError(500, "InternalServerError", "An error occurred while storing the Send.")
+	}
+
+	if size > limit {
+		s.deleteBlob(blobKey)
+
+		if limit < maxSize {
+			return models.NewError(507, "InsufficientStorage", "The file does not fit in the remaining storage quota.")
+		}
+
+		return models.NewError(413, "PayloadTooLarge", "Send files can be at most "+strconv.FormatInt(maxSize, 10)+" bytes.")
+	}
+
+	createSend.BlobKey = blobKey
+	createSend.Size = size
+	return nil
+}
+
+func (s *SendServices) deleteBlob(blobKey string) {
+	err := s.blobStore.Delete(blobKey)
+	if err != nil {
+		s.logger.Error(err.Error())
+	}
+}
+
+// sendAvailable reports whether the Send can still be accessed at the given
+// time.
+func sendAvailable(found *models.Send, now time.Time) bool {
+	deletionDate, err := time.Parse(time.RFC3339, found.DeletionDate)
+	if err != nil || !deletionDate.After(now) {
+		return false
+	}
+
+	if found.ExpirationDate != nil {
+		expirationDate, err := time.Parse(time.RFC3339, *found.ExpirationDate)
+		if err != nil || !expirationDate.After(now) {
+			return false
+		}
+	}
+
+	return found.MaxAccessCount == nil || found.AccessCount < *found.MaxAccessCount
+}
+
+func hashSendPassword(password string, salt []byte) string {
+	hash := crypto.DeriveKeySha256([]byte(password), salt, SEND_PASSWORD_ITERATIONS, 32)
+	return base64.StdEncoding.EncodeToString(hash)
+}
+
+// hideSendSecrets clears what the server keeps to itself: the password hash
+// and the location of the file.
+func hideSendSecrets(found *models.Send) {
+	found.PasswordHash = ""
+	found.PasswordSalt = ""
+	found.BlobKey = ""
+}
@@ -0,0 +1,598 @@
+package services
+
+import (
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/safepass/server/internal/config"
+	"github.com/safepass/server/internal/consts"
+	"github.com/safepass/server/internal/repositories"
+	"github.com/safepass/server/pkg/dtos/password"
+	"github.com/safepass/server/pkg/dtos/user"
+	"github.com/safepass/server/pkg/dtos/vault"
+	"github.com/safepass/server/pkg/models"
+)
+
+type SyncServicesMethods interface {
+	Sync(vaultID string, userID int, since int64) (*vault.SyncResponse, *models.Error)
+	ApplyBatch(vaultID string, userID int, request *vault.BatchRequest) (*vault.BatchResponse, *models.Error)
+	PurgeTombstones() error
+}
+
+type SyncServices struct {
+	passwordRepository          *repositories.PasswordRepository
+	passwordTombstoneRepository *repositories.PasswordTombstoneRepository
+	passwordShareRepository     *repositories.PasswordShareRepository
+	vaultServices               *VaultServices
+	userServices                *UserServices
+	appConfig                   *config.Config
+
+	SyncServicesMethods
+}
+
+func NewSyncServices(passwordRepository *repositories.PasswordRepository, passwordTombstoneRepository *repositories.PasswordTombstoneRepository, passwordShareRepository *repositories.PasswordShareRepository, vaultServices *VaultServices, userServices *UserServices, config *config.Config) *SyncServices {
+	return &SyncServices{
+		passwordRepository:          passwordRepository,
+		passwordTombstoneRepository: passwordTombstoneRepository,
+		passwordShareRepository:     passwordShareRepository,
+		vaultServices:               vaultServices,
+		userServices:                userServices,
+		appConfig:                   config,
+	}
+}
+
+// Sync returns what changed in the vault after the given revision. A client
+// without a revision, with one from before purged tombstones or with one the
+// vault never reached gets a full sync instead.
+//
+// The vault is read before its items, so changes made while syncing are at
+// worst returned again by the next sync and never skipped. The default vault
+// also syncs the items shared with its owner, as it lists them.
+func (s *SyncServices) Sync(vaultID string, userID int, since int64) (*vault.SyncResponse, *models.Error) {
+	current, merr := s.vaultServices.GetUserVault(vaultID, userID)
+	if merr != nil {
+		return nil, merr
+	}
+
+	fullSync := since <= 0 || since < current.PurgedRevision || since > current.Revision
+	if fullSync {
+		since = 0
+	}
+
+	response := &vault.SyncResponse{
+		Revision:   current.Revision,
+		FullSync:   fullSync,
+		DeletedIDs: []int{},
+	}
+
+	response.Items, merr = s.passwordRepository.GetPasswordsChangedSince(strconv.Itoa(current.ID), since)
+	if merr != nil {
+		return nil, merr
+	}
+
+	if !fullSync {
+		tombstones, merr := s.passwordTombstoneRepository.GetTombstones(strconv.Itoa(current.ID), since)
+		if merr != nil {
+			return nil, merr
+		}
+
+		for _, tombstone := range tombstones {
+			response.DeletedIDs = append(response.DeletedIDs, tombstone.PasswordID)
+		}
+	}
+
+	if current.IsDefault {
+		merr = s.syncShares(current, since, fullSync, response)
+		if merr != nil {
+			return nil, merr
+		}
+	}
+
+	if fullSync || current.VaultRevision > since {
+		response.Vault = vault.NewVaultResponse(current)
+	}
+
+	if fullSync || current.ProfileRevision > since {
+		profile, merr := s.userServices.GetUserByID(strconv.Itoa(userID))
+		if merr != nil {
+			return nil, merr
+		}
+
+		response.Profile = user.NewPublicUser(profile)
+	}
+
+	return response, nil
+}
+
+// syncShares adds the shares to the vault's owner that changed after the
+// revision. Shared items in the trash are gone for the recipient, like
+// revoked shares.
+func (s *SyncServices) syncShares(current *models.Vault, since int64, fullSync bool, response *vault.SyncResponse) *models.Error {
+	shares, merr := s.passwordShareRepository.GetSharesByRecipientChangedSince(strconv.Itoa(current.UserID), since)
+	if merr != nil {
+		return merr
+	}
+
+	for _, share := range shares {
+		if share.Password == nil {
+			continue
+		}
+
+		if share.Password.DeletedAt != nil {
+			if !fullSync {
+				response.DeletedIDs = append(response.DeletedIDs, share.PasswordID)
+			}
+
+			continue
+		}
+
+		response.Items = append(response.Items, sharedPassword(share))
+	}
+
+	return nil
+}
+
+// PurgeTombstones deletes the tombstones older than the retention period.
+func (s *SyncServices) PurgeTombstones() error {
+	days := s.appConfig.Vault.TombstoneRetentionDays
+	if days <= 0 {
+		return nil
+	}
+
+	_, merr := s.passwordTombstoneRepository.PurgeTombstones(time.Now().AddDate(0, 0, -days))
+	if merr != nil {
+		return fmt.Errorf("%s", merr.Description)
+	}
+
+	return nil
+}
+
+// batchAttempts is how often a batch is planned again when the vault changes
+// while it is applied.
+const batchAttempts = 3
+
+// batchItem is an item operations of a batch apply to: one the server has,
+// or one created earlier in the batch, which ref then names. Once an
+// operation has changed it, later operations follow on from that change
+// rather than from their base revision.
+type batchItem struct {
+	password *models.Password
+	ref      int
+	changed  bool
+	trashed  bool
+	itemKey  string
+	folderID *int
+	favorite bool
+}
+
+// batchPlan holds the writes for a batch and, per operation, its result, the
+// item it applies to and the conflict copy it saves to.
+type batchPlan struct {
+	writes  []*password.BatchWrite
+	results []*vault.BatchResult
+	items   []*batchItem
+	copies  []*batchItem
+}
+
+// ApplyBatch applies the operations a client queued while offline, in order
+// and in a single transaction. Conflicts are resolved in favor of the server:
+//
+//   - an update of an item that changed after its base revision, or that was
+//     deleted, is saved as a new item, a conflict copy, and the server's
+//     version is kept;
+//   - a delete of an item that changed after its base revision is dropped;
+//   - a delete of an item that is already deleted succeeds;
+//   - a create with a client id the vault already has is a duplicate and
+//     creates nothing.
+//
+// An invalid operation rejects the whole batch.
+func (s *SyncServices) ApplyBatch(vaultID string, userID int, request *vault.BatchRequest) (*vault.BatchResponse, *models.Error) {
+	current, merr := s.vaultServices.GetUserVault(vaultID, userID)
+	if merr != nil {
+		return nil, merr
+	}
+
+	merr = s.vaultServices.CanWrite(current)
+	if merr != nil {
+		return nil, merr
+	}
+
+	merr = s.checkBatch(current, request)
+	if merr != nil {
+		return nil, merr
+	}
+
+	for attempt := 0; attempt < batchAttempts; attempt++ {
+		plan, merr := s.planBatch(current, request)
+		if merr != nil {
+			return nil, merr
+		}
+
+		response := &vault.BatchResponse{
+			Revision: current.Revision,
+			Results:  plan.results,
+		}
+
+		if len(plan.writes) == 0 {
+			plan.resolve(nil)
+			return response, nil
+		}
+
+		written, merr := s.passwordRepository.ApplyBatch(current.ID, plan.writes, s.appConfig.Vault.PasswordHistoryLimit)
+		if merr != nil && merr.Code == 409 {
+			continue
+		}
+
+		if merr != nil {
+			return nil, merr
+		}
+
+		response.Revision = written.Revision
+		plan.resolve(written)
+		s.notifyBatch(current, plan, written)
+
+		return response, nil
+	}
+
+	return nil, models.NewError(409, "Conflict", "The vault kept changing while the batch was applied. Send it again.")
+}
+
+// checkBatch validates the operations of a batch and the folders and tags
+// they use.
+func (s *SyncServices) checkBatch(current *models.Vault, request *vault.BatchRequest) *models.Error {
+	var tagIDs []int
+	folderIDs := make(map[int]bool)
+
+	for i, op := range request.Operations {
+		if op.Action == consts.BatchActions.CREATE && op.ClientID == "" {
+			return models.NewError(422, "UnprocessableContent", fmt.Sprintf("Operation %d: client_id is required.", i))
+		}
+
+		if op.Action != consts.BatchActions.CREATE && op.ItemID == 0 && op.ClientID == "" {
+			return models.NewError(422, "UnprocessableContent", fmt.Sprintf("Operation %d: item_id or client_id is required.", i))
+		}
+
+		if op.Action == consts.BatchActions.DELETE {
+			continue
+		}
+
+		if op.Item == nil {
+			return models.NewError(422, "UnprocessableContent", fmt.Sprintf("Operation %d: item is required.", i))
+		}
+
+		merr := validateItem(op.Item)
+		if merr != nil {
+			merr.Description = fmt.Sprintf("Operation %d: %s", i, merr.Description)
+			return merr
+		}
+
+		tagIDs = append(tagIDs, op.Item.TagIDs...)
+		if op.Item.FolderID != nil {
+			folderIDs[*op.Item.FolderID] = true
+		}
+	}
+
+	for folderID := range folderIDs {
+		merr := s.vaultServices.checkFolder(&folderID, current.UserID)
+		if merr != nil {
+			return merr
+		}
+	}
+
+	return s.vaultServices.checkTags(tagIDs, current.UserID)
+}
+
+// planBatch resolves the operations of a batch against the items as they are
+// now and turns them into writes.
+func (s *SyncServices) planBatch(current *models.Vault, request *vault.BatchRequest) (*batchPlan, *models.Error) {
+	byID, byClientID, merr := s.loadBatchItems(current, request)
+	if merr != nil {
+		return nil, merr
+	}
+
+	plan := &batchPlan{
+		results: make([]*vault.BatchResult, len(request.Operations)),
+		items:   make([]*batchItem, len(request.Operations)),
+		copies:  make([]*batchItem, len(request.Operations)),
+	}
+
+	for i, op := range request.Operations {
+		result := &vault.BatchResult{
+			Index:    i,
+			Action:   op.Action,
+			ClientID: op.ClientID,
+			Status:   consts.BatchStatuses.APPLIED,
+		}
+		plan.results[i] = result
+
+		if op.Action == consts.BatchActions.CREATE {
+			if item, ok := byClientID[op.ClientID]; ok {
+				result.Status = consts.BatchStatuses.DUPLICATE
+				plan.items[i] = item
+				continue
+			}
+
+			pw := newCreatePassword(current.ID, op.Item)
+			pw.Favorite = op.Item.Favorite != nil && *op.Item.Favorite
+			pw.ClientID = &op.ClientID
+
+			plan.items[i] = plan.create(i, pw, op.Item.TagIDs)
+			byClientID[op.ClientID] = plan.items[i]
+			continue
+		}
+
+		item := byClientID[op.ClientID]
+		if op.ItemID != 0 {
+			item = byID[op.ItemID]
+		}
+		plan.items[i] = item
+
+		// Operations on items of the server have to say which revision they
+		// were made on, unless an earlier operation already changed the item.
+		based := item != nil && item.password != nil && !item.changed && !item.trashed
+		if based && op.BaseRevision == nil {
+			return nil, models.NewError(422, "UnprocessableContent", fmt.Sprintf("Operation %d: base_revision is required.", i))
+		}
+
+		stale := based && *op.BaseRevision != item.password.Revision
+
+		if op.Action == consts.BatchActions.DELETE {
+			if item == nil || item.trashed {
+				continue
+			}
+
+			if stale {
+				result.Status = consts.BatchStatuses.CONFLICT
+				result.Message = "The item changed on the server and was not deleted."
+				continue
+			}
+
+			plan.writes = append(plan.writes, item.write(i, consts.BatchActions.DELETE, based, nil, nil))
+			item.changed = true
+			item.trashed = true
+			continue
+		}
+
+		if item == nil || item.trashed || stale {
+			result.Status = consts.BatchStatuses.CONFLICT
+			result.Message = "The item changed on the server. The changes were saved to a copy."
+			pw, tagIDs := conflictCopy(current, op.Item, item)
+			plan.copies[i] = plan.create(i, pw, tagIDs)
+			continue
+		}
+
+		if item.password != nil && !item.changed {
+			merr = keepStoredUris(op.Item, item.password)
+			if merr != nil {
+				merr.Description = fmt.Sprintf("Operation %d: %s", i, merr.Description)
+				return nil, merr
+			}
+		}
+
+		pw := newCreatePassword(current.ID, op.Item)
+		pw.FolderID = item.folderID
+		if op.Item.FolderID != nil {
+			pw.FolderID = op.Item.FolderID
+		}
+
+		pw.Favorite = item.favorite
+		if op.Item.Favorite != nil {
+			pw.Favorite = *op.Item.Favorite
+		}
+
+		if item.password != nil {
+			merr = s.vaultServices.checkItemKeyChange(item.password, pw.ItemKey)
+			if merr != nil {
+				merr.Description = fmt.Sprintf("Operation %d: %s", i, merr.Description)
+				return nil, merr
+			}
+		}
+
+		plan.writes = append(plan.writes, item.write(i, consts.BatchActions.UPDATE, based, pw, op.Item.TagIDs))
+		item.changed = true
+		item.folderID = pw.FolderID
+		item.favorite = pw.Favorite
+		if pw.ItemKey != "" {
+			item.itemKey = pw.ItemKey
+		}
+	}
+
+	return plan, nil
+}
+
+// loadBatchItems reads the items of the vault the operations of a batch name,
+// by id and by client id.
+func (s *SyncServices) loadBatchItems(current *models.Vault, request *vault.BatchRequest) (map[int]*batchItem, map[string]*batchItem, *models.Error) {
+	var ids, clientIDs []string
+	for _, op := range request.Operations {
+		if op.ItemID != 0 {
+			ids = append(ids, strconv.Itoa(op.ItemID))
+		} else if op.ClientID != "" {
+			clientIDs = append(clientIDs, op.ClientID)
+		}
+	}
+
+	var passwords []*models.Password
+	if len(ids) > 0 {
+		found, merr := s.passwordRepository.GetPasswordsWithTrash(ids)
+		if merr != nil {
+			return nil, nil, merr
+		}
+
+		passwords = append(passwords, found...)
+	}
+
+	if len(clientIDs) > 0 {
+		found, merr := s.passwordRepository.GetPasswordsByClientIDs(strconv.Itoa(current.ID), clientIDs)
+		if merr != nil {
+			return nil, nil, merr
+		}
+
+		passwords = append(passwords, found...)
+	}
+
+	byID := make(map[int]*batchItem)
+	byClientID := make(map[string]*batchItem)
+
+	for _, pw := range passwords {
+		// Items of other vaults are treated like ids that do not exist, so a
+		// batch cannot tell whether an id is in use elsewhere.
+		if pw.VaultID != current.ID {
+			continue
+		}
+
+		item, ok := byID[pw.ID]
+		if !ok {
+			item = &batchItem{
+				password: pw,
+				ref:      -1,
+				trashed:  pw.DeletedAt != nil,
+				itemKey:  pw.ItemKey,
+				folderID: pw.FolderID,
+				favorite: pw.Favorite,
+			}
+			byID[pw.ID] = item
+		}
+
+		if pw.ClientID != nil {
+			byClientID[*pw.ClientID] = item
+		}
+	}
+
+	return byID, byClientID, nil
+}
+
+// create adds the write for a new item and returns the item.
+func (p *batchPlan) create(index int, pw *password.CreatePassword, tagIDs []int) *batchItem {
+	p.writes = append(p.writes, &password.BatchWrite{
+		Index:  index,
+		Action: consts.BatchActions.CREATE,
+		Item:   pw,
+		TagIDs: tagIDs,
+	})
+
+	return &batchItem{
+		ref:      index,
+		itemKey:  pw.ItemKey,
+		folderID: pw.FolderID,
+		favorite: pw.Favorite,
+	}
+}
+
+// write returns the write of an update or a delete of the item. Based writes
+// expect the item to still have the revision that was read.
+func (b *batchItem) write(index int, action string, based bool, pw *password.CreatePassword, tagIDs []int) *password.BatchWrite {
+	write := &password.BatchWrite{
+		Index:  index,
+		Action: action,
+		Item:   pw,
+		TagIDs: tagIDs,
+	}
+
+	if b.password == nil {
+		write.Ref = &b.ref
+	} else {
+		write.PasswordID = b.password.ID
+	}
+
+	if based {
+		write.Revision = &b.password.Revision
+	}
+
+	return write
+}
+
+// conflictCopy returns the new item, and its tags, the changes of an update
+// that lost a conflict are saved to. It has the item key, folder and tags of
+// the item it copies unless the update changes them.
+func conflictCopy(current *models.Vault, request *password.CreatePasswordRequest, item *batchItem) (*password.CreatePassword, []int) {
+	pw := newCreatePassword(current.ID, request)
+	pw.FolderID = request.FolderID
+	tagIDs := request.TagIDs
+
+	if item == nil {
+		return pw, tagIDs
+	}
+
+	if pw.ItemKey == "" {
+		pw.ItemKey = item.itemKey
+	}
+
+	if pw.FolderID == nil {
+		pw.FolderID = item.folderID
+	}
+
+	if item.password != nil {
+		pw.ConflictOf = &item.password.ID
+
+		if tagIDs == nil {
+			for _, tag := range item.password.Tags {
+				tagIDs = append(tagIDs, tag.TagID)
+			}
+		}
+	}
+
+	return pw, tagIDs
+}
+
+// resolve fills in the server's ids and revisions of the items in the results
+// once the writes have been applied.
+func (p *batchPlan) resolve(written *password.BatchWriteResult) {
+	ids := make(map[int]int)
+	revisions := make(map[int]int64)
+	if written != nil {
+		for _, item := range written.Items {
+			ids[item.Index] = item.ID
+			revisions[item.Index] = item.Revision
+		}
+	}
+
+	for i, result := range p.results {
+		if copy := p.copies[i]; copy != nil {
+			result.ConflictItemID = ids[copy.ref]
+		}
+
+		item := p.items[i]
+		if item == nil {
+			continue
+		}
+
+		if item.password != nil {
+			result.ItemID = item.password.ID
+			result.Revision = item.password.Revision
+		} else {
+			result.ItemID = ids[item.ref]
+			result.Revision = revisions[item.ref]
+		}
+
+		if revision, ok := revisions[i]; ok && p.copies[i] == nil {
+			result.Revision = revision
+		}
+	}
+}
+
+// notifyBatch tells the devices of the vault's owner about the items the
+// batch created, changed and deleted.
+func (s *SyncServices) notifyBatch(current *models.Vault, plan *batchPlan, written *password.BatchWriteResult) {
+	ids := make(map[int]int)
+	for _, item := range written.Items {
+		ids[item.Index] = item.ID
+	}
+
+	var created, updated, trashed []int
+	for _, write := range plan.writes {
+		switch write.Action {
+		case consts.BatchActions.CREATE:
+			created = append(created, ids[write.Index])
+		case consts.BatchActions.UPDATE:
+			updated = append(updated, ids[write.Index])
+		case consts.BatchActions.DELETE:
+			trashed = append(trashed, ids[write.Index])
+		}
+	}
+
+	s.vaultServices.notifyItems(consts.VaultEventTypes.ITEM_CREATED, current.ID, current.UserID, created...)
+	s.vaultServices.notifyItems(consts.VaultEventTypes.ITEM_UPDATED, current.ID, current.UserID, updated...)
+	s.vaultServices.notifyItems(consts.VaultEventTypes.ITEM_TRASHED, current.ID, current.UserID, trashed...)
+}
@@ -0,0 +1,69 @@
+package services
+
+import (
+	"strconv"
+	"time"
+
+	"github.com/safepass/server/internal/consts"
+	"github.com/safepass/server/internal/repositories"
+	"github.com/safepass/server/pkg/dtos/tag"
+	"github.com/safepass/server/pkg/models"
+)
+
+type TagServicesMethods interface {
+	GetTags(userID int) ([]*models.Tag, *models.Error)
+	CreateTag(userID int, request *tag.TagRequest) (*models.Tag, *models.Error)
+	UpdateTag(tagID int, userID int, request *tag.TagRequest) (*models.Tag, *models.Error)
+	DeleteTag(tagID int, userID int) *models.Error
+}
+
+type TagServices struct {
+	tagRepository *repositories.TagRepository
+	vaultServices *VaultServices
+
+	TagServicesMethods
+}
+
+func NewTagServices(tagRepository *repositories.TagRepository, vaultServices *VaultServices) *TagServices {
+	return &TagServices{
+		tagRepository: tagRepository,
+		vaultServices: vaultServices,
+	}
+}
+
+func (t *TagServices) GetTags(userID int) ([]*models.Tag, *models.Error) {
+	return t.tagRepository.GetTagsByUserID(strconv.Itoa(userID))
+}
+
+func (t *TagServices) CreateTag(userID int, request *tag.TagRequest) (*models.Tag, *models.Error) {
+	return t.tagRepository.CreateTag(&tag.CreateTag{
+		UserID:         userID,
+		EncryptedLabel: request.EncryptedLabel,
+		UpdatedAt:      time.Now(),
+	})
+}
+
+func (t *TagServices) UpdateTag(tagID int, userID int, request *tag.TagRequest) (*models.Tag, *models.Error) {
+	return t.tagRepository.UpdateTag(strconv.Itoa(tagID), &tag.CreateTag{
+		UserID:         userID,
+		EncryptedLabel: request.EncryptedLabel,
+		UpdatedAt:      time.Now(),
+	})
+}
+
+// DeleteTag deletes the tag and takes it off the passwords that have it,
+// which is notified as an update of each of them.
+func (t *TagServices) DeleteTag(tagID int, userID int) *models.Error {
+	passwords, merr := t.vaultServices.passwordRepository.GetPasswordsByTagID(strconv.Itoa(tagID))
+	if merr != nil {
+		return merr
+	}
+
+	_, merr = t.tagRepository.DeleteTag(strconv.Itoa(tagID), strconv.Itoa(userID))
+	if merr != nil {
+		return merr
+	}
+
+	t.vaultServices.notifyPasswords(consts.VaultEventTypes.ITEM_UPDATED, userID, passwords)
+	return nil
+}
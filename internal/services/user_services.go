@@ -1,8 +1,17 @@
 package services
 
 import (
-	"github.com/safepass/server/internal/consts"
+	"encoding/base64"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/safepass/server/internal/config"
+	"github.com/safepass/server/internal/logging"
 	"github.com/safepass/server/internal/repositories"
+	"github.com/safepass/server/pkg/crypto"
+	"github.com/safepass/server/pkg/dtos/audit"
 	"github.com/safepass/server/pkg/dtos/user"
 	"github.com/safepass/server/pkg/models"
 )
@@ -14,17 +23,35 @@ type UserServicesMethods interface {
 	CreateUser(*user.CreateUser) *models.IdentityResult
 	UpdateUser(id string, user *user.UpdateUserRequest) (*models.User, *models.IdentityResult)
 	DeleteUser(id string) (*models.User, *models.Error)
+	DeleteAccount(id string, request *user.DeleteAccountRequest) (*time.Time, *models.Error)
+	CancelAccountDeletion(user *models.User) *models.Error
+	PurgeScheduledDeletions() error
+	SetMasterPasswordHash(id string, masterPasswordHash string, iterationCount int) *models.Error
+
+	GetUserKey(id string) (*models.UserKey, *models.Error)
+	SetUserKey(id int, request *user.SetUserKeyRequest) (*models.UserKey, *models.Error)
+	GetPublicKeyByEmail(email string) (*user.PublicKey, *models.Error)
 }
 
 type UserServices struct {
-	userRepository *repositories.UserRepository
+	userRepository            *repositories.UserRepository
+	userKeyRepository         *repositories.UserKeyRepository
+	auditRepository           *repositories.AuditRepository
+	emailVerificationServices *EmailVerificationServices
+	logger                    *logging.Logger
+	appConfig                 *config.Config
 
 	UserServicesMethods
 }
 
-func NewUserServices(userRepository *repositories.UserRepository) *UserServices {
+func NewUserServices(userRepository *repositories.UserRepository, userKeyRepository *repositories.UserKeyRepository, auditRepository *repositories.AuditRepository, emailVerificationServices *EmailVerificationServices, logger *logging.Logger, config *config.Config) *UserServices {
 	return &UserServices{
-		userRepository: userRepository,
+		userRepository:            userRepository,
+		userKeyRepository:         userKeyRepository,
+		auditRepository:           auditRepository,
+		emailVerificationServices: emailVerificationServices,
+		logger:                    logger,
+		appConfig:                 config,
 	}
 }
 
@@ -62,13 +89,73 @@ func (u *UserServices) CreateUser(userRequest *user.CreateUser) *models.Identity
 }
 
 func (u *UserServices) UpdateUser(id string, userRequest *user.UpdateUserRequest) (*models.User, *models.IdentityResult) {
+	current, merr := u.userRepository.GetUserByID(id)
+	if merr != nil {
+		return nil, &models.IdentityResult{
+			Errors:    []*models.Error{merr},
+			Succeeded: false,
+			Message:   "Update error",
+		}
+	}
+
 	newUser := &user.UpdateUser{
-		Username: userRequest.Username,
-		Email:    userRequest.Email,
-		RoleId:   consts.Roles.USER,
+		Username:  userRequest.Username,
+		Name:      userRequest.Name,
+		Surname:   userRequest.Surname,
+		UpdatedAt: time.Now(),
+	}
+
+	// Changing the email changes the login identity, so the caller has to
+	// prove knowledge of the master password once more.
+	email := normalizeEmail(userRequest.Email)
+	if email != "" && email != current.Email {
+		if userRequest.MasterPasswordHash == "" {
+			description := "Master password hash is required to change the email"
+			return nil, &models.IdentityResult{
+				Errors:    []*models.Error{models.NewError(401, "Unauthorized", description)},
+				Succeeded: false,
+				Message:   "Update error",
+			}
+		}
+
+		merr = verifyMasterPasswordHash(current, userRequest.MasterPasswordHash)
+		if merr != nil {
+			return nil, &models.IdentityResult{
+				Errors:    []*models.Error{merr},
+				Succeeded: false,
+				Message:   "Update error",
+			}
+		}
+
+		newUser.Email = email
+	}
+
+	if newUser.Username == "" && newUser.Email == "" && newUser.Name == "" && newUser.Surname == "" {
+		return current, &models.IdentityResult{
+			Errors:    nil,
+			Succeeded: true,
+			Message:   "Nothing to update",
+		}
 	}
 
 	res, identityResult := u.userRepository.UpdateUser(id, newUser)
+	if !identityResult.Succeeded || newUser.Email == "" {
+		return res, identityResult
+	}
+
+	// The new address has not been proven yet.
+	merr = u.userRepository.SetVerifiedAt(res.ID, nil)
+	if merr != nil {
+		return nil, &models.IdentityResult{
+			Errors:    []*models.Error{merr},
+			Succeeded: false,
+			Message:   "Update error",
+		}
+	}
+
+	res.VerifiedAt = nil
+	u.emailVerificationServices.SendVerificationEmail(res)
+
 	return res, identityResult
 }
 
@@ -77,3 +164,166 @@ func (u *UserServices) DeleteUser(id string) (*models.User, *models.Error) {
 
 	return res, err
 }
+
+// DeleteAccount deletes the account after re-verifying the master password.
+// When a scheduled deletion is requested the time it will be carried out is
+// returned instead.
+func (u *UserServices) DeleteAccount(id string, request *user.DeleteAccountRequest) (*time.Time, *models.Error) {
+	current, merr := u.userRepository.GetUserByID(id)
+	if merr != nil {
+		return nil, merr
+	}
+
+	merr = verifyMasterPasswordHash(current, request.MasterPasswordHash)
+	if merr != nil {
+		return nil, merr
+	}
+
+	if request.Scheduled && u.appConfig.Account.DeletionGracePeriod <= 0 {
+		return nil, models.NewError(422, "UnprocessableContent", "Scheduled deletion is not enabled on this server.")
+	}
+
+	if request.Scheduled {
+		at := time.Now().UTC().Add(time.Hour * time.Duration(u.appConfig.Account.DeletionGracePeriod))
+
+		merr = u.userRepository.SetDeletionScheduledAt(current.ID, &at)
+		if merr != nil {
+			return nil, merr
+		}
+
+		u.auditRepository.CreateAuditLog(&audit.CreateAuditLog{
+			UserID:   current.ID,
+			Event:    "account_deletion_scheduled",
+			Metadata: map[string]interface{}{"scheduled_at": at},
+		})
+
+		return &at, nil
+	}
+
+	merr = u.userRepository.DeleteAccount(current.ID, "requested")
+	return nil, merr
+}
+
+// normalizeEmail returns the form email addresses are stored and looked up
+// in: trimmed and lowercased.
+func normalizeEmail(email string) string {
+	return strings.ToLower(strings.TrimSpace(email))
+}
+
+// CancelAccountDeletion clears a pending scheduled deletion.
+func (u *UserServices) CancelAccountDeletion(user *models.User) *models.Error {
+	if user.DeletionScheduledAt == nil {
+		return nil
+	}
+
+	merr := u.userRepository.SetDeletionScheduledAt(user.ID, nil)
+	if merr != nil {
+		return merr
+	}
+
+	user.DeletionScheduledAt = nil
+	u.auditRepository.CreateAuditLog(&audit.CreateAuditLog{
+		UserID: user.ID,
+		Event:  "account_deletion_cancelled",
+	})
+
+	return nil
+}
+
+// PurgeScheduledDeletions deletes every account whose grace period is over.
+func (u *UserServices) PurgeScheduledDeletions() error {
+	users, merr := u.userRepository.GetUsersScheduledForDeletion(time.Now())
+	if merr != nil {
+		return fmt.Errorf("%s", merr.Description)
+	}
+
+	failed := 0
+	for _, user := range users {
+		merr = u.userRepository.DeleteAccount(user.ID, "scheduled")
+		if merr != nil {
+			u.logger.Error(fmt.Sprintf("scheduled deletion of user %d failed: %s", user.ID, merr.Description))
+			failed++
+		}
+	}
+
+	if failed > 0 {
+		return fmt.Errorf("%d of %d scheduled deletions failed", failed, len(users))
+	}
+
+	return nil
+}
+
+// SetMasterPasswordHash stores the master password hash derived with the
+// given iteration count under a new salt.
+func (u *UserServices) SetMasterPasswordHash(id string, masterPasswordHash string, iterationCount int) *models.Error {
+	hash, salt, merr := hashMasterPasswordHash(masterPasswordHash, iterationCount)
+	if merr != nil {
+		return merr
+	}
+
+	newUser := &user.UpdateUser{
+		MasterPasswordHash: hash,
+		Salt:               salt,
+		IterationCount:     iterationCount,
+		UpdatedAt:          time.Now(),
+	}
+
+	_, identityResult := u.userRepository.UpdateUser(id, newUser)
+	if !identityResult.Succeeded {
+		return identityResult.Errors[0]
+	}
+
+	return nil
+}
+
+func (u *UserServices) GetUserKey(id string) (*models.UserKey, *models.Error) {
+	return u.userKeyRepository.GetUserKey(id)
+}
+
+// SetUserKey stores the user's key pair after checking that the public key
+// matches the declared algorithm. The fingerprint is computed server side so
+// every client shows the same value. A key pair, once set, is not replaced:
+// item keys shared with the user are wrapped with its public key.
+func (u *UserServices) SetUserKey(id int, request *user.SetUserKeyRequest) (*models.UserKey, *models.Error) {
+	der, err := base64.StdEncoding.DecodeString(request.PublicKey)
+	if err != nil {
+		description := "Public key is not valid Base64"
+		return nil, models.NewError(422, "UnprocessableContent", description)
+	}
+
+	algorithm, err := crypto.PublicKeyAlgorithm(der)
+	if err != nil {
+		description := "Public key is not valid: " + err.Error()
+		return nil, models.NewError(422, "UnprocessableContent", description)
+	}
+
+	if algorithm != request.Algorithm {
+		description := fmt.Sprintf("Public key is a %s key, not %s", algorithm, request.Algorithm)
+		return nil, models.NewError(422, "UnprocessableContent", description)
+	}
+
+	key := &user.SetUserKey{
+		UserID:              id,
+		PublicKey:           request.PublicKey,
+		EncryptedPrivateKey: request.EncryptedPrivateKey,
+		Algorithm:           algorithm,
+		Fingerprint:         crypto.Fingerprint(der),
+		UpdatedAt:           time.Now(),
+	}
+
+	return u.userKeyRepository.CreateUserKey(key)
+}
+
+func (u *UserServices) GetPublicKeyByEmail(email string) (*user.PublicKey, *models.Error) {
+	owner, merr := u.userRepository.GetUserByEmail(email)
+	if merr != nil {
+		return nil, merr
+	}
+
+	key, merr := u.userKeyRepository.GetUserKey(strconv.Itoa(owner.ID))
+	if merr != nil {
+		return nil, merr
+	}
+
+	return user.NewPublicKey(owner, key), nil
+}
@@ -1,40 +1,85 @@
 package services
 
 import (
+	"fmt"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/safepass/server/internal/config"
+	"github.com/safepass/server/internal/consts"
 	"github.com/safepass/server/internal/repositories"
 	"github.com/safepass/server/pkg/dtos/password"
 	"github.com/safepass/server/pkg/dtos/vault"
 	"github.com/safepass/server/pkg/models"
 )
 
+// DEFAULT_VAULT_NAME is the name of the vault created at registration.
+const DEFAULT_VAULT_NAME = "Personal"
+
 type VaultServicesMethods interface {
 	GetVaultByUserID(string) (*models.Vault, *models.Error)
 	CreateVault(int, string) *models.Error
 
-	GetPasswords(vaultID string) ([]*models.Password, *models.Error)
-	GetPassword(passwordID string, vaultID int) (*models.Password, *models.Error)
+	GetVaults(userID int) ([]*models.Vault, *models.Error)
+	GetUserVault(vaultID string, userID int) (*models.Vault, *models.Error)
+	AddVault(userID int, request *vault.CreateVaultRequest) (*models.Vault, *models.Error)
+	RenameVault(vaultID string, userID int, request *vault.UpdateVaultRequest) (*models.Vault, *models.Error)
+	DeleteVault(vaultID string, userID int) *models.Error
+
+	GetPasswords(vaultID string, filter *password.PasswordFilter) ([]*models.Password, *models.Error)
+	GetAccessiblePasswords(vault *models.Vault, filter *password.PasswordFilter) ([]*models.Password, *models.Error)
+	GetPassword(passwordID string, vault *models.Vault) (*models.Password, *models.Error)
 	CreatePassword(vaultID int, passwordRequest *password.CreatePasswordRequest) (*models.Password, *models.Error)
-	UpdatePassword(passwordID int, vaultID int, passwordRequest *password.CreatePasswordRequest) (*models.Password, *models.Error)
+	ImportPasswords(vault *models.Vault, request *password.ImportRequest) (*password.ImportResponse, *models.Error)
+	UpdatePassword(passwordID int, vault *models.Vault, passwordRequest *password.CreatePasswordRequest) (*models.Password, *models.Error)
 	DeletePassword(id int, vaultID int) (*models.Password, *models.Error)
+	GetTrash(vault *models.Vault) ([]*models.Password, *models.Error)
+	RestoreTrashedPassword(passwordID int, vault *models.Vault) (*models.Password, *models.Error)
+	DeletePasswordPermanently(passwordID int, vault *models.Vault) *models.Error
+	PurgeTrash() error
+	MovePassword(passwordID int, vault *models.Vault, folderID *int) (*models.Password, *models.Error)
+	MovePasswords(vault *models.Vault, passwordIDs []int, folderID *int) ([]*models.Password, *models.Error)
+	TagPasswords(vault *models.Vault, request *password.BulkTagRequest) ([]*models.Password, *models.Error)
+	UntagPasswords(vault *models.Vault, request *password.BulkTagRequest) ([]*models.Password, *models.Error)
+	SetFavorite(passwordID int, vault *models.Vault, favorite bool) (*models.Password, *models.Error)
+	GetPasswordHistory(passwordID int, vault *models.Vault) ([]*models.PasswordHistory, *models.Error)
+	RestorePassword(passwordID int, historyID int, vault *models.Vault) (*models.Password, *models.Error)
+
+	GetShares(passwordID int, vault *models.Vault) ([]*models.PasswordShare, *models.Error)
+	SharePassword(passwordID int, vault *models.Vault, shareRequest *password.CreateShareRequest) (*models.PasswordShare, *models.Error)
+	RevokeShare(passwordID int, shareID int, vault *models.Vault) *models.Error
+
+	CanWrite(vault *models.Vault) *models.Error
 }
 
 type VaultServices struct {
-	vaultRepository    *repositories.VaultRepository
-	passwordRepository *repositories.PasswordRepository
-	appConfig          *config.Config
+	vaultRepository           *repositories.VaultRepository
+	passwordRepository        *repositories.PasswordRepository
+	passwordShareRepository   *repositories.PasswordShareRepository
+	folderRepository          *repositories.FolderRepository
+	tagRepository             *repositories.TagRepository
+	passwordHistoryRepository *repositories.PasswordHistoryRepository
+	userServices              *UserServices
+	policyServices            *PolicyServices
+	notificationServices      *NotificationServices
+	appConfig                 *config.Config
 
 	VaultServicesMethods
 }
 
-func NewVaultServices(vaultRepository *repositories.VaultRepository, passwordRepository *repositories.PasswordRepository, config *config.Config) *VaultServices {
+func NewVaultServices(vaultRepository *repositories.VaultRepository, passwordRepository *repositories.PasswordRepository, passwordShareRepository *repositories.PasswordShareRepository, folderRepository *repositories.FolderRepository, tagRepository *repositories.TagRepository, passwordHistoryRepository *repositories.PasswordHistoryRepository, userServices *UserServices, policyServices *PolicyServices, notificationServices *NotificationServices, config *config.Config) *VaultServices {
 	return &VaultServices{
-		vaultRepository:    vaultRepository,
-		passwordRepository: passwordRepository,
-		appConfig:          config,
+		vaultRepository:           vaultRepository,
+		passwordRepository:        passwordRepository,
+		passwordShareRepository:   passwordShareRepository,
+		folderRepository:          folderRepository,
+		tagRepository:             tagRepository,
+		passwordHistoryRepository: passwordHistoryRepository,
+		userServices:              userServices,
+		policyServices:            policyServices,
+		notificationServices:      notificationServices,
+		appConfig:                 config,
 	}
 }
 
@@ -44,10 +89,16 @@ func (v *VaultServices) GetVaultByUserID(userID string) (*models.Vault, *models.
 	return vault, err
 }
 
+// CreateVault creates the user's default vault.
 func (v *VaultServices) CreateVault(userID int, protectedSymmetricKey string) *models.Error {
+	_, merr := v.createVault(userID, DEFAULT_VAULT_NAME, protectedSymmetricKey, true)
+	return merr
+}
+
+func (v *VaultServices) createVault(userID int, name string, protectedSymmetricKey string, isDefault bool) (*models.Vault, *models.Error) {
 	parts := strings.Split(protectedSymmetricKey, ":")
 	if len(parts) != 2 {
-		return models.NewError(422, "Unprocessable Content", "Protected symmetric key is not valid.")
+		return nil, models.NewError(422, "Unprocessable Content", "Protected symmetric key is not valid.")
 	}
 
 	mac := parts[0]
@@ -55,62 +106,863 @@ func (v *VaultServices) CreateVault(userID int, protectedSymmetricKey string) *m
 
 	vault := &vault.CreateVault{
 		UserID:                userID,
+		Name:                  name,
+		IsDefault:             isDefault,
 		ProtectedSymmetricKey: symmetricKey,
 		Mac:                   mac,
 		Algorithm:             "AESCBCPKCS5Padding",
 	}
 
-	err := v.vaultRepository.CreateVault(vault)
-	return err
+	return v.vaultRepository.CreateVault(vault)
+}
+
+func (v *VaultServices) GetVaults(userID int) ([]*models.Vault, *models.Error) {
+	return v.vaultRepository.GetVaultsByUserId(strconv.Itoa(userID))
+}
+
+// GetUserVault returns one of the user's vaults. "@me" refers to the default
+// vault; vaults of other users are reported as not found.
+func (v *VaultServices) GetUserVault(vaultID string, userID int) (*models.Vault, *models.Error) {
+	if vaultID == "@me" {
+		return v.vaultRepository.GetVaultByUserId(strconv.Itoa(userID))
+	}
+
+	if _, err := strconv.Atoi(vaultID); err != nil {
+		return nil, models.NewError(400, "BadRequest", "Vault id is not valid.")
+	}
+
+	vault, merr := v.vaultRepository.GetVault(vaultID)
+	if merr != nil {
+		return nil, merr
+	}
+
+	if vault.UserID != userID {
+		return nil, models.NewError(404, "NotFound", "No vault found with id="+vaultID)
+	}
+
+	return vault, nil
 }
 
-func (v *VaultServices) GetPasswords(vaultID string) ([]*models.Password, *models.Error) {
-	passwords, err := v.passwordRepository.GetPasswordsByVaultID(vaultID)
+// AddVault creates an additional vault. The client generates a new vault key
+// and sends it wrapped with the master key, as at registration.
+func (v *VaultServices) AddVault(userID int, request *vault.CreateVaultRequest) (*models.Vault, *models.Error) {
+	newVault, merr := v.createVault(userID, request.Name, request.ProtectedSymmetricKey, false)
+	if merr != nil {
+		return nil, merr
+	}
+
+	v.notifyVault(consts.VaultEventTypes.VAULT_CREATED, newVault)
+	return newVault, nil
+}
+
+func (v *VaultServices) RenameVault(vaultID string, userID int, request *vault.UpdateVaultRequest) (*models.Vault, *models.Error) {
+	current, merr := v.GetUserVault(vaultID, userID)
+	if merr != nil {
+		return nil, merr
+	}
+
+	renamed, merr := v.vaultRepository.RenameVault(strconv.Itoa(current.ID), &vault.UpdateVault{
+		Name:      request.Name,
+		UpdatedAt: time.Now(),
+	})
+	if merr != nil {
+		return nil, merr
+	}
+
+	v.notifyVault(consts.VaultEventTypes.VAULT_UPDATED, current)
+	return renamed, nil
+}
+
+// DeleteVault deletes a vault with its passwords. The default vault can only
+// be removed by deleting the account.
+func (v *VaultServices) DeleteVault(vaultID string, userID int) *models.Error {
+	current, merr := v.GetUserVault(vaultID, userID)
+	if merr != nil {
+		return merr
+	}
+
+	if current.IsDefault {
+		return models.NewError(409, "Conflict", "The default vault cannot be deleted.")
+	}
+
+	merr = v.vaultRepository.DeleteVault(current.ID, userID)
+	if merr != nil {
+		return merr
+	}
+
+	v.notifyVault(consts.VaultEventTypes.VAULT_DELETED, current)
+	return nil
+}
+
+func (v *VaultServices) GetPasswords(vaultID string, filter *password.PasswordFilter) ([]*models.Password, *models.Error) {
+	passwords, err := v.passwordRepository.GetPasswordsByVaultID(vaultID, filter)
 
 	return passwords, err
 }
 
-func (v *VaultServices) GetPassword(passwordID string, vaultID int) (*models.Password, *models.Error) {
+// GetAccessiblePasswords returns the vault's own passwords followed by the
+// passwords other users have shared with the vault's owner.
+func (v *VaultServices) GetAccessiblePasswords(vault *models.Vault, filter *password.PasswordFilter) ([]*models.Password, *models.Error) {
+	passwords, merr := v.passwordRepository.GetPasswordsByVaultID(strconv.Itoa(vault.ID), filter)
+	if merr != nil {
+		return nil, merr
+	}
+
+	shares, merr := v.passwordShareRepository.GetSharesByRecipientID(strconv.Itoa(vault.UserID))
+	if merr != nil {
+		return nil, merr
+	}
+
+	for _, share := range shares {
+		if share.Password == nil || share.Password.DeletedAt != nil {
+			continue
+		}
+
+		// Match against the shared view, which has no folder, favorite or tags.
+		pw := sharedPassword(share)
+		if !filter.Matches(pw) {
+			continue
+		}
+
+		passwords = append(passwords, pw)
+	}
+
+	return passwords, nil
+}
+
+func (v *VaultServices) GetPassword(passwordID string, vault *models.Vault) (*models.Password, *models.Error) {
 	password, err := v.passwordRepository.GetPassword(passwordID)
 	if err != nil {
 		return nil, err
 	}
 
-	if password.VaultID != vaultID {
-		return nil, models.NewError(401, "Unauthorized", "")
+	share, err := v.authorizePassword(password, vault, false)
+	if err != nil {
+		return nil, err
+	}
+
+	if share != nil {
+		share.Password = password
+		return sharedPassword(share), nil
 	}
 
 	return password, nil
 }
 
 func (v *VaultServices) CreatePassword(vaultID int, passwordRequest *password.CreatePasswordRequest) (*models.Password, *models.Error) {
-	pw := &password.CreatePassword{
-		VaultID:           vaultID,
-		AppName:           passwordRequest.AppName,
-		Uri:               passwordRequest.Uri,
-		Username:          passwordRequest.Username,
-		EncryptedPassword: passwordRequest.EncryptedPassword,
+	merr := validateItem(passwordRequest)
+	if merr != nil {
+		return nil, merr
+	}
+
+	if passwordRequest.FolderID != nil || len(passwordRequest.TagIDs) > 0 {
+		vault, merr := v.vaultRepository.GetVault(strconv.Itoa(vaultID))
+		if merr != nil {
+			return nil, merr
+		}
+
+		merr = v.checkFolder(passwordRequest.FolderID, vault.UserID)
+		if merr != nil {
+			return nil, merr
+		}
+
+		merr = v.checkTags(passwordRequest.TagIDs, vault.UserID)
+		if merr != nil {
+			return nil, merr
+		}
 	}
 
+	pw := newCreatePassword(vaultID, passwordRequest)
+	pw.Favorite = passwordRequest.Favorite != nil && *passwordRequest.Favorite
+
 	newPw, merr := v.passwordRepository.CreatePassword(pw)
-	return newPw, merr
+	if merr != nil {
+		return nil, merr
+	}
+
+	newPw, merr = v.setTags(newPw, passwordRequest.TagIDs)
+	if merr != nil {
+		return nil, merr
+	}
+
+	v.notifyItems(consts.VaultEventTypes.ITEM_CREATED, vaultID, 0, newPw.ID)
+	return newPw, nil
+}
+
+// ImportPasswords creates the folders and items of an import, encrypted by the
+// client, all together: when one of them cannot be created none is.
+func (v *VaultServices) ImportPasswords(vault *models.Vault, request *password.ImportRequest) (*password.ImportResponse, *models.Error) {
+	var tagIDs []int
+	folderIDs := make(map[int]bool)
+	writes := make([]*password.ImportWrite, 0, len(request.Items))
+
+	for i, item := range request.Items {
+		merr := validateItem(&item.CreatePasswordRequest)
+		if merr != nil {
+			merr.Description = fmt.Sprintf("Item %d: %s", i, merr.Description)
+			return nil, merr
+		}
+
+		if item.FolderIndex != nil {
+			if item.FolderID != nil {
+				return nil, models.NewError(422, "UnprocessableContent", fmt.Sprintf("Item %d: folder_id and folder_index cannot both be set.", i))
+			}
+
+			if *item.FolderIndex >= len(request.Folders) {
+				return nil, models.NewError(422, "UnprocessableContent", fmt.Sprintf("Item %d: folder_index %d is out of range.", i, *item.FolderIndex))
+			}
+		}
+
+		if item.FolderID != nil {
+			folderIDs[*item.FolderID] = true
+		}
+
+		tagIDs = append(tagIDs, item.TagIDs...)
+
+		pw := newCreatePassword(vault.ID, &item.CreatePasswordRequest)
+		pw.Favorite = item.Favorite != nil && *item.Favorite
+
+		writes = append(writes, &password.ImportWrite{Item: pw, FolderIndex: item.FolderIndex, TagIDs: item.TagIDs})
+	}
+
+	for folderID := range folderIDs {
+		merr := v.checkFolder(&folderID, vault.UserID)
+		if merr != nil {
+			return nil, merr
+		}
+	}
+
+	merr := v.checkTags(tagIDs, vault.UserID)
+	if merr != nil {
+		return nil, merr
+	}
+
+	folderNames := make([]string, 0, len(request.Folders))
+	for _, folder := range request.Folders {
+		folderNames = append(folderNames, folder.EncryptedName)
+	}
+
+	result, merr := v.passwordRepository.ImportPasswords(vault.UserID, vault.ID, folderNames, writes)
+	if merr != nil {
+		return nil, merr
+	}
+
+	v.notifyItems(consts.VaultEventTypes.ITEM_CREATED, vault.ID, vault.UserID, result.PasswordIDs...)
+	return result, nil
+}
+
+// UpdatePassword updates a password of the vault, or a password shared with
+// the vault's owner with write permission. Recipients cannot replace the
+// item key, since the owner's copy is wrapped with the owner's vault key.
+func (v *VaultServices) UpdatePassword(passwordID int, vault *models.Vault, passwordRequest *password.CreatePasswordRequest) (*models.Password, *models.Error) {
+	current, merr := v.passwordRepository.GetPassword(strconv.Itoa(passwordID))
+	if merr != nil {
+		return nil, merr
+	}
+
+	share, merr := v.authorizePassword(current, vault, true)
+	if merr != nil {
+		return nil, merr
+	}
+
+	if passwordRequest.Revision != nil && *passwordRequest.Revision != current.Revision {
+		return nil, v.conflict(passwordID, vault, *passwordRequest.Revision)
+	}
+
+	merr = validateItem(passwordRequest)
+	if merr != nil {
+		return nil, merr
+	}
+
+	merr = keepStoredUris(passwordRequest, current)
+	if merr != nil {
+		return nil, merr
+	}
+
+	pw := newCreatePassword(current.VaultID, passwordRequest)
+
+	// Folders are personal, so recipients of a share cannot file the owner's
+	// item. Without a folder in the request the item stays where it is.
+	if share != nil || passwordRequest.FolderID == nil {
+		pw.FolderID = current.FolderID
+	} else {
+		merr = v.checkFolder(passwordRequest.FolderID, vault.UserID)
+		if merr != nil {
+			return nil, merr
+		}
+	}
+
+	// Favorites and tags are the owner's as well. Without them in the request
+	// they are kept.
+	pw.Favorite = current.Favorite
+	if share == nil && passwordRequest.Favorite != nil {
+		pw.Favorite = *passwordRequest.Favorite
+	}
+
+	var tagIDs []int
+	if share == nil && passwordRequest.TagIDs != nil {
+		tagIDs = passwordRequest.TagIDs
+		merr = v.checkTags(tagIDs, vault.UserID)
+		if merr != nil {
+			return nil, merr
+		}
+	}
+
+	if share != nil {
+		pw.ItemKey = ""
+	}
+
+	merr = v.checkItemKeyChange(current, pw.ItemKey)
+	if merr != nil {
+		return nil, merr
+	}
+
+	newPw, merr := v.passwordRepository.UpdatePassword(strconv.Itoa(passwordID), pw, passwordRequest.Revision, v.appConfig.Vault.PasswordHistoryLimit)
+	if merr != nil && merr.Code == 409 {
+		return nil, v.conflict(passwordID, vault, *passwordRequest.Revision)
+	}
+
+	if merr != nil {
+		return nil, merr
+	}
+
+	newPw.Attachments = current.Attachments
+
+	if share != nil {
+		v.notifyItems(consts.VaultEventTypes.ITEM_UPDATED, current.VaultID, share.OwnerID, passwordID)
+
+		share.Password = newPw
+		return sharedPassword(share), nil
+	}
+
+	v.notifyItems(consts.VaultEventTypes.ITEM_UPDATED, current.VaultID, vault.UserID, passwordID)
+
+	if tagIDs == nil {
+		newPw.Tags = current.Tags
+		return newPw, nil
+	}
+
+	return v.setTags(newPw, tagIDs)
+}
+
+// DeletePassword moves a password of the vault to the trash, from where it
+// can be restored until it is purged.
+func (v *VaultServices) DeletePassword(id int, vaultID int) (*models.Password, *models.Error) {
+	newPw, merr := v.passwordRepository.TrashPassword(strconv.Itoa(id), strconv.Itoa(vaultID))
+	if merr != nil {
+		return nil, merr
+	}
+
+	v.notifyItems(consts.VaultEventTypes.ITEM_TRASHED, vaultID, 0, id)
+	return newPw, nil
+}
+
+func (v *VaultServices) GetTrash(vault *models.Vault) ([]*models.Password, *models.Error) {
+	return v.passwordRepository.GetTrashedPasswords(strconv.Itoa(vault.ID))
+}
+
+// RestoreTrashedPassword moves a password of the vault out of the trash.
+func (v *VaultServices) RestoreTrashedPassword(passwordID int, vault *models.Vault) (*models.Password, *models.Error) {
+	newPw, merr := v.passwordRepository.UntrashPassword(strconv.Itoa(passwordID), strconv.Itoa(vault.ID))
+	if merr != nil {
+		return nil, merr
+	}
+
+	v.notifyItems(consts.VaultEventTypes.ITEM_RESTORED, vault.ID, vault.UserID, passwordID)
+	return newPw, nil
+}
+
+// DeletePasswordPermanently deletes a password that is in the vault's trash.
+func (v *VaultServices) DeletePasswordPermanently(passwordID int, vault *models.Vault) *models.Error {
+	_, merr := v.passwordRepository.GetTrashedPassword(strconv.Itoa(passwordID), strconv.Itoa(vault.ID))
+	if merr != nil {
+		return merr
+	}
+
+	_, merr = v.passwordRepository.DeletePassword(strconv.Itoa(passwordID), strconv.Itoa(vault.ID))
+	if merr != nil {
+		return merr
+	}
+
+	v.notifyItems(consts.VaultEventTypes.ITEM_DELETED, vault.ID, vault.UserID, passwordID)
+	return nil
+}
+
+// PurgeTrash deletes the passwords that have been in the trash for longer than
+// the retention period.
+func (v *VaultServices) PurgeTrash() error {
+	days := v.appConfig.Vault.TrashRetentionDays
+	if days <= 0 {
+		return nil
+	}
+
+	merr := v.passwordRepository.PurgeTrashedPasswords(time.Now().AddDate(0, 0, -days))
+	if merr != nil {
+		return fmt.Errorf("%s", merr.Description)
+	}
+
+	return nil
+}
+
+// MovePassword moves one of the vault's passwords into a folder, or out of
+// its folder when folderID is nil.
+func (v *VaultServices) MovePassword(passwordID int, vault *models.Vault, folderID *int) (*models.Password, *models.Error) {
+	passwords, merr := v.MovePasswords(vault, []int{passwordID}, folderID)
+	if merr != nil {
+		return nil, merr
+	}
+
+	if len(passwords) == 0 {
+		return nil, models.NewError(404, "NotFound", "Password not found with id="+strconv.Itoa(passwordID))
+	}
+
+	return passwords[0], nil
+}
+
+// MovePasswords moves the vault's passwords with the given ids into a folder.
+// Ids of passwords outside the vault are skipped; the moved passwords are
+// returned.
+func (v *VaultServices) MovePasswords(vault *models.Vault, passwordIDs []int, folderID *int) ([]*models.Password, *models.Error) {
+	merr := v.checkFolder(folderID, vault.UserID)
+	if merr != nil {
+		return nil, merr
+	}
+
+	ids := make([]string, 0, len(passwordIDs))
+	for _, id := range passwordIDs {
+		ids = append(ids, strconv.Itoa(id))
+	}
+
+	passwords, merr := v.passwordRepository.MovePasswords(strconv.Itoa(vault.ID), ids, folderID)
+	if merr != nil {
+		return nil, merr
+	}
+
+	v.notifyItems(consts.VaultEventTypes.ITEM_UPDATED, vault.ID, vault.UserID, passwordIDsOf(passwords)...)
+	return passwords, nil
+}
+
+// conflict is the error for an update based on an outdated revision of a
+// password. It carries the password as the vault sees it now.
+func (v *VaultServices) conflict(passwordID int, vault *models.Vault, revision int64) *models.Error {
+	current, merr := v.GetPassword(strconv.Itoa(passwordID), vault)
+	if merr != nil {
+		return merr
+	}
+
+	description := "The item has changed since revision " + strconv.FormatInt(revision, 10) + "."
+	return models.NewConflictError(description, current)
+}
+
+// GetPasswordHistory returns the previous passwords of a password the vault
+// can read, newest first.
+func (v *VaultServices) GetPasswordHistory(passwordID int, vault *models.Vault) ([]*models.PasswordHistory, *models.Error) {
+	pw, merr := v.passwordRepository.GetPassword(strconv.Itoa(passwordID))
+	if merr != nil {
+		return nil, merr
+	}
+
+	_, merr = v.authorizePassword(pw, vault, false)
+	if merr != nil {
+		return nil, merr
+	}
+
+	return v.passwordHistoryRepository.GetHistory(strconv.Itoa(passwordID))
+}
+
+// RestorePassword makes a previous password the current one again. The
+// replaced password goes into the history in its place.
+func (v *VaultServices) RestorePassword(passwordID int, historyID int, vault *models.Vault) (*models.Password, *models.Error) {
+	current, merr := v.passwordRepository.GetPassword(strconv.Itoa(passwordID))
+	if merr != nil {
+		return nil, merr
+	}
+
+	share, merr := v.authorizePassword(current, vault, true)
+	if merr != nil {
+		return nil, merr
+	}
+
+	newPw, merr := v.passwordRepository.RestorePasswordHistory(passwordID, current.VaultID, historyID, v.appConfig.Vault.PasswordHistoryLimit)
+	if merr != nil {
+		return nil, merr
+	}
+
+	newPw.Tags = current.Tags
+	newPw.Attachments = current.Attachments
+
+	if share != nil {
+		v.notifyItems(consts.VaultEventTypes.ITEM_UPDATED, current.VaultID, share.OwnerID, passwordID)
+
+		share.Password = newPw
+		return sharedPassword(share), nil
+	}
+
+	v.notifyItems(consts.VaultEventTypes.ITEM_UPDATED, current.VaultID, vault.UserID, passwordID)
+	return newPw, nil
+}
+
+// TagPasswords puts the tags on the vault's passwords with the given ids.
+// Ids of passwords outside the vault are skipped; the tagged passwords are
+// returned.
+func (v *VaultServices) TagPasswords(vault *models.Vault, request *password.BulkTagRequest) ([]*models.Password, *models.Error) {
+	merr := v.checkTags(request.TagIDs, vault.UserID)
+	if merr != nil {
+		return nil, merr
+	}
+
+	passwords, merr := v.getVaultPasswords(vault, request.PasswordIDs)
+	if merr != nil || len(passwords) == 0 {
+		return passwords, merr
+	}
+
+	tags := make([]*models.PasswordTag, 0, len(passwords)*len(request.TagIDs))
+	for _, pw := range passwords {
+		for _, tagID := range request.TagIDs {
+			tags = append(tags, &models.PasswordTag{PasswordID: pw.ID, TagID: tagID})
+		}
+	}
+
+	merr = v.passwordRepository.AddTags(tags)
+	if merr != nil {
+		return nil, merr
+	}
+
+	v.notifyItems(consts.VaultEventTypes.ITEM_UPDATED, vault.ID, vault.UserID, passwordIDsOf(passwords)...)
+	return v.getVaultPasswords(vault, request.PasswordIDs)
+}
+
+// UntagPasswords takes the tags off the vault's passwords with the given ids.
+func (v *VaultServices) UntagPasswords(vault *models.Vault, request *password.BulkTagRequest) ([]*models.Password, *models.Error) {
+	passwords, merr := v.getVaultPasswords(vault, request.PasswordIDs)
+	if merr != nil || len(passwords) == 0 {
+		return passwords, merr
+	}
+
+	passwordIDs := make([]string, 0, len(passwords))
+	for _, pw := range passwords {
+		passwordIDs = append(passwordIDs, strconv.Itoa(pw.ID))
+	}
+
+	tagIDs := make([]string, 0, len(request.TagIDs))
+	for _, tagID := range request.TagIDs {
+		tagIDs = append(tagIDs, strconv.Itoa(tagID))
+	}
+
+	merr = v.passwordRepository.RemoveTags(passwordIDs, tagIDs)
+	if merr != nil {
+		return nil, merr
+	}
+
+	v.notifyItems(consts.VaultEventTypes.ITEM_UPDATED, vault.ID, vault.UserID, passwordIDsOf(passwords)...)
+	return v.getVaultPasswords(vault, request.PasswordIDs)
+}
+
+// SetFavorite marks one of the vault's passwords as a favorite or unmarks it.
+func (v *VaultServices) SetFavorite(passwordID int, vault *models.Vault, favorite bool) (*models.Password, *models.Error) {
+	newPw, merr := v.passwordRepository.SetFavorite(strconv.Itoa(passwordID), strconv.Itoa(vault.ID), favorite)
+	if merr != nil {
+		return nil, merr
+	}
+
+	v.notifyItems(consts.VaultEventTypes.ITEM_UPDATED, vault.ID, vault.UserID, passwordID)
+	return newPw, nil
+}
+
+func (v *VaultServices) getVaultPasswords(vault *models.Vault, passwordIDs []int) ([]*models.Password, *models.Error) {
+	ids := make([]string, 0, len(passwordIDs))
+	for _, id := range passwordIDs {
+		ids = append(ids, strconv.Itoa(id))
+	}
+
+	return v.passwordRepository.GetPasswordsByIDs(strconv.Itoa(vault.ID), ids)
+}
+
+// setTags replaces the tags of a password with the given ones.
+func (v *VaultServices) setTags(pw *models.Password, tagIDs []int) (*models.Password, *models.Error) {
+	merr := v.passwordRepository.SetTags(pw.ID, tagIDs)
+	if merr != nil {
+		return nil, merr
+	}
+
+	pw.Tags = make([]*models.PasswordTag, 0, len(tagIDs))
+	for _, tagID := range tagIDs {
+		pw.Tags = append(pw.Tags, &models.PasswordTag{TagID: tagID})
+	}
+
+	return pw, nil
+}
+
+// checkTags checks that all the tags belong to the user.
+func (v *VaultServices) checkTags(tagIDs []int, userID int) *models.Error {
+	if len(tagIDs) == 0 {
+		return nil
+	}
+
+	ids := make([]string, 0, len(tagIDs))
+	for _, id := range tagIDs {
+		ids = append(ids, strconv.Itoa(id))
+	}
+
+	tags, merr := v.tagRepository.GetTagsByIDs(ids)
+	if merr != nil {
+		return merr
+	}
+
+	owned := make(map[int]bool, len(tags))
+	for _, tag := range tags {
+		if tag.UserID == userID {
+			owned[tag.ID] = true
+		}
+	}
+
+	for _, id := range tagIDs {
+		if !owned[id] {
+			return models.NewError(404, "NotFound", "Tag not found with id="+strconv.Itoa(id))
+		}
+	}
+
+	return nil
+}
+
+// checkFolder checks that the folder, when set, belongs to the user.
+func (v *VaultServices) checkFolder(folderID *int, userID int) *models.Error {
+	if folderID == nil {
+		return nil
+	}
+
+	folder, merr := v.folderRepository.GetFolder(strconv.Itoa(*folderID))
+	if merr != nil {
+		return merr
+	}
+
+	if folder.UserID != userID {
+		return models.NewError(404, "NotFound", "Folder not found with id="+strconv.Itoa(*folderID))
+	}
+
+	return nil
 }
 
-func (v *VaultServices) UpdatePassword(passwordID int, vaultID int, passwordRequest *password.CreatePasswordRequest) (*models.Password, *models.Error) {
-	pw := &password.CreatePassword{
+// CanWrite reports whether the vault's owner is allowed to modify it under the
+// configured account policies.
+func (v *VaultServices) CanWrite(vault *models.Vault) *models.Error {
+	if v.appConfig.Account.RequireVerifiedEmail && vault.User.VerifiedAt == nil {
+		return models.NewError(403, "Forbidden", "Email address must be verified before the vault can be modified.")
+	}
+
+	return nil
+}
+
+// authorizePassword checks that the vault may access the password. Passwords
+// of the vault itself are always accessible; otherwise the password has to be
+// shared with the vault's owner, with write permission when write is set.
+// The share is returned for shared passwords.
+func (v *VaultServices) authorizePassword(pw *models.Password, vault *models.Vault, write bool) (*models.PasswordShare, *models.Error) {
+	if pw.VaultID == vault.ID {
+		return nil, nil
+	}
+
+	share, merr := v.passwordShareRepository.GetShareByRecipient(strconv.Itoa(pw.ID), strconv.Itoa(vault.UserID))
+	if merr != nil {
+		if merr.Code == 404 {
+			return nil, models.NewError(401, "Unauthorized", "")
+		}
+
+		return nil, merr
+	}
+
+	if write && share.Permission != consts.SharePermissions.WRITE {
+		return nil, models.NewError(403, "Forbidden", "This password is shared with you read-only.")
+	}
+
+	return share, nil
+}
+
+func (v *VaultServices) GetShares(passwordID int, vault *models.Vault) ([]*models.PasswordShare, *models.Error) {
+	_, merr := v.getOwnPassword(passwordID, vault)
+	if merr != nil {
+		return nil, merr
+	}
+
+	return v.passwordShareRepository.GetSharesByPasswordID(strconv.Itoa(passwordID))
+}
+
+// checkItemKeyChange refuses a new item key for a shared password. Shares
+// hold the item key wrapped for their recipient, so they have to be revoked
+// and shared again under the new key.
+func (v *VaultServices) checkItemKeyChange(current *models.Password, itemKey string) *models.Error {
+	if itemKey == "" || itemKey == current.ItemKey {
+		return nil
+	}
+
+	shares, merr := v.passwordShareRepository.GetSharesByPasswordID(strconv.Itoa(current.ID))
+	if merr != nil {
+		return merr
+	}
+
+	if len(shares) > 0 {
+		description := fmt.Sprintf("Password with id=%d is shared. Revoke its shares before changing the item key.", current.ID)
+		return models.NewError(409, "Conflict", description)
+	}
+
+	return nil
+}
+
+// SharePassword shares one of the vault's passwords with another user. The
+// client wraps the item key with the recipient's public key; the server only
+// checks that the recipient has a key pair and that it is the expected one.
+func (v *VaultServices) SharePassword(passwordID int, vault *models.Vault, shareRequest *password.CreateShareRequest) (*models.PasswordShare, *models.Error) {
+	pw, merr := v.getOwnPassword(passwordID, vault)
+	if merr != nil {
+		return nil, merr
+	}
+
+	if pw.ItemKey == "" {
+		return nil, models.NewError(422, "UnprocessableContent", "Only passwords with an item key can be shared.")
+	}
+
+	recipient, merr := v.userServices.GetPublicKeyByEmail(shareRequest.RecipientEmail)
+	if merr != nil {
+		return nil, merr
+	}
+
+	if recipient.UserID == vault.UserID {
+		return nil, models.NewError(400, "BadRequest", "You cannot share a password with yourself.")
+	}
+
+	if shareRequest.RecipientFingerprint != "" && shareRequest.RecipientFingerprint != recipient.Fingerprint {
+		return nil, models.NewError(409, "Conflict", "The recipient's public key has changed.")
+	}
+
+	merr = v.policyServices.CheckSharing(vault.UserID, recipient.UserID)
+	if merr != nil {
+		return nil, merr
+	}
+
+	share, merr := v.passwordShareRepository.UpsertShare(&password.CreateShare{
+		PasswordID:       pw.ID,
+		OwnerID:          vault.UserID,
+		RecipientID:      recipient.UserID,
+		Permission:       shareRequest.Permission,
+		EncryptedItemKey: shareRequest.EncryptedItemKey,
+		UpdatedAt:        time.Now(),
+	})
+	if merr != nil {
+		return nil, merr
+	}
+
+	v.notifyShare(consts.VaultEventTypes.SHARE_CREATED, share)
+	return share, nil
+}
+
+// RevokeShare removes a share. The owner can revoke any share of the
+// password, a recipient can remove the share made to them.
+func (v *VaultServices) RevokeShare(passwordID int, shareID int, vault *models.Vault) *models.Error {
+	share, merr := v.passwordShareRepository.GetShare(strconv.Itoa(shareID))
+	if merr != nil {
+		return merr
+	}
+
+	if share.PasswordID != passwordID || (share.OwnerID != vault.UserID && share.RecipientID != vault.UserID) {
+		return models.NewError(404, "NotFound", "Share not found with id="+strconv.Itoa(shareID))
+	}
+
+	_, merr = v.passwordShareRepository.DeleteShare(strconv.Itoa(shareID))
+	if merr != nil {
+		return merr
+	}
+
+	v.notifyShare(consts.VaultEventTypes.SHARE_REVOKED, share)
+	return nil
+}
+
+// notifyVault, notifyItems and notifyShare push a successful write to the
+// devices concerned. They do nothing without notification services.
+func (v *VaultServices) notifyVault(eventType string, vault *models.Vault) {
+	if v.notificationServices != nil {
+		v.notificationServices.NotifyVault(eventType, vault)
+	}
+}
+
+func (v *VaultServices) notifyItems(eventType string, vaultID int, userID int, passwordIDs ...int) {
+	if v.notificationServices != nil {
+		v.notificationServices.NotifyItems(eventType, vaultID, userID, passwordIDs)
+	}
+}
+
+func (v *VaultServices) notifyShare(eventType string, share *models.PasswordShare) {
+	if v.notificationServices != nil {
+		v.notificationServices.NotifyShare(eventType, share)
+	}
+}
+
+// notifyPasswords notifies the changes of passwords that can be spread over
+// several vaults of the user, vault by vault.
+func (v *VaultServices) notifyPasswords(eventType string, userID int, passwords []*models.Password) {
+	byVault := make(map[int][]int)
+	for _, pw := range passwords {
+		byVault[pw.VaultID] = append(byVault[pw.VaultID], pw.ID)
+	}
+
+	for vaultID, ids := range byVault {
+		v.notifyItems(eventType, vaultID, userID, ids...)
+	}
+}
+
+func passwordIDsOf(passwords []*models.Password) []int {
+	ids := make([]int, 0, len(passwords))
+	for _, pw := range passwords {
+		ids = append(ids, pw.ID)
+	}
+
+	return ids
+}
+
+func (v *VaultServices) getOwnPassword(passwordID int, vault *models.Vault) (*models.Password, *models.Error) {
+	pw, merr := v.passwordRepository.GetPassword(strconv.Itoa(passwordID))
+	if merr != nil {
+		return nil, merr
+	}
+
+	if pw.VaultID != vault.ID {
+		return nil, models.NewError(401, "Unauthorized", "")
+	}
+
+	return pw, nil
+}
+
+func newCreatePassword(vaultID int, passwordRequest *password.CreatePasswordRequest) *password.CreatePassword {
+	return &password.CreatePassword{
 		VaultID:           vaultID,
+		Type:              passwordRequest.Type,
 		AppName:           passwordRequest.AppName,
 		Uri:               passwordRequest.Uri,
 		Username:          passwordRequest.Username,
 		EncryptedPassword: passwordRequest.EncryptedPassword,
+		ItemKey:           passwordRequest.ItemKey,
+		FolderID:          passwordRequest.FolderID,
+		SecureNote:        passwordRequest.SecureNote,
+		Card:              passwordRequest.Card,
+		Identity:          passwordRequest.Identity,
+		SshKey:            passwordRequest.SshKey,
+		ApiCredential:     passwordRequest.ApiCredential,
+		Fields:            passwordRequest.Fields,
+		Uris:              passwordRequest.Uris,
 	}
-
-	newPw, merr := v.passwordRepository.UpdatePassword(strconv.Itoa(passwordID), pw)
-	return newPw, merr
 }
 
-func (v *VaultServices) DeletePassword(id int, vaultID int) (*models.Password, *models.Error) {
-	newPw, merr := v.passwordRepository.DeletePassword(strconv.Itoa(id), strconv.Itoa(vaultID))
+// sharedPassword turns a share with its embedded password into the password
+// as seen by the recipient.
+func sharedPassword(share *models.PasswordShare) *models.Password {
+	pw := share.Password
+	share.Password = nil
+
+	pw.ItemKey = ""
+	pw.FolderID = nil
+	pw.Favorite = false
+	pw.Tags = nil
+	pw.Shared = true
+	pw.Share = share
 
-	return newPw, merr
+	return pw
 }
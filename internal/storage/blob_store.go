@@ -0,0 +1,35 @@
+package storage
+
+import (
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/safepass/server/internal/config"
+)
+
+// ErrBlobNotFound is returned when there is no blob with the given key.
+var ErrBlobNotFound = errors.New("blob not found")
+
+// BlobStore keeps the encrypted content of attachments. Keys are generated by
+// the server.
+type BlobStore interface {
+	// Put stores the content read from r under key and returns the number of
+	// bytes written. A failed Put leaves no blob behind.
+	Put(key string, r io.Reader) (int64, error)
+	// Get opens the blob for reading. The caller closes it.
+	Get(key string) (io.ReadCloser, error)
+	// Delete removes the blob. Deleting a missing blob is not an error.
+	Delete(key string) error
+}
+
+// NewBlobStore returns the blob store selected by the attachments driver in
+// the config.
+func NewBlobStore(attachmentsConfig config.AttachmentsConfig) (BlobStore, error) {
+	switch attachmentsConfig.Driver {
+	case "local", "":
+		return NewLocalBlobStore(attachmentsConfig.Path)
+	default:
+		return nil, fmt.Errorf("unknown blob store driver: %s", attachmentsConfig.Driver)
+	}
+}
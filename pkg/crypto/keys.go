@@ -0,0 +1,59 @@
+package crypto
+
+import (
+	"crypto/ecdh"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/hex"
+	"errors"
+	"strings"
+)
+
+const (
+	ALGORITHM_RSA_OAEP = "RSA-OAEP"
+	ALGORITHM_X25519   = "X25519"
+
+	MIN_RSA_KEY_BITS = 2048
+)
+
+// PublicKeyAlgorithm parses a DER encoded SubjectPublicKeyInfo and returns the
+// algorithm it is used with. Only RSA keys of at least MIN_RSA_KEY_BITS and
+// X25519 keys are accepted.
+func PublicKeyAlgorithm(der []byte) (string, error) {
+	key, err := x509.ParsePKIXPublicKey(der)
+	if err != nil {
+		return "", err
+	}
+
+	switch k := key.(type) {
+	case *rsa.PublicKey:
+		if k.N.BitLen() < MIN_RSA_KEY_BITS {
+			return "", errors.New("RSA key must be at least 2048 bits")
+		}
+
+		return ALGORITHM_RSA_OAEP, nil
+	case *ecdh.PublicKey:
+		if k.Curve() != ecdh.X25519() {
+			return "", errors.New("Only X25519 keys are supported for ECDH")
+		}
+
+		return ALGORITHM_X25519, nil
+	default:
+		return "", errors.New("Unsupported public key type")
+	}
+}
+
+// Fingerprint returns the SHA-256 of the public key as groups of four hex
+// digits, so users can compare it with each other out-of-band.
+func Fingerprint(der []byte) string {
+	sum := sha256.Sum256(der)
+	encoded := hex.EncodeToString(sum[:])
+
+	groups := make([]string, 0, len(encoded)/4)
+	for i := 0; i < len(encoded); i += 4 {
+		groups = append(groups, encoded[i:i+4])
+	}
+
+	return strings.Join(groups, " ")
+}
@@ -0,0 +1,10 @@
+package attachment
+
+type CreateAttachment struct {
+	PasswordID        int    `json:"password_id"`
+	UserID            int    `json:"user_id"`
+	BlobKey           string `json:"blob_key"`
+	EncryptedFileName string `json:"encrypted_file_name"`
+	EncryptedKey      string `json:"encrypted_key"`
+	Size              int64  `json:"size"`
+}
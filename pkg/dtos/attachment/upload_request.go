@@ -0,0 +1,11 @@
+package attachment
+
+import "io"
+
+// UploadRequest is an attachment upload. The metadata comes from the form
+// fields sent before the file; Data streams the encrypted file.
+type UploadRequest struct {
+	EncryptedFileName string `validate:"required,max=1024"`
+	EncryptedKey      string `validate:"required,max=1024"`
+	Data              io.Reader
+}
@@ -0,0 +1,7 @@
+package audit
+
+type CreateAuditLog struct {
+	UserID   int                    `json:"user_id"`
+	Event    string                 `json:"event"`
+	Metadata map[string]interface{} `json:"metadata,omitempty"`
+}
@@ -0,0 +1,6 @@
+package emergency
+
+type AcceptRequest struct {
+	// PublicKey defaults to the grantee's stored public key when omitted.
+	PublicKey string `json:"public_key,omitempty"`
+}
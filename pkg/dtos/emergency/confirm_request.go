@@ -0,0 +1,7 @@
+package emergency
+
+type ConfirmRequest struct {
+	// KeyEncrypted is the grantor's vault key wrapped with the grantee's
+	// public key.
+	KeyEncrypted string `json:"key_encrypted" validate:"required"`
+}
@@ -0,0 +1,9 @@
+package emergency
+
+type CreateEmergencyAccess struct {
+	GrantorID    int    `json:"grantor_id"`
+	GranteeEmail string `json:"grantee_email"`
+	Type         string `json:"type"`
+	Status       string `json:"status"`
+	WaitTimeDays int    `json:"wait_time_days"`
+}
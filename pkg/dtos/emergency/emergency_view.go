@@ -0,0 +1,12 @@
+package emergency
+
+import "github.com/safepass/server/pkg/models"
+
+// EmergencyView is what a grantee receives once recovery is approved.
+// Emergency access covers the default vault only; UncoveredVaults names the
+// grantor's other vaults, which the grantee cannot read.
+type EmergencyView struct {
+	KeyEncrypted    string             `json:"key_encrypted"`
+	Passwords       []*models.Password `json:"passwords,omitempty"`
+	UncoveredVaults []string           `json:"uncovered_vaults"`
+}
@@ -0,0 +1,7 @@
+package emergency
+
+type InviteRequest struct {
+	Email        string `json:"email" validate:"required,email"`
+	Type         string `json:"type" validate:"required,oneof=view takeover"`
+	WaitTimeDays int    `json:"wait_time_days,omitempty" validate:"omitempty,min=1,max=90"`
+}
@@ -0,0 +1,13 @@
+package emergency
+
+// Takeover is what an emergency takeover writes to the grantor's account: the
+// new master password hash as stored by the server and the default vault key
+// wrapped under the new master key. DiscardVaults deletes the other vaults.
+type Takeover struct {
+	MasterPasswordHash    string
+	Salt                  string
+	IterationCount        int
+	ProtectedSymmetricKey string
+	Mac                   string
+	DiscardVaults         bool
+}
@@ -0,0 +1,11 @@
+package emergency
+
+type TakeoverRequest struct {
+	MasterPasswordHash    string `json:"master_password_hash" validate:"required"`
+	ProtectedSymmetricKey string `json:"protected_symmetric_key" validate:"required"`
+
+	// DiscardUncoveredVaults confirms that the grantor's vaults other than the
+	// default one are deleted. Their keys are wrapped under the old master
+	// password, so they could never be opened again after the takeover.
+	DiscardUncoveredVaults bool `json:"discard_uncovered_vaults,omitempty"`
+}
@@ -0,0 +1,11 @@
+package emergency
+
+import "time"
+
+type UpdateEmergencyAccess struct {
+	Status              string     `json:"status,omitempty"`
+	GranteePublicKey    string     `json:"grantee_public_key,omitempty"`
+	KeyEncrypted        string     `json:"key_encrypted,omitempty"`
+	RecoveryInitiatedAt *time.Time `json:"recovery_initiated_at,omitempty"`
+	UpdatedAt           time.Time  `json:"updated_at"`
+}
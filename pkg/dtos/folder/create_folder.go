@@ -0,0 +1,9 @@
+package folder
+
+import "time"
+
+type CreateFolder struct {
+	UserID        int       `json:"user_id"`
+	EncryptedName string    `json:"encrypted_name"`
+	UpdatedAt     time.Time `json:"updated_at"`
+}
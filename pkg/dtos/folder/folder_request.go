@@ -0,0 +1,5 @@
+package folder
+
+type FolderRequest struct {
+	EncryptedName string `json:"encrypted_name" validate:"required"`
+}
@@ -0,0 +1,6 @@
+package organization
+
+type CollectionAccessRequest struct {
+	CollectionID int  `json:"collection_id" validate:"required"`
+	ReadOnly     bool `json:"read_only,omitempty"`
+}
@@ -0,0 +1,6 @@
+package organization
+
+type CollectionRequest struct {
+	// Name is encrypted with the organization key.
+	Name string `json:"name" validate:"required"`
+}
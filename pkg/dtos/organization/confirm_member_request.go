@@ -0,0 +1,7 @@
+package organization
+
+type ConfirmMemberRequest struct {
+	// EncryptedOrgKey is the organization key wrapped with the member's
+	// public key.
+	EncryptedOrgKey string `json:"encrypted_org_key" validate:"required"`
+}
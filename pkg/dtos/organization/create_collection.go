@@ -0,0 +1,9 @@
+package organization
+
+import "time"
+
+type CreateCollection struct {
+	OrganizationID int       `json:"organization_id"`
+	Name           string    `json:"name"`
+	UpdatedAt      time.Time `json:"updated_at"`
+}
@@ -0,0 +1,9 @@
+package organization
+
+type CreateMember struct {
+	OrganizationID  int    `json:"organization_id"`
+	UserID          int    `json:"user_id"`
+	Role            string `json:"role"`
+	Status          string `json:"status"`
+	EncryptedOrgKey string `json:"encrypted_org_key,omitempty"`
+}
@@ -0,0 +1,5 @@
+package organization
+
+type CreateOrganization struct {
+	Name string `json:"name"`
+}
@@ -0,0 +1,23 @@
+package organization
+
+import "github.com/safepass/server/pkg/models"
+
+type CreateOrganizationPassword struct {
+	OrganizationID    int    `json:"organization_id"`
+	CollectionID      int    `json:"collection_id"`
+	Type              string `json:"type"`
+	AppName           string `json:"app_name,omitempty"`
+	Uri               string `json:"uri,omitempty"`
+	Username          string `json:"username,omitempty"`
+	EncryptedPassword string `json:"encrypted_password"`
+	ItemKey           string `json:"item_key,omitempty"`
+
+	SecureNote    *models.SecureNote    `json:"secure_note"`
+	Card          *models.Card          `json:"card"`
+	Identity      *models.Identity      `json:"identity"`
+	SshKey        *models.SshKey        `json:"ssh_key"`
+	ApiCredential *models.ApiCredential `json:"api_credential"`
+
+	Fields []*models.CustomField `json:"fields"`
+	Uris   []*models.LoginUri    `json:"uris"`
+}
@@ -0,0 +1,8 @@
+package organization
+
+type CreateOrganizationRequest struct {
+	Name string `json:"name" validate:"required,max=128"`
+	// EncryptedOrgKey is the new organization key wrapped with the
+	// creator's public key.
+	EncryptedOrgKey string `json:"encrypted_org_key" validate:"required"`
+}
@@ -0,0 +1,7 @@
+package organization
+
+type InviteMemberRequest struct {
+	Email       string                     `json:"email" validate:"required,email"`
+	Role        string                     `json:"role" validate:"required,oneof=owner admin member read_only"`
+	Collections []*CollectionAccessRequest `json:"collections,omitempty" validate:"dive,required"`
+}
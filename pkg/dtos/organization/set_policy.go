@@ -0,0 +1,15 @@
+package organization
+
+import (
+	"time"
+
+	"github.com/safepass/server/pkg/models"
+)
+
+type SetPolicy struct {
+	OrganizationID int               `json:"organization_id"`
+	Type           string            `json:"type"`
+	Enabled        bool              `json:"enabled"`
+	Data           models.PolicyData `json:"data"`
+	UpdatedAt      time.Time         `json:"updated_at"`
+}
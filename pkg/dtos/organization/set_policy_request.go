@@ -0,0 +1,8 @@
+package organization
+
+import "github.com/safepass/server/pkg/models"
+
+type SetPolicyRequest struct {
+	Enabled bool              `json:"enabled"`
+	Data    models.PolicyData `json:"data"`
+}
@@ -0,0 +1,10 @@
+package organization
+
+import "time"
+
+type UpdateMember struct {
+	Role            string    `json:"role,omitempty"`
+	Status          string    `json:"status,omitempty"`
+	EncryptedOrgKey string    `json:"encrypted_org_key,omitempty"`
+	UpdatedAt       time.Time `json:"updated_at"`
+}
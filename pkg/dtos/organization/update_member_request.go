@@ -0,0 +1,7 @@
+package organization
+
+type UpdateMemberRequest struct {
+	Role string `json:"role,omitempty" validate:"omitempty,oneof=owner admin member read_only"`
+	// Collections replaces the member's collection access when not nil.
+	Collections []*CollectionAccessRequest `json:"collections,omitempty" validate:"omitempty,dive,required"`
+}
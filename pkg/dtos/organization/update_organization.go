@@ -0,0 +1,8 @@
+package organization
+
+import "time"
+
+type UpdateOrganization struct {
+	Name      string    `json:"name"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
@@ -0,0 +1,26 @@
+package password
+
+// BatchWrite is a write of a batch as the database applies it. PasswordID, or
+// Ref for an item created earlier in the batch, names the item of updates and
+// trashes. Revision is the revision the item is expected to have, TagIDs
+// replace the item's tags when they are not nil.
+type BatchWrite struct {
+	Index      int             `json:"index"`
+	Action     string          `json:"action"`
+	PasswordID int             `json:"password_id,omitempty"`
+	Ref        *int            `json:"ref,omitempty"`
+	Revision   *int64          `json:"revision,omitempty"`
+	Item       *CreatePassword `json:"item,omitempty"`
+	TagIDs     []int           `json:"tag_ids"`
+}
+
+// BatchWriteResult holds the id and revision of the item of each write, and
+// the revision of the vault after the batch.
+type BatchWriteResult struct {
+	Revision int64 `json:"revision"`
+	Items    []struct {
+		Index    int   `json:"index"`
+		ID       int   `json:"id"`
+		Revision int64 `json:"revision"`
+	} `json:"items"`
+}
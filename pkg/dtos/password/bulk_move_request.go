@@ -0,0 +1,6 @@
+package password
+
+type BulkMoveRequest struct {
+	PasswordIDs []int `json:"password_ids" validate:"required,min=1,max=500"`
+	FolderID    *int  `json:"folder_id"`
+}
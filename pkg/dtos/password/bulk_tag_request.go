@@ -0,0 +1,6 @@
+package password
+
+type BulkTagRequest struct {
+	PasswordIDs []int `json:"password_ids" validate:"required,min=1,max=500"`
+	TagIDs      []int `json:"tag_ids" validate:"required,min=1,max=50"`
+}
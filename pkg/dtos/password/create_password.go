@@ -1,9 +1,27 @@
 package password
 
+import "github.com/safepass/server/pkg/models"
+
 type CreatePassword struct {
 	VaultID           int    `json:"vault_id" validate:"required"`
+	Type              string `json:"type"`
 	AppName           string `json:"app_name,omitempty"`
 	Uri               string `json:"uri,omitempty"`
 	Username          string `json:"username,omitempty"`
 	EncryptedPassword string `json:"encrypted_password" validate:"required"`
+	ItemKey           string `json:"item_key,omitempty"`
+	FolderID          *int   `json:"folder_id"`
+	Favorite          bool   `json:"favorite"`
+
+	SecureNote    *models.SecureNote    `json:"secure_note"`
+	Card          *models.Card          `json:"card"`
+	Identity      *models.Identity      `json:"identity"`
+	SshKey        *models.SshKey        `json:"ssh_key"`
+	ApiCredential *models.ApiCredential `json:"api_credential"`
+
+	Fields []*models.CustomField `json:"fields"`
+	Uris   []*models.LoginUri    `json:"uris"`
+
+	ClientID   *string `json:"client_id,omitempty"`
+	ConflictOf *int    `json:"conflict_of,omitempty"`
 }
@@ -1,8 +1,35 @@
 package password
 
+import "github.com/safepass/server/pkg/models"
+
+// CreatePasswordRequest creates or replaces a vault item. Type defaults to
+// login; which fields are required depends on the type.
 type CreatePasswordRequest struct {
+	Type              string `json:"type,omitempty" validate:"omitempty,oneof=login secure_note card identity ssh_key api_credential"`
 	AppName           string `json:"app_name,omitempty"`
 	Uri               string `json:"uri,omitempty"`
 	Username          string `json:"username,omitempty"`
-	EncryptedPassword string `json:"encrypted_password" validate:"required"`
+	EncryptedPassword string `json:"encrypted_password,omitempty"`
+	ItemKey           string `json:"item_key,omitempty"`
+	FolderID          *int   `json:"folder_id,omitempty"`
+	Favorite          *bool  `json:"favorite,omitempty"`
+	TagIDs            []int  `json:"tag_ids,omitempty" validate:"omitempty,max=50"`
+
+	// Revision is the revision of the item the update is based on. When it
+	// is set and the item has changed since, the update is rejected.
+	Revision *int64 `json:"revision,omitempty"`
+
+	SecureNote    *models.SecureNote    `json:"secure_note,omitempty"`
+	Card          *models.Card          `json:"card,omitempty"`
+	Identity      *models.Identity      `json:"identity,omitempty"`
+	SshKey        *models.SshKey        `json:"ssh_key,omitempty"`
+	ApiCredential *models.ApiCredential `json:"api_credential,omitempty"`
+
+	Fields []*models.CustomField `json:"fields,omitempty" validate:"omitempty,max=50"`
+	Uris   []*models.LoginUri    `json:"uris,omitempty" validate:"omitempty,max=20"`
+
+	// UrisOmitted is set by validation when a login comes without uris, as
+	// sent by older clients that only know uri. Updates then keep the stored
+	// list.
+	UrisOmitted bool `json:"-"`
 }
@@ -0,0 +1,12 @@
+package password
+
+import "time"
+
+type CreateShare struct {
+	PasswordID       int       `json:"password_id"`
+	OwnerID          int       `json:"owner_id"`
+	RecipientID      int       `json:"recipient_id"`
+	Permission       string    `json:"permission"`
+	EncryptedItemKey string    `json:"encrypted_item_key"`
+	UpdatedAt        time.Time `json:"updated_at"`
+}
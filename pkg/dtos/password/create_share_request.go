@@ -0,0 +1,11 @@
+package password
+
+type CreateShareRequest struct {
+	RecipientEmail string `json:"recipient_email" validate:"required,email"`
+	Permission     string `json:"permission" validate:"required,oneof=read write"`
+	// EncryptedItemKey is the item key wrapped with the recipient's public key.
+	EncryptedItemKey string `json:"encrypted_item_key" validate:"required"`
+	// RecipientFingerprint, when given, must match the recipient's current
+	// key so a key that changed after the client looked it up is rejected.
+	RecipientFingerprint string `json:"recipient_fingerprint,omitempty"`
+}
@@ -0,0 +1,5 @@
+package password
+
+type FavoriteRequest struct {
+	Favorite bool `json:"favorite"`
+}
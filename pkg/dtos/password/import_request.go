@@ -0,0 +1,18 @@
+package password
+
+import "github.com/safepass/server/pkg/dtos/folder"
+
+// ImportRequest imports the items of another password manager's export,
+// encrypted by the client. The folders are created along with the items,
+// which refer to them by their index in Folders.
+type ImportRequest struct {
+	Folders []*folder.FolderRequest `json:"folders,omitempty" validate:"omitempty,max=1000,dive,required"`
+	Items   []*ImportItem           `json:"items" validate:"required,min=1,max=5000,dive,required"`
+}
+
+// ImportItem is an item of an import. It goes in the folder at FolderIndex,
+// or in the existing folder FolderID.
+type ImportItem struct {
+	CreatePasswordRequest
+	FolderIndex *int `json:"folder_index,omitempty" validate:"omitempty,min=0"`
+}
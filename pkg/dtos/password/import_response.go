@@ -0,0 +1,8 @@
+package password
+
+// ImportResponse holds the ids of the created folders and items, in the order
+// of the request.
+type ImportResponse struct {
+	FolderIDs   []int `json:"folder_ids"`
+	PasswordIDs []int `json:"password_ids"`
+}
@@ -0,0 +1,8 @@
+package password
+
+// ImportWrite is an item of an import as the database creates it.
+type ImportWrite struct {
+	Item        *CreatePassword `json:"item"`
+	FolderIndex *int            `json:"folder_index,omitempty"`
+	TagIDs      []int           `json:"tag_ids,omitempty"`
+}
@@ -0,0 +1,7 @@
+package password
+
+// MoveRequest moves a password into a folder, or out of its folder when
+// FolderID is null.
+type MoveRequest struct {
+	FolderID *int `json:"folder_id"`
+}
@@ -0,0 +1,60 @@
+package password
+
+import (
+	"strconv"
+
+	"github.com/safepass/server/pkg/models"
+)
+
+// PasswordFilter narrows down password listings. Empty fields match every
+// password.
+type PasswordFilter struct {
+	Type string `validate:"omitempty,oneof=login secure_note card identity ssh_key api_credential"`
+
+	// Folder is a folder id, or "none" for passwords that are in no folder.
+	Folder string `validate:"omitempty,number|eq=none"`
+
+	// Tag is a tag id; Favorite keeps only favorites when set.
+	Tag      string `validate:"omitempty,number"`
+	Favorite bool
+}
+
+// Matches reports whether the password passes the filter, for listings that
+// are not filtered by the database.
+func (f *PasswordFilter) Matches(pw *models.Password) bool {
+	if f == nil {
+		return true
+	}
+
+	if f.Type != "" && pw.Type != f.Type {
+		return false
+	}
+
+	if f.Favorite && !pw.Favorite {
+		return false
+	}
+
+	if f.Tag != "" && !hasTag(pw, f.Tag) {
+		return false
+	}
+
+	if f.Folder == "none" && pw.FolderID != nil {
+		return false
+	}
+
+	if f.Folder != "" && f.Folder != "none" && (pw.FolderID == nil || strconv.Itoa(*pw.FolderID) != f.Folder) {
+		return false
+	}
+
+	return true
+}
+
+func hasTag(pw *models.Password, tagID string) bool {
+	for _, tag := range pw.Tags {
+		if strconv.Itoa(tag.TagID) == tagID {
+			return true
+		}
+	}
+
+	return false
+}
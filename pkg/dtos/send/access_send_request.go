@@ -0,0 +1,7 @@
+package send
+
+// AccessSendRequest opens a Send. Password is needed for Sends protected by
+// one.
+type AccessSendRequest struct {
+	Password string `json:"password,omitempty" validate:"max=1024"`
+}
@@ -0,0 +1,20 @@
+package send
+
+import "time"
+
+type CreateSend struct {
+	AccessID          string     `json:"access_id"`
+	UserID            int        `json:"user_id"`
+	Type              string     `json:"type"`
+	EncryptedName     string     `json:"encrypted_name"`
+	EncryptedKey      string     `json:"encrypted_key"`
+	EncryptedText     string     `json:"encrypted_text,omitempty"`
+	EncryptedFileName string     `json:"encrypted_file_name,omitempty"`
+	BlobKey           string     `json:"blob_key,omitempty"`
+	Size              int64      `json:"size"`
+	PasswordHash      string     `json:"password_hash,omitempty"`
+	PasswordSalt      string     `json:"password_salt,omitempty"`
+	MaxAccessCount    *int       `json:"max_access_count"`
+	ExpirationDate    *time.Time `json:"expiration_date"`
+	DeletionDate      time.Time  `json:"deletion_date"`
+}
@@ -0,0 +1,22 @@
+package send
+
+import (
+	"io"
+	"time"
+)
+
+// CreateSendRequest creates a Send. Text Sends carry EncryptedText; file Sends
+// are uploaded as a multipart form with this request as JSON in the "send"
+// part, followed by the encrypted file in the "data" part.
+type CreateSendRequest struct {
+	EncryptedName     string     `json:"encrypted_name" validate:"required,max=1024"`
+	EncryptedKey      string     `json:"encrypted_key" validate:"required,max=1024"`
+	EncryptedText     string     `json:"encrypted_text,omitempty" validate:"max=100000"`
+	EncryptedFileName string     `json:"encrypted_file_name,omitempty" validate:"max=1024"`
+	Password          string     `json:"password,omitempty" validate:"max=1024"`
+	MaxAccessCount    *int       `json:"max_access_count,omitempty" validate:"omitempty,min=1"`
+	ExpirationDate    *time.Time `json:"expiration_date,omitempty"`
+	DeletionDate      time.Time  `json:"deletion_date" validate:"required"`
+
+	Data io.Reader `json:"-"`
+}
@@ -0,0 +1,26 @@
+package send
+
+import "github.com/safepass/server/pkg/models"
+
+// SendAccessResponse is a Send as seen by anyone with its link.
+type SendAccessResponse struct {
+	AccessID          string  `json:"access_id"`
+	Type              string  `json:"type"`
+	EncryptedName     string  `json:"encrypted_name"`
+	EncryptedText     string  `json:"encrypted_text,omitempty"`
+	EncryptedFileName string  `json:"encrypted_file_name,omitempty"`
+	Size              int64   `json:"size,omitempty"`
+	ExpirationDate    *string `json:"expiration_date"`
+}
+
+func NewSendAccessResponse(s *models.Send) *SendAccessResponse {
+	return &SendAccessResponse{
+		AccessID:          s.AccessID,
+		Type:              s.Type,
+		EncryptedName:     s.EncryptedName,
+		EncryptedText:     s.EncryptedText,
+		EncryptedFileName: s.EncryptedFileName,
+		Size:              s.Size,
+		ExpirationDate:    s.ExpirationDate,
+	}
+}
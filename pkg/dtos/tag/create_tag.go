@@ -0,0 +1,9 @@
+package tag
+
+import "time"
+
+type CreateTag struct {
+	UserID         int       `json:"user_id"`
+	EncryptedLabel string    `json:"encrypted_label"`
+	UpdatedAt      time.Time `json:"updated_at"`
+}
@@ -0,0 +1,5 @@
+package tag
+
+type TagRequest struct {
+	EncryptedLabel string `json:"encrypted_label" validate:"required"`
+}
@@ -9,4 +9,7 @@ type CreateUser struct {
 	Salt               string `json:"salt"`
 	IterationCount     int    `json:"iteration_count"`
 	RoleId             int    `json:"role_id"`
+
+	MasterPasswordHint          string `json:"master_password_hint,omitempty"`
+	MasterPasswordHintEncrypted bool   `json:"master_password_hint_encrypted"`
 }
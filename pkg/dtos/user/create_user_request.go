@@ -7,4 +7,5 @@ type CreateUserRequest struct {
 	Surname               string `json:"surname,omitempty"`
 	MasterPasswordHash    string `json:"master_password_hash" validate:"required"`
 	ProtectedSymmetricKey string `json:"protected_symmetric_key" validate:"required"`
+	MasterPasswordHint    string `json:"master_password_hint,omitempty" validate:"omitempty,max=128"`
 }
@@ -0,0 +1,8 @@
+package user
+
+type DeleteAccountRequest struct {
+	MasterPasswordHash string `json:"master_password_hash" validate:"required"`
+	// Scheduled delays the deletion by the configured grace period. Logging
+	// in before it elapses cancels the deletion.
+	Scheduled bool `json:"scheduled,omitempty"`
+}
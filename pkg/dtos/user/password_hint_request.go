@@ -0,0 +1,5 @@
+package user
+
+type PasswordHintRequest struct {
+	Email string `json:"email" validate:"required,email"`
+}
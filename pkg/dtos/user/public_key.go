@@ -0,0 +1,24 @@
+package user
+
+import "github.com/safepass/server/pkg/models"
+
+// PublicKey is another user's public key as shown to authenticated users.
+type PublicKey struct {
+	UserID      int    `json:"user_id"`
+	Username    string `json:"username"`
+	Email       string `json:"email"`
+	PublicKey   string `json:"public_key"`
+	Algorithm   string `json:"algorithm"`
+	Fingerprint string `json:"fingerprint"`
+}
+
+func NewPublicKey(u *models.User, key *models.UserKey) *PublicKey {
+	return &PublicKey{
+		UserID:      u.ID,
+		Username:    u.Username,
+		Email:       u.Email,
+		PublicKey:   key.PublicKey,
+		Algorithm:   key.Algorithm,
+		Fingerprint: key.Fingerprint,
+	}
+}
@@ -0,0 +1,35 @@
+package user
+
+import (
+	"time"
+
+	"github.com/safepass/server/pkg/models"
+)
+
+// PublicUser is the user representation that is safe to send to clients.
+// It never carries the master password hash, salt or KDF parameters.
+type PublicUser struct {
+	ID        int       `json:"id"`
+	Username  string    `json:"username"`
+	Email     string    `json:"email"`
+	Name      string    `json:"name"`
+	Surname   string    `json:"surname"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+
+	VerifiedAt *time.Time `json:"verified_at"`
+}
+
+func NewPublicUser(u *models.User) *PublicUser {
+	return &PublicUser{
+		ID:        u.ID,
+		Username:  u.Username,
+		Email:     u.Email,
+		Name:      u.Name,
+		Surname:   u.Surname,
+		CreatedAt: u.CreatedAt,
+		UpdatedAt: u.UpdatedAt,
+
+		VerifiedAt: u.VerifiedAt,
+	}
+}
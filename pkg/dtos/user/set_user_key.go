@@ -0,0 +1,12 @@
+package user
+
+import "time"
+
+type SetUserKey struct {
+	UserID              int       `json:"user_id"`
+	PublicKey           string    `json:"public_key"`
+	EncryptedPrivateKey string    `json:"encrypted_private_key"`
+	Algorithm           string    `json:"algorithm"`
+	Fingerprint         string    `json:"fingerprint"`
+	UpdatedAt           time.Time `json:"updated_at"`
+}
@@ -0,0 +1,8 @@
+package user
+
+type SetUserKeyRequest struct {
+	// PublicKey is the Base64 DER encoded SubjectPublicKeyInfo.
+	PublicKey           string `json:"public_key" validate:"required,base64"`
+	EncryptedPrivateKey string `json:"encrypted_private_key" validate:"required"`
+	Algorithm           string `json:"algorithm" validate:"required,oneof=RSA-OAEP X25519"`
+}
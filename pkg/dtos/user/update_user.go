@@ -3,13 +3,13 @@ package user
 import "time"
 
 type UpdateUser struct {
-	Username           string    `json:"username"`
-	Email              string    `json:"email"`
-	Name               string    `json:"name"`
-	Surname            string    `json:"surname"`
-	MasterPasswordHash string    `json:"master_password_hash"`
-	Salt               string    `json:"salt"`
-	IterationCount     int       `json:"iteration_count"`
-	RoleId             int       `json:"role_id"`
+	Username           string    `json:"username,omitempty"`
+	Email              string    `json:"email,omitempty"`
+	Name               string    `json:"name,omitempty"`
+	Surname            string    `json:"surname,omitempty"`
+	MasterPasswordHash string    `json:"master_password_hash,omitempty"`
+	Salt               string    `json:"salt,omitempty"`
+	IterationCount     int       `json:"iteration_count,omitempty"`
+	RoleId             int       `json:"role_id,omitempty"`
 	UpdatedAt          time.Time `json:"updated_at"`
 }
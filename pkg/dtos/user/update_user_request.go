@@ -1,9 +1,9 @@
 package user
 
 type UpdateUserRequest struct {
-	Username           string `json:"username,omitempty"`
-	Email              string `json:"email,omitempty"`
-	Name               string `json:"name,omitempty"`
-	Surname            string `json:"surname,omitempty"`
+	Username           string `json:"username,omitempty" validate:"omitempty,max=64"`
+	Email              string `json:"email,omitempty" validate:"omitempty,email"`
+	Name               string `json:"name,omitempty" validate:"omitempty,max=64"`
+	Surname            string `json:"surname,omitempty" validate:"omitempty,max=64"`
 	MasterPasswordHash string `json:"master_password_hash,omitempty"`
 }
@@ -0,0 +1,5 @@
+package user
+
+type VerifyEmailRequest struct {
+	Token string `json:"token" validate:"required"`
+}
@@ -0,0 +1,22 @@
+package vault
+
+import "github.com/safepass/server/pkg/dtos/password"
+
+// BatchRequest holds item changes a client queued while offline. They are
+// applied in order and all together.
+type BatchRequest struct {
+	Operations []*BatchOperation `json:"operations" validate:"required,min=1,max=500,dive,required"`
+}
+
+// BatchOperation creates, updates or deletes an item. ClientID is the
+// client's own id for the item: creates carry it so that a batch sent twice
+// does not create the item twice, and later operations can use it instead of
+// ItemID until the client knows the server's id. Operations on items the
+// client got from the server carry the BaseRevision they were made on.
+type BatchOperation struct {
+	Action       string                          `json:"action" validate:"required,oneof=create update delete"`
+	ClientID     string                          `json:"client_id,omitempty" validate:"omitempty,max=64"`
+	ItemID       int                             `json:"item_id,omitempty"`
+	BaseRevision *int64                          `json:"base_revision,omitempty"`
+	Item         *password.CreatePasswordRequest `json:"item,omitempty"`
+}
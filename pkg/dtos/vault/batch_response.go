@@ -0,0 +1,22 @@
+package vault
+
+// BatchResponse holds the outcome of each operation of a batch, in order, and
+// the vault's revision after it.
+type BatchResponse struct {
+	Revision int64          `json:"revision"`
+	Results  []*BatchResult `json:"results"`
+}
+
+// BatchResult is the outcome of an operation. ItemID is the server's id for
+// the item. When an update lost a conflict, ConflictItemID is the copy its
+// changes were saved to.
+type BatchResult struct {
+	Index          int    `json:"index"`
+	Action         string `json:"action"`
+	ClientID       string `json:"client_id,omitempty"`
+	Status         string `json:"status"`
+	ItemID         int    `json:"item_id,omitempty"`
+	Revision       int64  `json:"revision,omitempty"`
+	ConflictItemID int    `json:"conflict_item_id,omitempty"`
+	Message        string `json:"message,omitempty"`
+}
@@ -2,6 +2,8 @@ package vault
 
 type CreateVault struct {
 	UserID                int    `json:"user_id"`
+	Name                  string `json:"name"`
+	IsDefault             bool   `json:"is_default"`
 	ProtectedSymmetricKey string `json:"protected_symmetric_key"`
 	Mac                   string `json:"mac"`
 	Algorithm             string `json:"algorithm"`
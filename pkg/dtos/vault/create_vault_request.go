@@ -0,0 +1,6 @@
+package vault
+
+type CreateVaultRequest struct {
+	Name                  string `json:"name" validate:"required,max=128"`
+	ProtectedSymmetricKey string `json:"protected_symmetric_key" validate:"required"`
+}
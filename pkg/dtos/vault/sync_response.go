@@ -0,0 +1,19 @@
+package vault
+
+import (
+	"github.com/safepass/server/pkg/dtos/user"
+	"github.com/safepass/server/pkg/models"
+)
+
+// SyncResponse holds what changed in a vault after the revision a client last
+// synced. Revision is the revision to send next time. On a full sync Items
+// holds every item and DeletedIDs is empty; the vault and the profile are only
+// included when they changed.
+type SyncResponse struct {
+	Revision   int64              `json:"revision"`
+	FullSync   bool               `json:"full_sync"`
+	Vault      *VaultResponse     `json:"vault,omitempty"`
+	Profile    *user.PublicUser   `json:"profile,omitempty"`
+	Items      []*models.Password `json:"items"`
+	DeletedIDs []int              `json:"deleted_ids"`
+}
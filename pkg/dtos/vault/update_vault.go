@@ -0,0 +1,8 @@
+package vault
+
+import "time"
+
+type UpdateVault struct {
+	Name      string    `json:"name"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
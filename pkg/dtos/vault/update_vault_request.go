@@ -0,0 +1,5 @@
+package vault
+
+type UpdateVaultRequest struct {
+	Name string `json:"name" validate:"required,max=128"`
+}
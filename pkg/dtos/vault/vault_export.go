@@ -0,0 +1,28 @@
+package vault
+
+import "github.com/safepass/server/pkg/models"
+
+// VaultExport is a full export of a vault in the SafePass format. Items,
+// folder names and tag labels are as encrypted by the client, and the vault's
+// protected key lets its owner decrypt them with their master password.
+// Attachments are listed on their items, without their content. Mac is the
+// server's MAC over the rest of the export.
+type VaultExport struct {
+	Format     string             `json:"format"`
+	Version    int                `json:"version"`
+	ExportedAt string             `json:"exported_at"`
+	Vault      *ExportedVault     `json:"vault" validate:"required"`
+	Folders    []*models.Folder   `json:"folders"`
+	Tags       []*models.Tag      `json:"tags"`
+	Items      []*models.Password `json:"items"`
+	Mac        string             `json:"mac" validate:"required"`
+}
+
+type ExportedVault struct {
+	ID                    int    `json:"id"`
+	Name                  string `json:"name"`
+	ProtectedSymmetricKey string `json:"protected_symmetric_key"`
+	Mac                   string `json:"mac"`
+	Algorithm             string `json:"algorithm"`
+	Revision              int64  `json:"revision"`
+}
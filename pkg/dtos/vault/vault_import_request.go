@@ -0,0 +1,9 @@
+package vault
+
+// VaultImportRequest restores a SafePass export into a vault. When the
+// export's key was wrapped with another master password, ProtectedSymmetricKey
+// is the export's vault key re-wrapped with the current one, as mac:key.
+type VaultImportRequest struct {
+	Export                *VaultExport `json:"export" validate:"required"`
+	ProtectedSymmetricKey string       `json:"protected_symmetric_key,omitempty"`
+}
@@ -0,0 +1,12 @@
+package vault
+
+// VaultImportResponse maps the ids of the export to the ids of the items,
+// folders and tags they were restored as. Duplicates are the ids of exported
+// items the vault already had, which were not imported again.
+type VaultImportResponse struct {
+	ItemIDs     map[int]int `json:"item_ids"`
+	FolderIDs   map[int]int `json:"folder_ids"`
+	TagIDs      map[int]int `json:"tag_ids"`
+	Duplicates  []int       `json:"duplicates"`
+	KeyReplaced bool        `json:"key_replaced"`
+}
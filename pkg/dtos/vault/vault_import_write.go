@@ -0,0 +1,21 @@
+package vault
+
+import "github.com/safepass/server/pkg/dtos/password"
+
+// VaultImportWrite is an item of a vault import as the database creates it.
+// FolderIndex and TagIndexes refer to the folders and tags the import
+// creates, FolderID and TagIDs to existing ones.
+type VaultImportWrite struct {
+	Item        *password.CreatePassword `json:"item"`
+	FolderIndex *int                     `json:"folder_index,omitempty"`
+	TagIDs      []int                    `json:"tag_ids,omitempty"`
+	TagIndexes  []int                    `json:"tag_indexes,omitempty"`
+}
+
+// VaultImportResult holds the ids of the created folders, tags and items, in
+// the order they were sent.
+type VaultImportResult struct {
+	FolderIDs   []int `json:"folder_ids"`
+	TagIDs      []int `json:"tag_ids"`
+	PasswordIDs []int `json:"password_ids"`
+}
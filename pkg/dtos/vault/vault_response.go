@@ -0,0 +1,45 @@
+package vault
+
+import (
+	"github.com/safepass/server/pkg/dtos/user"
+	"github.com/safepass/server/pkg/models"
+)
+
+// VaultResponse is the vault as returned by the API, with the owner reduced
+// to its public fields.
+type VaultResponse struct {
+	ID                    int    `json:"id"`
+	Name                  string `json:"name"`
+	IsDefault             bool   `json:"is_default"`
+	ProtectedSymmetricKey string `json:"protected_symmetric_key"`
+	Mac                   string `json:"mac"`
+	Algorithm             string `json:"algorithm"`
+	CreatedAt             string `json:"created_at"`
+	UpdatedAt             string `json:"updated_at"`
+	Revision              int64  `json:"revision"`
+
+	UserID int              `json:"user_id"`
+	User   *user.PublicUser `json:"user,omitempty"`
+}
+
+func NewVaultResponse(v *models.Vault) *VaultResponse {
+	response := &VaultResponse{
+		ID:                    v.ID,
+		Name:                  v.Name,
+		IsDefault:             v.IsDefault,
+		ProtectedSymmetricKey: v.ProtectedSymmetricKey,
+		Mac:                   v.Mac,
+		Algorithm:             v.Algorithm,
+		CreatedAt:             v.CreatedAt,
+		UpdatedAt:             v.UpdatedAt,
+		Revision:              v.Revision,
+		UserID:                v.UserID,
+	}
+
+	// The owner is only loaded when the vault is fetched with its user.
+	if v.User.ID != 0 {
+		response.User = user.NewPublicUser(&v.User)
+	}
+
+	return response
+}
@@ -0,0 +1,325 @@
+package importers
+
+import (
+	"crypto/aes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"io"
+	"strings"
+
+	"github.com/safepass/server/pkg/crypto"
+	"golang.org/x/crypto/argon2"
+	"golang.org/x/crypto/hkdf"
+)
+
+type bitwardenExport struct {
+	Encrypted         bool   `json:"encrypted"`
+	PasswordProtected bool   `json:"passwordProtected"`
+	Salt              string `json:"salt"`
+	KdfType           int    `json:"kdfType"`
+	KdfIterations     int    `json:"kdfIterations"`
+	KdfMemory         int    `json:"kdfMemory"`
+	KdfParallelism    int    `json:"kdfParallelism"`
+	KeyValidation     string `json:"encKeyValidation_DO_NOT_EDIT"`
+	Data              string `json:"data"`
+
+	Folders []*bitwardenFolder `json:"folders"`
+	Items   []*bitwardenItem   `json:"items"`
+}
+
+type bitwardenFolder struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+}
+
+type bitwardenItem struct {
+	Type     int    `json:"type"`
+	Name     string `json:"name"`
+	Notes    string `json:"notes"`
+	Favorite bool   `json:"favorite"`
+	FolderID string `json:"folderId"`
+
+	Login *struct {
+		Username string `json:"username"`
+		Password string `json:"password"`
+		Totp     string `json:"totp"`
+		Uris     []*struct {
+			Uri   string `json:"uri"`
+			Match *int   `json:"match"`
+		} `json:"uris"`
+	} `json:"login"`
+
+	Card *struct {
+		CardholderName string `json:"cardholderName"`
+		Brand          string `json:"brand"`
+		Number         string `json:"number"`
+		ExpMonth       string `json:"expMonth"`
+		ExpYear        string `json:"expYear"`
+		Code           string `json:"code"`
+	} `json:"card"`
+
+	Identity *struct {
+		Title          string `json:"title"`
+		FirstName      string `json:"firstName"`
+		MiddleName     string `json:"middleName"`
+		LastName       string `json:"lastName"`
+		Address1       string `json:"address1"`
+		Address2       string `json:"address2"`
+		Address3       string `json:"address3"`
+		City           string `json:"city"`
+		State          string `json:"state"`
+		PostalCode     string `json:"postalCode"`
+		Country        string `json:"country"`
+		Company        string `json:"company"`
+		Email          string `json:"email"`
+		Phone          string `json:"phone"`
+		SSN            string `json:"ssn"`
+		Username       string `json:"username"`
+		PassportNumber string `json:"passportNumber"`
+		LicenseNumber  string `json:"licenseNumber"`
+	} `json:"identity"`
+
+	Fields []*struct {
+		Name  string `json:"name"`
+		Value string `json:"value"`
+		Type  int    `json:"type"`
+	} `json:"fields"`
+}
+
+// bitwardenMatches maps Bitwarden's URI match detection to SafePass match
+// types, by index.
+var bitwardenMatches = []string{"base_domain", "host", "starts_with", "exact", "regex", "never"}
+
+// ParseBitwardenJSON reads a Bitwarden JSON export. Encrypted exports can be
+// read when they are protected with a password; exports encrypted with the
+// account key cannot be.
+func ParseBitwardenJSON(data []byte, password string) ([]*Item, error) {
+	var export bitwardenExport
+	err := json.Unmarshal(data, &export)
+	if err != nil {
+		return nil, err
+	}
+
+	if export.Encrypted {
+		if !export.PasswordProtected {
+			return nil, ErrUnsupported
+		}
+
+		if password == "" {
+			return nil, ErrPasswordRequired
+		}
+
+		plaintext, err := decryptBitwardenExport(&export, password)
+		if err != nil {
+			return nil, err
+		}
+
+		export = bitwardenExport{}
+		err = json.Unmarshal(plaintext, &export)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	folders := make(map[string]string, len(export.Folders))
+	for _, folder := range export.Folders {
+		if folder == nil {
+			continue
+		}
+
+		folders[folder.ID] = folder.Name
+	}
+
+	items := make([]*Item, 0, len(export.Items))
+	for _, bwItem := range export.Items {
+		if bwItem == nil {
+			continue
+		}
+
+		item := &Item{
+			Name:     bwItem.Name,
+			Folder:   folders[bwItem.FolderID],
+			Favorite: bwItem.Favorite,
+		}
+
+		switch {
+		case bwItem.Type == 1 && bwItem.Login != nil:
+			item.Type = TypeLogin
+			item.Username = bwItem.Login.Username
+			item.Password = bwItem.Login.Password
+
+			for _, uri := range bwItem.Login.Uris {
+				if uri == nil {
+					continue
+				}
+
+				var match string
+				if uri.Match != nil && *uri.Match >= 0 && *uri.Match < len(bitwardenMatches) {
+					match = bitwardenMatches[*uri.Match]
+				}
+
+				item.addUri(uri.Uri, match)
+			}
+
+			item.addField(FieldHidden, "TOTP", bwItem.Login.Totp)
+		case bwItem.Type == 3 && bwItem.Card != nil:
+			item.Type = TypeCard
+			item.Card = &Card{
+				CardholderName: bwItem.Card.CardholderName,
+				Brand:          bwItem.Card.Brand,
+				Number:         bwItem.Card.Number,
+				ExpMonth:       bwItem.Card.ExpMonth,
+				ExpYear:        bwItem.Card.ExpYear,
+				Code:           bwItem.Card.Code,
+			}
+		case bwItem.Type == 4 && bwItem.Identity != nil:
+			id := bwItem.Identity
+
+			item.Type = TypeIdentity
+			item.Identity = &Identity{
+				Title:          id.Title,
+				FirstName:      id.FirstName,
+				MiddleName:     id.MiddleName,
+				LastName:       id.LastName,
+				Email:          id.Email,
+				Phone:          id.Phone,
+				Address:        joinNonEmpty(", ", id.Address1, id.Address2, id.Address3, id.City, id.State, id.PostalCode, id.Country),
+				Company:        id.Company,
+				SSN:            id.SSN,
+				PassportNumber: id.PassportNumber,
+				LicenseNumber:  id.LicenseNumber,
+			}
+
+			item.addField(FieldText, "Username", id.Username)
+		default:
+			item.Type = TypeSecureNote
+		}
+
+		for _, field := range bwItem.Fields {
+			if field == nil {
+				continue
+			}
+
+			switch field.Type {
+			case 0:
+				item.addField(FieldText, field.Name, field.Value)
+			case 1:
+				item.addField(FieldHidden, field.Name, field.Value)
+			case 2:
+				item.addField(FieldBoolean, field.Name, field.Value)
+			}
+		}
+
+		item.addNotes(bwItem.Notes)
+		items = append(items, item)
+	}
+
+	return finishAll(items), nil
+}
+
+// The largest KDF settings Bitwarden lets users choose. Exports asking for
+// more are rejected, so a crafted file cannot make the KDF run for hours or
+// Argon2 allocate any amount of memory. KdfMemory is in MiB.
+const (
+	bitwardenMaxPbkdf2Iterations  = 2000000
+	bitwardenMaxArgon2Iterations  = 10
+	bitwardenMaxArgon2Memory      = 1024
+	bitwardenMaxArgon2Parallelism = 16
+)
+
+// decryptBitwardenExport derives the key of a password protected export, checks
+// it against the key validation value and decrypts the data.
+func decryptBitwardenExport(export *bitwardenExport, password string) ([]byte, error) {
+	if export.KdfIterations < 1 {
+		return nil, ErrKdfSettings
+	}
+
+	var key []byte
+	switch export.KdfType {
+	case 0:
+		if export.KdfIterations > bitwardenMaxPbkdf2Iterations {
+			return nil, ErrKdfSettings
+		}
+
+		key = crypto.DeriveKeySha256([]byte(password), []byte(export.Salt), export.KdfIterations, 32)
+	case 1:
+		if export.KdfIterations > bitwardenMaxArgon2Iterations ||
+			export.KdfMemory < 1 || export.KdfMemory > bitwardenMaxArgon2Memory ||
+			export.KdfParallelism < 1 || export.KdfParallelism > bitwardenMaxArgon2Parallelism {
+			return nil, ErrKdfSettings
+		}
+
+		salt := sha256.Sum256([]byte(export.Salt))
+		key = argon2.IDKey([]byte(password), salt[:], uint32(export.KdfIterations), uint32(export.KdfMemory)*1024, uint8(export.KdfParallelism), 32)
+	default:
+		return nil, ErrKdfSettings
+	}
+
+	encKey := make([]byte, 32)
+	macKey := make([]byte, 32)
+	io.ReadFull(hkdf.Expand(sha256.New, key, []byte("enc")), encKey)
+	io.ReadFull(hkdf.Expand(sha256.New, key, []byte("mac")), macKey)
+
+	_, err := decryptEncString(export.KeyValidation, encKey, macKey)
+	if err != nil {
+		return nil, err
+	}
+
+	return decryptEncString(export.Data, encKey, macKey)
+}
+
+// decryptEncString decrypts a Bitwarden "2.iv|data|mac" string: AES-256-CBC
+// with an HMAC-SHA256 over the IV and the data.
+func decryptEncString(encString string, encKey []byte, macKey []byte) ([]byte, error) {
+	encType, rest, ok := strings.Cut(encString, ".")
+	if !ok || encType != "2" {
+		return nil, ErrWrongPassword
+	}
+
+	parts := strings.Split(rest, "|")
+	if len(parts) != 3 {
+		return nil, ErrWrongPassword
+	}
+
+	var decoded [3][]byte
+	for n, part := range parts {
+		value, err := base64.StdEncoding.DecodeString(part)
+		if err != nil {
+			return nil, ErrWrongPassword
+		}
+
+		decoded[n] = value
+	}
+
+	iv, ciphertext, mac := decoded[0], decoded[1], decoded[2]
+	if len(iv) != aes.BlockSize || len(ciphertext) == 0 || len(ciphertext)%aes.BlockSize != 0 {
+		return nil, ErrWrongPassword
+	}
+
+	hash := hmac.New(sha256.New, macKey)
+	hash.Write(iv)
+	hash.Write(ciphertext)
+	if !hmac.Equal(hash.Sum(nil), mac) {
+		return nil, ErrWrongPassword
+	}
+
+	plaintext, err := crypto.DecryptAES(append(iv, ciphertext...), encKey)
+	if err != nil {
+		return nil, ErrWrongPassword
+	}
+
+	return plaintext, nil
+}
+
+func joinNonEmpty(separator string, values ...string) string {
+	var parts []string
+	for _, value := range values {
+		if value = strings.TrimSpace(value); value != "" {
+			parts = append(parts, value)
+		}
+	}
+
+	return strings.Join(parts, separator)
+}
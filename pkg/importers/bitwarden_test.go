@@ -0,0 +1,231 @@
+package importers
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"io"
+	"testing"
+
+	"github.com/safepass/server/pkg/crypto"
+	"golang.org/x/crypto/argon2"
+	"golang.org/x/crypto/hkdf"
+)
+
+const bitwardenSample = `{
+  "encrypted": false,
+  "folders": [{"id": "f1", "name": "Work"}],
+  "items": [
+    {
+      "type": 1, "name": "GitHub", "folderId": "f1", "favorite": true, "notes": "2fa on",
+      "login": {
+        "username": "octo", "password": "hunter2", "totp": "otpauth://totp/octo",
+        "uris": [{"uri": "https://github.com", "match": 1}, {"uri": " ", "match": null}]
+      },
+      "fields": [{"name": "PIN", "value": "1234", "type": 1}, {"name": "Remember", "value": "true", "type": 2}]
+    },
+    {"type": 2, "name": "Wifi", "notes": "pass: abc", "secureNote": {"type": 0}},
+    {
+      "type": 3, "name": "Visa",
+      "card": {"cardholderName": "Jane Doe", "brand": "Visa", "number": "4111111111111111", "expMonth": "4", "expYear": "2030", "code": "123"}
+    },
+    {"type": 3, "name": "Empty card", "card": {"brand": "Visa"}},
+    {
+      "type": 4, "name": "Me",
+      "identity": {"firstName": "Jane", "lastName": "Doe", "address1": "1 Main St", "city": "Springfield", "username": "jdoe"}
+    }
+  ]
+}`
+
+var bitwardenItems = []*Item{
+	{
+		Type:     TypeLogin,
+		Name:     "GitHub",
+		Folder:   "Work",
+		Favorite: true,
+		Username: "octo",
+		Password: "hunter2",
+		Uris:     []*Uri{{Uri: "https://github.com", Match: "host"}},
+		Fields: []*Field{
+			{Type: FieldHidden, Name: "TOTP", Value: "otpauth://totp/octo"},
+			{Type: FieldHidden, Name: "PIN", Value: "1234"},
+			{Type: FieldBoolean, Name: "Remember", Value: "true"},
+			{Type: FieldText, Name: "Notes", Value: "2fa on"},
+		},
+	},
+	{
+		Type:       TypeSecureNote,
+		Name:       "Wifi",
+		SecureNote: &SecureNote{Notes: "pass: abc"},
+	},
+	{
+		Type: TypeCard,
+		Name: "Visa",
+		Card: &Card{CardholderName: "Jane Doe", Brand: "Visa", Number: "4111111111111111", ExpMonth: "4", ExpYear: "2030", Code: "123"},
+	},
+	{
+		Type:       TypeSecureNote,
+		Name:       "Empty card",
+		SecureNote: &SecureNote{},
+		Fields:     []*Field{{Type: FieldText, Name: "Brand", Value: "Visa"}},
+	},
+	{
+		Type:     TypeIdentity,
+		Name:     "Me",
+		Identity: &Identity{FirstName: "Jane", LastName: "Doe", Address: "1 Main St, Springfield"},
+		Fields:   []*Field{{Type: FieldText, Name: "Username", Value: "jdoe"}},
+	},
+}
+
+// encryptBitwardenSample builds a password protected export of the sample the
+// way Bitwarden does.
+func encryptBitwardenSample(t *testing.T, password string, kdf map[string]int) []byte {
+	t.Helper()
+
+	salt := "c2FsdHNhbHRzYWx0"
+
+	var key []byte
+	if kdf["kdfType"] == 1 {
+		hashedSalt := sha256.Sum256([]byte(salt))
+		key = argon2.IDKey([]byte(password), hashedSalt[:], uint32(kdf["kdfIterations"]), uint32(kdf["kdfMemory"])*1024, uint8(kdf["kdfParallelism"]), 32)
+	} else {
+		key = crypto.DeriveKeySha256([]byte(password), []byte(salt), kdf["kdfIterations"], 32)
+	}
+
+	encKey := make([]byte, 32)
+	macKey := make([]byte, 32)
+	io.ReadFull(hkdf.Expand(sha256.New, key, []byte("enc")), encKey)
+	io.ReadFull(hkdf.Expand(sha256.New, key, []byte("mac")), macKey)
+
+	encrypt := func(plaintext []byte) string {
+		ciphertext, err := crypto.EncryptAES(plaintext, encKey)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		iv, data := ciphertext[:16], ciphertext[16:]
+		hash := hmac.New(sha256.New, macKey)
+		hash.Write(iv)
+		hash.Write(data)
+
+		return "2." + base64.StdEncoding.EncodeToString(iv) + "|" + base64.StdEncoding.EncodeToString(data) + "|" + base64.StdEncoding.EncodeToString(hash.Sum(nil))
+	}
+
+	export := map[string]interface{}{
+		"encrypted":                    true,
+		"passwordProtected":            true,
+		"salt":                         salt,
+		"encKeyValidation_DO_NOT_EDIT": encrypt([]byte("validation")),
+		"data":                         encrypt([]byte(bitwardenSample)),
+	}
+
+	for name, value := range kdf {
+		export[name] = value
+	}
+
+	data, err := json.Marshal(export)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return data
+}
+
+func TestParseBitwardenJSON(t *testing.T) {
+	pbkdf2 := map[string]int{"kdfType": 0, "kdfIterations": 1000}
+	argon := map[string]int{"kdfType": 1, "kdfIterations": 2, "kdfMemory": 8, "kdfParallelism": 1}
+
+	tests := []struct {
+		name     string
+		data     []byte
+		password string
+		want     []*Item
+		err      error
+	}{
+		{
+			name: "plain",
+			data: []byte(bitwardenSample),
+			want: bitwardenItems,
+		},
+		{
+			name: "null entries",
+			data: []byte(`{"folders": [null], "items": [null, {"type": 1, "name": "GitHub", "login": {"username": "octo", "uris": [null, {"uri": "https://github.com"}]}, "fields": [null]}]}`),
+			want: []*Item{
+				{
+					Type:     TypeLogin,
+					Name:     "GitHub",
+					Username: "octo",
+					Uris:     []*Uri{{Uri: "https://github.com"}},
+				},
+			},
+		},
+		{
+			name:     "encrypted with pbkdf2",
+			data:     encryptBitwardenSample(t, "correct horse", pbkdf2),
+			password: "correct horse",
+			want:     bitwardenItems,
+		},
+		{
+			name:     "encrypted with argon2",
+			data:     encryptBitwardenSample(t, "correct horse", argon),
+			password: "correct horse",
+			want:     bitwardenItems,
+		},
+		{
+			name:     "wrong password",
+			data:     encryptBitwardenSample(t, "correct horse", pbkdf2),
+			password: "battery staple",
+			err:      ErrWrongPassword,
+		},
+		{
+			name: "missing password",
+			data: encryptBitwardenSample(t, "correct horse", pbkdf2),
+			err:  ErrPasswordRequired,
+		},
+		{
+			name:     "encrypted with the account key",
+			data:     []byte(`{"encrypted": true, "passwordProtected": false, "items": []}`),
+			password: "correct horse",
+			err:      ErrUnsupported,
+		},
+		{
+			name:     "too many pbkdf2 iterations",
+			data:     []byte(`{"encrypted": true, "passwordProtected": true, "kdfType": 0, "kdfIterations": 2000000000}`),
+			password: "correct horse",
+			err:      ErrKdfSettings,
+		},
+		{
+			name:     "too much argon2 memory",
+			data:     []byte(`{"encrypted": true, "passwordProtected": true, "kdfType": 1, "kdfIterations": 3, "kdfMemory": 4194304, "kdfParallelism": 4}`),
+			password: "correct horse",
+			err:      ErrKdfSettings,
+		},
+		{
+			name:     "too many argon2 iterations",
+			data:     []byte(`{"encrypted": true, "passwordProtected": true, "kdfType": 1, "kdfIterations": 100000, "kdfMemory": 64, "kdfParallelism": 4}`),
+			password: "correct horse",
+			err:      ErrKdfSettings,
+		},
+		{
+			name:     "unknown kdf",
+			data:     []byte(`{"encrypted": true, "passwordProtected": true, "kdfType": 7, "kdfIterations": 1}`),
+			password: "correct horse",
+			err:      ErrKdfSettings,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			items, err := ParseBitwardenJSON(test.data, test.password)
+			if !errors.Is(err, test.err) {
+				t.Fatalf("err = %v, want %v", err, test.err)
+			}
+
+			if test.err == nil {
+				checkItems(t, items, test.want)
+			}
+		})
+	}
+}
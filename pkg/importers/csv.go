@@ -0,0 +1,268 @@
+package importers
+
+import (
+	"bytes"
+	"encoding/csv"
+	"errors"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+// csvRow is a row of a CSV export, read by header name.
+type csvRow struct {
+	header map[string]int
+	names  []string
+	values []string
+}
+
+// readCSV reads a CSV export with a header row. Header names are matched
+// case-insensitively.
+func readCSV(data []byte) ([]*csvRow, error) {
+	data = bytes.TrimPrefix(data, []byte("\xef\xbb\xbf"))
+
+	reader := csv.NewReader(bytes.NewReader(data))
+	reader.FieldsPerRecord = -1
+	reader.LazyQuotes = true
+
+	records, err := reader.ReadAll()
+	if err != nil {
+		return nil, err
+	}
+
+	if len(records) == 0 {
+		return nil, errors.New("importers: the export has no header row")
+	}
+
+	header := make(map[string]int, len(records[0]))
+	names := make([]string, len(records[0]))
+	for n, name := range records[0] {
+		names[n] = strings.TrimSpace(name)
+		header[strings.ToLower(names[n])] = n
+	}
+
+	rows := make([]*csvRow, 0, len(records)-1)
+	for _, record := range records[1:] {
+		if len(record) == 1 && strings.TrimSpace(record[0]) == "" {
+			continue
+		}
+
+		rows = append(rows, &csvRow{header: header, names: names, values: record})
+	}
+
+	return rows, nil
+}
+
+// get returns the value of the first of the columns the row has.
+func (r *csvRow) get(columns ...string) string {
+	for _, column := range columns {
+		n, ok := r.header[column]
+		if ok && n < len(r.values) {
+			return strings.TrimSpace(r.values[n])
+		}
+	}
+
+	return ""
+}
+
+// rest adds the values of the columns not in known to the item as fields.
+func (r *csvRow) rest(item *Item, known ...string) {
+	skip := make(map[string]bool, len(known))
+	for _, column := range known {
+		skip[column] = true
+	}
+
+	for n, name := range r.names {
+		if skip[strings.ToLower(name)] || n >= len(r.values) {
+			continue
+		}
+
+		item.addField(FieldText, name, strings.TrimSpace(r.values[n]))
+	}
+}
+
+// ParseLastPassCSV reads a LastPass CSV export. Secure notes are the rows
+// with the URL http://sn; credit cards among them become cards.
+func ParseLastPassCSV(data []byte) ([]*Item, error) {
+	rows, err := readCSV(data)
+	if err != nil {
+		return nil, err
+	}
+
+	items := make([]*Item, 0, len(rows))
+	for _, row := range rows {
+		name := row.get("name")
+		notes := row.get("extra")
+
+		var item *Item
+		if row.get("url") == "http://sn" {
+			item = lastPassNote(name, notes)
+		} else {
+			item = newLogin(name, row.get("username"), row.get("password"), []string{row.get("url")}, notes)
+			item.addField(FieldHidden, "TOTP", row.get("totp"))
+		}
+
+		item.Folder = strings.ReplaceAll(row.get("grouping"), "\\", "/")
+		item.Favorite = row.get("fav") == "1"
+		items = append(items, item)
+	}
+
+	return finishAll(items), nil
+}
+
+// lastPassNote reads a LastPass secure note. Notes of a type, such as credit
+// cards, are lines of "Key:Value" starting with NoteType.
+func lastPassNote(name string, notes string) *Item {
+	if !strings.HasPrefix(notes, "NoteType:") {
+		return newSecureNote(name, notes)
+	}
+
+	values := make(map[string]string)
+	var order []string
+	var rest string
+
+	lines := strings.Split(notes, "\n")
+	for n, line := range lines {
+		key, value, ok := strings.Cut(line, ":")
+		if !ok {
+			continue
+		}
+
+		// Notes are last and may span lines.
+		if key == "Notes" {
+			rest = strings.Join(append([]string{value}, lines[n+1:]...), "\n")
+			break
+		}
+
+		// Empty dates are a lone comma.
+		if value = strings.TrimSpace(value); value == "," {
+			value = ""
+		}
+
+		values[key] = value
+		order = append(order, key)
+	}
+
+	item := &Item{Type: TypeSecureNote, Name: name}
+
+	if values["NoteType"] == "Credit Card" {
+		month, year, _ := strings.Cut(values["Expiration Date"], ",")
+
+		item.Type = TypeCard
+		item.Card = &Card{
+			CardholderName: values["Name on Card"],
+			Brand:          values["Type"],
+			Number:         values["Number"],
+			ExpMonth:       lastPassMonth(month),
+			ExpYear:        year,
+			Code:           values["Security Code"],
+		}
+
+		delete(values, "Name on Card")
+		delete(values, "Type")
+		delete(values, "Number")
+		delete(values, "Expiration Date")
+		delete(values, "Security Code")
+	}
+
+	for _, key := range order {
+		if key != "NoteType" && key != "Language" {
+			item.addField(FieldText, key, values[key])
+		}
+	}
+
+	item.addNotes(rest)
+	return item
+}
+
+var lastPassMonths = []string{"January", "February", "March", "April", "May", "June", "July", "August", "September", "October", "November", "December"}
+
+func lastPassMonth(month string) string {
+	for n, name := range lastPassMonths {
+		if strings.EqualFold(month, name) {
+			return strconv.Itoa(n + 1)
+		}
+	}
+
+	return month
+}
+
+// ParseChromeCSV reads the passwords exported by Chrome and other Chromium
+// based browsers.
+func ParseChromeCSV(data []byte) ([]*Item, error) {
+	rows, err := readCSV(data)
+	if err != nil {
+		return nil, err
+	}
+
+	items := make([]*Item, 0, len(rows))
+	for _, row := range rows {
+		items = append(items, newLogin(row.get("name"), row.get("username"), row.get("password"), []string{row.get("url")}, row.get("note")))
+	}
+
+	return finishAll(items), nil
+}
+
+// ParseFirefoxCSV reads the logins exported by Firefox. Firefox has no names
+// for logins, so they are named after their host.
+func ParseFirefoxCSV(data []byte) ([]*Item, error) {
+	rows, err := readCSV(data)
+	if err != nil {
+		return nil, err
+	}
+
+	items := make([]*Item, 0, len(rows))
+	for _, row := range rows {
+		uri := row.get("url")
+		if uri == "chrome://FirefoxAccounts" {
+			continue
+		}
+
+		items = append(items, newLogin(hostOf(uri), row.get("username"), row.get("password"), []string{uri}, ""))
+	}
+
+	return finishAll(items), nil
+}
+
+// ParseOnePasswordCSV reads a 1Password CSV export. Columns 1Password has no
+// counterpart for in SafePass become fields.
+func ParseOnePasswordCSV(data []byte) ([]*Item, error) {
+	rows, err := readCSV(data)
+	if err != nil {
+		return nil, err
+	}
+
+	known := []string{"title", "name", "url", "urls", "website", "username", "password", "notes", "notesplain", "otpauth", "one-time password", "favorite", "archived", "tags", "type"}
+
+	items := make([]*Item, 0, len(rows))
+	for _, row := range rows {
+		uris := strings.Split(row.get("url", "urls", "website"), ",")
+
+		item := newLogin(row.get("title", "name"), row.get("username"), row.get("password"), uris, row.get("notes", "notesplain"))
+		item.addField(FieldHidden, "TOTP", row.get("otpauth", "one-time password"))
+		item.Favorite = strings.EqualFold(row.get("favorite"), "true") || row.get("favorite") == "1"
+
+		if tags := row.get("tags"); tags != "" {
+			item.Folder, _, _ = strings.Cut(tags, ",")
+		}
+
+		row.rest(item, known...)
+		items = append(items, item)
+	}
+
+	return finishAll(items), nil
+}
+
+// hostOf returns the host of a URI, which may lack a scheme.
+func hostOf(uri string) string {
+	if !strings.Contains(uri, "://") {
+		uri = "https://" + uri
+	}
+
+	parsed, err := url.Parse(uri)
+	if err != nil {
+		return ""
+	}
+
+	return parsed.Hostname()
+}
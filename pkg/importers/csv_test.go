@@ -0,0 +1,117 @@
+package importers
+
+import "testing"
+
+func TestParseCSV(t *testing.T) {
+	tests := []struct {
+		name  string
+		parse func([]byte) ([]*Item, error)
+		data  string
+		want  []*Item
+	}{
+		{
+			name:  "lastpass",
+			parse: ParseLastPassCSV,
+			data: "url,username,password,totp,extra,name,grouping,fav\n" +
+				"https://example.com,alice,pw1,,some notes,Example,Work\\Dev,1\n" +
+				"http://sn,,,,\"NoteType:Credit Card\nLanguage:en-US\nName on Card:Jane Doe\nType:Visa\nNumber:4111111111111111\nSecurity Code:123\nStart Date:,\nExpiration Date:April,2030\nNotes:first line\nsecond line\",My Visa,,0\n" +
+				"http://sn,,,,plain note,Note,,0\n",
+			want: []*Item{
+				{
+					Type:     TypeLogin,
+					Name:     "Example",
+					Folder:   "Work/Dev",
+					Favorite: true,
+					Username: "alice",
+					Password: "pw1",
+					Uris:     []*Uri{{Uri: "https://example.com"}},
+					Fields:   []*Field{{Type: FieldText, Name: "Notes", Value: "some notes"}},
+				},
+				{
+					Type:   TypeCard,
+					Name:   "My Visa",
+					Card:   &Card{CardholderName: "Jane Doe", Brand: "Visa", Number: "4111111111111111", ExpMonth: "4", ExpYear: "2030", Code: "123"},
+					Fields: []*Field{{Type: FieldText, Name: "Notes", Value: "first line\nsecond line"}},
+				},
+				{
+					Type:       TypeSecureNote,
+					Name:       "Note",
+					SecureNote: &SecureNote{Notes: "plain note"},
+				},
+			},
+		},
+		{
+			name:  "chrome",
+			parse: ParseChromeCSV,
+			data: "name,url,username,password,note\n" +
+				"Example,https://example.com/login,alice,pw,\n" +
+				",https://bare.example.org,bob,pw2,hello\n",
+			want: []*Item{
+				{
+					Type:     TypeLogin,
+					Name:     "Example",
+					Username: "alice",
+					Password: "pw",
+					Uris:     []*Uri{{Uri: "https://example.com/login"}},
+				},
+				{
+					Type:     TypeLogin,
+					Name:     "bare.example.org",
+					Username: "bob",
+					Password: "pw2",
+					Uris:     []*Uri{{Uri: "https://bare.example.org"}},
+					Fields:   []*Field{{Type: FieldText, Name: "Notes", Value: "hello"}},
+				},
+			},
+		},
+		{
+			name:  "firefox",
+			parse: ParseFirefoxCSV,
+			data: "\"url\",\"username\",\"password\",\"httpRealm\",\"formActionOrigin\",\"guid\",\"timeCreated\",\"timeLastUsed\",\"timePasswordChanged\"\n" +
+				"\"https://www.mozilla.org\",\"alice\",\"pw\",\"\",\"https://www.mozilla.org\",\"{1}\",\"1\",\"1\",\"1\"\n" +
+				"\"chrome://FirefoxAccounts\",\"x\",\"y\",\"\",\"\",\"{2}\",\"1\",\"1\",\"1\"\n",
+			want: []*Item{
+				{
+					Type:     TypeLogin,
+					Name:     "www.mozilla.org",
+					Username: "alice",
+					Password: "pw",
+					Uris:     []*Uri{{Uri: "https://www.mozilla.org"}},
+				},
+			},
+		},
+		{
+			name:  "1password",
+			parse: ParseOnePasswordCSV,
+			data: "\xef\xbb\xbfTitle,Url,Username,Password,OTPAuth,Favorite,Archived,Tags,Notes,Custom\n" +
+				"Example,\"https://a.example.com,https://b.example.com\",alice,pw,otpauth://totp/a,true,false,\"Work,Shared\",n1,extra\n",
+			want: []*Item{
+				{
+					Type:     TypeLogin,
+					Name:     "Example",
+					Folder:   "Work",
+					Favorite: true,
+					Username: "alice",
+					Password: "pw",
+					Uris:     []*Uri{{Uri: "https://a.example.com"}, {Uri: "https://b.example.com"}},
+					Fields: []*Field{
+						{Type: FieldText, Name: "Notes", Value: "n1"},
+						{Type: FieldHidden, Name: "TOTP", Value: "otpauth://totp/a"},
+						{Type: FieldText, Name: "Custom", Value: "extra"},
+					},
+				},
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			items, err := test.parse([]byte(test.data))
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			checkItems(t, items, test.want)
+		})
+	}
+}
@@ -0,0 +1,300 @@
+// Package importers reads the exports of other password managers into vault
+// items. The server never sees plaintext, so the parsers run on the client:
+// it parses an export, encrypts the items and sends them to the import
+// endpoint.
+package importers
+
+import (
+	"errors"
+	"strings"
+)
+
+// The export formats Parse reads.
+const (
+	FormatBitwardenJSON   = "bitwarden_json"
+	FormatOnePassword1PUX = "1password_1pux"
+	FormatOnePasswordCSV  = "1password_csv"
+	FormatLastPassCSV     = "lastpass_csv"
+	FormatKeePassXML      = "keepass_xml"
+	FormatChromeCSV       = "chrome_csv"
+	FormatFirefoxCSV      = "firefox_csv"
+)
+
+// The item types and custom field types of SafePass items.
+const (
+	TypeLogin      = "login"
+	TypeSecureNote = "secure_note"
+	TypeCard       = "card"
+	TypeIdentity   = "identity"
+
+	FieldText    = "text"
+	FieldHidden  = "hidden"
+	FieldBoolean = "boolean"
+)
+
+var (
+	ErrUnknownFormat    = errors.New("importers: unknown export format")
+	ErrPasswordRequired = errors.New("importers: the export is encrypted and needs its password")
+	ErrWrongPassword    = errors.New("importers: wrong password or corrupted export")
+	ErrUnsupported      = errors.New("importers: exports encrypted with the account key cannot be read, export with a password instead")
+	ErrKdfSettings      = errors.New("importers: the key derivation settings of the export are not supported")
+)
+
+// Item is a vault item read from an export, in plaintext. It mirrors the
+// SafePass item: logins have a username, a password and URIs, the other types
+// their payload, and anything else is kept in custom fields. Notes of items
+// other than secure notes and one-time password secrets become fields, since
+// SafePass items have no place for them.
+type Item struct {
+	Type     string `json:"type"`
+	Name     string `json:"name"`
+	Folder   string `json:"folder,omitempty"`
+	Favorite bool   `json:"favorite,omitempty"`
+
+	Username string `json:"username,omitempty"`
+	Password string `json:"password,omitempty"`
+	Uris     []*Uri `json:"uris,omitempty"`
+
+	SecureNote *SecureNote `json:"secure_note,omitempty"`
+	Card       *Card       `json:"card,omitempty"`
+	Identity   *Identity   `json:"identity,omitempty"`
+
+	Fields []*Field `json:"fields,omitempty"`
+}
+
+// Uri is a URI of a login. Match is one of the SafePass URI match types, or
+// empty for the client's default.
+type Uri struct {
+	Uri   string `json:"uri"`
+	Match string `json:"match,omitempty"`
+}
+
+type SecureNote struct {
+	Notes string `json:"notes"`
+}
+
+type Card struct {
+	CardholderName string `json:"cardholder_name,omitempty"`
+	Brand          string `json:"brand,omitempty"`
+	Number         string `json:"number"`
+	ExpMonth       string `json:"exp_month,omitempty"`
+	ExpYear        string `json:"exp_year,omitempty"`
+	Code           string `json:"code,omitempty"`
+}
+
+type Identity struct {
+	Title          string `json:"title,omitempty"`
+	FirstName      string `json:"first_name"`
+	MiddleName     string `json:"middle_name,omitempty"`
+	LastName       string `json:"last_name"`
+	Email          string `json:"email,omitempty"`
+	Phone          string `json:"phone,omitempty"`
+	Address        string `json:"address,omitempty"`
+	Company        string `json:"company,omitempty"`
+	SSN            string `json:"ssn,omitempty"`
+	PassportNumber string `json:"passport_number,omitempty"`
+	LicenseNumber  string `json:"license_number,omitempty"`
+}
+
+type Field struct {
+	Type  string `json:"type"`
+	Name  string `json:"name"`
+	Value string `json:"value"`
+}
+
+// Parse reads an export in the given format. Password is only used for
+// encrypted exports.
+func Parse(format string, data []byte, password string) ([]*Item, error) {
+	switch format {
+	case FormatBitwardenJSON:
+		return ParseBitwardenJSON(data, password)
+	case FormatOnePassword1PUX:
+		return ParseOnePassword1PUX(data)
+	case FormatOnePasswordCSV:
+		return ParseOnePasswordCSV(data)
+	case FormatLastPassCSV:
+		return ParseLastPassCSV(data)
+	case FormatKeePassXML:
+		return ParseKeePassXML(data)
+	case FormatChromeCSV:
+		return ParseChromeCSV(data)
+	case FormatFirefoxCSV:
+		return ParseFirefoxCSV(data)
+	}
+
+	return nil, ErrUnknownFormat
+}
+
+// Folders returns the distinct folders of the items in the order they first
+// appear, for creating them before the items.
+func Folders(items []*Item) []string {
+	seen := make(map[string]bool)
+
+	var folders []string
+	for _, item := range items {
+		if item.Folder != "" && !seen[item.Folder] {
+			seen[item.Folder] = true
+			folders = append(folders, item.Folder)
+		}
+	}
+
+	return folders
+}
+
+// newLogin returns a login, or a secure note when there are no credentials
+// but notes.
+func newLogin(name string, username string, password string, uris []string, notes string) *Item {
+	item := &Item{
+		Type:     TypeLogin,
+		Name:     name,
+		Username: username,
+		Password: password,
+	}
+
+	for _, uri := range uris {
+		item.addUri(uri, "")
+	}
+
+	if username == "" && password == "" && len(item.Uris) == 0 && strings.TrimSpace(notes) != "" {
+		return newSecureNote(name, notes)
+	}
+
+	item.addNotes(notes)
+	return item
+}
+
+func newSecureNote(name string, notes string) *Item {
+	return &Item{
+		Type:       TypeSecureNote,
+		Name:       name,
+		SecureNote: &SecureNote{Notes: notes},
+	}
+}
+
+func (i *Item) addUri(uri string, match string) {
+	uri = strings.TrimSpace(uri)
+	if uri == "" {
+		return
+	}
+
+	i.Uris = append(i.Uris, &Uri{Uri: uri, Match: match})
+}
+
+func (i *Item) addField(fieldType string, name string, value string) {
+	if value == "" {
+		return
+	}
+
+	i.Fields = append(i.Fields, &Field{Type: fieldType, Name: name, Value: value})
+}
+
+// addNotes keeps notes in the secure note payload, or in a field for other
+// types.
+func (i *Item) addNotes(notes string) {
+	notes = strings.TrimSpace(notes)
+	if notes == "" {
+		return
+	}
+
+	if i.Type == TypeSecureNote {
+		if i.SecureNote == nil {
+			i.SecureNote = &SecureNote{}
+		}
+
+		if i.SecureNote.Notes != "" {
+			i.SecureNote.Notes += "\n\n"
+		}
+
+		i.SecureNote.Notes += notes
+		return
+	}
+
+	i.addField(FieldText, "Notes", notes)
+}
+
+// finish fills in what SafePass requires of an item: a name, and the payload
+// of its type. Cards without a number and identities without a name fall back
+// to a secure note, with what there is in fields.
+func (i *Item) finish() {
+	if i.Name == "" {
+		i.Name = defaultName(i)
+	}
+
+	switch {
+	case i.Type == TypeCard && (i.Card == nil || i.Card.Number == ""):
+		i.Type = TypeSecureNote
+		i.cardFields()
+	case i.Type == TypeIdentity && (i.Identity == nil || (i.Identity.FirstName == "" && i.Identity.LastName == "")):
+		i.Type = TypeSecureNote
+		i.identityFields()
+	}
+
+	if i.Type == TypeSecureNote && i.SecureNote == nil {
+		i.SecureNote = &SecureNote{Notes: i.takeNotes()}
+	}
+}
+
+// takeNotes removes the notes field and returns its value.
+func (i *Item) takeNotes() string {
+	for n, field := range i.Fields {
+		if field.Name == "Notes" && field.Type == FieldText {
+			i.Fields = append(i.Fields[:n], i.Fields[n+1:]...)
+			return field.Value
+		}
+	}
+
+	return ""
+}
+
+func (i *Item) cardFields() {
+	if i.Card == nil {
+		return
+	}
+
+	i.addField(FieldText, "Cardholder name", i.Card.CardholderName)
+	i.addField(FieldText, "Brand", i.Card.Brand)
+	i.addField(FieldHidden, "Number", i.Card.Number)
+	i.addField(FieldText, "Expiration month", i.Card.ExpMonth)
+	i.addField(FieldText, "Expiration year", i.Card.ExpYear)
+	i.addField(FieldHidden, "Security code", i.Card.Code)
+	i.Card = nil
+}
+
+func (i *Item) identityFields() {
+	if i.Identity == nil {
+		return
+	}
+
+	i.addField(FieldText, "Title", i.Identity.Title)
+	i.addField(FieldText, "Middle name", i.Identity.MiddleName)
+	i.addField(FieldText, "Email", i.Identity.Email)
+	i.addField(FieldText, "Phone", i.Identity.Phone)
+	i.addField(FieldText, "Address", i.Identity.Address)
+	i.addField(FieldText, "Company", i.Identity.Company)
+	i.addField(FieldHidden, "SSN", i.Identity.SSN)
+	i.addField(FieldHidden, "Passport number", i.Identity.PassportNumber)
+	i.addField(FieldHidden, "License number", i.Identity.LicenseNumber)
+	i.Identity = nil
+}
+
+func defaultName(item *Item) string {
+	if len(item.Uris) > 0 {
+		if host := hostOf(item.Uris[0].Uri); host != "" {
+			return host
+		}
+	}
+
+	if item.Username != "" {
+		return item.Username
+	}
+
+	return "Untitled"
+}
+
+func finishAll(items []*Item) []*Item {
+	for _, item := range items {
+		item.finish()
+	}
+
+	return items
+}
@@ -0,0 +1,63 @@
+package importers
+
+import (
+	"encoding/json"
+	"errors"
+	"reflect"
+	"testing"
+)
+
+// checkItems compares parsed items with the expected ones and prints both as
+// JSON when they differ.
+func checkItems(t *testing.T, got []*Item, want []*Item) {
+	t.Helper()
+
+	if !reflect.DeepEqual(got, want) {
+		gotJSON, _ := json.MarshalIndent(got, "", "  ")
+		wantJSON, _ := json.MarshalIndent(want, "", "  ")
+		t.Errorf("items differ\ngot:  %s\nwant: %s", gotJSON, wantJSON)
+	}
+}
+
+func TestParse(t *testing.T) {
+	chrome := []byte("name,url,username,password,note\nExample,https://example.com,alice,pw,\n")
+
+	tests := []struct {
+		name   string
+		format string
+		data   []byte
+		count  int
+		err    error
+	}{
+		{"known format", FormatChromeCSV, chrome, 1, nil},
+		{"unknown format", "dashlane_csv", chrome, 0, ErrUnknownFormat},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			items, err := Parse(test.format, test.data, "")
+			if !errors.Is(err, test.err) {
+				t.Fatalf("err = %v, want %v", err, test.err)
+			}
+
+			if len(items) != test.count {
+				t.Errorf("got %d items, want %d", len(items), test.count)
+			}
+		})
+	}
+}
+
+func TestFolders(t *testing.T) {
+	items := []*Item{
+		{Name: "a", Folder: "Work"},
+		{Name: "b"},
+		{Name: "c", Folder: "Home"},
+		{Name: "d", Folder: "Work"},
+	}
+
+	got := Folders(items)
+	want := []string{"Work", "Home"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Folders = %v, want %v", got, want)
+	}
+}
@@ -0,0 +1,102 @@
+package importers
+
+import (
+	"encoding/xml"
+	"strings"
+)
+
+type keePassFile struct {
+	Meta struct {
+		RecycleBinEnabled string `xml:"RecycleBinEnabled"`
+		RecycleBinUUID    string `xml:"RecycleBinUUID"`
+	} `xml:"Meta"`
+	Root struct {
+		Groups []*keePassGroup `xml:"Group"`
+	} `xml:"Root"`
+}
+
+type keePassGroup struct {
+	UUID    string          `xml:"UUID"`
+	Name    string          `xml:"Name"`
+	Entries []*keePassEntry `xml:"Entry"`
+	Groups  []*keePassGroup `xml:"Group"`
+}
+
+type keePassEntry struct {
+	Strings []*struct {
+		Key   string `xml:"Key"`
+		Value struct {
+			Text            string `xml:",chardata"`
+			ProtectInMemory string `xml:"ProtectInMemory,attr"`
+		} `xml:"Value"`
+	} `xml:"String"`
+}
+
+// ParseKeePassXML reads a KeePass 2 XML export. Groups below the root group
+// become folders named after their path, and the recycle bin is left out.
+func ParseKeePassXML(data []byte) ([]*Item, error) {
+	var file keePassFile
+	err := xml.Unmarshal(data, &file)
+	if err != nil {
+		return nil, err
+	}
+
+	recycleBin := ""
+	if !strings.EqualFold(file.Meta.RecycleBinEnabled, "false") {
+		recycleBin = file.Meta.RecycleBinUUID
+	}
+
+	var items []*Item
+	for _, root := range file.Root.Groups {
+		items = keePassItems(items, root, "", recycleBin)
+	}
+
+	return finishAll(items), nil
+}
+
+func keePassItems(items []*Item, group *keePassGroup, folder string, recycleBin string) []*Item {
+	for _, entry := range group.Entries {
+		item := keePassItem(entry)
+		item.Folder = folder
+		items = append(items, item)
+	}
+
+	for _, child := range group.Groups {
+		if recycleBin != "" && child.UUID == recycleBin {
+			continue
+		}
+
+		path := child.Name
+		if folder != "" {
+			path = folder + "/" + child.Name
+		}
+
+		items = keePassItems(items, child, path, recycleBin)
+	}
+
+	return items
+}
+
+func keePassItem(entry *keePassEntry) *Item {
+	values := make(map[string]string, len(entry.Strings))
+	for _, value := range entry.Strings {
+		values[value.Key] = value.Value.Text
+	}
+
+	item := newLogin(values["Title"], values["UserName"], values["Password"], []string{values["URL"]}, values["Notes"])
+
+	for _, value := range entry.Strings {
+		switch value.Key {
+		case "Title", "UserName", "Password", "URL", "Notes":
+			continue
+		}
+
+		if strings.EqualFold(value.Value.ProtectInMemory, "true") {
+			item.addField(FieldHidden, value.Key, value.Value.Text)
+		} else {
+			item.addField(FieldText, value.Key, value.Value.Text)
+		}
+	}
+
+	return item
+}
@@ -0,0 +1,116 @@
+package importers
+
+import "testing"
+
+const keePassSample = `<?xml version="1.0" encoding="utf-8" standalone="yes"?>
+<KeePassFile>
+  <Meta>
+    <RecycleBinEnabled>True</RecycleBinEnabled>
+    <RecycleBinUUID>bin</RecycleBinUUID>
+  </Meta>
+  <Root>
+    <Group>
+      <UUID>root</UUID>
+      <Name>Database</Name>
+      <Entry>
+        <String><Key>Title</Key><Value>Root entry</Value></String>
+        <String><Key>UserName</Key><Value>root</Value></String>
+        <String><Key>Password</Key><Value ProtectInMemory="True">pw</Value></String>
+      </Entry>
+      <Group>
+        <UUID>g1</UUID>
+        <Name>Email</Name>
+        <Entry>
+          <String><Key>Title</Key><Value>Mail</Value></String>
+          <String><Key>UserName</Key><Value>alice</Value></String>
+          <String><Key>Password</Key><Value ProtectInMemory="True">p</Value></String>
+          <String><Key>URL</Key><Value>https://mail.example.com</Value></String>
+          <String><Key>Notes</Key><Value>n</Value></String>
+          <String><Key>Recovery</Key><Value ProtectInMemory="True">code</Value></String>
+          <String><Key>Hint</Key><Value>h</Value></String>
+        </Entry>
+        <Group>
+          <UUID>g2</UUID>
+          <Name>Old</Name>
+          <Entry>
+            <String><Key>Title</Key><Value>Nested</Value></String>
+            <String><Key>Notes</Key><Value>only notes</Value></String>
+          </Entry>
+        </Group>
+      </Group>
+      <Group>
+        <UUID>bin</UUID>
+        <Name>Recycle Bin</Name>
+        <Entry>
+          <String><Key>Title</Key><Value>Deleted</Value></String>
+          <String><Key>Password</Key><Value>x</Value></String>
+        </Entry>
+      </Group>
+    </Group>
+  </Root>
+</KeePassFile>`
+
+func TestParseKeePassXML(t *testing.T) {
+	tests := []struct {
+		name    string
+		data    string
+		want    []*Item
+		wantErr bool
+	}{
+		{
+			name: "export",
+			data: keePassSample,
+			want: []*Item{
+				{
+					Type:     TypeLogin,
+					Name:     "Root entry",
+					Username: "root",
+					Password: "pw",
+				},
+				{
+					Type:     TypeLogin,
+					Name:     "Mail",
+					Folder:   "Email",
+					Username: "alice",
+					Password: "p",
+					Uris:     []*Uri{{Uri: "https://mail.example.com"}},
+					Fields: []*Field{
+						{Type: FieldText, Name: "Notes", Value: "n"},
+						{Type: FieldHidden, Name: "Recovery", Value: "code"},
+						{Type: FieldText, Name: "Hint", Value: "h"},
+					},
+				},
+				{
+					Type:       TypeSecureNote,
+					Name:       "Nested",
+					Folder:     "Email/Old",
+					SecureNote: &SecureNote{Notes: "only notes"},
+				},
+			},
+		},
+		{
+			name:    "not xml",
+			data:    "Title,Password\nx,y\n",
+			wantErr: true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			items, err := ParseKeePassXML([]byte(test.data))
+			if test.wantErr {
+				if err == nil {
+					t.Fatal("expected an error")
+				}
+
+				return
+			}
+
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			checkItems(t, items, test.want)
+		})
+	}
+}
@@ -0,0 +1,310 @@
+package importers
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+)
+
+type onePasswordExport struct {
+	Accounts []*struct {
+		Vaults []*struct {
+			Attrs struct {
+				Name string `json:"name"`
+			} `json:"attrs"`
+			Items []*onePasswordItem `json:"items"`
+		} `json:"vaults"`
+	} `json:"accounts"`
+}
+
+type onePasswordItem struct {
+	FavIndex     int    `json:"favIndex"`
+	State        string `json:"state"`
+	CategoryUuid string `json:"categoryUuid"`
+
+	Overview struct {
+		Title string `json:"title"`
+		Url   string `json:"url"`
+		Urls  []*struct {
+			Url string `json:"url"`
+		} `json:"urls"`
+	} `json:"overview"`
+
+	Details struct {
+		LoginFields []*struct {
+			Value       string `json:"value"`
+			Name        string `json:"name"`
+			FieldType   string `json:"fieldType"`
+			Designation string `json:"designation"`
+		} `json:"loginFields"`
+		NotesPlain string `json:"notesPlain"`
+		Password   string `json:"password"`
+		Sections   []*struct {
+			Title  string `json:"title"`
+			Fields []*struct {
+				Title string                     `json:"title"`
+				ID    string                     `json:"id"`
+				Value map[string]json.RawMessage `json:"value"`
+			} `json:"fields"`
+		} `json:"sections"`
+	} `json:"details"`
+}
+
+// onePasswordMaxDataSize is the largest export.data read from a 1PUX archive,
+// in bytes.
+const onePasswordMaxDataSize = 256 << 20
+
+// The 1Password categories with a SafePass counterpart. Items of other
+// categories become secure notes.
+const (
+	onePasswordLogin      = "001"
+	onePasswordCard       = "002"
+	onePasswordSecureNote = "003"
+	onePasswordIdentity   = "004"
+	onePasswordPassword   = "005"
+)
+
+// ParseOnePassword1PUX reads a 1Password 1PUX export, a zip archive holding
+// the items in export.data. Each 1Password vault becomes a folder; items in
+// the trash are left out.
+func ParseOnePassword1PUX(data []byte) ([]*Item, error) {
+	archive, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		return nil, err
+	}
+
+	var export onePasswordExport
+	found := false
+
+	for _, file := range archive.File {
+		if file.Name != "export.data" {
+			continue
+		}
+
+		reader, err := file.Open()
+		if err != nil {
+			return nil, err
+		}
+
+		content, err := io.ReadAll(io.LimitReader(reader, onePasswordMaxDataSize+1))
+		reader.Close()
+		if err != nil {
+			return nil, err
+		}
+
+		if len(content) > onePasswordMaxDataSize {
+			return nil, errors.New("importers: export.data is too large")
+		}
+
+		err = json.Unmarshal(content, &export)
+		if err != nil {
+			return nil, err
+		}
+
+		found = true
+		break
+	}
+
+	if !found {
+		return nil, errors.New("importers: the archive has no export.data")
+	}
+
+	var items []*Item
+	for _, account := range export.Accounts {
+		if account == nil {
+			continue
+		}
+
+		for _, vault := range account.Vaults {
+			if vault == nil {
+				continue
+			}
+
+			for _, opItem := range vault.Items {
+				if opItem == nil || opItem.State == "trashed" {
+					continue
+				}
+
+				item := onePasswordItemToItem(opItem)
+				item.Folder = vault.Attrs.Name
+				items = append(items, item)
+			}
+		}
+	}
+
+	return finishAll(items), nil
+}
+
+func onePasswordItemToItem(opItem *onePasswordItem) *Item {
+	item := &Item{
+		Type:     TypeSecureNote,
+		Name:     opItem.Overview.Title,
+		Favorite: opItem.FavIndex > 0,
+	}
+
+	switch opItem.CategoryUuid {
+	case onePasswordLogin, onePasswordPassword:
+		item.Type = TypeLogin
+		item.Password = opItem.Details.Password
+
+		for _, field := range opItem.Details.LoginFields {
+			if field == nil {
+				continue
+			}
+
+			switch field.Designation {
+			case "username":
+				item.Username = field.Value
+			case "password":
+				item.Password = field.Value
+			}
+		}
+
+		item.addUri(opItem.Overview.Url, "")
+		for _, uri := range opItem.Overview.Urls {
+			if uri != nil && uri.Url != opItem.Overview.Url {
+				item.addUri(uri.Url, "")
+			}
+		}
+	case onePasswordCard:
+		item.Type = TypeCard
+		item.Card = &Card{}
+	case onePasswordIdentity:
+		item.Type = TypeIdentity
+		item.Identity = &Identity{}
+	}
+
+	for _, section := range opItem.Details.Sections {
+		if section == nil {
+			continue
+		}
+
+		for _, field := range section.Fields {
+			if field == nil {
+				continue
+			}
+
+			kind, value := onePasswordValue(field.Value)
+			if value == "" || item.setOnePasswordField(field.ID, value) {
+				continue
+			}
+
+			name := field.Title
+			if name == "" {
+				name = section.Title
+			}
+
+			switch kind {
+			case "concealed", "totp", "creditCardNumber":
+				item.addField(FieldHidden, name, value)
+			default:
+				item.addField(FieldText, name, value)
+			}
+		}
+	}
+
+	item.addNotes(opItem.Details.NotesPlain)
+	return item
+}
+
+// setOnePasswordField puts the value of a field of a card or identity in the
+// payload, and reports whether it did.
+func (i *Item) setOnePasswordField(id string, value string) bool {
+	if card := i.Card; card != nil {
+		switch id {
+		case "cardholder":
+			card.CardholderName = value
+		case "type":
+			card.Brand = value
+		case "ccnum":
+			card.Number = value
+		case "cvv":
+			card.Code = value
+		case "expiry":
+			// Expiry dates are YYYYMM.
+			if len(value) == 6 {
+				card.ExpYear, card.ExpMonth = value[:4], strings.TrimPrefix(value[4:], "0")
+			} else {
+				return false
+			}
+		default:
+			return false
+		}
+
+		return true
+	}
+
+	if identity := i.Identity; identity != nil {
+		switch id {
+		case "firstname":
+			identity.FirstName = value
+		case "initial":
+			identity.MiddleName = value
+		case "lastname":
+			identity.LastName = value
+		case "email":
+			identity.Email = value
+		case "defphone":
+			identity.Phone = value
+		case "address":
+			identity.Address = value
+		case "company":
+			identity.Company = value
+		default:
+			return false
+		}
+
+		return true
+	}
+
+	return false
+}
+
+// onePasswordValue returns the kind and the text of a 1Password field value,
+// an object with one member named after the kind.
+func onePasswordValue(value map[string]json.RawMessage) (string, string) {
+	for kind, raw := range value {
+		switch kind {
+		case "email":
+			var email struct {
+				EmailAddress string `json:"email_address"`
+			}
+			if json.Unmarshal(raw, &email) == nil {
+				return kind, email.EmailAddress
+			}
+		case "address":
+			var address struct {
+				Street  string `json:"street"`
+				City    string `json:"city"`
+				State   string `json:"state"`
+				Zip     string `json:"zip"`
+				Country string `json:"country"`
+			}
+			if json.Unmarshal(raw, &address) == nil {
+				return kind, joinNonEmpty(", ", address.Street, address.City, address.State, address.Zip, address.Country)
+			}
+		case "date":
+			var seconds int64
+			if json.Unmarshal(raw, &seconds) == nil && seconds != 0 {
+				return kind, time.Unix(seconds, 0).UTC().Format("2006-01-02")
+			}
+		case "monthYear":
+			var monthYear int
+			if json.Unmarshal(raw, &monthYear) == nil && monthYear != 0 {
+				return kind, strconv.Itoa(monthYear)
+			}
+		}
+
+		var text string
+		if json.Unmarshal(raw, &text) == nil {
+			return kind, text
+		}
+	}
+
+	return "", ""
+}
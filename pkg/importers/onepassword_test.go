@@ -0,0 +1,179 @@
+package importers
+
+import (
+	"archive/zip"
+	"bytes"
+	"testing"
+)
+
+const onePasswordSample = `{
+  "accounts": [{
+    "vaults": [{
+      "attrs": {"name": "Private"},
+      "items": [
+        {
+          "favIndex": 1, "state": "active", "categoryUuid": "001",
+          "overview": {
+            "title": "Example", "url": "https://example.com",
+            "urls": [{"url": "https://example.com"}, {"url": "https://login.example.com"}]
+          },
+          "details": {
+            "loginFields": [
+              {"value": "alice", "designation": "username"},
+              {"value": "s3cret", "designation": "password"}
+            ],
+            "notesPlain": "note",
+            "sections": [{"title": "Security", "fields": [{"title": "PIN", "id": "pin", "value": {"concealed": "9999"}}]}]
+          }
+        },
+        {"state": "trashed", "categoryUuid": "001", "overview": {"title": "Old"}},
+        {
+          "categoryUuid": "002", "overview": {"title": "Amex"},
+          "details": {"sections": [{"fields": [
+            {"title": "number", "id": "ccnum", "value": {"creditCardNumber": "378282246310005"}},
+            {"id": "expiry", "value": {"monthYear": 203012}},
+            {"id": "cvv", "value": {"concealed": "1234"}},
+            {"id": "cardholder", "value": {"string": "Bob"}}
+          ]}]}
+        },
+        {
+          "categoryUuid": "004", "overview": {"title": "Bob"},
+          "details": {"sections": [{"fields": [
+            {"id": "firstname", "value": {"string": "Bob"}},
+            {"id": "lastname", "value": {"string": "Smith"}},
+            {"id": "email", "value": {"email": {"email_address": "bob@example.com"}}},
+            {"title": "Birth date", "id": "birthdate", "value": {"date": 946684800}}
+          ]}]}
+        },
+        {"categoryUuid": "003", "overview": {"title": "Memo"}, "details": {"notesPlain": "remember"}}
+      ]
+    }]
+  }]
+}`
+
+// zipFiles builds a zip archive holding the given files.
+func zipFiles(t *testing.T, files map[string]string) []byte {
+	t.Helper()
+
+	var buffer bytes.Buffer
+	archive := zip.NewWriter(&buffer)
+
+	for name, content := range files {
+		writer, err := archive.Create(name)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		writer.Write([]byte(content))
+	}
+
+	err := archive.Close()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return buffer.Bytes()
+}
+
+func TestParseOnePassword1PUX(t *testing.T) {
+	tests := []struct {
+		name    string
+		data    []byte
+		want    []*Item
+		wantErr bool
+	}{
+		{
+			name: "export",
+			data: zipFiles(t, map[string]string{
+				"export.attributes": `{"version": 3}`,
+				"export.data":       onePasswordSample,
+			}),
+			want: []*Item{
+				{
+					Type:     TypeLogin,
+					Name:     "Example",
+					Folder:   "Private",
+					Favorite: true,
+					Username: "alice",
+					Password: "s3cret",
+					Uris:     []*Uri{{Uri: "https://example.com"}, {Uri: "https://login.example.com"}},
+					Fields: []*Field{
+						{Type: FieldHidden, Name: "PIN", Value: "9999"},
+						{Type: FieldText, Name: "Notes", Value: "note"},
+					},
+				},
+				{
+					Type:   TypeCard,
+					Name:   "Amex",
+					Folder: "Private",
+					Card:   &Card{CardholderName: "Bob", Number: "378282246310005", ExpMonth: "12", ExpYear: "2030", Code: "1234"},
+				},
+				{
+					Type:     TypeIdentity,
+					Name:     "Bob",
+					Folder:   "Private",
+					Identity: &Identity{FirstName: "Bob", LastName: "Smith", Email: "bob@example.com"},
+					Fields:   []*Field{{Type: FieldText, Name: "Birth date", Value: "2000-01-01"}},
+				},
+				{
+					Type:       TypeSecureNote,
+					Name:       "Memo",
+					Folder:     "Private",
+					SecureNote: &SecureNote{Notes: "remember"},
+				},
+			},
+		},
+		{
+			name: "null entries",
+			data: zipFiles(t, map[string]string{
+				"export.data": `{"accounts": [null, {"vaults": [null, {"attrs": {"name": "Private"}, "items": [null, {
+					"categoryUuid": "001",
+					"overview": {"title": "Example", "urls": [null, {"url": "https://example.com"}]},
+					"details": {
+						"loginFields": [null, {"value": "alice", "designation": "username"}],
+						"sections": [null, {"fields": [null, {"title": "PIN", "id": "pin", "value": {"concealed": "9999"}}]}]
+					}
+				}]}]}]}`,
+			}),
+			want: []*Item{
+				{
+					Type:     TypeLogin,
+					Name:     "Example",
+					Folder:   "Private",
+					Username: "alice",
+					Uris:     []*Uri{{Uri: "https://example.com"}},
+					Fields:   []*Field{{Type: FieldHidden, Name: "PIN", Value: "9999"}},
+				},
+			},
+		},
+		{
+			name:    "no export.data",
+			data:    zipFiles(t, map[string]string{"export.attributes": `{"version": 3}`}),
+			wantErr: true,
+		},
+		{
+			name:    "not a zip archive",
+			data:    []byte(onePasswordSample),
+			wantErr: true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			items, err := ParseOnePassword1PUX(test.data)
+			if test.wantErr {
+				if err == nil {
+					t.Fatal("expected an error")
+				}
+
+				return
+			}
+
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			checkItems(t, items, test.want)
+		})
+	}
+}
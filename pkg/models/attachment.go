@@ -0,0 +1,23 @@
+package models
+
+// Attachment is an encrypted file on a vault item. The file name and the
+// attachment key are encrypted by the client, the key with the item key.
+// BlobKey locates the content in the blob store and is never returned to
+// clients.
+type Attachment struct {
+	ID                int    `json:"id"`
+	PasswordID        int    `json:"password_id,omitempty"`
+	UserID            int    `json:"user_id,omitempty"`
+	BlobKey           string `json:"blob_key,omitempty"`
+	EncryptedFileName string `json:"encrypted_file_name"`
+	EncryptedKey      string `json:"encrypted_key"`
+	Size              int64  `json:"size"`
+	CreatedAt         string `json:"created_at"`
+}
+
+// DeletedBlob is the blob of a deleted attachment that still has to be
+// removed from the blob store.
+type DeletedBlob struct {
+	ID      int    `json:"id"`
+	BlobKey string `json:"blob_key"`
+}
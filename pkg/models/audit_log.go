@@ -0,0 +1,9 @@
+package models
+
+type AuditLog struct {
+	ID        int                    `json:"id"`
+	UserID    int                    `json:"user_id"`
+	Event     string                 `json:"event"`
+	Metadata  map[string]interface{} `json:"metadata"`
+	CreatedAt string                 `json:"created_at"`
+}
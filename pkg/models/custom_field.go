@@ -0,0 +1,11 @@
+package models
+
+// CustomField is a user-defined field of a vault item, such as a security
+// question or a PIN. Name and value are encrypted by the client; a linked
+// field has no value and names the item field it stands for in LinkedField.
+type CustomField struct {
+	Type           string `json:"type"`
+	EncryptedName  string `json:"encrypted_name"`
+	EncryptedValue string `json:"encrypted_value,omitempty"`
+	LinkedField    string `json:"linked_field,omitempty"`
+}
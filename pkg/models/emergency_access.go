@@ -0,0 +1,29 @@
+package models
+
+import "time"
+
+// UserSummary is the part of another user's account that may be shown to
+// people they interact with.
+type UserSummary struct {
+	ID       int    `json:"id"`
+	Username string `json:"username"`
+	Email    string `json:"email"`
+}
+
+type EmergencyAccess struct {
+	ID                  int        `json:"id"`
+	GrantorID           int        `json:"grantor_id"`
+	GranteeID           int        `json:"grantee_id"`
+	GranteeEmail        string     `json:"grantee_email"`
+	Type                string     `json:"type"`
+	Status              string     `json:"status"`
+	WaitTimeDays        int        `json:"wait_time_days"`
+	GranteePublicKey    string     `json:"grantee_public_key,omitempty"`
+	KeyEncrypted        string     `json:"key_encrypted,omitempty"`
+	RecoveryInitiatedAt *time.Time `json:"recovery_initiated_at"`
+	CreatedAt           string     `json:"created_at"`
+	UpdatedAt           string     `json:"updated_at"`
+
+	Grantor *UserSummary `json:"grantor,omitempty"`
+	Grantee *UserSummary `json:"grantee,omitempty"`
+}
@@ -4,6 +4,10 @@ type Error struct {
 	Code        int    `json:"code"`
 	CodeString  string `json:"code_string"`
 	Description string `json:"description"`
+
+	Violations []*PolicyViolation `json:"violations,omitempty"`
+	// Current is the server copy of an item an update conflicted with.
+	Current *Password `json:"current,omitempty"`
 }
 
 func NewError(code int, codeString, description string) *Error {
@@ -13,3 +17,23 @@ func NewError(code int, codeString, description string) *Error {
 		Description: description,
 	}
 }
+
+// NewConflictError is returned when an update was based on an outdated copy of
+// the item; current is the item as it is now.
+func NewConflictError(description string, current *Password) *Error {
+	return &Error{
+		Code:        409,
+		CodeString:  "Conflict",
+		Description: description,
+		Current:     current,
+	}
+}
+
+func NewPolicyViolationError(violations []*PolicyViolation) *Error {
+	return &Error{
+		Code:        403,
+		CodeString:  "PolicyViolation",
+		Description: "The request violates an organization policy.",
+		Violations:  violations,
+	}
+}
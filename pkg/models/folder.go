@@ -0,0 +1,9 @@
+package models
+
+type Folder struct {
+	ID            int    `json:"id"`
+	UserID        int    `json:"user_id"`
+	EncryptedName string `json:"encrypted_name"`
+	CreatedAt     string `json:"created_at"`
+	UpdatedAt     string `json:"updated_at"`
+}
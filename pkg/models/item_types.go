@@ -0,0 +1,43 @@
+package models
+
+// The payloads of the typed vault items. Every value is encrypted by the
+// client; the server only checks that required fields are present.
+
+type SecureNote struct {
+	EncryptedNotes string `json:"encrypted_notes"`
+}
+
+type Card struct {
+	EncryptedCardholderName string `json:"encrypted_cardholder_name,omitempty"`
+	EncryptedBrand          string `json:"encrypted_brand,omitempty"`
+	EncryptedNumber         string `json:"encrypted_number"`
+	EncryptedExpMonth       string `json:"encrypted_exp_month,omitempty"`
+	EncryptedExpYear        string `json:"encrypted_exp_year,omitempty"`
+	EncryptedCode           string `json:"encrypted_code,omitempty"`
+}
+
+type Identity struct {
+	EncryptedTitle      string `json:"encrypted_title,omitempty"`
+	EncryptedFirstName  string `json:"encrypted_first_name"`
+	EncryptedMiddleName string `json:"encrypted_middle_name,omitempty"`
+	EncryptedLastName   string `json:"encrypted_last_name"`
+	EncryptedEmail      string `json:"encrypted_email,omitempty"`
+	EncryptedPhone      string `json:"encrypted_phone,omitempty"`
+	EncryptedAddress    string `json:"encrypted_address,omitempty"`
+	EncryptedCompany    string `json:"encrypted_company,omitempty"`
+	EncryptedSSN        string `json:"encrypted_ssn,omitempty"`
+	EncryptedPassport   string `json:"encrypted_passport_number,omitempty"`
+	EncryptedLicense    string `json:"encrypted_license_number,omitempty"`
+}
+
+type SshKey struct {
+	EncryptedPrivateKey  string `json:"encrypted_private_key"`
+	EncryptedPublicKey   string `json:"encrypted_public_key"`
+	EncryptedFingerprint string `json:"encrypted_fingerprint,omitempty"`
+}
+
+type ApiCredential struct {
+	EncryptedKeyID    string `json:"encrypted_key_id,omitempty"`
+	EncryptedSecret   string `json:"encrypted_secret"`
+	EncryptedEndpoint string `json:"encrypted_endpoint,omitempty"`
+}
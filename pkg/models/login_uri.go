@@ -0,0 +1,8 @@
+package models
+
+// LoginUri is one of the URIs of a login with the rule clients use to match it
+// against the page they autofill.
+type LoginUri struct {
+	Uri   string `json:"uri"`
+	Match string `json:"match,omitempty"`
+}
@@ -0,0 +1,41 @@
+package models
+
+type Organization struct {
+	ID        int    `json:"id"`
+	Name      string `json:"name"`
+	CreatedAt string `json:"created_at"`
+	UpdatedAt string `json:"updated_at"`
+}
+
+type OrganizationMember struct {
+	ID              int    `json:"id"`
+	OrganizationID  int    `json:"organization_id"`
+	UserID          int    `json:"user_id"`
+	Role            string `json:"role"`
+	Status          string `json:"status"`
+	EncryptedOrgKey string `json:"encrypted_org_key,omitempty"`
+	CreatedAt       string `json:"created_at"`
+	UpdatedAt       string `json:"updated_at"`
+
+	User         *UserSummary        `json:"user,omitempty"`
+	Organization *Organization       `json:"organization,omitempty"`
+	Collections  []*CollectionAccess `json:"collections,omitempty"`
+}
+
+type Collection struct {
+	ID             int    `json:"id"`
+	OrganizationID int    `json:"organization_id"`
+	Name           string `json:"name"`
+	CreatedAt      string `json:"created_at"`
+	UpdatedAt      string `json:"updated_at"`
+
+	// ReadOnly is set for the requesting member when their access to the
+	// collection does not allow changes.
+	ReadOnly bool `json:"read_only"`
+}
+
+type CollectionAccess struct {
+	CollectionID int  `json:"collection_id"`
+	MemberID     int  `json:"member_id"`
+	ReadOnly     bool `json:"read_only"`
+}
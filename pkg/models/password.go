@@ -1,12 +1,39 @@
 package models
 
 type Password struct {
-	ID                int    `json:"id"`
-	VaultID           int    `json:"vault_id"`
-	AppName           string `json:"app_name"`
-	Uri               string `json:"uri"`
-	Username          string `json:"username"`
-	EncryptedPassword string `json:"encrypted_password"`
-	CreatedAt         string `json:"created_at"`
-	UpdatedAt         string `json:"updated_at"`
+	ID                int     `json:"id"`
+	VaultID           int     `json:"vault_id"`
+	Type              string  `json:"type"`
+	AppName           string  `json:"app_name"`
+	Uri               string  `json:"uri"`
+	Username          string  `json:"username"`
+	EncryptedPassword string  `json:"encrypted_password"`
+	ItemKey           string  `json:"item_key,omitempty"`
+	OrganizationID    *int    `json:"organization_id,omitempty"`
+	CollectionID      *int    `json:"collection_id,omitempty"`
+	FolderID          *int    `json:"folder_id"`
+	Favorite          bool    `json:"favorite"`
+	DeletedAt         *string `json:"deleted_at,omitempty"`
+	Revision          int64   `json:"revision"`
+	ClientID          *string `json:"client_id,omitempty"`
+	ConflictOf        *int    `json:"conflict_of,omitempty"`
+	CreatedAt         string  `json:"created_at"`
+	UpdatedAt         string  `json:"updated_at"`
+
+	SecureNote    *SecureNote    `json:"secure_note,omitempty"`
+	Card          *Card          `json:"card,omitempty"`
+	Identity      *Identity      `json:"identity,omitempty"`
+	SshKey        *SshKey        `json:"ssh_key,omitempty"`
+	ApiCredential *ApiCredential `json:"api_credential,omitempty"`
+
+	Fields []*CustomField `json:"fields,omitempty"`
+	Uris   []*LoginUri    `json:"uris,omitempty"`
+
+	Tags        []*PasswordTag `json:"tags,omitempty"`
+	Attachments []*Attachment  `json:"attachments,omitempty"`
+
+	// Shared is set on items another user has shared with the caller, Share
+	// then holds the permission and the item key wrapped for the caller.
+	Shared bool           `json:"shared"`
+	Share  *PasswordShare `json:"share,omitempty"`
 }
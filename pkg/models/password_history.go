@@ -0,0 +1,10 @@
+package models
+
+// PasswordHistory is a previous encrypted password of a vault item.
+// CreatedAt is the time the password was replaced.
+type PasswordHistory struct {
+	ID                int    `json:"id"`
+	PasswordID        int    `json:"password_id"`
+	EncryptedPassword string `json:"encrypted_password"`
+	CreatedAt         string `json:"created_at"`
+}
@@ -0,0 +1,17 @@
+package models
+
+type PasswordShare struct {
+	ID               int    `json:"id"`
+	PasswordID       int    `json:"password_id"`
+	OwnerID          int    `json:"owner_id"`
+	RecipientID      int    `json:"recipient_id"`
+	Permission       string `json:"permission"`
+	EncryptedItemKey string `json:"encrypted_item_key"`
+	Revision         int64  `json:"revision"`
+	CreatedAt        string `json:"created_at"`
+	UpdatedAt        string `json:"updated_at"`
+
+	Owner     *UserSummary `json:"owner,omitempty"`
+	Recipient *UserSummary `json:"recipient,omitempty"`
+	Password  *Password    `json:"password,omitempty"`
+}
@@ -0,0 +1,10 @@
+package models
+
+// PasswordTombstone records the deletion of a vault item for delta sync.
+type PasswordTombstone struct {
+	ID         int    `json:"id"`
+	VaultID    int    `json:"vault_id"`
+	PasswordID int    `json:"password_id"`
+	Revision   int64  `json:"revision"`
+	CreatedAt  string `json:"created_at"`
+}
@@ -0,0 +1,24 @@
+package models
+
+type OrganizationPolicy struct {
+	ID             int        `json:"id"`
+	OrganizationID int        `json:"organization_id"`
+	Type           string     `json:"type"`
+	Enabled        bool       `json:"enabled"`
+	Data           PolicyData `json:"data"`
+	CreatedAt      string     `json:"created_at"`
+	UpdatedAt      string     `json:"updated_at"`
+}
+
+// PolicyData holds the parameters of the policy types that take one.
+type PolicyData struct {
+	MinIterations     int `json:"min_iterations,omitempty" validate:"gte=0"`
+	MaxSessionTimeout int `json:"max_session_timeout,omitempty" validate:"gte=0"`
+}
+
+// PolicyViolation names the organization policy a request was rejected by.
+type PolicyViolation struct {
+	OrganizationID int    `json:"organization_id"`
+	Policy         string `json:"policy"`
+	Message        string `json:"message"`
+}
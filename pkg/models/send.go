@@ -0,0 +1,28 @@
+package models
+
+// Send is an encrypted text or file shared through a link. Its content and
+// name are encrypted with a key the link carries; EncryptedKey is that key
+// wrapped for the owner. The access password is hashed by the server, and
+// PasswordHash, PasswordSalt and BlobKey are never returned to clients.
+type Send struct {
+	ID                int     `json:"id"`
+	AccessID          string  `json:"access_id"`
+	UserID            int     `json:"user_id"`
+	Type              string  `json:"type"`
+	EncryptedName     string  `json:"encrypted_name"`
+	EncryptedKey      string  `json:"encrypted_key"`
+	EncryptedText     string  `json:"encrypted_text,omitempty"`
+	EncryptedFileName string  `json:"encrypted_file_name,omitempty"`
+	BlobKey           string  `json:"blob_key,omitempty"`
+	Size              int64   `json:"size,omitempty"`
+	PasswordHash      string  `json:"password_hash,omitempty"`
+	PasswordSalt      string  `json:"password_salt,omitempty"`
+	HasPassword       bool    `json:"has_password"`
+	MaxAccessCount    *int    `json:"max_access_count"`
+	AccessCount       int     `json:"access_count"`
+	LastAccessedAt    *string `json:"last_accessed_at"`
+	ExpirationDate    *string `json:"expiration_date"`
+	DeletionDate      string  `json:"deletion_date"`
+	CreatedAt         string  `json:"created_at"`
+	UpdatedAt         string  `json:"updated_at"`
+}
@@ -0,0 +1,14 @@
+package models
+
+type Tag struct {
+	ID             int    `json:"id"`
+	UserID         int    `json:"user_id"`
+	EncryptedLabel string `json:"encrypted_label"`
+	CreatedAt      string `json:"created_at"`
+	UpdatedAt      string `json:"updated_at"`
+}
+
+type PasswordTag struct {
+	PasswordID int `json:"password_id,omitempty"`
+	TagID      int `json:"tag_id"`
+}
@@ -14,4 +14,14 @@ type User struct {
 	CreatedAt          time.Time `json:"created_at"`
 	UpdatedAt          time.Time `json:"updated_at"`
 	RoleId             int       `json:"role_id"`
+
+	MasterPasswordHint          string `json:"master_password_hint"`
+	MasterPasswordHintEncrypted bool   `json:"master_password_hint_encrypted"`
+
+	VerifiedAt          *time.Time `json:"verified_at"`
+	DeletionScheduledAt *time.Time `json:"deletion_scheduled_at"`
+
+	// SecurityStamp is carried by session tokens. Changing it revokes every
+	// session issued before.
+	SecurityStamp string `json:"security_stamp"`
 }
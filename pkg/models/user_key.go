@@ -0,0 +1,11 @@
+package models
+
+type UserKey struct {
+	UserID              int    `json:"user_id"`
+	PublicKey           string `json:"public_key"`
+	EncryptedPrivateKey string `json:"encrypted_private_key"`
+	Algorithm           string `json:"algorithm"`
+	Fingerprint         string `json:"fingerprint"`
+	CreatedAt           string `json:"created_at"`
+	UpdatedAt           string `json:"updated_at"`
+}
@@ -2,12 +2,23 @@ package models
 
 type Vault struct {
 	ID                    int    `json:"id"`
+	Name                  string `json:"name"`
+	IsDefault             bool   `json:"is_default"`
 	ProtectedSymmetricKey string `json:"protected_symmetric_key"`
 	Mac                   string `json:"mac"`
 	Algorithm             string `json:"algorithm"`
 	CreatedAt             string `json:"created_at"`
 	UpdatedAt             string `json:"updated_at"`
 
+	// Revision goes up with every change to the vault, its items or its
+	// owner's profile. VaultRevision and ProfileRevision are the revisions
+	// of the last change to the vault itself and to the profile; sync
+	// tombstones up to PurgedRevision have been purged.
+	Revision        int64 `json:"revision"`
+	VaultRevision   int64 `json:"vault_revision"`
+	ProfileRevision int64 `json:"profile_revision"`
+	PurgedRevision  int64 `json:"purged_revision"`
+
 	UserID int  `json:"user_id"`
 	User   User `json:"users"`
 }
@@ -0,0 +1,14 @@
+package models
+
+// VaultEvent tells a user's devices that something changed. It only names
+// what changed; devices fetch the change itself through sync. Shared is set
+// for events about items shared with the user, which live in another user's
+// vault.
+type VaultEvent struct {
+	ID        int64  `json:"id"`
+	Type      string `json:"type"`
+	VaultID   int    `json:"vault_id,omitempty"`
+	ItemIDs   []int  `json:"item_ids,omitempty"`
+	Shared    bool   `json:"shared,omitempty"`
+	CreatedAt string `json:"created_at"`
+}
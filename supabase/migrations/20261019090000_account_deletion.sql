@@ -0,0 +1,60 @@
+-- Account deletion: audit trail, scheduled deletion and a single-transaction cascade.
+
+create table if not exists audit_logs (
+    id          bigint generated by default as identity primary key,
+    user_id     bigint      not null,
+    event       text        not null,
+    metadata    jsonb,
+    created_at  timestamptz not null default now()
+);
+
+create index if not exists audit_logs_user_id_idx on audit_logs (user_id);
+
+alter table users add column if not exists deletion_scheduled_at timestamptz;
+
+-- delete_user_account removes everything that belongs to a user and records
+-- the deletion in audit_logs. Tables that are optional in a deployment are
+-- only touched when they exist, so the function keeps working as features
+-- are added.
+create or replace function delete_user_account(p_user_id bigint, p_reason text)
+returns boolean
+language plpgsql
+as $$
+declare
+    v_vault_ids bigint[];
+    v_password_count integer;
+begin
+    select coalesce(array_agg(id), '{}') into v_vault_ids from vaults where user_id = p_user_id;
+
+    delete from passwords where vault_id = any (v_vault_ids);
+    get diagnostics v_password_count = row_count;
+
+    delete from vaults where user_id = p_user_id;
+
+    if to_regclass('public.sessions') is not null then
+        execute 'delete from sessions where user_id = $1' using p_user_id;
+    end if;
+
+    if to_regclass('public.two_factor_secrets') is not null then
+        execute 'delete from two_factor_secrets where user_id = $1' using p_user_id;
+    end if;
+
+    if to_regclass('public.two_factor_recovery_codes') is not null then
+        execute 'delete from two_factor_recovery_codes where user_id = $1' using p_user_id;
+    end if;
+
+    delete from users where id = p_user_id;
+    if not found then
+        raise exception 'user % not found', p_user_id using errcode = 'P0002';
+    end if;
+
+    insert into audit_logs (user_id, event, metadata)
+    values (
+        p_user_id,
+        'account_deleted',
+        jsonb_build_object('reason', p_reason, 'vaults', cardinality(v_vault_ids), 'passwords', v_password_count)
+    );
+
+    return true;
+end;
+$$;
@@ -0,0 +1,8 @@
+-- Email verification: users stay unverified until they follow the link sent
+-- to their address.
+
+alter table users add column if not exists verified_at timestamptz;
+
+-- Accounts that exist before verification was introduced count as verified,
+-- otherwise require_verified_email would lock all of them out of writes.
+update users set verified_at = created_at where verified_at is null;
@@ -0,0 +1,5 @@
+-- Optional master password hint. The hint is encrypted with the server's hint
+-- key when one is configured, master_password_hint_encrypted tells which.
+
+alter table users add column if not exists master_password_hint text;
+alter table users add column if not exists master_password_hint_encrypted boolean not null default false;
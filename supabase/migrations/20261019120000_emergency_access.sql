@@ -0,0 +1,24 @@
+-- Emergency access: a grantor names a trusted contact (grantee) who can
+-- request access to the grantor's vault. Access is granted after the wait
+-- period unless the grantor rejects the request.
+
+create table if not exists emergency_access (
+    id                     bigint generated by default as identity primary key,
+    grantor_id             bigint      not null references users (id) on delete cascade,
+    grantee_id             bigint      not null references users (id) on delete cascade,
+    type                   text        not null check (type in ('view', 'takeover')),
+    status                 text        not null default 'invited'
+                                       check (status in ('invited', 'accepted', 'confirmed', 'recovery_initiated', 'recovery_approved')),
+    wait_time_days         integer     not null check (wait_time_days between 1 and 90),
+    grantee_public_key     text,
+    key_encrypted          text,
+    recovery_initiated_at  timestamptz,
+    created_at             timestamptz not null default now(),
+    updated_at             timestamptz not null default now(),
+
+    unique (grantor_id, grantee_id),
+    check (grantor_id <> grantee_id)
+);
+
+create index if not exists emergency_access_grantee_id_idx on emergency_access (grantee_id);
+create index if not exists emergency_access_status_idx on emergency_access (status);
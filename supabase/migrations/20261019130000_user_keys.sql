@@ -0,0 +1,13 @@
+-- Per-user asymmetric key pairs. The private key is encrypted by the client
+-- with the user's vault key, the server only ever sees the public key in the
+-- clear.
+
+create table if not exists user_keys (
+    user_id                bigint      primary key references users (id) on delete cascade,
+    public_key             text        not null,
+    encrypted_private_key  text        not null,
+    algorithm              text        not null check (algorithm in ('RSA-OAEP', 'X25519')),
+    fingerprint            text        not null,
+    created_at             timestamptz not null default now(),
+    updated_at             timestamptz not null default now()
+);
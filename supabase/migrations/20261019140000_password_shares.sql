@@ -0,0 +1,20 @@
+-- Item level sharing. A shared item carries its own item key; the owner wraps
+-- that key with each recipient's public key.
+
+alter table passwords add column if not exists item_key text;
+
+create table if not exists password_shares (
+    id                  bigint generated by default as identity primary key,
+    password_id         bigint      not null references passwords (id) on delete cascade,
+    owner_id            bigint      not null references users (id) on delete cascade,
+    recipient_id        bigint      not null references users (id) on delete cascade,
+    permission          text        not null check (permission in ('read', 'write')),
+    encrypted_item_key  text        not null,
+    created_at          timestamptz not null default now(),
+    updated_at          timestamptz not null default now(),
+
+    unique (password_id, recipient_id),
+    check (owner_id <> recipient_id)
+);
+
+create index if not exists password_shares_recipient_id_idx on password_shares (recipient_id);
@@ -0,0 +1,53 @@
+-- Organizations own collections of password items. Items in a collection are
+-- encrypted under the organization key, which is wrapped for every member
+-- with the member's public key.
+
+create table if not exists organizations (
+    id          bigint generated by default as identity primary key,
+    name        text        not null,
+    created_at  timestamptz not null default now(),
+    updated_at  timestamptz not null default now()
+);
+
+create table if not exists organization_members (
+    id                 bigint generated by default as identity primary key,
+    organization_id    bigint      not null references organizations (id) on delete cascade,
+    user_id            bigint      not null references users (id) on delete cascade,
+    role               text        not null check (role in ('owner', 'admin', 'member', 'read_only')),
+    status             text        not null default 'invited' check (status in ('invited', 'accepted', 'confirmed')),
+    encrypted_org_key  text,
+    created_at         timestamptz not null default now(),
+    updated_at         timestamptz not null default now(),
+
+    unique (organization_id, user_id)
+);
+
+create index if not exists organization_members_user_id_idx on organization_members (user_id);
+
+create table if not exists collections (
+    id               bigint generated by default as identity primary key,
+    organization_id  bigint      not null references organizations (id) on delete cascade,
+    name             text        not null,
+    created_at       timestamptz not null default now(),
+    updated_at       timestamptz not null default now()
+);
+
+create index if not exists collections_organization_id_idx on collections (organization_id);
+
+create table if not exists collection_members (
+    collection_id  bigint  not null references collections (id) on delete cascade,
+    member_id      bigint  not null references organization_members (id) on delete cascade,
+    read_only      boolean not null default false,
+
+    primary key (collection_id, member_id)
+);
+
+alter table passwords alter column vault_id drop not null;
+alter table passwords add column if not exists organization_id bigint references organizations (id) on delete cascade;
+alter table passwords add column if not exists collection_id bigint references collections (id) on delete cascade;
+
+alter table passwords drop constraint if exists passwords_owner_check;
+alter table passwords add constraint passwords_owner_check
+    check ((vault_id is not null and collection_id is null) or (vault_id is null and collection_id is not null and organization_id is not null));
+
+create index if not exists passwords_collection_id_idx on passwords (collection_id);
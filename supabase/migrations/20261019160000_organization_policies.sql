@@ -0,0 +1,21 @@
+-- Organization policies are rules admins enforce on their members. Each
+-- organization has at most one row per policy type; data holds the policy's
+-- parameters.
+
+create table if not exists organization_policies (
+    id               bigint generated by default as identity primary key,
+    organization_id  bigint      not null references organizations (id) on delete cascade,
+    type             text        not null check (type in (
+        'require_two_factor',
+        'min_kdf_iterations',
+        'disable_personal_export',
+        'max_session_timeout',
+        'disable_external_sharing'
+    )),
+    enabled          boolean     not null default false,
+    data             jsonb       not null default '{}'::jsonb,
+    created_at       timestamptz not null default now(),
+    updated_at       timestamptz not null default now(),
+
+    unique (organization_id, type)
+);
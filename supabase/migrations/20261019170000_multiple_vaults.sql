@@ -0,0 +1,32 @@
+-- Users can own several named vaults, each with its own protected key. One of
+-- them is the default vault that registration creates and `@me` refers to.
+
+alter table vaults add column if not exists name text not null default 'Personal';
+alter table vaults add column if not exists is_default boolean not null default false;
+
+alter table vaults drop constraint if exists vaults_user_id_key;
+
+update vaults set is_default = true
+where id in (select min(id) from vaults group by user_id)
+  and not exists (select 1 from vaults v where v.user_id = vaults.user_id and v.is_default);
+
+create index if not exists vaults_user_id_idx on vaults (user_id);
+create unique index if not exists vaults_user_id_default_idx on vaults (user_id) where is_default;
+
+-- delete_vault removes a non-default vault of the user together with its
+-- passwords in a single transaction.
+create or replace function delete_vault(p_vault_id bigint, p_user_id bigint)
+returns boolean
+language plpgsql
+as $$
+begin
+    if not exists (select 1 from vaults where id = p_vault_id and user_id = p_user_id and not is_default) then
+        return false;
+    end if;
+
+    delete from passwords where vault_id = p_vault_id;
+    delete from vaults where id = p_vault_id;
+
+    return true;
+end;
+$$;
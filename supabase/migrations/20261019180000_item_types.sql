@@ -0,0 +1,18 @@
+-- Vault items have a type. Logins keep using the existing columns, the other
+-- types store their encrypted fields in a jsonb payload column of their own.
+
+alter table passwords add column if not exists type text not null default 'login';
+
+alter table passwords drop constraint if exists passwords_type_check;
+alter table passwords add constraint passwords_type_check
+    check (type in ('login', 'secure_note', 'card', 'identity', 'ssh_key', 'api_credential'));
+
+alter table passwords add column if not exists secure_note jsonb;
+alter table passwords add column if not exists card jsonb;
+alter table passwords add column if not exists identity jsonb;
+alter table passwords add column if not exists ssh_key jsonb;
+alter table passwords add column if not exists api_credential jsonb;
+
+alter table passwords alter column encrypted_password drop not null;
+
+create index if not exists passwords_vault_id_type_idx on passwords (vault_id, type);
@@ -0,0 +1,17 @@
+-- Folders are personal: they belong to a user and organize the items of all
+-- of the user's vaults. Deleting a folder keeps its items, which end up in no
+-- folder.
+
+create table if not exists folders (
+    id              bigint generated by default as identity primary key,
+    user_id         bigint      not null references users (id) on delete cascade,
+    encrypted_name  text        not null,
+    created_at      timestamptz not null default now(),
+    updated_at      timestamptz not null default now()
+);
+
+create index if not exists folders_user_id_idx on folders (user_id);
+
+alter table passwords add column if not exists folder_id bigint references folders (id) on delete set null;
+
+create index if not exists passwords_folder_id_idx on passwords (folder_id);
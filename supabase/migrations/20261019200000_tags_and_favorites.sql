@@ -0,0 +1,23 @@
+-- Tags are personal labels, encrypted by the client, that can be put on any
+-- number of the user's items. Favorites are a flag on the item.
+
+create table if not exists tags (
+    id               bigint generated by default as identity primary key,
+    user_id          bigint      not null references users (id) on delete cascade,
+    encrypted_label  text        not null,
+    created_at       timestamptz not null default now(),
+    updated_at       timestamptz not null default now()
+);
+
+create index if not exists tags_user_id_idx on tags (user_id);
+
+create table if not exists password_tags (
+    password_id  bigint not null references passwords (id) on delete cascade,
+    tag_id       bigint not null references tags (id) on delete cascade,
+
+    primary key (password_id, tag_id)
+);
+
+create index if not exists password_tags_tag_id_idx on password_tags (tag_id);
+
+alter table passwords add column if not exists favorite boolean not null default false;
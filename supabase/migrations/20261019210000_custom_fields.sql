@@ -0,0 +1,8 @@
+-- Vault items can carry an ordered list of custom fields. The list is stored
+-- as a jsonb array; names and values are encrypted by the client.
+
+alter table passwords add column if not exists fields jsonb;
+
+alter table passwords drop constraint if exists passwords_fields_check;
+alter table passwords add constraint passwords_fields_check
+    check (fields is null or (jsonb_typeof(fields) = 'array' and jsonb_array_length(fields) <= 50));
@@ -0,0 +1,11 @@
+-- Logins can have several URIs, each with its own match rule. The list is
+-- stored as a jsonb array; the uri column keeps the first URI for older
+-- clients.
+
+alter table passwords add column if not exists uris jsonb;
+
+update passwords
+   set uris = jsonb_build_array(jsonb_build_object('uri', uri))
+ where uris is null
+   and uri is not null
+   and uri <> '';
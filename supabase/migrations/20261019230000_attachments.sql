@@ -0,0 +1,41 @@
+-- Encrypted file attachments on vault items. The blobs live in the blob store;
+-- the rows keep the encrypted file name, the attachment key wrapped with the
+-- item key and the size counted against the owner's quota.
+
+create table if not exists attachments (
+    id                   bigint generated by default as identity primary key,
+    password_id          bigint      not null references passwords (id) on delete cascade,
+    user_id              bigint      not null references users (id) on delete cascade,
+    blob_key             text        not null unique,
+    encrypted_file_name  text        not null,
+    encrypted_key        text        not null,
+    size                 bigint      not null check (size >= 0),
+    created_at           timestamptz not null default now()
+);
+
+create index if not exists attachments_password_id_idx on attachments (password_id);
+create index if not exists attachments_user_id_idx on attachments (user_id);
+
+-- Blobs of deleted attachments are queued here, whichever way the rows went:
+-- deleting the attachment, its item, a vault or the whole account. A job
+-- removes them from the blob store and then from the queue.
+create table if not exists deleted_blobs (
+    id          bigint generated by default as identity primary key,
+    blob_key    text        not null,
+    created_at  timestamptz not null default now()
+);
+
+create or replace function queue_deleted_blob()
+returns trigger
+language plpgsql
+as $$
+begin
+    insert into deleted_blobs (blob_key) values (old.blob_key);
+    return old;
+end;
+$$;
+
+drop trigger if exists attachments_queue_deleted_blob on attachments;
+create trigger attachments_queue_deleted_blob
+    after delete on attachments
+    for each row execute function queue_deleted_blob();
@@ -0,0 +1,12 @@
+-- Previous encrypted passwords of vault items. An entry is written whenever an
+-- update replaces the password; the service keeps the newest entries up to
+-- the configured limit.
+
+create table if not exists password_history (
+    id                  bigint generated by default as identity primary key,
+    password_id         bigint      not null references passwords (id) on delete cascade,
+    encrypted_password  text        not null,
+    created_at          timestamptz not null default now()
+);
+
+create index if not exists password_history_password_id_idx on password_history (password_id, created_at desc);
@@ -0,0 +1,7 @@
+-- Deleting a vault item moves it to the trash. Trashed items keep all their
+-- data until they are restored, deleted for good or purged after the
+-- retention period.
+
+alter table passwords add column if not exists deleted_at timestamptz;
+
+create index if not exists passwords_deleted_at_idx on passwords (deleted_at) where deleted_at is not null;
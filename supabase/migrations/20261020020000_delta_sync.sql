@@ -0,0 +1,181 @@
+-- Delta sync: every vault has a revision counter that goes up with each write
+-- to the vault, its items or its owner's profile. Items carry the revision of
+-- their last change and deleted items leave a tombstone, so clients can ask
+-- for everything that changed after the revision they last saw.
+
+alter table vaults add column if not exists revision bigint not null default 0;
+-- vault_revision and profile_revision are the revisions of the last change to
+-- the vault itself and to its owner's profile.
+alter table vaults add column if not exists vault_revision bigint not null default 0;
+alter table vaults add column if not exists profile_revision bigint not null default 0;
+-- purged_revision is the newest revision whose tombstones have been purged.
+-- Clients that last synced before it need a full sync.
+alter table vaults add column if not exists purged_revision bigint not null default 0;
+
+alter table passwords add column if not exists revision bigint not null default 0;
+
+create index if not exists passwords_vault_id_revision_idx on passwords (vault_id, revision);
+
+create table if not exists password_tombstones (
+    id           bigint generated by default as identity primary key,
+    vault_id     bigint      not null references vaults (id) on delete cascade,
+    password_id  bigint      not null,
+    revision     bigint      not null,
+    created_at   timestamptz not null default now()
+);
+
+create index if not exists password_tombstones_vault_id_revision_idx on password_tombstones (vault_id, revision);
+create index if not exists password_tombstones_created_at_idx on password_tombstones (created_at);
+
+-- next_vault_revision bumps the revision of a vault and returns it, or null
+-- when the vault is gone.
+create or replace function next_vault_revision(p_vault_id bigint)
+returns bigint
+language sql
+as $$
+    update vaults set revision = revision + 1 where id = p_vault_id returning revision;
+$$;
+
+create or replace function bump_password_revision()
+returns trigger
+language plpgsql
+as $$
+begin
+    if new.vault_id is not null then
+        new.revision := coalesce(next_vault_revision(new.vault_id), new.revision);
+    end if;
+
+    return new;
+end;
+$$;
+
+drop trigger if exists passwords_bump_revision on passwords;
+create trigger passwords_bump_revision
+    before insert or update on passwords
+    for each row execute function bump_password_revision();
+
+create or replace function record_password_tombstone()
+returns trigger
+language plpgsql
+as $$
+declare
+    v_revision bigint;
+begin
+    if old.vault_id is null then
+        return old;
+    end if;
+
+    v_revision := next_vault_revision(old.vault_id);
+    if v_revision is not null then
+        insert into password_tombstones (vault_id, password_id, revision)
+        values (old.vault_id, old.id, v_revision);
+    end if;
+
+    return old;
+end;
+$$;
+
+drop trigger if exists passwords_record_tombstone on passwords;
+create trigger passwords_record_tombstone
+    after delete on passwords
+    for each row execute function record_password_tombstone();
+
+-- Tags and attachments are part of the item a client syncs, so changing them
+-- touches the item, which gives it a new revision.
+create or replace function touch_password()
+returns trigger
+language plpgsql
+as $$
+declare
+    v_password_id bigint;
+begin
+    if tg_op = 'DELETE' then
+        v_password_id := old.password_id;
+    else
+        v_password_id := new.password_id;
+    end if;
+
+    update passwords set revision = revision where id = v_password_id;
+
+    return null;
+end;
+$$;
+
+drop trigger if exists password_tags_touch_password on password_tags;
+create trigger password_tags_touch_password
+    after insert or delete on password_tags
+    for each row execute function touch_password();
+
+drop trigger if exists attachments_touch_password on attachments;
+create trigger attachments_touch_password
+    after insert or delete on attachments
+    for each row execute function touch_password();
+
+-- Changes to the vault itself, such as a rename or a new key, bump its
+-- revision. Updates that already bump it, or only record purged tombstones,
+-- are left alone.
+create or replace function bump_vault_revision()
+returns trigger
+language plpgsql
+as $$
+begin
+    if new.revision = old.revision and new.purged_revision = old.purged_revision then
+        new.revision := old.revision + 1;
+        new.vault_revision := new.revision;
+    end if;
+
+    return new;
+end;
+$$;
+
+drop trigger if exists vaults_bump_revision on vaults;
+create trigger vaults_bump_revision
+    before update on vaults
+    for each row execute function bump_vault_revision();
+
+create or replace function bump_profile_revision()
+returns trigger
+language plpgsql
+as $$
+begin
+    update vaults
+       set revision = revision + 1,
+           profile_revision = revision + 1
+     where user_id = new.id;
+
+    return null;
+end;
+$$;
+
+drop trigger if exists users_bump_profile_revision on users;
+create trigger users_bump_profile_revision
+    after update on users
+    for each row
+    when (old is distinct from new)
+    execute function bump_profile_revision();
+
+-- purge_password_tombstones deletes tombstones created before p_before and
+-- records the newest purged revision on their vaults.
+create or replace function purge_password_tombstones(p_before timestamptz)
+returns integer
+language plpgsql
+as $$
+declare
+    v_count integer;
+begin
+    update vaults v
+       set purged_revision = greatest(v.purged_revision, t.max_revision)
+      from (
+            select vault_id, max(revision) as max_revision
+              from password_tombstones
+             where created_at < p_before
+             group by vault_id
+           ) t
+     where v.id = t.vault_id;
+
+    delete from password_tombstones where created_at < p_before;
+    get diagnostics v_count = row_count;
+
+    return v_count;
+end;
+$$;
@@ -0,0 +1,129 @@
+-- Batch sync: offline clients send the item changes they queued in one batch.
+-- Items created offline carry the client's id for them, so a batch that is
+-- sent again does not create them twice. Conflict copies point at the item
+-- whose server version won.
+
+alter table passwords add column if not exists client_id text;
+alter table passwords add column if not exists conflict_of bigint references passwords (id) on delete set null;
+
+create unique index if not exists passwords_vault_id_client_id_idx on passwords (vault_id, client_id) where client_id is not null;
+
+-- apply_password_batch applies the writes of a batch in order in a single
+-- transaction. Conflicts have been resolved by the service against the
+-- revisions it read; a write whose item has changed since fails the whole
+-- batch with serialization_failure, and the service resolves it again.
+--
+-- Each write has an action (create, update or delete, which moves the item to
+-- the trash), the item, the password_id or the ref of the create earlier in
+-- the batch it applies to, the revision it expects, and tag_ids when the tags
+-- are replaced. Updates keep the password history like single updates do.
+create or replace function apply_password_batch(p_vault_id bigint, p_writes jsonb, p_history_limit integer)
+returns jsonb
+language plpgsql
+as $$
+declare
+    v_write    jsonb;
+    v_item     passwords;
+    v_current  passwords;
+    v_id       bigint;
+    v_revision bigint;
+    v_ids      jsonb := '{}'::jsonb;
+    v_results  jsonb := '[]'::jsonb;
+begin
+    for v_write in select value from jsonb_array_elements(p_writes)
+    loop
+        v_item := jsonb_populate_record(null::passwords, v_write->'item');
+
+        if v_write->>'ref' is not null then
+            v_id := (v_ids->>(v_write->>'ref'))::bigint;
+        else
+            v_id := (v_write->>'password_id')::bigint;
+        end if;
+
+        if v_write->>'action' = 'create' then
+            v_id := null;
+
+            insert into passwords (vault_id, type, app_name, uri, username, encrypted_password, item_key, folder_id, favorite,
+                                   secure_note, card, identity, ssh_key, api_credential, fields, uris, client_id, conflict_of)
+            values (p_vault_id, v_item.type, v_item.app_name, v_item.uri, v_item.username, v_item.encrypted_password,
+                    v_item.item_key, v_item.folder_id, coalesce(v_item.favorite, false), v_item.secure_note, v_item.card,
+                    v_item.identity, v_item.ssh_key, v_item.api_credential, v_item.fields, v_item.uris, v_item.client_id,
+                    v_item.conflict_of)
+            on conflict (vault_id, client_id) where client_id is not null do nothing
+            returning id into v_id;
+
+            if v_id is null then
+                select id into v_id from passwords where vault_id = p_vault_id and client_id = v_item.client_id;
+            end if;
+        else
+            select * into v_current from passwords
+             where id = v_id and vault_id = p_vault_id and deleted_at is null
+               for update;
+
+            if not found or (v_write->>'revision' is not null and v_current.revision <> (v_write->>'revision')::bigint) then
+                raise exception 'password % changed while the batch was applied', v_id using errcode = '40001';
+            end if;
+
+            if v_write->>'action' = 'update' then
+                if coalesce(v_item.item_key, '') <> '' and v_item.item_key <> coalesce(v_current.item_key, '') then
+                    delete from password_history where password_id = v_id;
+                elsif p_history_limit > 0 and coalesce(v_current.encrypted_password, '') <> ''
+                      and v_current.encrypted_password <> v_item.encrypted_password then
+                    insert into password_history (password_id, encrypted_password)
+                    values (v_id, v_current.encrypted_password);
+
+                    delete from password_history
+                     where password_id = v_id
+                       and id not in (
+                            select id from password_history
+                             where password_id = v_id
+                             order by created_at desc, id desc
+                             limit p_history_limit
+                           );
+                end if;
+
+                update passwords
+                   set type = v_item.type,
+                       app_name = coalesce(v_item.app_name, app_name),
+                       uri = coalesce(v_item.uri, uri),
+                       username = coalesce(v_item.username, username),
+                       encrypted_password = v_item.encrypted_password,
+                       item_key = coalesce(nullif(v_item.item_key, ''), item_key),
+                       folder_id = v_item.folder_id,
+                       favorite = coalesce(v_item.favorite, false),
+                       secure_note = v_item.secure_note,
+                       card = v_item.card,
+                       identity = v_item.identity,
+                       ssh_key = v_item.ssh_key,
+                       api_credential = v_item.api_credential,
+                       fields = v_item.fields,
+                       uris = v_item.uris
+                 where id = v_id;
+            else
+                update passwords set deleted_at = now() where id = v_id;
+            end if;
+        end if;
+
+        if jsonb_typeof(v_write->'tag_ids') = 'array' then
+            delete from password_tags where password_id = v_id;
+
+            insert into password_tags (password_id, tag_id)
+            select v_id, value::bigint from jsonb_array_elements_text(v_write->'tag_ids');
+        end if;
+
+        select revision into v_revision from passwords where id = v_id;
+
+        v_ids := v_ids || jsonb_build_object(v_write->>'index', v_id);
+        v_results := v_results || jsonb_build_array(jsonb_build_object(
+            'index', (v_write->>'index')::integer,
+            'id', v_id,
+            'revision', v_revision
+        ));
+    end loop;
+
+    return jsonb_build_object(
+        'revision', (select revision from vaults where id = p_vault_id),
+        'items', v_results
+    );
+end;
+$$;
@@ -0,0 +1,60 @@
+-- Imports: the items of another password manager's export are created with
+-- their folders in a single transaction, so a failed import leaves nothing
+-- behind.
+
+-- import_passwords creates the folders of the user, then the items of the
+-- vault. Each item has the item, the folder_index of one of the new folders
+-- it goes in, and its tag_ids. Returns the ids of the folders and of the
+-- items, in order.
+create or replace function import_passwords(p_user_id bigint, p_vault_id bigint, p_folders jsonb, p_items jsonb)
+returns jsonb
+language plpgsql
+as $$
+declare
+    v_folder_ids   bigint[] := '{}';
+    v_password_ids jsonb    := '[]'::jsonb;
+    v_name         text;
+    v_entry        jsonb;
+    v_item         passwords;
+    v_folder_id    bigint;
+    v_id           bigint;
+begin
+    for v_name in select value from jsonb_array_elements_text(coalesce(p_folders, '[]'::jsonb))
+    loop
+        insert into folders (user_id, encrypted_name)
+        values (p_user_id, v_name)
+        returning id into v_id;
+
+        v_folder_ids := v_folder_ids || v_id;
+    end loop;
+
+    for v_entry in select value from jsonb_array_elements(p_items)
+    loop
+        v_item := jsonb_populate_record(null::passwords, v_entry->'item');
+
+        v_folder_id := v_item.folder_id;
+        if v_entry->>'folder_index' is not null then
+            v_folder_id := v_folder_ids[(v_entry->>'folder_index')::integer + 1];
+        end if;
+
+        insert into passwords (vault_id, type, app_name, uri, username, encrypted_password, item_key, folder_id, favorite,
+                               secure_note, card, identity, ssh_key, api_credential, fields, uris)
+        values (p_vault_id, v_item.type, v_item.app_name, v_item.uri, v_item.username, v_item.encrypted_password,
+                v_item.item_key, v_folder_id, coalesce(v_item.favorite, false), v_item.secure_note, v_item.card,
+                v_item.identity, v_item.ssh_key, v_item.api_credential, v_item.fields, v_item.uris)
+        returning id into v_id;
+
+        if jsonb_typeof(v_entry->'tag_ids') = 'array' then
+            insert into password_tags (password_id, tag_id)
+            select v_id, value::bigint from jsonb_array_elements_text(v_entry->'tag_ids');
+        end if;
+
+        v_password_ids := v_password_ids || to_jsonb(v_id);
+    end loop;
+
+    return jsonb_build_object(
+        'folder_ids', to_jsonb(v_folder_ids),
+        'password_ids', v_password_ids
+    );
+end;
+$$;
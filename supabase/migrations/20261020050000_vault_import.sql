@@ -0,0 +1,86 @@
+-- Vault imports: a SafePass export is restored into a vault in a single
+-- transaction, with the folders and tags it needs.
+
+-- import_vault creates the folders and tags of the user, then the items of the
+-- vault, and returns their ids in order. Items refer to the new folders and
+-- tags by folder_index and tag_indexes, to existing ones by the item's
+-- folder_id and tag_ids.
+--
+-- With p_protected_key the vault's key is replaced by the export's. That is
+-- only done for a vault without items, since they are encrypted with the old
+-- key; a vault that got items since the service checked fails the import
+-- with serialization_failure.
+create or replace function import_vault(p_user_id bigint, p_vault_id bigint, p_mac text, p_protected_key text,
+                                        p_folders jsonb, p_tags jsonb, p_items jsonb)
+returns jsonb
+language plpgsql
+as $$
+declare
+    v_folder_ids   bigint[] := '{}';
+    v_tag_ids      bigint[] := '{}';
+    v_password_ids jsonb    := '[]'::jsonb;
+    v_name         text;
+    v_entry        jsonb;
+    v_item         passwords;
+    v_folder_id    bigint;
+    v_id           bigint;
+begin
+    if p_protected_key is not null then
+        perform 1 from vaults where id = p_vault_id for update;
+
+        if exists (select 1 from passwords where vault_id = p_vault_id) then
+            raise exception 'vault % is not empty', p_vault_id using errcode = '40001';
+        end if;
+
+        update vaults set protected_symmetric_key = p_protected_key, mac = p_mac where id = p_vault_id;
+    end if;
+
+    for v_name in select value from jsonb_array_elements_text(coalesce(p_folders, '[]'::jsonb))
+    loop
+        insert into folders (user_id, encrypted_name)
+        values (p_user_id, v_name)
+        returning id into v_id;
+
+        v_folder_ids := v_folder_ids || v_id;
+    end loop;
+
+    for v_name in select value from jsonb_array_elements_text(coalesce(p_tags, '[]'::jsonb))
+    loop
+        insert into tags (user_id, encrypted_label)
+        values (p_user_id, v_name)
+        returning id into v_id;
+
+        v_tag_ids := v_tag_ids || v_id;
+    end loop;
+
+    for v_entry in select value from jsonb_array_elements(p_items)
+    loop
+        v_item := jsonb_populate_record(null::passwords, v_entry->'item');
+
+        v_folder_id := v_item.folder_id;
+        if v_entry->>'folder_index' is not null then
+            v_folder_id := v_folder_ids[(v_entry->>'folder_index')::integer + 1];
+        end if;
+
+        insert into passwords (vault_id, type, app_name, uri, username, encrypted_password, item_key, folder_id, favorite,
+                               secure_note, card, identity, ssh_key, api_credential, fields, uris)
+        values (p_vault_id, v_item.type, v_item.app_name, v_item.uri, v_item.username, v_item.encrypted_password,
+                v_item.item_key, v_folder_id, coalesce(v_item.favorite, false), v_item.secure_note, v_item.card,
+                v_item.identity, v_item.ssh_key, v_item.api_credential, v_item.fields, v_item.uris)
+        returning id into v_id;
+
+        insert into password_tags (password_id, tag_id)
+        select v_id, value::bigint from jsonb_array_elements_text(coalesce(v_entry->'tag_ids', '[]'::jsonb))
+        union
+        select v_id, v_tag_ids[value::integer + 1] from jsonb_array_elements_text(coalesce(v_entry->'tag_indexes', '[]'::jsonb));
+
+        v_password_ids := v_password_ids || to_jsonb(v_id);
+    end loop;
+
+    return jsonb_build_object(
+        'folder_ids', to_jsonb(v_folder_ids),
+        'tag_ids', to_jsonb(v_tag_ids),
+        'password_ids', v_password_ids
+    );
+end;
+$$;
@@ -0,0 +1,55 @@
+-- Sends: encrypted texts and files shared through a link with anyone, for a
+-- limited time. Content and name are encrypted with a key that only the link
+-- and the owner have. Files are kept in the blob store like attachments.
+
+create table if not exists sends (
+    id                   bigint generated by default as identity primary key,
+    access_id            text        not null unique,
+    user_id              bigint      not null references users (id) on delete cascade,
+    type                 text        not null check (type in ('text', 'file')),
+    encrypted_name       text        not null,
+    encrypted_key        text        not null,
+    encrypted_text       text,
+    encrypted_file_name  text,
+    blob_key             text,
+    size                 bigint      not null default 0,
+    password_hash        text,
+    password_salt        text,
+    has_password         boolean     generated always as (password_hash is not null) stored,
+    max_access_count     integer     check (max_access_count > 0),
+    access_count         integer     not null default 0,
+    last_accessed_at     timestamptz,
+    expiration_date      timestamptz,
+    deletion_date        timestamptz not null,
+    created_at           timestamptz not null default now(),
+    updated_at           timestamptz not null default now()
+);
+
+create index if not exists sends_user_id_idx on sends (user_id);
+create index if not exists sends_deletion_date_idx on sends (deletion_date);
+
+-- Files of deleted Sends go through the same queue as attachments.
+drop trigger if exists sends_queue_deleted_blob on sends;
+create trigger sends_queue_deleted_blob
+    after delete on sends
+    for each row
+    when (old.blob_key is not null)
+    execute function queue_deleted_blob();
+
+-- record_send_access counts an access to a Send and returns it, or nothing
+-- when the Send is expired, past its deletion date or out of accesses. The
+-- check and the count happen in one statement, so concurrent accesses cannot
+-- go over the maximum.
+create or replace function record_send_access(p_send_id bigint)
+returns setof sends
+language sql
+as $$
+    update sends
+       set access_count = access_count + 1,
+           last_accessed_at = now()
+     where id = p_send_id
+       and deletion_date > now()
+       and (expiration_date is null or expiration_date > now())
+       and (max_access_count is null or access_count < max_access_count)
+    returning *;
+$$;
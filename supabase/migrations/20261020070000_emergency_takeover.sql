@@ -0,0 +1,81 @@
+-- Emergency takeover in one transaction: the grantor's vault key, master
+-- password and sessions change together with the grant, which ends in the
+-- terminal recovery_completed state so the takeover cannot be repeated.
+
+-- security_stamp is carried by session tokens and checked on every request.
+-- A new stamp revokes all sessions issued before it.
+alter table users add column if not exists security_stamp uuid not null default gen_random_uuid();
+
+alter table emergency_access drop constraint if exists emergency_access_status_check;
+alter table emergency_access add constraint emergency_access_status_check
+    check (status in ('invited', 'accepted', 'confirmed', 'recovery_initiated', 'recovery_approved', 'recovery_completed'));
+
+-- emergency_takeover raises 40001 when the grant is no longer an approved
+-- takeover, for example because it was rejected or already used. Emergency
+-- access only covers the default vault: the grantor's other vaults stay
+-- wrapped under the old master password, so they are deleted when
+-- p_discard_vaults is set and the takeover is refused otherwise.
+create or replace function emergency_takeover(
+    p_access_id bigint,
+    p_grantee_id bigint,
+    p_master_password_hash text,
+    p_salt text,
+    p_iteration_count integer,
+    p_protected_key text,
+    p_mac text,
+    p_discard_vaults boolean
+)
+returns setof emergency_access
+language plpgsql
+as $$
+declare
+    v_grantor_id bigint;
+begin
+    select grantor_id into v_grantor_id
+      from emergency_access
+     where id = p_access_id
+       and grantee_id = p_grantee_id
+       and type = 'takeover'
+       and status = 'recovery_approved'
+       for update;
+
+    if not found then
+        raise exception 'emergency access % cannot be taken over', p_access_id using errcode = '40001';
+    end if;
+
+    if exists (select 1 from vaults where user_id = v_grantor_id and not is_default) then
+        if not p_discard_vaults then
+            raise exception 'user % has vaults emergency access does not cover', v_grantor_id using errcode = '40001';
+        end if;
+
+        delete from passwords
+         where vault_id in (select id from vaults where user_id = v_grantor_id and not is_default);
+        delete from vaults where user_id = v_grantor_id and not is_default;
+    end if;
+
+    update vaults
+       set protected_symmetric_key = p_protected_key,
+           mac = p_mac
+     where user_id = v_grantor_id and is_default;
+
+    if not found then
+        raise exception 'default vault of user % not found', v_grantor_id using errcode = 'P0002';
+    end if;
+
+    update users
+       set master_password_hash = p_master_password_hash,
+           salt = p_salt,
+           iteration_count = p_iteration_count,
+           security_stamp = gen_random_uuid(),
+           updated_at = now()
+     where id = v_grantor_id;
+
+    return query
+    update emergency_access
+       set status = 'recovery_completed',
+           key_encrypted = null,
+           updated_at = now()
+     where id = p_access_id
+    returning *;
+end;
+$$;
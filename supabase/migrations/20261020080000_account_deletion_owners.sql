@@ -0,0 +1,73 @@
+-- delete_user_account no longer leaves ownerless organizations behind and
+-- reports a missing user as false instead of raising.
+--
+-- It raises 40001 when the user is the only confirmed owner of an
+-- organization that has other members; ownership has to be transferred
+-- first. Organizations the user is the only member of are deleted with the
+-- account.
+create or replace function delete_user_account(p_user_id bigint, p_reason text)
+returns boolean
+language plpgsql
+as $$
+declare
+    v_vault_ids bigint[];
+    v_password_count integer;
+    v_organization_id bigint;
+begin
+    perform 1 from users where id = p_user_id for update;
+    if not found then
+        return false;
+    end if;
+
+    select m.organization_id into v_organization_id
+      from organization_members m
+     where m.user_id = p_user_id
+       and m.role = 'owner'
+       and m.status = 'confirmed'
+       and exists (select 1 from organization_members o
+                    where o.organization_id = m.organization_id and o.user_id <> p_user_id)
+       and not exists (select 1 from organization_members o
+                        where o.organization_id = m.organization_id and o.user_id <> p_user_id
+                          and o.role = 'owner' and o.status = 'confirmed')
+     limit 1;
+
+    if found then
+        raise exception 'user % is the only owner of organization %', p_user_id, v_organization_id using errcode = '40001';
+    end if;
+
+    delete from organizations
+     where id in (select organization_id from organization_members where user_id = p_user_id)
+       and not exists (select 1 from organization_members o
+                        where o.organization_id = organizations.id and o.user_id <> p_user_id);
+
+    select coalesce(array_agg(id), '{}') into v_vault_ids from vaults where user_id = p_user_id;
+
+    delete from passwords where vault_id = any (v_vault_ids);
+    get diagnostics v_password_count = row_count;
+
+    delete from vaults where user_id = p_user_id;
+
+    if to_regclass('public.sessions') is not null then
+        execute 'delete from sessions where user_id = $1' using p_user_id;
+    end if;
+
+    if to_regclass('public.two_factor_secrets') is not null then
+        execute 'delete from two_factor_secrets where user_id = $1' using p_user_id;
+    end if;
+
+    if to_regclass('public.two_factor_recovery_codes') is not null then
+        execute 'delete from two_factor_recovery_codes where user_id = $1' using p_user_id;
+    end if;
+
+    delete from users where id = p_user_id;
+
+    insert into audit_logs (user_id, event, metadata)
+    values (
+        p_user_id,
+        'account_deleted',
+        jsonb_build_object('reason', p_reason, 'vaults', cardinality(v_vault_ids), 'passwords', v_password_count)
+    );
+
+    return true;
+end;
+$$;
@@ -0,0 +1,12 @@
+-- Send files count toward the same storage quota as attachments.
+
+-- user_storage_used returns the total size in bytes of the user's attachments
+-- and Send files.
+create or replace function user_storage_used(p_user_id bigint)
+returns bigint
+language sql
+stable
+as $$
+    select coalesce((select sum(size) from attachments where user_id = p_user_id), 0)
+         + coalesce((select sum(size) from sends where user_id = p_user_id), 0);
+$$;
@@ -0,0 +1,67 @@
+-- The storage quota is enforced when attachments and Send files are recorded.
+-- Both take a per-user lock and sum the user's storage before inserting, so
+-- concurrent uploads cannot go over the quota together.
+
+-- lock_user_storage serializes the quota checks of a user until the end of
+-- the transaction.
+create or replace function lock_user_storage(p_user_id bigint)
+returns void
+language sql
+as $$
+    select pg_advisory_xact_lock(hashtextextended('user_storage:' || p_user_id, 0));
+$$;
+
+-- create_attachment records an uploaded attachment, or raises 53100 when it
+-- does not fit in p_quota bytes.
+create or replace function create_attachment(p_attachment jsonb, p_quota bigint)
+returns setof attachments
+language plpgsql
+as $$
+declare
+    v_attachment attachments;
+begin
+    v_attachment := jsonb_populate_record(null::attachments, p_attachment);
+
+    perform lock_user_storage(v_attachment.user_id);
+
+    if user_storage_used(v_attachment.user_id) + v_attachment.size > p_quota then
+        raise exception 'attachment exceeds the storage quota of user %', v_attachment.user_id using errcode = '53100';
+    end if;
+
+    return query
+    insert into attachments (password_id, user_id, blob_key, encrypted_file_name, encrypted_key, size)
+    values (v_attachment.password_id, v_attachment.user_id, v_attachment.blob_key, v_attachment.encrypted_file_name,
+            v_attachment.encrypted_key, v_attachment.size)
+    returning *;
+end;
+$$;
+
+-- create_send records a Send, or raises 53100 when its file does not fit in
+-- p_quota bytes. Text Sends take no storage and are always recorded.
+create or replace function create_send(p_send jsonb, p_quota bigint)
+returns setof sends
+language plpgsql
+as $$
+declare
+    v_send sends;
+begin
+    v_send := jsonb_populate_record(null::sends, p_send);
+
+    if v_send.size > 0 then
+        perform lock_user_storage(v_send.user_id);
+
+        if user_storage_used(v_send.user_id) + v_send.size > p_quota then
+            raise exception 'Send exceeds the storage quota of user %', v_send.user_id using errcode = '53100';
+        end if;
+    end if;
+
+    return query
+    insert into sends (access_id, user_id, type, encrypted_name, encrypted_key, encrypted_text, encrypted_file_name,
+                       blob_key, size, password_hash, password_salt, max_access_count, expiration_date, deletion_date)
+    values (v_send.access_id, v_send.user_id, v_send.type, v_send.encrypted_name, v_send.encrypted_key,
+            v_send.encrypted_text, v_send.encrypted_file_name, v_send.blob_key, coalesce(v_send.size, 0),
+            v_send.password_hash, v_send.password_salt, v_send.max_access_count, v_send.expiration_date,
+            v_send.deletion_date)
+    returning *;
+end;
+$$;
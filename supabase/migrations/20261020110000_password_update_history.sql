@@ -0,0 +1,138 @@
+-- Single password updates and history restores keep the history in the same
+-- transaction as the change, like batch updates, so a failed or conflicting
+-- update leaves no history entry behind.
+
+-- record_password_history keeps the current encrypted password of an item in
+-- its history when an update replaces it, dropping the oldest entries over
+-- p_history_limit. A new item key makes the previous passwords unreadable,
+-- so it clears the history instead.
+create or replace function record_password_history(
+    p_current passwords,
+    p_item_key text,
+    p_encrypted_password text,
+    p_history_limit integer
+)
+returns void
+language plpgsql
+as $$
+begin
+    if coalesce(p_item_key, '') <> '' and p_item_key <> coalesce(p_current.item_key, '') then
+        delete from password_history where password_id = p_current.id;
+        return;
+    end if;
+
+    if p_history_limit <= 0 or coalesce(p_current.encrypted_password, '') = ''
+       or p_current.encrypted_password = p_encrypted_password then
+        return;
+    end if;
+
+    insert into password_history (password_id, encrypted_password)
+    values (p_current.id, p_current.encrypted_password);
+
+    delete from password_history
+     where password_id = p_current.id
+       and id not in (
+            select id from password_history
+             where password_id = p_current.id
+             order by created_at desc, id desc
+             limit p_history_limit
+           );
+end;
+$$;
+
+-- update_password replaces an item of the vault and records its history. It
+-- raises 40001 when p_revision is given and the item has changed since, and
+-- P0002 when the vault has no such item.
+create or replace function update_password(
+    p_password_id bigint,
+    p_vault_id bigint,
+    p_item jsonb,
+    p_revision bigint,
+    p_history_limit integer
+)
+returns setof passwords
+language plpgsql
+as $$
+declare
+    v_item    passwords;
+    v_current passwords;
+begin
+    v_item := jsonb_populate_record(null::passwords, p_item);
+
+    select * into v_current from passwords
+     where id = p_password_id and vault_id = p_vault_id
+       for update;
+
+    if not found then
+        raise exception 'password % not found', p_password_id using errcode = 'P0002';
+    end if;
+
+    if p_revision is not null and v_current.revision <> p_revision then
+        raise exception 'password % has changed since revision %', p_password_id, p_revision using errcode = '40001';
+    end if;
+
+    perform record_password_history(v_current, v_item.item_key, v_item.encrypted_password, p_history_limit);
+
+    return query
+    update passwords
+       set type = v_item.type,
+           app_name = coalesce(v_item.app_name, app_name),
+           uri = coalesce(v_item.uri, uri),
+           username = coalesce(v_item.username, username),
+           encrypted_password = v_item.encrypted_password,
+           item_key = coalesce(nullif(v_item.item_key, ''), item_key),
+           folder_id = v_item.folder_id,
+           favorite = coalesce(v_item.favorite, false),
+           secure_note = v_item.secure_note,
+           card = v_item.card,
+           identity = v_item.identity,
+           ssh_key = v_item.ssh_key,
+           api_credential = v_item.api_credential,
+           fields = v_item.fields,
+           uris = v_item.uris
+     where id = p_password_id
+    returning *;
+end;
+$$;
+
+-- restore_password_history makes a previous password of an item the current
+-- one again; the replaced password goes into the history in its place. It
+-- raises P0002 when the item or the history entry is not found.
+create or replace function restore_password_history(
+    p_password_id bigint,
+    p_vault_id bigint,
+    p_history_id bigint,
+    p_history_limit integer
+)
+returns setof passwords
+language plpgsql
+as $$
+declare
+    v_current  passwords;
+    v_restored text;
+begin
+    select * into v_current from passwords
+     where id = p_password_id and vault_id = p_vault_id
+       for update;
+
+    if not found then
+        raise exception 'password % not found', p_password_id using errcode = 'P0002';
+    end if;
+
+    delete from password_history
+     where id = p_history_id and password_id = p_password_id
+    returning encrypted_password into v_restored;
+
+    if not found then
+        raise exception 'password history entry % not found', p_history_id using errcode = 'P0002';
+    end if;
+
+    perform record_password_history(v_current, null, v_restored, p_history_limit);
+
+    return query
+    update passwords
+       set encrypted_password = v_restored
+     where id = p_password_id
+    returning *;
+end;
+$$;
@@ -0,0 +1,10 @@
+-- Email addresses are stored lowercased and looked up that way. Existing
+-- addresses are lowercased unless that would clash with another account;
+-- those accounts still log in with the address as it is stored.
+update users u
+   set email = lower(u.email)
+ where u.email <> lower(u.email)
+   and not exists (
+        select 1 from users o
+         where o.id <> u.id and lower(o.email) = lower(u.email)
+       );
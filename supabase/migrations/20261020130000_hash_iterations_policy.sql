@@ -0,0 +1,17 @@
+-- min_kdf_iterations becomes min_hash_iterations. The policy only raises the
+-- iterations of the server-side hash of the master password hash; the client
+-- key derivation that protects the vault key is never seen by the server.
+alter table organization_policies drop constraint if exists organization_policies_type_check;
+
+update organization_policies
+   set type = 'min_hash_iterations'
+ where type = 'min_kdf_iterations';
+
+alter table organization_policies add constraint organization_policies_type_check
+    check (type in (
+        'require_two_factor',
+        'min_hash_iterations',
+        'disable_personal_export',
+        'max_session_timeout',
+        'disable_external_sharing'
+    ));
@@ -0,0 +1,40 @@
+-- Emergency access invitations are addressed to an email address rather than
+-- an account, so inviting does not tell whether the address is registered.
+-- grantee_id stays null until the owner of the address, once verified,
+-- claims the invitation.
+alter table emergency_access add column if not exists grantee_email text;
+
+update emergency_access e
+   set grantee_email = u.email
+  from users u
+ where u.id = e.grantee_id
+   and e.grantee_email is null;
+
+alter table emergency_access alter column grantee_email set not null;
+alter table emergency_access alter column grantee_id drop not null;
+
+alter table emergency_access drop constraint if exists emergency_access_grantor_id_grantee_email_key;
+alter table emergency_access add constraint emergency_access_grantor_id_grantee_email_key
+    unique (grantor_id, grantee_email);
+
+create index if not exists emergency_access_grantee_email_idx on emergency_access (grantee_email) where grantee_id is null;
+
+-- claim_emergency_access hands the pending invitations sent to p_email to
+-- p_grantee_id. Invitations from the grantee themselves, or from grantors who
+-- already granted the grantee access, are left pending.
+create or replace function claim_emergency_access(p_grantee_id bigint, p_email text)
+returns setof emergency_access
+language sql
+as $$
+    update emergency_access e
+       set grantee_id = p_grantee_id,
+           updated_at = now()
+     where e.grantee_id is null
+       and e.grantee_email = p_email
+       and e.grantor_id <> p_grantee_id
+       and not exists (
+            select 1 from emergency_access o
+             where o.grantor_id = e.grantor_id and o.grantee_id = p_grantee_id
+           )
+    returning e.*;
+$$;
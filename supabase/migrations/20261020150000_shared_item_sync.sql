@@ -0,0 +1,77 @@
+-- Shared items are synced with the recipient's default vault, where they are
+-- listed. A share carries the revision of that vault at its last change, which
+-- is the share itself changing or the shared item changing, and a removed
+-- share leaves a tombstone there.
+
+alter table password_shares add column if not exists revision bigint not null default 0;
+
+create index if not exists password_shares_recipient_id_revision_idx on password_shares (recipient_id, revision);
+
+create or replace function bump_share_revision()
+returns trigger
+language plpgsql
+as $$
+declare
+    v_vault_id bigint;
+begin
+    select id into v_vault_id from vaults where user_id = new.recipient_id and is_default;
+    if found then
+        new.revision := coalesce(next_vault_revision(v_vault_id), new.revision);
+    end if;
+
+    return new;
+end;
+$$;
+
+drop trigger if exists password_shares_bump_revision on password_shares;
+create trigger password_shares_bump_revision
+    before insert or update on password_shares
+    for each row execute function bump_share_revision();
+
+create or replace function touch_password_shares()
+returns trigger
+language plpgsql
+as $$
+begin
+    update password_shares set revision = revision where password_id = new.id;
+
+    return null;
+end;
+$$;
+
+drop trigger if exists passwords_touch_shares on passwords;
+create trigger passwords_touch_shares
+    after update on passwords
+    for each row execute function touch_password_shares();
+
+create or replace function record_share_tombstone()
+returns trigger
+language plpgsql
+as $$
+declare
+    v_vault_id bigint;
+    v_revision bigint;
+begin
+    select id into v_vault_id from vaults where user_id = old.recipient_id and is_default;
+    if not found then
+        return old;
+    end if;
+
+    v_revision := next_vault_revision(v_vault_id);
+    if v_revision is not null then
+        insert into password_tombstones (vault_id, password_id, revision)
+        values (v_vault_id, old.password_id, v_revision);
+    end if;
+
+    return old;
+end;
+$$;
+
+drop trigger if exists password_shares_record_tombstone on password_shares;
+create trigger password_shares_record_tombstone
+    after delete on password_shares
+    for each row execute function record_share_tombstone();
+
+-- Existing shares get a revision, so clients that synced before see them in
+-- their next delta.
+update password_shares set revision = revision;